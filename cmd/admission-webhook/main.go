@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"k8s.io/klog/v2/klogr"
@@ -38,6 +39,9 @@ var (
 	healthProbeBindAddress  string
 	gracefulShutdownTimeout time.Duration
 	cacheSyncTimeout        time.Duration
+	breakGlassUsers         string
+	breakGlassGroups        string
+	breakGlassMaxDuration   time.Duration
 )
 
 func main() {
@@ -46,6 +50,9 @@ func main() {
 	flag.StringVar(&healthProbeBindAddress, "health-probe-bind-addr", fmt.Sprintf(":%d", configuration.HealthProbePort), "The address the healthz & readyz probes bind to.")
 	flag.DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", configuration.GracefulShutdownTimeout, "The duration of time to wait while shutting down for all controllers to stop.")
 	flag.DurationVar(&cacheSyncTimeout, "cache-sync-timeout", configuration.CacheSyncTimeout, "The duration of time to wait while informers synchronize.")
+	flag.StringVar(&breakGlassUsers, "break-glass-users", "", "A comma-separated list of users allowed to exempt managed objects from the webhook and the remediator in an emergency.")
+	flag.StringVar(&breakGlassGroups, "break-glass-groups", "", "A comma-separated list of groups whose members are allowed to exempt managed objects from the webhook and the remediator in an emergency.")
+	flag.DurationVar(&breakGlassMaxDuration, "break-glass-max-duration", webhook.DefaultBreakGlassMaxDuration, "The longest duration of a break-glass exemption.")
 
 	log.Setup()
 
@@ -107,7 +114,12 @@ func main() {
 		<-certDone
 
 		setupLog.Info("registering validator for webhook")
-		if err := webhook.AddValidator(mgr); err != nil {
+		breakGlassOpts := webhook.BreakGlassOptions{
+			Users:       splitList(breakGlassUsers),
+			Groups:      splitList(breakGlassGroups),
+			MaxDuration: breakGlassMaxDuration,
+		}
+		if err := webhook.AddValidator(mgr, breakGlassOpts); err != nil {
 			setupLog.Error(err, "unable to register validator for webhook")
 			os.Exit(1)
 		}
@@ -122,3 +134,14 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// splitList splits a comma-separated list, ignoring empty items.
+func splitList(s string) []string {
+	var result []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
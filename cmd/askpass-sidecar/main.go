@@ -0,0 +1,74 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/credentials"
+	"kpt.dev/configsync/pkg/reconcilermanager"
+	"kpt.dev/configsync/pkg/util"
+	utillog "kpt.dev/configsync/pkg/util/log"
+)
+
+var (
+	flPort = flag.Int("port", util.EnvInt("ASKPASS_PORT", reconcilermanager.AskpassSidecarPort),
+		"the port on which to serve the credentials")
+	flAuth = flag.String("auth", util.EnvString(reconcilermanager.AskpassAuth, ""),
+		fmt.Sprintf("the authentication type for access to the Git repository. Must be one of %s or %s",
+			configsync.AuthGitHubApp, configsync.AuthK8sServiceAccount))
+	flSecretDir = flag.String("secret-dir", util.EnvString("ASKPASS_SECRET_DIR", "/etc/git-secret"),
+		"the directory where the Secret of the GitHub App is mounted")
+	flTokenExchangeURL = flag.String("token-exchange-url", util.EnvString(reconcilermanager.TokenExchangeURL, ""),
+		"the token endpoint that exchanges the Kubernetes Service Account token for credentials")
+	flTokenExchangeUsername = flag.String("token-exchange-username", util.EnvString(reconcilermanager.TokenExchangeUsername, ""),
+		"the username that goes with the exchanged token")
+)
+
+func main() {
+	utillog.Setup()
+	klog.Infof("serving Git credentials with arguments --port=%d --auth=%s --secret-dir=%s --token-exchange-url=%s --token-exchange-username=%s",
+		*flPort, *flAuth, *flSecretDir, *flTokenExchangeURL, *flTokenExchangeUsername)
+
+	var provider credentials.Provider
+	switch configsync.AuthType(*flAuth) {
+	case configsync.AuthGitHubApp:
+		app, err := credentials.NewGitHubAppFromDir(*flSecretDir)
+		if err != nil {
+			klog.Fatalf("failed to configure the GitHub App: %v", err)
+		}
+		provider = app
+	case configsync.AuthK8sServiceAccount:
+		if *flTokenExchangeURL == "" {
+			klog.Fatal("--token-exchange-url must be specified")
+		}
+		provider = credentials.NewTokenExchange(*flTokenExchangeURL, *flTokenExchangeUsername)
+	default:
+		klog.Fatalf("unsupported authentication type %q", *flAuth)
+	}
+
+	http.Handle("/git_askpass", credentials.AskpassHandler(credentials.Cached(provider)))
+	// Only git-sync, in the same pod, needs the credentials.
+	addr := fmt.Sprintf("localhost:%d", *flPort)
+	if err := http.ListenAndServe(addr, nil); err != nil {
+		klog.Errorf("failed to serve the credentials on %s: %v", addr, err)
+		os.Exit(1)
+	}
+}
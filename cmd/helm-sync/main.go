@@ -16,6 +16,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -23,8 +24,11 @@ import (
 
 	"k8s.io/klog/v2/klogr"
 	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/credentials"
 	"kpt.dev/configsync/pkg/helm"
+	"kpt.dev/configsync/pkg/oci"
 	"kpt.dev/configsync/pkg/reconcilermanager"
+	"kpt.dev/configsync/pkg/trigger"
 	"kpt.dev/configsync/pkg/util"
 	utillog "kpt.dev/configsync/pkg/util/log"
 )
@@ -38,11 +42,13 @@ var (
 		"the version of the helm chart being synced")
 	flValues = flag.String("values", os.Getenv(reconcilermanager.HelmValues),
 		"set the helm chart values, will be used to override the default values")
+	flValuesSources = flag.String("values-sources", os.Getenv(reconcilermanager.HelmValuesSources),
+		"a JSON list of values, merged in order before --values. Each entry sets either a values file, relative to the chart or absolute, or inline values")
 	flIncludeCRDs = flag.String("include-crds", os.Getenv(reconcilermanager.HelmIncludeCRDs),
 		"include CRDs in the helm rendering output")
 	flAuth = flag.String("auth", util.EnvString(reconcilermanager.HelmAuthType, string(configsync.AuthNone)),
-		fmt.Sprintf("the authentication type for access to the Helm repository. Must be one of %s, %s, %s or %s. Defaults to %s",
-			configsync.AuthGCPServiceAccount, configsync.AuthToken, configsync.AuthGCENode, configsync.AuthNone, configsync.AuthNone))
+		fmt.Sprintf("the authentication type for access to the Helm repository. Must be one of %s, %s, %s, %s or %s. Defaults to %s",
+			configsync.AuthGCPServiceAccount, configsync.AuthToken, configsync.AuthGCENode, configsync.AuthK8sServiceAccount, configsync.AuthNone, configsync.AuthNone))
 	flReleaseName = flag.String("release-name", os.Getenv(reconcilermanager.HelmReleaseName),
 		"the name of helm release")
 	flNamespace = flag.String("namespace", os.Getenv(reconcilermanager.HelmReleaseNamespace),
@@ -61,10 +67,23 @@ var (
 		"exit after the first sync")
 	flMaxSyncFailures = flag.Int("max-sync-failures", util.EnvInt("HELM_SYNC_MAX_SYNC_FAILURES", 0),
 		"the number of consecutive failures allowed before aborting (the first sync must succeed, -1 will retry forever after the initial sync)")
+	flVerificationProvider = flag.String("verification-provider", util.EnvString(reconcilermanager.HelmSyncVerificationProvider, ""),
+		fmt.Sprintf("the tool used to sign the helm chart in an OCI registry, either %s or %s. The signature is not verified if it is empty",
+			configsync.VerificationProviderCosign, configsync.VerificationProviderNotation))
+	flVerificationKeysDir = flag.String("verification-keys-dir", util.EnvString("HELM_SYNC_VERIFICATION_KEYS_DIR", "/etc/verification-keys"),
+		"the directory that holds the trusted PEM-encoded public keys or root certificates")
 	flUsername = flag.String("username", util.EnvString("HELM_SYNC_USERNAME", ""),
 		"the username to use for helm authantication")
 	flPassword = flag.String("password", util.EnvString("HELM_SYNC_PASSWORD", ""),
 		"the password or personal access token to use for helm authantication")
+	flTokenExchangeURL = flag.String("token-exchange-url", util.EnvString(reconcilermanager.TokenExchangeURL, ""),
+		fmt.Sprintf("the token endpoint that exchanges the Kubernetes Service Account token for credentials, when --auth is %s", configsync.AuthK8sServiceAccount))
+	flTokenExchangeUsername = flag.String("token-exchange-username", util.EnvString(reconcilermanager.TokenExchangeUsername, ""),
+		"the username that goes with the exchanged token")
+
+	// The webhook token is read from the environment only, to avoid leaking it
+	// through the process arguments.
+	webhookToken = os.Getenv(reconcilermanager.WebhookToken)
 )
 
 func main() {
@@ -72,9 +91,10 @@ func main() {
 	log := utillog.NewLogger(klogr.New(), *flRoot, *flErrorFile)
 	log.Info("rendering Helm chart with arguments", "--repo", *flRepo,
 		"--chart", *flChart, "--version", *flVersion, "--root", *flRoot,
-		"--values", *flValues, "--include-crds", *flIncludeCRDs, "--dest", *flDest, "--wait", *flWait,
+		"--values", *flValues, "--values-sources", *flValuesSources, "--include-crds", *flIncludeCRDs, "--dest", *flDest, "--wait", *flWait,
 		"--error-file", *flErrorFile, "--timeout", *flSyncTimeout,
-		"--one-time", *flOneTime, "--max-sync-failures", *flMaxSyncFailures)
+		"--one-time", *flOneTime, "--max-sync-failures", *flMaxSyncFailures,
+		"--verification-provider", *flVerificationProvider, "--verification-keys-dir", *flVerificationKeysDir)
 
 	if *flRepo == "" {
 		utillog.HandleError(log, true, "ERROR: --repo must be specified")
@@ -98,23 +118,67 @@ func main() {
 		}
 	}
 
+	var provider credentials.Provider
+	if configsync.AuthType(*flAuth) == configsync.AuthK8sServiceAccount {
+		if *flTokenExchangeURL == "" {
+			utillog.HandleError(log, true, "ERROR: --token-exchange-url must be specified when --auth is %s", *flAuth)
+		}
+		provider = credentials.Cached(credentials.NewTokenExchange(*flTokenExchangeURL, *flTokenExchangeUsername))
+	}
+
+	var valuesSources []helm.ValuesSource
+	if *flValuesSources != "" {
+		if err := json.Unmarshal([]byte(*flValuesSources), &valuesSources); err != nil {
+			utillog.HandleError(log, true, "ERROR: failed to parse --values-sources: %v", err)
+		}
+	}
+	if *flValues != "" {
+		valuesSources = append(valuesSources, helm.ValuesSource{Inline: *flValues})
+	}
+
+	var verifier *oci.Verifier
+	if *flVerificationProvider != "" {
+		verifier = &oci.Verifier{
+			Provider: configsync.VerificationProvider(*flVerificationProvider),
+			KeysDir:  *flVerificationKeysDir,
+		}
+	}
+
+	// Fetch immediately when the reconciler receives a source change
+	// notification. Only listen on the loopback interface, because the
+	// notifications are forwarded by the hydration-controller in the same Pod.
+	var triggers <-chan struct{}
+	if webhookToken != "" {
+		webhookServer := trigger.NewServer(webhookToken)
+		triggers = webhookServer.Triggers()
+		go func() {
+			addr := fmt.Sprintf("127.0.0.1:%d", reconcilermanager.SourceWebhookPort)
+			if err := webhookServer.Run(context.Background(), addr); err != nil {
+				// Keep polling for source changes, so don't exit.
+				log.Error(err, "webhook server exited")
+			}
+		}()
+	}
+
 	initialSync := true
 	failCount := 0
 	for {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(*flSyncTimeout))
 		hydrator := &helm.Hydrator{
-			Chart:       *flChart,
-			Repo:        *flRepo,
-			Version:     *flVersion,
-			ReleaseName: *flReleaseName,
-			Namespace:   *flNamespace,
-			Values:      *flValues,
-			IncludeCRDs: *flIncludeCRDs,
-			Auth:        configsync.AuthType(*flAuth),
-			HydrateRoot: *flRoot,
-			Dest:        *flDest,
-			UserName:    *flUsername,
-			Password:    *flPassword,
+			Chart:         *flChart,
+			Repo:          *flRepo,
+			Version:       *flVersion,
+			ReleaseName:   *flReleaseName,
+			Namespace:     *flNamespace,
+			ValuesSources: valuesSources,
+			IncludeCRDs:   *flIncludeCRDs,
+			Auth:          configsync.AuthType(*flAuth),
+			HydrateRoot:   *flRoot,
+			Dest:          *flDest,
+			UserName:      *flUsername,
+			Password:      *flPassword,
+			Credentials:   provider,
+			Verifier:      verifier,
 		}
 		if err := hydrator.HelmTemplate(ctx); err != nil {
 			if *flMaxSyncFailures != -1 && failCount >= *flMaxSyncFailures {
@@ -127,7 +191,7 @@ func main() {
 			log.Error(err, "unexpected error rendering chart, will retry")
 			log.Info("waiting before retrying", "waitTime", util.WaitTime(*flWait))
 			cancel()
-			time.Sleep(util.WaitTime(*flWait))
+			trigger.Wait(util.WaitTime(*flWait), triggers)
 			continue
 		}
 
@@ -143,6 +207,6 @@ func main() {
 		log.DeleteErrorFile()
 		log.Info("next sync", "wait_time", util.WaitTime(*flWait))
 		cancel()
-		time.Sleep(util.WaitTime(*flWait))
+		trigger.Wait(util.WaitTime(*flWait), triggers)
 	}
 }
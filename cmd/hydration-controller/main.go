@@ -17,6 +17,7 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
 	"strings"
 
@@ -30,6 +31,8 @@ import (
 	"kpt.dev/configsync/pkg/profiler"
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/reconcilermanager/controllers"
+	"kpt.dev/configsync/pkg/trigger"
+	"kpt.dev/configsync/pkg/util"
 	"kpt.dev/configsync/pkg/util/log"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -70,6 +73,22 @@ var (
 
 	reconcilerName = flag.String("reconciler-name", os.Getenv(reconcilermanager.ReconcilerNameKey),
 		"Name of the reconciler Deployment.")
+
+	enableHelm = flag.Bool("enable-helm", util.EnvBool(reconcilermanager.KustomizeEnableHelm, true),
+		"Render the helmCharts field of kustomizations with the Helm chart inflator generator.")
+
+	enableAlphaPlugins = flag.Bool("enable-alpha-plugins", util.EnvBool(reconcilermanager.KustomizeEnableAlphaPlugins, true),
+		"Enable the Kustomize alpha plugins, including exec plugins and the Helm inflation function.")
+
+	loadRestrictor = flag.String("load-restrictor", os.Getenv(reconcilermanager.KustomizeLoadRestrictor),
+		"The load restrictor of `kustomize build`. Uses the Kustomize default if unset.")
+
+	helmCommand = flag.String("helm-command", os.Getenv(reconcilermanager.KustomizeHelmCommand),
+		"The path of the Helm binary used by `kustomize build`. Uses the Kustomize default if unset.")
+
+	// The webhook token is read from the environment only, to avoid leaking it
+	// through the process arguments.
+	webhookToken = os.Getenv(reconcilermanager.WebhookToken)
 )
 
 func main() {
@@ -120,7 +139,37 @@ func main() {
 		PollingPeriod:   *pollingPeriod,
 		RehydratePeriod: *rehydratePeriod,
 		ReconcilerName:  *reconcilerName,
+		BuildOptions: hydrate.BuildOptions{
+			DisableHelm:         !*enableHelm,
+			DisableAlphaPlugins: !*enableAlphaPlugins,
+			LoadRestrictor:      *loadRestrictor,
+			HelmCommand:         *helmCommand,
+		},
+	}
+
+	ctx := context.Background()
+	if webhookToken != "" {
+		// Only listen on the loopback interface, because the notifications are
+		// forwarded by the reconciler container in the same Pod.
+		// The notifications are forwarded to the source sidecar, so that the
+		// source is fetched immediately.
+		var webhookServer *trigger.Server
+		switch hydrator.SourceType {
+		case v1beta1.GitSource:
+			webhookServer = trigger.NewServer(webhookToken)
+			webhookServer.SignalProcesses(reconcilermanager.GitSync)
+		default:
+			webhookServer = trigger.NewServer(webhookToken, trigger.LocalURL(reconcilermanager.SourceWebhookPort))
+		}
+		hydrator.Triggers = webhookServer.Triggers()
+		go func() {
+			addr := fmt.Sprintf("127.0.0.1:%d", reconcilermanager.HydrationWebhookPort)
+			if err := webhookServer.Run(ctx, addr); err != nil {
+				// The hydrator keeps polling for source changes, so don't exit.
+				klog.Errorf("Webhook server exited: %v", err)
+			}
+		}()
 	}
 
-	hydrator.Run(context.Background())
+	hydrator.Run(ctx)
 }
@@ -0,0 +1,64 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"github.com/spf13/cobra"
+	"kpt.dev/configsync/cmd/nomos/flags"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/importer/filesystem"
+)
+
+var (
+	syncName      string
+	syncNamespace string
+	clusterName   string
+)
+
+func init() {
+	flags.AddPath(Cmd)
+	flags.AddSourceFormat(Cmd)
+	flags.AddAPIServerTimeout(Cmd)
+	Cmd.Flags().StringVar(&syncName, "sync-name", "",
+		"Name of the RootSync or RepoSync to compare against. Defaults to root-sync, or repo-sync if --sync-namespace is set.")
+	Cmd.Flags().StringVar(&syncNamespace, "sync-namespace", configsync.ControllerNamespace,
+		"Namespace of the RootSync or RepoSync to compare against. A RepoSync is used unless it is config-management-system.")
+	Cmd.Flags().StringVar(&clusterName, "cluster-name", "",
+		"Name of the cluster, used to evaluate cluster selectors.")
+}
+
+// Cmd is the Cobra object representing the nomos diff command.
+var Cmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show what the next sync of a local directory would change on the current cluster.",
+	Long: `Show what the next sync of a local directory would change on the current cluster.
+
+Parses and validates the directory as nomos vet does, then compares the result
+with the live objects on the cluster of the current context, and with the
+objects in the ResourceGroup inventory of the RootSync or RepoSync. Prints the
+objects that would be created, updated (with the fields that would change),
+deleted, or abandoned because of the Prevent Deletion annotation. Objects with
+management disabled are ignored.`,
+	Example: `  nomos diff
+  nomos diff --path=my/directory --sync-name=my-root-sync
+  nomos diff --sync-namespace=bookstore --source-format=unstructured`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Don't show usage on error, as argument validation passed.
+		cmd.SilenceUsage = true
+
+		return runDiff(cmd.Context(), filesystem.SourceFormat(flags.SourceFormat), flags.APIServerTimeout)
+	},
+}
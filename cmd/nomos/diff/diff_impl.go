@@ -0,0 +1,405 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/pkg/live"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	nomosparse "kpt.dev/configsync/cmd/nomos/parse"
+	"kpt.dev/configsync/cmd/nomos/util"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/applier"
+	"kpt.dev/configsync/pkg/client/restconfig"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/diff"
+	"kpt.dev/configsync/pkg/hydrate"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/importer/filesystem"
+	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
+	"kpt.dev/configsync/pkg/importer/reader"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/parse"
+	"kpt.dev/configsync/pkg/reconcilermanager"
+	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/validate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// operationOrder is the order in which the changes are printed.
+var operationOrder = map[diff.Operation]int{
+	diff.Create:             0,
+	diff.Update:             1,
+	diff.Delete:             2,
+	diff.Abandon:            3,
+	diff.ManagementConflict: 4,
+	diff.Error:              5,
+}
+
+// change is a change the next sync would make to an object.
+type change struct {
+	operation diff.Operation
+	id        core.ID
+	// fields are the fields that would be updated.
+	fields []diff.FieldDiff
+	// manager is the manager of an object in management conflict.
+	manager string
+}
+
+// runDiff parses the directory at flags.Path, and prints the changes that
+// syncing it with the RootSync or RepoSync would make to the current cluster.
+func runDiff(ctx context.Context, sourceFormat filesystem.SourceFormat, apiServerTimeout time.Duration) error {
+	scope, name := syncScope(syncNamespace, syncName)
+
+	cfg, err := restconfig.NewRestConfig(apiServerTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to create rest config: %w", err)
+	}
+	mapper, err := apiutil.NewDynamicRESTMapper(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create mapper: %w", err)
+	}
+	c, err := client.New(cfg, client.Options{
+		Scheme: core.Scheme,
+		Mapper: mapper,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	if sourceFormat == "" {
+		sourceFormat, err = syncSourceFormat(ctx, c, scope, name)
+		if err != nil {
+			return err
+		}
+	}
+	objs, err := parseDirectory(ctx, sourceFormat, scope, name, apiServerTimeout)
+	if err != nil {
+		return err
+	}
+	setManagementMetadata(objs, scope, name)
+	declaredObjs := make(map[core.ID]client.Object)
+	for _, obj := range filesystem.AsCoreObjects(objs) {
+		declaredObjs[core.IDOf(obj)] = obj
+	}
+
+	inventory, err := inventoryIDs(ctx, c, scope, name)
+	if err != nil {
+		return err
+	}
+	actual, previous, err := liveObjects(ctx, c, declaredObjs, inventory)
+	if err != nil {
+		return err
+	}
+	changes, err := computeChanges(scope, name, declaredObjs, previous, actual)
+	if err != nil {
+		return err
+	}
+	return printChanges(os.Stdout, changes)
+}
+
+// syncScope returns the scope and the name of the RootSync or RepoSync.
+func syncScope(namespace, name string) (declared.Scope, string) {
+	if namespace == configsync.ControllerNamespace {
+		if name == "" {
+			name = configsync.RootSyncName
+		}
+		return declared.RootReconciler, name
+	}
+	if name == "" {
+		name = configsync.RepoSyncName
+	}
+	return declared.Scope(namespace), name
+}
+
+// syncSourceFormat returns the source format of the RootSync, or unstructured
+// for a RepoSync.
+func syncSourceFormat(ctx context.Context, c client.Client, scope declared.Scope, name string) (filesystem.SourceFormat, error) {
+	if scope != declared.RootReconciler {
+		return filesystem.SourceFormatUnstructured, nil
+	}
+	rs := &v1beta1.RootSync{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: configsync.ControllerNamespace, Name: name}, rs); err != nil {
+		return "", fmt.Errorf("failed to get RootSync %s/%s: %w", configsync.ControllerNamespace, name, err)
+	}
+	if rs.Spec.SourceFormat == "" {
+		return filesystem.SourceFormatHierarchy, nil
+	}
+	return filesystem.SourceFormat(rs.Spec.SourceFormat), nil
+}
+
+// parseDirectory parses and validates the directory at flags.Path, as the
+// reconciler of the RootSync or RepoSync would.
+func parseDirectory(ctx context.Context, sourceFormat filesystem.SourceFormat, scope declared.Scope, name string, apiServerTimeout time.Duration) ([]ast.FileObject, error) {
+	rootDir, needsHydrate, err := hydrate.ValidateHydrateFlags(sourceFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	if needsHydrate {
+		// update rootDir to point to the hydrated output for further processing.
+		if rootDir, err = hydrate.ValidateAndRunKustomize(rootDir.OSPath()); err != nil {
+			return nil, err
+		}
+		// delete the hydrated output directory in the end.
+		defer func() {
+			_ = os.RemoveAll(rootDir.OSPath())
+		}()
+	}
+
+	files, err := nomosparse.FindFiles(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := filesystem.NewParser(&reader.File{})
+
+	options, err := hydrate.ValidateOptions(ctx, rootDir, apiServerTimeout)
+	if err != nil {
+		return nil, err
+	}
+	options.ClusterName = clusterName
+	if scope == declared.RootReconciler {
+		options.ReconcilerName = core.RootReconcilerName(name)
+	} else {
+		options.ReconcilerName = core.NsReconcilerName(string(scope), name)
+	}
+
+	switch sourceFormat {
+	case filesystem.SourceFormatHierarchy:
+		if scope != declared.RootReconciler {
+			return nil, fmt.Errorf("the %s of a RepoSync must be %s", reconcilermanager.SourceFormat, filesystem.SourceFormatUnstructured)
+		}
+		files = filesystem.FilterHierarchyFiles(rootDir, files)
+	case filesystem.SourceFormatUnstructured:
+		options = parse.OptionsForScope(options, scope)
+	default:
+		return nil, fmt.Errorf("unknown %s value %q", reconcilermanager.SourceFormat, sourceFormat)
+	}
+
+	filePaths := reader.FilePaths{
+		RootDir:   rootDir,
+		PolicyDir: cmpath.RelativeOS(rootDir.OSPath()),
+		Files:     files,
+	}
+	objs, errs := parser.Parse(filePaths)
+	var validateErrs status.MultiError
+	if sourceFormat == filesystem.SourceFormatHierarchy {
+		objs, validateErrs = validate.Hierarchical(objs, options)
+	} else {
+		objs, validateErrs = validate.Unstructured(objs, options)
+	}
+	errs = status.Append(errs, validateErrs)
+	if status.HasBlockingErrors(errs) {
+		return nil, errs
+	}
+	if errs != nil {
+		// The reconciler applies the objects despite non-blocking errors.
+		util.PrintErrOrDie(errs)
+	}
+	return objs, nil
+}
+
+// setManagementMetadata sets the metadata that the reconciler adds to the
+// declared objects, except for the metadata that changes with every commit.
+func setManagementMetadata(objs []ast.FileObject, scope declared.Scope, name string) {
+	inventoryNamespace := string(scope)
+	if scope == declared.RootReconciler {
+		inventoryNamespace = configsync.ControllerNamespace
+	}
+	for _, obj := range objs {
+		core.SetLabel(obj, metadata.ManagedByKey, metadata.ManagedByValue)
+		core.SetAnnotation(obj, metadata.ResourceManagerKey, declared.ResourceManager(scope, name))
+		core.SetAnnotation(obj, metadata.ResourceIDKey, core.GKNN(obj))
+		core.SetAnnotation(obj, metadata.OwningInventoryKey, applier.InventoryID(name, inventoryNamespace))
+		if core.GetAnnotation(obj, metadata.ResourceManagementKey) != metadata.ResourceManagementDisabled {
+			core.SetAnnotation(obj, metadata.ResourceManagementKey, metadata.ResourceManagementEnabled)
+		}
+		// The declared fields are an implementation detail of the admission
+		// webhook, which would show up as an update of most objects.
+		core.RemoveAnnotations(obj, metadata.DeclaredFieldsKey)
+	}
+}
+
+// inventoryIDs returns the IDs of the objects in the ResourceGroup inventory of
+// the RootSync or RepoSync, or nothing if it was never synced.
+func inventoryIDs(ctx context.Context, c client.Client, scope declared.Scope, name string) ([]core.ID, error) {
+	rg := &unstructured.Unstructured{}
+	rg.SetGroupVersionKind(live.ResourceGroupGVK)
+	key := client.ObjectKey{Namespace: string(scope), Name: name}
+	if scope == declared.RootReconciler {
+		key.Namespace = configsync.ControllerNamespace
+	}
+	if err := c.Get(ctx, key, rg); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the ResourceGroup %s: %w", key, err)
+	}
+	objMetas, err := live.WrapInventoryObj(rg).Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the ResourceGroup %s: %w", key, err)
+	}
+	var ids []core.ID
+	for _, objMeta := range objMetas {
+		ids = append(ids, core.ID{
+			GroupKind: objMeta.GroupKind,
+			ObjectKey: client.ObjectKey{Namespace: objMeta.Namespace, Name: objMeta.Name},
+		})
+	}
+	return ids, nil
+}
+
+// liveObjects returns the live objects of the declared objects, and of the
+// inventory objects that are no longer declared.
+func liveObjects(ctx context.Context, c client.Client, declaredObjs map[core.ID]client.Object, inventory []core.ID) (map[core.ID]client.Object, map[core.ID]client.Object, error) {
+	actual := make(map[core.ID]client.Object)
+	for id, obj := range declaredObjs {
+		u, err := getObject(ctx, c, obj.GetObjectKind().GroupVersionKind(), id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if u != nil {
+			actual[id] = u
+		}
+	}
+
+	previous := make(map[core.ID]client.Object)
+	for _, id := range inventory {
+		if _, found := declaredObjs[id]; found {
+			continue
+		}
+		mapping, err := c.RESTMapper().RESTMapping(id.GroupKind)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				// The type was removed, so is the object.
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to map %v: %w", id.GroupKind, err)
+		}
+		u, err := getObject(ctx, c, mapping.GroupVersionKind, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if u != nil {
+			previous[id] = u
+		}
+	}
+	return actual, previous, nil
+}
+
+// getObject returns the live object, or nil if it does not exist.
+func getObject(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, id core.ID) (*unstructured.Unstructured, error) {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	if err := c.Get(ctx, id.ObjectKey, u); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %v: %w", id, err)
+	}
+	return u, nil
+}
+
+// computeChanges returns the changes that the next sync would make, sorted by
+// operation and ID.
+func computeChanges(scope declared.Scope, name string, declaredObjs, previous, actual map[core.ID]client.Object) ([]change, error) {
+	var changes []change
+	for _, d := range diff.ThreeWay(declaredObjs, previous, actual) {
+		c := change{operation: d.Operation(scope, name)}
+		if d.Declared != nil {
+			c.id = core.IDOf(d.Declared)
+		} else {
+			c.id = core.IDOf(d.Actual)
+		}
+		switch c.operation {
+		case diff.NoOp:
+			continue
+		case diff.Update:
+			fields, err := d.FieldDiffs()
+			if err != nil {
+				return nil, err
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			c.fields = fields
+		case diff.ManagementConflict:
+			c.manager = core.GetAnnotation(d.Actual, metadata.ResourceManagerKey)
+		}
+		changes = append(changes, c)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].operation != changes[j].operation {
+			return operationOrder[changes[i].operation] < operationOrder[changes[j].operation]
+		}
+		return changes[i].id.String() < changes[j].id.String()
+	})
+	return changes, nil
+}
+
+// printChanges prints one line per changed object, followed by the fields that
+// would be updated, and a summary.
+func printChanges(w io.Writer, changes []change) error {
+	counts := make(map[diff.Operation]int)
+	for _, c := range changes {
+		counts[c.operation]++
+		line := fmt.Sprintf("%-20s %v", strings.ToUpper(string(c.operation)), c.id)
+		if c.manager != "" {
+			line += fmt.Sprintf(" (managed by %s)", c.manager)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		for _, f := range c.fields {
+			if _, err := fmt.Fprintf(w, "    %s: %s -> %s\n", f.Path, formatValue(f.Actual), formatValue(f.Declared)); err != nil {
+				return err
+			}
+		}
+	}
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "No changes.")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d to create, %d to update, %d to delete, %d to abandon.\n",
+		counts[diff.Create], counts[diff.Update], counts[diff.Delete], counts[diff.Abandon])
+	return err
+}
+
+// formatValue formats the value of a field as JSON.
+func formatValue(value interface{}) string {
+	if value == nil {
+		return "<unset>"
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(b)
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/testing/fake"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func configMap(name string, data map[string]interface{}, opts ...core.MetaMutator) ast.FileObject {
+	u := fake.UnstructuredObject(kinds.ConfigMap(), append(opts, core.Namespace("bookstore"), core.Name(name))...)
+	u.Object["data"] = data
+	return fake.FileObject(u, name+".yaml")
+}
+
+func TestSyncScope(t *testing.T) {
+	testCases := []struct {
+		namespace string
+		name      string
+		wantScope declared.Scope
+		wantName  string
+	}{
+		{namespace: "config-management-system", wantScope: declared.RootReconciler, wantName: "root-sync"},
+		{namespace: "config-management-system", name: "my-rs", wantScope: declared.RootReconciler, wantName: "my-rs"},
+		{namespace: "bookstore", wantScope: "bookstore", wantName: "repo-sync"},
+		{namespace: "bookstore", name: "my-rs", wantScope: "bookstore", wantName: "my-rs"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.namespace+"/"+tc.name, func(t *testing.T) {
+			scope, name := syncScope(tc.namespace, tc.name)
+			if scope != tc.wantScope || name != tc.wantName {
+				t.Errorf("syncScope() = (%q, %q), want (%q, %q)", scope, name, tc.wantScope, tc.wantName)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	scope := declared.Scope("bookstore")
+	syncName := "repo-sync"
+
+	declaredFileObjs := []ast.FileObject{
+		configMap("created", map[string]interface{}{"color": "blue"}),
+		configMap("updated", map[string]interface{}{"color": "blue", "size": "large"}),
+		configMap("unchanged", map[string]interface{}{"color": "blue"}),
+		configMap("disabled", map[string]interface{}{"color": "blue"},
+			core.Annotation(metadata.ResourceManagementKey, metadata.ResourceManagementDisabled)),
+		configMap("conflict", map[string]interface{}{"color": "blue"}),
+	}
+	setManagementMetadata(declaredFileObjs, scope, syncName)
+	declaredObjs := make(map[core.ID]client.Object)
+	for _, obj := range declaredFileObjs {
+		declaredObjs[core.IDOf(obj)] = obj.Unstructured
+	}
+
+	// Live objects were synced by the same RepoSync, unless stated otherwise.
+	liveFileObjs := []ast.FileObject{
+		configMap("updated", map[string]interface{}{"color": "red"}),
+		configMap("unchanged", map[string]interface{}{"color": "blue"},
+			core.Annotation(metadata.SyncTokenAnnotationKey, "abc123")),
+		configMap("pruned", map[string]interface{}{"color": "blue"}),
+		configMap("abandoned", map[string]interface{}{"color": "blue"},
+			core.Annotation(common.LifecycleDeleteAnnotation, common.PreventDeletion)),
+		configMap("conflict", map[string]interface{}{"color": "blue"}),
+	}
+	setManagementMetadata(liveFileObjs, scope, syncName)
+	core.SetAnnotation(liveFileObjs[4], metadata.ResourceManagerKey, declared.ResourceManager(declared.RootReconciler, "root-sync"))
+	actual := make(map[core.ID]client.Object)
+	previous := make(map[core.ID]client.Object)
+	for _, obj := range liveFileObjs {
+		id := core.IDOf(obj)
+		if _, found := declaredObjs[id]; found {
+			actual[id] = obj.Unstructured
+		} else {
+			previous[id] = obj.Unstructured
+		}
+	}
+
+	changes, err := computeChanges(scope, syncName, declaredObjs, previous, actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := printChanges(&out, changes); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `CREATE               ConfigMap, bookstore/created
+UPDATE               ConfigMap, bookstore/updated
+    .data.color: "red" -> "blue"
+    .data.size: <unset> -> "large"
+DELETE               ConfigMap, bookstore/pruned
+ABANDON              ConfigMap, bookstore/abandoned
+MANAGEMENT-CONFLICT  ConfigMap, bookstore/conflict (managed by :root)
+
+1 to create, 1 to update, 1 to delete, 1 to abandon.
+`
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	var out bytes.Buffer
+	if err := printChanges(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "No changes.\n"; got != want {
+		t.Errorf("got output %q, want %q", got, want)
+	}
+}
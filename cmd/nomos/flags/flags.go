@@ -37,6 +37,12 @@ const (
 	// clusterFlag is the flag name for the Clusters below.
 	clustersFlag = "clusters"
 
+	// clusterLabelsFlag is the flag name for the ClusterLabels below.
+	clusterLabelsFlag = "cluster-labels"
+
+	// policyFileFlag is the flag name for the PolicyFile below.
+	policyFileFlag = "policy-file"
+
 	// SkipAPIServerFlag is the flag name for SkipAPIServer below.
 	SkipAPIServerFlag = "no-api-server-check"
 
@@ -61,6 +67,15 @@ var (
 	// Clusters contains the list of Cluster names (specified in clusters/) to perform an action on.
 	Clusters []string
 
+	// ClusterLabels contains the comma-separated key=value labels of the
+	// cluster used to evaluate the ClusterSelectors instead of those of the
+	// Cluster objects in the repo.
+	ClusterLabels string
+
+	// PolicyFile is the path of a file declaring the ConfigMap of the
+	// validation policies to evaluate instead of the one on the cluster.
+	PolicyFile string
+
 	// Path says where the Nomos directory is
 	Path string
 
@@ -92,6 +107,18 @@ func AddClusters(cmd *cobra.Command) {
 		`Accepts a comma-separated list of Cluster names to use in multi-cluster commands. Defaults to all clusters. Use "" for no clusters.`)
 }
 
+// AddClusterLabels adds the --cluster-labels flag.
+func AddClusterLabels(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&ClusterLabels, clusterLabelsFlag, "",
+		`Accepts a comma-separated list of key=value cluster labels to evaluate ClusterSelectors against, as the reconciler does with the labels of the live cluster. Defaults to the labels of the Cluster objects in the repository.`)
+}
+
+// AddPolicyFile adds the --policy-file flag.
+func AddPolicyFile(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&PolicyFile, policyFileFlag, "",
+		`Accepts the path of a file declaring the validation-policies ConfigMap, whose policies are evaluated along with the ones declared in the repository, as the reconciler does with the ConfigMap on the cluster. Defaults to the ConfigMap on the cluster unless --no-api-server-check is set.`)
+}
+
 // AddPath adds the --path flag.
 func AddPath(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&Path, pathFlag, PathDefault,
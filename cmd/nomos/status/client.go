@@ -274,7 +274,9 @@ func (c *ClusterClient) multiRepoClusterStatus(ctx context.Context, cs *ClusterS
 				// We always expect a ResourceGroup, even if we have no managed resources.
 				errs = append(errs, rgNotFoundErrMsg(rootSyncNsAndNames[i].Name, rootSyncNsAndNames[i].Namespace))
 			}
-			repos = append(repos, RootRepoStatus(rs, rg, syncingConditionSupported))
+			repo := RootRepoStatus(rs, rg, syncingConditionSupported)
+			repo.details = rootSyncDetails(rs)
+			repos = append(repos, repo)
 		}
 		sort.Slice(repos, func(i, j int) bool {
 			return repos[i].scope < repos[j].scope || (repos[i].scope == repos[j].scope && repos[i].syncName < repos[j].syncName)
@@ -304,7 +306,9 @@ func (c *ClusterClient) multiRepoClusterStatus(ctx context.Context, cs *ClusterS
 				// We always expect a ResourceGroup, even if we have no managed resources.
 				errs = append(errs, rgNotFoundErrMsg(repoSyncNsAndNames[i].Name, repoSyncNsAndNames[i].Namespace))
 			}
-			repos = append(repos, namespaceRepoStatus(rs, rg, syncingConditionSupported))
+			repo := namespaceRepoStatus(rs, rg, syncingConditionSupported)
+			repo.details = repoSyncDetails(rs)
+			repos = append(repos, repo)
 		}
 		sort.Slice(repos, func(i, j int) bool {
 			return repos[i].scope < repos[j].scope || (repos[i].scope == repos[j].scope && repos[i].syncName < repos[j].syncName)
@@ -348,7 +352,9 @@ func (c *ClusterClient) namespaceRepoClusterStatus(ctx context.Context, cs *Clus
 				// We always expect a ResourceGroup, even if we have no managed resources.
 				errs = append(errs, rgNotFoundErrMsg(nsAndNames[i].Name, nsAndNames[i].Namespace))
 			}
-			repos = append(repos, namespaceRepoStatus(rs, rg, syncingConditionSupported))
+			repo := namespaceRepoStatus(rs, rg, syncingConditionSupported)
+			repo.details = repoSyncDetails(rs)
+			repos = append(repos, repo)
 		}
 		sort.Slice(repos, func(i, j int) bool {
 			return repos[i].scope < repos[j].scope || (repos[i].scope == repos[j].scope && repos[i].syncName < repos[j].syncName)
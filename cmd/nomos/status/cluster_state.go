@@ -84,6 +84,12 @@ type RepoState struct {
 	// errorSummary summarizes the `errors` field.
 	errorSummary *v1beta1.ErrorSummary
 	resources    []resourceState
+	// suspended is the message of the Suspended condition, if the reconciler
+	// is suspended.
+	suspended string
+	// details holds the fields only printed in the structured output, or nil
+	// in the mono-repo mode.
+	details *syncDetails
 }
 
 func (r *RepoState) printRows(writer io.Writer) {
@@ -94,6 +100,10 @@ func (r *RepoState) printRows(writer io.Writer) {
 		fmt.Fprintf(writer, "%s%s\t%s\t\n", util.Indent, r.status, r.commit)
 	}
 
+	if r.suspended != "" {
+		fmt.Fprintf(writer, "%sSuspended:\t%s\t\n", util.Indent, r.suspended)
+	}
+
 	if r.errorSummary != nil && r.errorSummary.TotalCount > 0 {
 		if r.errorSummary.Truncated {
 			fmt.Fprintf(writer, "%sTotalErrorCount: %d, ErrorTruncated: %v, ErrorCountAfterTruncation: %d\n", util.Indent,
@@ -200,7 +210,7 @@ func monoRepoStatus(git *v1beta1.Git, status v1.RepoStatus) *RepoState {
 	totalErrorCount := len(errors)
 
 	result := &RepoState{
-		scope:  "<root>",
+		scope:  rootScope,
 		git:    git,
 		status: getSyncStatus(status),
 		commit: commitHash(status.Sync.LatestToken),
@@ -315,6 +325,9 @@ func namespaceRepoStatus(rs *v1beta1.RepoSync, rg *unstructured.Unstructured, sy
 	stalledCondition := reposync.GetCondition(rs.Status.Conditions, v1beta1.RepoSyncStalled)
 	reconcilingCondition := reposync.GetCondition(rs.Status.Conditions, v1beta1.RepoSyncReconciling)
 	syncingCondition := reposync.GetCondition(rs.Status.Conditions, v1beta1.RepoSyncSyncing)
+	if suspendedCondition := reposync.GetCondition(rs.Status.Conditions, v1beta1.RepoSyncSuspended); suspendedCondition != nil && suspendedCondition.Status == metav1.ConditionTrue {
+		repostate.suspended = suspendedCondition.Message
+	}
 	switch {
 	case stalledCondition != nil && stalledCondition.Status == metav1.ConditionTrue:
 		repostate.status = stalledMsg
@@ -405,7 +418,7 @@ func namespaceRepoStatus(rs *v1beta1.RepoSync, rg *unstructured.Unstructured, sy
 // RootRepoStatus converts the given RootSync into a RepoState.
 func RootRepoStatus(rs *v1beta1.RootSync, rg *unstructured.Unstructured, syncingConditionSupported bool) *RepoState {
 	repostate := &RepoState{
-		scope:      "<root>",
+		scope:      rootScope,
 		syncName:   rs.Name,
 		sourceType: v1beta1.SourceType(rs.Spec.SourceType),
 		git:        rs.Spec.Git,
@@ -416,6 +429,9 @@ func RootRepoStatus(rs *v1beta1.RootSync, rg *unstructured.Unstructured, syncing
 	stalledCondition := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncStalled)
 	reconcilingCondition := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncReconciling)
 	syncingCondition := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncSyncing)
+	if suspendedCondition := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncSuspended); suspendedCondition != nil && suspendedCondition.Status == metav1.ConditionTrue {
+		repostate.suspended = suspendedCondition.Message
+	}
 	switch {
 	case stalledCondition != nil && stalledCondition.Status == metav1.ConditionTrue:
 		repostate.status = stalledMsg
@@ -551,26 +567,29 @@ func rootSyncErrors(rs *v1beta1.RootSync) []string {
 
 func multiRepoSyncStatusErrors(status v1beta1.Status) []string {
 	var errs []string
-	for _, err := range status.Rendering.Errors {
-		errs = append(errs, err.ErrorMessage)
-	}
-	for _, err := range status.Source.Errors {
-		errs = append(errs, err.ErrorMessage)
-	}
-	for _, err := range status.Sync.Errors {
-		errs = append(errs, err.ErrorMessage)
-	}
+	errs = append(errs, toErrorMessage(status.Rendering.Errors)...)
+	errs = append(errs, toErrorMessage(status.Source.Errors)...)
+	errs = append(errs, toErrorMessage(status.Sync.Errors)...)
 	return errs
 }
 
 func toErrorMessage(errs []v1beta1.ConfigSyncError) []string {
 	var msg []string
 	for _, err := range errs {
-		msg = append(msg, err.ErrorMessage)
+		msg = append(msg, errorMessage(err))
 	}
 	return msg
 }
 
+// errorMessage returns the message of the error, followed by the hint from
+// the error catalog if the reconciler reported one.
+func errorMessage(err v1beta1.ConfigSyncError) string {
+	if err.Hint == "" {
+		return err.ErrorMessage
+	}
+	return fmt.Sprintf("%s\nHint: %s", err.ErrorMessage, err.Hint)
+}
+
 // GetCommit returns RepoState's commit
 func (r *RepoState) GetCommit() string { return r.commit }
 
@@ -334,6 +334,21 @@ func TestRepoState_PrintRows(t *testing.T) {
 			},
 			"  bookstore:repo-sync\tN/A\t\n  ERROR\t\t\n  TotalErrorCount: 1\n  Error:\tmissing OCI config\t\n",
 		},
+		{
+			"suspended",
+			&RepoState{
+				scope:    "<root>",
+				syncName: "root-sync",
+				git: &v1beta1.Git{
+					Repo: "https://github.com/tester/sample/",
+				},
+				status:            "SYNCED",
+				lastSyncTimestamp: lastSyncTimestamp,
+				commit:            "abc123",
+				suspended:         "Syncing is suspended outside of the allow sync windows",
+			},
+			fmt.Sprintf("  <root>:root-sync\thttps://github.com/tester/sample@master\t\n  SYNCED @ %v\tabc123\t\n  Suspended:\tSyncing is suspended outside of the allow sync windows\t\n", lastSyncTimestamp),
+		},
 		{
 			"Helm field is missing when sourceType is helm",
 			&RepoState{
@@ -2781,6 +2796,29 @@ func TestRepoState_RootRepoStatus(t *testing.T) {
 				errorSummary: errorSummayWithTwoErrors,
 			},
 		},
+		{
+			name:                      "sync error with a hint",
+			gitSpec:                   git,
+			syncingConditionSupported: true,
+			conditions: []v1beta1.RootSyncCondition{
+				reconciledCondition,
+				syncingFalseCondition("abc123", []v1beta1.ErrorSource{v1beta1.SyncError}, errorSummayWithOneError),
+			},
+			syncStatus: v1beta1.SyncStatus{
+				Git:    toGitStatus(git),
+				Commit: "abc123",
+				Errors: []v1beta1.ConfigSyncError{{ErrorMessage: "KNV2013: forbidden", Hint: "Grant the permissions."}},
+			},
+			want: &RepoState{
+				scope:        "<root>",
+				syncName:     "root-sync",
+				git:          git,
+				status:       util.ErrorMsg,
+				commit:       "abc123",
+				errors:       []string{"KNV2013: forbidden\nHint: Grant the permissions."},
+				errorSummary: errorSummayWithOneError,
+			},
+		},
 		{
 			name:       "[accurate status before syncing condition is supported] repo is synced",
 			gitSpec:    git,
@@ -2839,6 +2877,35 @@ func TestRepoState_RootRepoStatus(t *testing.T) {
 				commit:            "abc123",
 			},
 		},
+		{
+			name:                      "repo is synced but suspended",
+			gitSpec:                   git,
+			syncingConditionSupported: true,
+			conditions: []v1beta1.RootSyncCondition{
+				reconciledCondition,
+				syncingFalseCondition("abc123", nil, &v1beta1.ErrorSummary{}),
+				{
+					Type:    v1beta1.RootSyncSuspended,
+					Status:  metav1.ConditionTrue,
+					Reason:  "Suspend",
+					Message: "Syncing is suspended by spec.suspend",
+				},
+			},
+			syncStatus: v1beta1.SyncStatus{
+				Git:        toGitStatus(git),
+				Commit:     "abc123",
+				LastUpdate: lastSyncTimestamp,
+			},
+			want: &RepoState{
+				scope:             "<root>",
+				syncName:          "root-sync",
+				git:               git,
+				status:            syncedMsg,
+				lastSyncTimestamp: lastSyncTimestamp,
+				commit:            "abc123",
+				suspended:         "Syncing is suspended by spec.suspend",
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,281 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kpt.dev/configsync/cmd/nomos/flags"
+	"kpt.dev/configsync/cmd/nomos/util"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/reposync"
+	"kpt.dev/configsync/pkg/rootsync"
+	"sigs.k8s.io/yaml"
+)
+
+// Output is the machine-readable status of all clusters, printed by
+// `nomos status --format json|yaml`. Fields are only ever added to it, so that
+// scripts can rely on its schema.
+type Output struct {
+	// Clusters lists the status of each cluster, sorted by name.
+	Clusters []ClusterOutput `json:"clusters"`
+}
+
+// ClusterOutput is the status of a cluster.
+type ClusterOutput struct {
+	// Name is the name of the kubeconfig context of the cluster.
+	Name string `json:"name"`
+	// Current is true for the current kubeconfig context.
+	Current bool `json:"current,omitempty"`
+	// Status is set if the status of the cluster itself is not healthy, e.g.
+	// ERROR or NOT INSTALLED.
+	Status string `json:"status,omitempty"`
+	// Error describes why the status of the cluster is not healthy.
+	Error string `json:"error,omitempty"`
+	// Syncs lists the status of each RootSync and RepoSync on the cluster.
+	Syncs []SyncOutput `json:"syncs,omitempty"`
+}
+
+// SyncOutput is the status of a RootSync or RepoSync, or of the Repo of a
+// cluster in the mono-repo mode.
+type SyncOutput struct {
+	// Kind is RootSync or RepoSync, and empty in the mono-repo mode.
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// SourceType is git, oci, or helm.
+	SourceType string `json:"sourceType,omitempty"`
+	// Source is the repository, image or chart, as printed in the text output.
+	Source string `json:"source"`
+	// Status is SYNCED, PENDING, RECONCILING, STALLED, or ERROR.
+	Status string `json:"status"`
+	// Commit is the commit of the Syncing condition, i.e. the commit being
+	// synced, or the last synced one.
+	Commit           string `json:"commit,omitempty"`
+	SourceCommit     string `json:"sourceCommit,omitempty"`
+	RenderingCommit  string `json:"renderingCommit,omitempty"`
+	SyncCommit       string `json:"syncCommit,omitempty"`
+	LastSyncedCommit string `json:"lastSyncedCommit,omitempty"`
+	// LastSyncTime is when the commit was synced, if the status is SYNCED.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// Conditions lists the conditions of the RootSync or RepoSync.
+	Conditions   []SyncCondition           `json:"conditions,omitempty"`
+	ErrorSummary *v1beta1.ErrorSummary     `json:"errorSummary,omitempty"`
+	Errors       []v1beta1.ConfigSyncError `json:"errors,omitempty"`
+	// Resources lists the status of the managed resources, unless
+	// --resources=false.
+	Resources []resourceState `json:"resources,omitempty"`
+}
+
+// SyncCondition is a condition of a RootSync or RepoSync.
+type SyncCondition struct {
+	Type           string      `json:"type"`
+	Status         string      `json:"status"`
+	Reason         string      `json:"reason,omitempty"`
+	Message        string      `json:"message,omitempty"`
+	Commit         string      `json:"commit,omitempty"`
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// syncDetails holds the fields of a RootSync or RepoSync that are only
+// printed in the structured output.
+type syncDetails struct {
+	kind       string
+	namespace  string
+	status     v1beta1.Status
+	conditions []SyncCondition
+	errors     []v1beta1.ConfigSyncError
+}
+
+// rootSyncDetails returns the details of the given RootSync. The errors are
+// the ones reported by the Stalled or Syncing condition.
+func rootSyncDetails(rs *v1beta1.RootSync) *syncDetails {
+	d := &syncDetails{
+		kind:      configsync.RootSyncKind,
+		namespace: rs.Namespace,
+		status:    rs.Status.Status,
+	}
+	for _, c := range rs.Status.Conditions {
+		d.conditions = append(d.conditions, SyncCondition{
+			Type:           string(c.Type),
+			Status:         string(c.Status),
+			Reason:         c.Reason,
+			Message:        c.Message,
+			Commit:         c.Commit,
+			LastUpdateTime: c.LastUpdateTime,
+		})
+	}
+	stalledCondition := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncStalled)
+	syncingCondition := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncSyncing)
+	switch {
+	case stalledCondition != nil && stalledCondition.Status == metav1.ConditionTrue:
+		d.errors = stalledErrors(stalledCondition.Message, stalledCondition.Errors)
+	case syncingCondition == nil:
+		d.errors = rootsync.Errors(rs, allErrorSources)
+	case syncingCondition.ErrorSummary != nil:
+		d.errors = rootsync.Errors(rs, syncingCondition.ErrorSourceRefs)
+	default:
+		d.errors = syncingCondition.Errors
+	}
+	return d
+}
+
+// repoSyncDetails returns the details of the given RepoSync. The errors are
+// the ones reported by the Stalled or Syncing condition.
+func repoSyncDetails(rs *v1beta1.RepoSync) *syncDetails {
+	d := &syncDetails{
+		kind:      configsync.RepoSyncKind,
+		namespace: rs.Namespace,
+		status:    rs.Status.Status,
+	}
+	for _, c := range rs.Status.Conditions {
+		d.conditions = append(d.conditions, SyncCondition{
+			Type:           string(c.Type),
+			Status:         string(c.Status),
+			Reason:         c.Reason,
+			Message:        c.Message,
+			Commit:         c.Commit,
+			LastUpdateTime: c.LastUpdateTime,
+		})
+	}
+	stalledCondition := reposync.GetCondition(rs.Status.Conditions, v1beta1.RepoSyncStalled)
+	syncingCondition := reposync.GetCondition(rs.Status.Conditions, v1beta1.RepoSyncSyncing)
+	switch {
+	case stalledCondition != nil && stalledCondition.Status == metav1.ConditionTrue:
+		d.errors = stalledErrors(stalledCondition.Message, stalledCondition.Errors)
+	case syncingCondition == nil:
+		d.errors = reposync.Errors(rs, allErrorSources)
+	case syncingCondition.ErrorSummary != nil:
+		d.errors = reposync.Errors(rs, syncingCondition.ErrorSourceRefs)
+	default:
+		d.errors = syncingCondition.Errors
+	}
+	return d
+}
+
+var allErrorSources = []v1beta1.ErrorSource{v1beta1.SourceError, v1beta1.RenderingError, v1beta1.SyncError}
+
+// stalledErrors returns the errors of a Stalled condition, which usually only
+// has a message.
+func stalledErrors(message string, errs []v1beta1.ConfigSyncError) []v1beta1.ConfigSyncError {
+	if len(errs) > 0 {
+		return errs
+	}
+	return []v1beta1.ConfigSyncError{{ErrorMessage: message}}
+}
+
+// output returns the structured status of the cluster.
+func (c *ClusterState) output(current bool) ClusterOutput {
+	result := ClusterOutput{
+		Name:    c.Ref,
+		Current: current,
+		Status:  c.status,
+		Error:   c.Error,
+	}
+	for _, repo := range c.repos {
+		result.Syncs = append(result.Syncs, repo.output())
+	}
+	return result
+}
+
+// output returns the structured status of the repo.
+func (r *RepoState) output() SyncOutput {
+	result := SyncOutput{
+		Name:         r.syncName,
+		SourceType:   string(r.sourceType),
+		Source:       sourceString(r.sourceType, r.git, r.oci, r.helm),
+		Status:       r.status,
+		ErrorSummary: r.errorSummary,
+	}
+	if r.commit != emptyCommit {
+		result.Commit = r.commit
+	}
+	if r.status == syncedMsg && !r.lastSyncTimestamp.IsZero() {
+		lastSyncTime := r.lastSyncTimestamp
+		result.LastSyncTime = &lastSyncTime
+	}
+	if resourceStatus {
+		result.Resources = r.resources
+	}
+	if r.details == nil {
+		// Only the error messages are known in the mono-repo mode.
+		for _, err := range r.errors {
+			result.Errors = append(result.Errors, v1beta1.ConfigSyncError{ErrorMessage: err})
+		}
+		return result
+	}
+	result.Kind = r.details.kind
+	result.Namespace = r.details.namespace
+	result.SourceCommit = r.details.status.Source.Commit
+	result.RenderingCommit = r.details.status.Rendering.Commit
+	result.SyncCommit = r.details.status.Sync.Commit
+	result.LastSyncedCommit = r.details.status.LastSyncedCommit
+	result.Conditions = r.details.conditions
+	result.Errors = r.details.errors
+	return result
+}
+
+// printOutput prints the structured status of the clusters in the given
+// format.
+func printOutput(out io.Writer, format string, stateMap map[string]*ClusterState, names []string, currentContext string) error {
+	output := Output{Clusters: []ClusterOutput{}}
+	for _, name := range names {
+		output.Clusters = append(output.Clusters, stateMap[name].output(name == currentContext))
+	}
+	var data []byte
+	var err error
+	switch format {
+	case flags.OutputJSON:
+		data, err = json.MarshalIndent(output, "", "  ")
+		data = append(data, '\n')
+	case flags.OutputYAML:
+		data, err = yaml.Marshal(output)
+	default:
+		return fmt.Errorf("unsupported output format %q: must be one of %s or %s", format, flags.OutputJSON, flags.OutputYAML)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal the status: %w", err)
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+// statusErrors returns an error listing the clusters that could not be
+// reached or are not healthy, and the syncs that are stalled or have errors.
+// It returns nil if there are none.
+func statusErrors(stateMap map[string]*ClusterState, names []string) error {
+	var errs []string
+	for _, name := range names {
+		state := stateMap[name]
+		if state.Error != "" {
+			errs = append(errs, fmt.Sprintf("%s: %s", name, state.Error))
+		}
+		for _, repo := range state.repos {
+			hasErrors := repo.errorSummary != nil && repo.errorSummary.TotalCount > 0
+			if repo.status == util.ErrorMsg || repo.status == stalledMsg || hasErrors {
+				errs = append(errs, fmt.Sprintf("%s: %s:%s is %s", name, repo.scope, repo.syncName, repo.status))
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("found errors in %d cluster(s) or sync(s):\n%s", len(errs), strings.Join(errs, "\n"))
+}
@@ -0,0 +1,194 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kpt.dev/configsync/cmd/nomos/flags"
+	"kpt.dev/configsync/cmd/nomos/util"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+func TestRepoState_Output(t *testing.T) {
+	syncErr := v1beta1.ConfigSyncError{
+		Code:         "1021",
+		ErrorMessage: "unknown kind",
+		Resources: []v1beta1.ResourceRef{{
+			SourcePath: "admin/anvil.yaml",
+			Name:       "heavy",
+			GVK:        metav1.GroupVersionKind{Group: "acme.com", Version: "v1", Kind: "Anvil"},
+		}},
+	}
+
+	rs := fake.RootSyncObjectV1Beta1(configsync.RootSyncName)
+	rs.Spec.SourceType = string(v1beta1.GitSource)
+	rs.Spec.Git = git
+	rs.Status.Source.Commit = "abcdef123456"
+	rs.Status.Rendering.Commit = "abcdef123456"
+	rs.Status.Sync.Commit = "abcdef123456"
+	rs.Status.Sync.Errors = []v1beta1.ConfigSyncError{syncErr}
+	rs.Status.LastSyncedCommit = "012345abcdef"
+	rs.Status.Conditions = []v1beta1.RootSyncCondition{{
+		Type:            v1beta1.RootSyncSyncing,
+		Status:          metav1.ConditionFalse,
+		Reason:          "Sync",
+		Message:         "Sync Completed",
+		Commit:          "abcdef123456",
+		ErrorSourceRefs: []v1beta1.ErrorSource{v1beta1.SyncError},
+		ErrorSummary:    errorSummayWithOneError,
+	}}
+
+	repo := RootRepoStatus(rs, nil, true)
+	repo.details = rootSyncDetails(rs)
+
+	want := SyncOutput{
+		Kind:             configsync.RootSyncKind,
+		Namespace:        configsync.ControllerNamespace,
+		Name:             configsync.RootSyncName,
+		SourceType:       string(v1beta1.GitSource),
+		Source:           "git@github.com:tester/sample/admin@v1",
+		Status:           util.ErrorMsg,
+		Commit:           "abcdef123456",
+		SourceCommit:     "abcdef123456",
+		RenderingCommit:  "abcdef123456",
+		SyncCommit:       "abcdef123456",
+		LastSyncedCommit: "012345abcdef",
+		Conditions: []SyncCondition{{
+			Type:    string(v1beta1.RootSyncSyncing),
+			Status:  string(metav1.ConditionFalse),
+			Reason:  "Sync",
+			Message: "Sync Completed",
+			Commit:  "abcdef123456",
+		}},
+		ErrorSummary: errorSummayWithOneError,
+		Errors:       []v1beta1.ConfigSyncError{syncErr},
+	}
+	if diff := cmp.Diff(want, repo.output()); diff != "" {
+		t.Errorf("output() got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestPrintOutput(t *testing.T) {
+	stateMap := map[string]*ClusterState{
+		"abc": {
+			Ref: "abc",
+			repos: []*RepoState{{
+				scope:      "bookinfo",
+				syncName:   configsync.RepoSyncName,
+				sourceType: v1beta1.GitSource,
+				git:        git,
+				status:     syncedMsg,
+				commit:     "abcdef",
+				details: &syncDetails{
+					kind:      configsync.RepoSyncKind,
+					namespace: "bookinfo",
+				},
+			}},
+		},
+		"def": unavailableCluster("def"),
+	}
+	want := `{
+  "clusters": [
+    {
+      "name": "abc",
+      "current": true,
+      "syncs": [
+        {
+          "kind": "RepoSync",
+          "namespace": "bookinfo",
+          "name": "repo-sync",
+          "sourceType": "git",
+          "source": "git@github.com:tester/sample/admin@v1",
+          "status": "SYNCED",
+          "commit": "abcdef"
+        }
+      ]
+    },
+    {
+      "name": "def",
+      "status": "N/A",
+      "error": "Failed to connect to cluster"
+    }
+  ]
+}
+`
+
+	var buffer bytes.Buffer
+	if err := printOutput(&buffer, flags.OutputJSON, stateMap, []string{"abc", "def"}, "abc"); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, buffer.String()); diff != "" {
+		t.Errorf("printOutput() got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestStatusErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		stateMap map[string]*ClusterState
+		wantErr  bool
+	}{
+		{
+			name: "all synced",
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{{scope: "<root>", syncName: "root-sync", status: syncedMsg}}},
+			},
+		},
+		{
+			name: "pending sync",
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{{scope: "<root>", syncName: "root-sync", status: pendingMsg}}},
+			},
+		},
+		{
+			name: "pending sync with errors",
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{{scope: "<root>", syncName: "root-sync", status: pendingMsg, errorSummary: errorSummayWithOneError}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "stalled sync",
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{{scope: "<root>", syncName: "root-sync", status: stalledMsg}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unavailable cluster",
+			stateMap: map[string]*ClusterState{
+				"abc": unavailableCluster("abc"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := statusErrors(tc.stateMap, []string{"abc"})
+			if tc.wantErr && err == nil {
+				t.Error("statusErrors() got nil, want an error")
+			} else if !tc.wantErr && err != nil {
+				t.Errorf("statusErrors() got error %v, want nil", err)
+			}
+		})
+	}
+}
@@ -39,12 +39,20 @@ const (
 	syncedMsg      = "SYNCED"
 	stalledMsg     = "STALLED"
 	reconcilingMsg = "RECONCILING"
+
+	// rootScope is the scope of the RootSyncs.
+	rootScope = "<root>"
 )
 
 var (
 	pollingInterval time.Duration
 	namespace       string
 	resourceStatus  bool
+	format          string
+	failOnErrors    bool
+	waitCommit      string
+	waitSelector    []string
+	waitTimeout     time.Duration
 )
 
 func init() {
@@ -53,6 +61,14 @@ func init() {
 	Cmd.Flags().DurationVar(&pollingInterval, "poll", 0*time.Second, "Polling interval (leave unset to run once)")
 	Cmd.Flags().StringVar(&namespace, "namespace", "", "Namespace repo to get status for (multi-repo only, leave unset to get all repos)")
 	Cmd.Flags().BoolVar(&resourceStatus, "resources", true, "show resource level status for Namespace repo (multi-repo only)")
+	Cmd.Flags().StringVar(&format, "format", "", "Output format. Accepts 'json' and 'yaml'. Defaults to tab-aligned text.")
+	Cmd.Flags().BoolVar(&failOnErrors, "fail-on-errors", false,
+		"Exit with a non-zero code if any cluster can't be reached or isn't healthy, or if any sync is stalled or has errors. Can't be used with --poll.")
+	Cmd.Flags().StringVar(&waitCommit, "wait-for-commit", "",
+		"Wait until all the RootSyncs and RepoSyncs on the clusters have synced this commit without errors and all their resources are Current. Can't be used with --poll or --format.")
+	Cmd.Flags().StringSliceVar(&waitSelector, "wait-selector", nil,
+		"Only wait for the syncs matching these name=<name>, kind=<RootSync|RepoSync> or repo=<repository> terms with --wait-for-commit. A sync must match one of the values given for each key. The repository is the Git repository, OCI image or Helm repository of the sync.")
+	Cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 10*time.Minute, "Timeout for --wait-for-commit")
 }
 
 // SaveToTempFile writes the `nomos status` output into a temporary file, and
@@ -94,10 +110,34 @@ var Cmd = &cobra.Command{
 	// TODO: make Configuration Management a constant (for product renaming)
 	Short: `Prints the status of all clusters with Configuration Management installed.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		switch format {
+		case "", flags.OutputJSON, flags.OutputYAML:
+		default:
+			return fmt.Errorf("unsupported output format %q: must be one of %s or %s", format, flags.OutputJSON, flags.OutputYAML)
+		}
+		if failOnErrors && pollingInterval > 0 {
+			return errors.New("--fail-on-errors can't be used with --poll")
+		}
+		if waitCommit != "" && (pollingInterval > 0 || format != "") {
+			return errors.New("--wait-for-commit can't be used with --poll or --format")
+		}
+		if len(waitSelector) > 0 && waitCommit == "" {
+			return errors.New("--wait-selector can only be used with --wait-for-commit")
+		}
+		selector, err := parseSyncSelector(waitSelector)
+		if err != nil {
+			return err
+		}
+
 		// Don't show usage on error, as argument validation passed.
 		cmd.SilenceUsage = true
 
-		fmt.Println("Connecting to clusters...")
+		if format == "" {
+			fmt.Println("Connecting to clusters...")
+		} else {
+			// Keep stdout parsable.
+			fmt.Fprintln(os.Stderr, "Connecting to clusters...")
+		}
 
 		clientMap, err := ClusterClients(cmd.Context(), flags.Contexts)
 		if err != nil {
@@ -115,6 +155,32 @@ var Cmd = &cobra.Command{
 		// Use a sorted order of names to avoid shuffling in the output.
 		names := clusterNames(clientMap)
 
+		if waitCommit != "" {
+			return waitForCommit(cmd.Context(), os.Stdout, waitCommit, selector, clientMap, names, waitTimeout)
+		}
+
+		if format != "" {
+			if pollingInterval > 0 {
+				for {
+					if format == flags.OutputYAML {
+						fmt.Println("---")
+					}
+					if _, err := printStructuredStatus(cmd.Context(), os.Stdout, format, clientMap, names); err != nil {
+						return err
+					}
+					time.Sleep(pollingInterval)
+				}
+			}
+			stateMap, err := printStructuredStatus(cmd.Context(), os.Stdout, format, clientMap, names)
+			if err != nil {
+				return err
+			}
+			if failOnErrors {
+				return statusErrors(stateMap, names)
+			}
+			return nil
+		}
+
 		writer := util.NewWriter(os.Stdout)
 		if pollingInterval > 0 {
 			for {
@@ -122,7 +188,10 @@ var Cmd = &cobra.Command{
 				time.Sleep(pollingInterval)
 			}
 		} else {
-			printStatus(cmd.Context(), writer, clientMap, names)
+			stateMap := printStatus(cmd.Context(), writer, clientMap, names)
+			if failOnErrors {
+				return statusErrors(stateMap, names)
+			}
 		}
 		return nil
 	},
@@ -160,8 +229,9 @@ func clusterStates(ctx context.Context, clientMap map[string]*ClusterClient) (ma
 // printStatus fetches ConfigManagementStatus and/or RepoStatus from each cluster in the given map
 // and then prints a formatted status row for each one. If there are any errors reported by either
 // object, those are printed in a second table under the status table.
+// It returns the states of the clusters.
 // nolint:errcheck
-func printStatus(ctx context.Context, writer *tabwriter.Writer, clientMap map[string]*ClusterClient, names []string) {
+func printStatus(ctx context.Context, writer *tabwriter.Writer, clientMap map[string]*ClusterClient, names []string) map[string]*ClusterState {
 	// First build up a map of all the states to display.
 	stateMap, monoRepoClusters := clusterStates(ctx, clientMap)
 
@@ -192,6 +262,17 @@ func printStatus(ctx context.Context, writer *tabwriter.Writer, clientMap map[st
 	}
 
 	writer.Flush()
+	return stateMap
+}
+
+// printStructuredStatus fetches the status of each cluster in the given map,
+// and prints it in the given structured format. It returns the states of the
+// clusters.
+func printStructuredStatus(ctx context.Context, out io.Writer, format string, clientMap map[string]*ClusterClient, names []string) (map[string]*ClusterState, error) {
+	stateMap, _ := clusterStates(ctx, clientMap)
+	// The current context is only informative, so ignore errors.
+	currentContext, _ := restconfig.CurrentContextName()
+	return stateMap, printOutput(out, format, stateMap, names, currentContext)
 }
 
 // clearTerminal executes an OS-specific command to clear all output on the terminal.
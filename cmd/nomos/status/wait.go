@@ -0,0 +1,243 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"kpt.dev/configsync/cmd/nomos/util"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+)
+
+const (
+	// waitInterval is how often the syncs are checked while waiting for a
+	// commit.
+	waitInterval = 5 * time.Second
+	// currentResourceStatus is the status of a managed resource that has been
+	// reconciled successfully.
+	currentResourceStatus = "Current"
+)
+
+// The keys of the terms of a syncSelector.
+const (
+	selectorName = "name"
+	selectorKind = "kind"
+	selectorRepo = "repo"
+)
+
+// syncSelector selects the syncs to wait for. A sync is selected if it matches
+// one of the values of each key. An empty syncSelector selects all the syncs.
+type syncSelector map[string][]string
+
+// parseSyncSelector parses a list of key=value terms, where the key is one of
+// name, kind or repo.
+func parseSyncSelector(terms []string) (syncSelector, error) {
+	s := syncSelector{}
+	for _, term := range terms {
+		kv := strings.SplitN(term, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, fmt.Errorf("invalid sync selector %q: must be of the form key=value", term)
+		}
+		switch kv[0] {
+		case selectorName, selectorRepo:
+		case selectorKind:
+			if kv[1] != configsync.RootSyncKind && kv[1] != configsync.RepoSyncKind {
+				return nil, fmt.Errorf("invalid sync selector %q: kind must be one of %s or %s", term, configsync.RootSyncKind, configsync.RepoSyncKind)
+			}
+		default:
+			return nil, fmt.Errorf("invalid sync selector %q: key must be one of %s, %s or %s", term, selectorName, selectorKind, selectorRepo)
+		}
+		s[kv[0]] = append(s[kv[0]], kv[1])
+	}
+	return s, nil
+}
+
+// matches returns true if the selector selects the sync.
+func (s syncSelector) matches(repo *RepoState) bool {
+	kind := configsync.RepoSyncKind
+	if repo.scope == rootScope {
+		kind = configsync.RootSyncKind
+	}
+	fields := map[string]string{
+		selectorName: repo.syncName,
+		selectorKind: kind,
+		selectorRepo: sourceRepo(repo),
+	}
+	for key, values := range s {
+		matched := false
+		for _, v := range values {
+			if fields[key] == v {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// sourceRepo returns the repository the sync pulls from: the Git repository,
+// the OCI image or the Helm repository.
+func sourceRepo(repo *RepoState) string {
+	switch {
+	case repo.sourceType == v1beta1.OciSource && repo.oci != nil:
+		return repo.oci.Image
+	case repo.sourceType == v1beta1.HelmSource && repo.helm != nil:
+		return repo.helm.Repo
+	case repo.git != nil:
+		return repo.git.Repo
+	}
+	return ""
+}
+
+// commitProgress is the progress of the syncs on a set of clusters towards a
+// commit.
+type commitProgress struct {
+	// selected is the number of syncs selected on the clusters which reported
+	// their syncs.
+	selected int
+	// synced is the number of syncs that synced the commit.
+	synced int
+	// pending lists the syncs that have not synced the commit yet.
+	pending []string
+	// failed lists the clusters and syncs that can not sync the commit.
+	failed []string
+}
+
+// checkCommit returns the progress of the syncs selected on the given clusters
+// towards the given commit. A sync has synced the commit once
+// status.sync.commit matches it, there are no errors, and all the managed
+// resources are Current.
+func checkCommit(commit string, selector syncSelector, stateMap map[string]*ClusterState, names []string) commitProgress {
+	var p commitProgress
+	for _, name := range names {
+		state := stateMap[name]
+		if state.isMulti != nil && !*state.isMulti {
+			p.failed = append(p.failed, fmt.Sprintf("%s: waiting for a commit is only supported in the multi-repo mode", name))
+			continue
+		}
+		if state.Error != "" {
+			p.pending = append(p.pending, fmt.Sprintf("%s: %s", name, state.Error))
+			continue
+		}
+		for _, repo := range state.repos {
+			if !selector.matches(repo) {
+				continue
+			}
+			p.selected++
+			ref := fmt.Sprintf("%s: %s:%s", name, repo.scope, repo.syncName)
+			syncCommit := ""
+			if repo.details != nil {
+				syncCommit = repo.details.status.Sync.Commit
+			}
+			switch {
+			case repo.status == stalledMsg:
+				p.failed = append(p.failed, fmt.Sprintf("%s is %s: %s", ref, repo.status, strings.Join(repo.errors, ", ")))
+			case !commitMatches(syncCommit, commit):
+				p.pending = append(p.pending, fmt.Sprintf("%s is %s at commit %s", ref, repo.status, commitHash(syncCommit)))
+			case repo.status == util.ErrorMsg:
+				p.failed = append(p.failed, fmt.Sprintf("%s has errors: %s", ref, strings.Join(repo.errors, ", ")))
+			case repo.status != syncedMsg:
+				p.pending = append(p.pending, fmt.Sprintf("%s is %s", ref, repo.status))
+			default:
+				if notCurrent := notCurrentResources(repo.resources); len(notCurrent) > 0 {
+					p.pending = append(p.pending, fmt.Sprintf("%s has %d resource(s) that are not Current: %s", ref, len(notCurrent), strings.Join(notCurrent, ", ")))
+				} else {
+					p.synced++
+				}
+			}
+		}
+	}
+	return p
+}
+
+// commitMatches returns true if got is the commit want, which may be
+// abbreviated.
+func commitMatches(got, want string) bool {
+	return got != "" && strings.HasPrefix(got, want)
+}
+
+// notCurrentResources lists the resources which are not Current, along with
+// their status.
+func notCurrentResources(resources []resourceState) []string {
+	var result []string
+	for _, r := range resources {
+		if r.Status != currentResourceStatus {
+			result = append(result, fmt.Sprintf("%s (%s)", r.String(), r.Status))
+		}
+	}
+	return result
+}
+
+// waitForCommit blocks until all the selected syncs on the given clusters have
+// synced the given commit. It returns an error listing the syncs that are not
+// synced if any sync fails to sync the commit, or if the timeout expires first.
+// It also returns an error if the clusters have no sync matching the selector.
+func waitForCommit(ctx context.Context, out io.Writer, commit string, selector syncSelector, clientMap map[string]*ClusterClient, names []string, timeout time.Duration) error {
+	var unreachable []string
+	for _, name := range names {
+		// The client of an unreachable cluster is never recreated, so fail fast.
+		if clientMap[name] == nil {
+			unreachable = append(unreachable, name)
+		}
+	}
+	if len(unreachable) > 0 {
+		return fmt.Errorf("failed to connect to cluster(s): %s", strings.Join(unreachable, ", "))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var pending []string
+	for {
+		stateMap, _ := clusterStates(ctx, clientMap)
+		if ctx.Err() != nil {
+			// The states may be incomplete, so keep the last known progress.
+			break
+		}
+		p := checkCommit(commit, selector, stateMap, names)
+		if len(p.failed) > 0 {
+			return fmt.Errorf("failed to sync commit %q in %d cluster(s) or sync(s):\n%s", commit, len(p.failed), strings.Join(p.failed, "\n"))
+		}
+		if len(p.pending) == 0 && p.selected == 0 {
+			return fmt.Errorf("no RootSync or RepoSync selected in %d cluster(s) to wait for commit %q", len(names), commit)
+		}
+		if len(p.pending) == 0 {
+			fmt.Fprintf(out, "Commit %q is synced by %d sync(s) in %d cluster(s)\n", commit, p.synced, len(names))
+			return nil
+		}
+		pending = p.pending
+		fmt.Fprintf(out, "Waiting for %d sync(s) to sync commit %q (%d synced)\n", len(pending), commit, p.synced)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(waitInterval):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if len(pending) == 0 {
+		return fmt.Errorf("timed out after %v waiting for commit %q before the status of all clusters was fetched", timeout, commit)
+	}
+	return fmt.Errorf("timed out after %v waiting for commit %q in %d cluster(s) or sync(s):\n%s", timeout, commit, len(pending), strings.Join(pending, "\n"))
+}
@@ -0,0 +1,244 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"kpt.dev/configsync/cmd/nomos/util"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+)
+
+func TestCheckCommit(t *testing.T) {
+	const commit = "abcdef1234567890"
+	syncedRepo := func(syncCommit string, resources ...resourceState) *RepoState {
+		return &RepoState{
+			scope:      rootScope,
+			syncName:   "root-sync",
+			sourceType: v1beta1.GitSource,
+			git:        &v1beta1.Git{Repo: "https://github.com/org/platform"},
+			status:     syncedMsg,
+			resources:  resources,
+			details:    &syncDetails{status: v1beta1.Status{Sync: v1beta1.SyncStatus{Commit: syncCommit}}},
+		}
+	}
+	multiRepo := true
+	monoRepo := false
+
+	pendingRepoSync := &RepoState{
+		scope:      "bookstore",
+		syncName:   "repo-sync",
+		sourceType: v1beta1.OciSource,
+		oci:        &v1beta1.Oci{Image: "us-docker.pkg.dev/org/bookstore"},
+		status:     pendingMsg,
+		details:    &syncDetails{},
+	}
+
+	testCases := []struct {
+		name     string
+		commit   string
+		selector syncSelector
+		stateMap map[string]*ClusterState
+		want     commitProgress
+	}{
+		{
+			name:   "synced with an abbreviated commit",
+			commit: "abcdef12",
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{syncedRepo(commit, resourceState{Kind: "Namespace", Name: "bookstore", Status: "Current"})}},
+			},
+			want: commitProgress{selected: 1, synced: 1},
+		},
+		{
+			name:   "synced an older commit",
+			commit: commit,
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{syncedRepo("0123456789abcdef")}},
+			},
+			want: commitProgress{selected: 1, pending: []string{"abc: <root>:root-sync is SYNCED at commit 01234567"}},
+		},
+		{
+			name:   "resources are not Current",
+			commit: commit,
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{syncedRepo(commit,
+					resourceState{Kind: "Namespace", Name: "bookstore", Status: "Current"},
+					resourceState{Kind: "Deployment", Group: "apps", Namespace: "bookstore", Name: "web", Status: "InProgress"},
+				)}},
+			},
+			want: commitProgress{selected: 1, pending: []string{"abc: <root>:root-sync has 1 resource(s) that are not Current: deployment.apps/web (InProgress)"}},
+		},
+		{
+			name:   "errors at the commit",
+			commit: commit,
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{{
+					scope:    "bookstore",
+					syncName: "repo-sync",
+					status:   util.ErrorMsg,
+					errors:   []string{"KNV1021: unknown kind"},
+					details:  &syncDetails{status: v1beta1.Status{Sync: v1beta1.SyncStatus{Commit: commit}}},
+				}}},
+			},
+			want: commitProgress{selected: 1, failed: []string{"abc: bookstore:repo-sync has errors: KNV1021: unknown kind"}},
+		},
+		{
+			name:   "stalled",
+			commit: commit,
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{{
+					scope:    "<root>",
+					syncName: "root-sync",
+					status:   stalledMsg,
+					errors:   []string{"missing secret"},
+					details:  &syncDetails{},
+				}}},
+			},
+			want: commitProgress{selected: 1, failed: []string{"abc: <root>:root-sync is STALLED: missing secret"}},
+		},
+		{
+			name:   "multiple clusters",
+			commit: commit,
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", isMulti: &multiRepo, repos: []*RepoState{syncedRepo(commit)}},
+				"def": {Ref: "def", status: util.UnknownMsg, Error: "No RootSync or RepoSync resources found"},
+				"ghi": {Ref: "ghi", isMulti: &monoRepo},
+			},
+			want: commitProgress{
+				selected: 1,
+				synced:   1,
+				pending:  []string{"def: No RootSync or RepoSync resources found"},
+				failed:   []string{"ghi: waiting for a commit is only supported in the multi-repo mode"},
+			},
+		},
+		{
+			name:     "select by kind",
+			commit:   commit,
+			selector: syncSelector{selectorKind: {"RootSync"}},
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{syncedRepo(commit), pendingRepoSync}},
+			},
+			want: commitProgress{selected: 1, synced: 1},
+		},
+		{
+			name:     "select by name",
+			commit:   commit,
+			selector: syncSelector{selectorName: {"repo-sync", "other"}},
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{syncedRepo(commit), pendingRepoSync}},
+			},
+			want: commitProgress{selected: 1, pending: []string{"abc: bookstore:repo-sync is PENDING at commit N/A"}},
+		},
+		{
+			name:     "select by repo",
+			commit:   commit,
+			selector: syncSelector{selectorRepo: {"https://github.com/org/platform"}},
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{syncedRepo(commit), pendingRepoSync}},
+			},
+			want: commitProgress{selected: 1, synced: 1},
+		},
+		{
+			name:     "select by OCI image and kind",
+			commit:   commit,
+			selector: syncSelector{selectorRepo: {"us-docker.pkg.dev/org/bookstore"}, selectorKind: {"RootSync"}},
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{syncedRepo(commit), pendingRepoSync}},
+			},
+			want: commitProgress{},
+		},
+		{
+			name:     "no sync selected",
+			commit:   commit,
+			selector: syncSelector{selectorName: {"other"}},
+			stateMap: map[string]*ClusterState{
+				"abc": {Ref: "abc", repos: []*RepoState{syncedRepo(commit)}},
+			},
+			want: commitProgress{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var names []string
+			for name := range tc.stateMap {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			got := checkCommit(tc.commit, tc.selector, tc.stateMap, names)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(commitProgress{})); diff != "" {
+				t.Errorf("checkCommit() got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseSyncSelector(t *testing.T) {
+	testCases := []struct {
+		name    string
+		terms   []string
+		want    syncSelector
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			want: syncSelector{},
+		},
+		{
+			name:  "all keys",
+			terms: []string{"name=root-sync", "kind=RepoSync", "repo=https://github.com/org/repo", "name=repo-sync"},
+			want: syncSelector{
+				selectorName: {"root-sync", "repo-sync"},
+				selectorKind: {"RepoSync"},
+				selectorRepo: {"https://github.com/org/repo"},
+			},
+		},
+		{
+			name:    "unknown key",
+			terms:   []string{"namespace=bookstore"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown kind",
+			terms:   []string{"kind=ConfigMap"},
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			terms:   []string{"name="},
+			wantErr: true,
+		},
+		{
+			name:    "not a term",
+			terms:   []string{"root-sync"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseSyncSelector(tc.terms)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseSyncSelector() got error %v, want error %t", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("parseSyncSelector() got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -30,6 +30,8 @@ var (
 
 func init() {
 	flags.AddClusters(Cmd)
+	flags.AddClusterLabels(Cmd)
+	flags.AddPolicyFile(Cmd)
 	flags.AddPath(Cmd)
 	flags.AddSkipAPIServerCheck(Cmd)
 	flags.AddSourceFormat(Cmd)
@@ -169,7 +169,27 @@ type clusterErrors struct {
 
 func (e clusterErrors) Error() string {
 	if e.name == "defaultcluster" {
-		return e.MultiError.Error()
+		return e.MultiError.Error() + hints(e.MultiError)
 	}
-	return fmt.Sprintf("errors for cluster %q:\n%v\n", e.name, e.MultiError.Error())
+	return fmt.Sprintf("errors for cluster %q:\n%v%s\n", e.name, e.MultiError.Error(), hints(e.MultiError))
+}
+
+// hints returns how to fix each kind of error in errs, according to the error
+// catalog. Each code is listed once, in the order it first appears.
+func hints(errs status.MultiError) string {
+	seen := make(map[string]bool)
+	var lines []string
+	for _, err := range errs.Errors() {
+		code := err.Code()
+		hint := status.Hint(code)
+		if hint == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		lines = append(lines, fmt.Sprintf("KNV%s: %s", code, hint))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\nHints:\n" + strings.Join(lines, "\n") + "\n"
 }
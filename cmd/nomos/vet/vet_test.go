@@ -17,12 +17,15 @@ package vet
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"kpt.dev/configsync/cmd/nomos/flags"
 	"kpt.dev/configsync/pkg/importer/filesystem"
 	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
 	ft "kpt.dev/configsync/pkg/importer/filesystem/filesystemtest"
+	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/validate/policy"
 )
 
 func resetFlags() {
@@ -31,6 +34,8 @@ func resetFlags() {
 	// are reset, successive calls to Cmd.Execute aren't guaranteed to be
 	// independent.
 	flags.Clusters = nil
+	flags.ClusterLabels = ""
+	flags.PolicyFile = ""
 	flags.Path = flags.PathDefault
 	flags.SkipAPIServer = true
 	flags.SourceFormat = string(filesystem.SourceFormatHierarchy)
@@ -121,6 +126,16 @@ func TestVet_MultiCluster(t *testing.T) {
 			args:      []string{"--clusters", "dev-cluster"},
 			wantError: false,
 		},
+		{
+			name:      "detect collision in defaultcluster with prod cluster labels",
+			args:      []string{"--clusters", "defaultcluster", "--cluster-labels", "env=prod"},
+			wantError: true,
+		},
+		{
+			name:      "do not detect collision with dev cluster labels",
+			args:      []string{"--cluster-labels", "env=dev"},
+			wantError: false,
+		},
 	}
 
 	for _, tc := range tcs {
@@ -141,3 +156,74 @@ func TestVet_MultiCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestVet_Hints(t *testing.T) {
+	Cmd.SilenceUsage = true
+	resetFlags()
+
+	os.Args = []string{
+		"vet", // this first argument does nothing, but is required to exist.
+		"--path", examplesDir.Join(cmpath.RelativeSlash("parse-errors/cluster-specific-collision")).OSPath(),
+	}
+
+	err := Cmd.Execute()
+	if err == nil {
+		t.Fatal("got no vet error, want err")
+	}
+	want := "Hints:\nKNV1029: " + status.Hint("1029") + "\n"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("got vet error:\n%v\nwant it to contain:\n%s", err, want)
+	}
+}
+
+func TestVet_PolicyFile(t *testing.T) {
+	Cmd.SilenceUsage = true
+
+	tcs := []struct {
+		name      string
+		policy    string
+		wantError bool
+	}{
+		{
+			name:   "complying objects",
+			policy: "expression: 'true'",
+		},
+		{
+			name:      "violating objects",
+			policy:    "match:\n      kinds: [Namespace]\n    expression: has(object.metadata.labels.reviewed)",
+			wantError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resetFlags()
+
+			policyFile := filepath.Join(t.TempDir(), "policies.yaml")
+			cm := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: validation-policies
+  namespace: config-management-system
+data:
+  policy: |
+    ` + tc.policy + "\n"
+			if err := os.WriteFile(policyFile, []byte(cm), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			os.Args = []string{
+				"vet", // this first argument does nothing, but is required to exist.
+				"--path", examplesDir.Join(cmpath.RelativeSlash("acme")).OSPath(),
+				"--policy-file", policyFile,
+			}
+
+			err := Cmd.Execute()
+			if !tc.wantError && err != nil {
+				t.Errorf("got vet errors, want nil:\n%v", err)
+			} else if tc.wantError && (err == nil || !strings.Contains(err.Error(), "KNV"+policy.ViolationErrorCode)) {
+				t.Errorf("got vet error %v, want KNV%s", err, policy.ViolationErrorCode)
+			}
+		})
+	}
+}
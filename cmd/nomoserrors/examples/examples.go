@@ -44,6 +44,7 @@ import (
 	"kpt.dev/configsync/pkg/syncer/client"
 	"kpt.dev/configsync/pkg/testing/fake"
 	"kpt.dev/configsync/pkg/util/clusterconfig"
+	"kpt.dev/configsync/pkg/validate/policy"
 	"kpt.dev/configsync/pkg/validate/raw/validate"
 	"kpt.dev/configsync/pkg/vet"
 	"kpt.dev/configsync/pkg/webhook/configuration"
@@ -321,6 +322,25 @@ func Generate() AllExamples {
 	// 1069
 	result.add(validate.SelfReconcileError(fake.RootSyncV1Beta1(configsync.RootSyncName)))
 
+	// 1070
+	result.add(validate.IllegalDriftPolicyAnnotationError(fake.Role(), "revert"))
+
+	// 1071
+	result.add(validate.IllegalApplyWaveAnnotationError(fake.Role(), "first"))
+
+	// 1072
+	policies := fake.ConfigMapObject(core.Namespace(configsync.ControllerNamespace), core.Name(configsync.ValidationPoliciesName))
+	noHostNetwork, err := policy.New(policies, "no-host-network",
+		"match:\n  kinds: [Deployment]\nexpression: '!has(object.spec.template.spec.hostNetwork) || !object.spec.template.spec.hostNetwork'\nmessage: hostNetwork is not allowed")
+	if err != nil {
+		panic(err)
+	}
+	result.add(policy.ViolationError(noHostNetwork, fake.Deployment("namespaces/foo")))
+	result.add(policy.EvaluationError(noHostNetwork, fake.Deployment("namespaces/foo"), errors.New("no such key: spec")))
+
+	// 1073
+	result.add(policy.InvalidPolicyError(policies, "no-host-network", errors.New(`unsupported engine "rego", must be one of "predicate"`)))
+
 	// 2001
 	result.add(status.PathWrapError(errors.New("error creating directory"), "namespaces/foo"))
 
@@ -375,6 +395,9 @@ func Generate() AllExamples {
 	// The transient error is not exposed in the R*Sync API, and is supposed to be autoresolvable.
 	result.add(status.TransientError(errors.New("transient error")))
 
+	// 2017
+	result.add(status.SignatureVerificationErrorBuilder.Sprint("no cosign signature is valid for the trusted public keys").Build())
+
 	// 9998
 	result.add(status.InternalError("we made a mistake"))
 
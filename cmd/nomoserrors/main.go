@@ -15,6 +15,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -30,7 +31,15 @@ import (
 	_ "kpt.dev/configsync/pkg/remediator"
 )
 
-var idFlag string
+var (
+	idFlag     string
+	formatFlag string
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "nomoserrors",
@@ -38,11 +47,21 @@ var rootCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(0),
 	Run: func(cmd *cobra.Command, args []string) {
 		e := examples.Generate()
+		idFlag = strings.TrimPrefix(idFlag, "KNV")
+
+		switch formatFlag {
+		case formatText:
+		case formatJSON:
+			printCatalog(idFlag)
+			return
+		default:
+			fmt.Printf("unknown --format %q, must be %q or %q\n", formatFlag, formatText, formatJSON)
+			os.Exit(1)
+		}
 
 		if idFlag == "" {
 			printErrorCodes()
 		}
-		idFlag = strings.TrimPrefix(idFlag, "KNV")
 		printErrors(idFlag, e)
 		printMissingErrors(e)
 	},
@@ -70,6 +89,10 @@ func printMissingErrors(e examples.AllExamples) {
 			// The code isn't deprecated and there aren't any examples for it.
 			fmt.Printf("Missing example(s) in cmd/nomoserrors/examples/examples.go for code: %s\n", id)
 			missing = true
+		} else if _, found := status.CatalogEntryFor(id); !e[id].Deprecated && !found {
+			// The code is in use but nothing documents how to fix it.
+			fmt.Printf("Missing entry in pkg/status/catalog.go for code: %s\n", id)
+			missing = true
 		}
 		previous = idInt
 	}
@@ -80,6 +103,8 @@ func printMissingErrors(e examples.AllExamples) {
 
 func init() {
 	rootCmd.Flags().StringVar(&idFlag, "id", "", "if set, only print errors for the passed ID")
+	rootCmd.Flags().StringVar(&formatFlag, "format", formatText,
+		fmt.Sprintf("output format, %q for the codes and example errors or %q for the error catalog", formatText, formatJSON))
 }
 
 func main() {
@@ -127,3 +152,23 @@ func printErrors(id string, e examples.AllExamples) {
 		}
 	}
 }
+
+// printCatalog prints the error catalog as JSON, only with the entry for the
+// passed ID if set.
+func printCatalog(id string) {
+	catalog := status.Catalog()
+	if id != "" {
+		entry, found := status.CatalogEntryFor(id)
+		if !found {
+			fmt.Printf("No entry in the error catalog for code: %s\n", id)
+			os.Exit(1)
+		}
+		catalog.Codes = []status.CatalogEntry{entry}
+	}
+	out, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println(string(out))
+}
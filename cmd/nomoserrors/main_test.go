@@ -0,0 +1,44 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"kpt.dev/configsync/cmd/nomoserrors/examples"
+	"kpt.dev/configsync/pkg/status"
+)
+
+// TestCatalog_RegisteredCodes verifies that every error code registered with
+// status.NewErrorBuilder, in any of the packages imported by nomoserrors, is
+// documented in the error catalog unless it is deprecated.
+func TestCatalog_RegisteredCodes(t *testing.T) {
+	e := examples.Generate()
+	registered := make(map[string]bool)
+	for _, code := range status.CodeRegistry() {
+		registered[code] = true
+		if e[code].Deprecated {
+			continue
+		}
+		if _, found := status.CatalogEntryFor(code); !found {
+			t.Errorf("KNV%s: missing entry in pkg/status/catalog.go", code)
+		}
+	}
+	for _, entry := range status.Catalog().Codes {
+		if !registered[entry.Code] {
+			t.Errorf("KNV%s: catalog entry for an unregistered code", entry.Code)
+		}
+	}
+}
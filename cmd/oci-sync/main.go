@@ -26,8 +26,10 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/google"
 	"k8s.io/klog/v2/klogr"
 	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/credentials"
 	"kpt.dev/configsync/pkg/oci"
 	"kpt.dev/configsync/pkg/reconcilermanager"
+	"kpt.dev/configsync/pkg/trigger"
 	"kpt.dev/configsync/pkg/util"
 	utillog "kpt.dev/configsync/pkg/util/log"
 )
@@ -35,8 +37,8 @@ import (
 var flImage = flag.String("image", util.EnvString(reconcilermanager.OciSyncImage, ""),
 	"the OCI image repository for the package")
 var flAuth = flag.String("auth", util.EnvString(reconcilermanager.OciSyncAuth, string(configsync.AuthNone)),
-	fmt.Sprintf("the authentication type for access to the OCI package. Must be one of %s, %s, or %s. Defaults to %s",
-		configsync.AuthGCPServiceAccount, configsync.AuthGCENode, configsync.AuthNone, configsync.AuthNone))
+	fmt.Sprintf("the authentication type for access to the OCI package. Must be one of %s, %s, %s, or %s. Defaults to %s",
+		configsync.AuthGCPServiceAccount, configsync.AuthGCENode, configsync.AuthK8sServiceAccount, configsync.AuthNone, configsync.AuthNone))
 var flRoot = flag.String("root", util.EnvString("OCI_SYNC_ROOT", util.EnvString("HOME", "")+"/oci"),
 	"the root directory for oci-sync operations, under which --dest will be created")
 var flDest = flag.String("dest", util.EnvString("OCI_SYNC_DEST", ""),
@@ -49,9 +51,22 @@ var flSyncTimeout = flag.Int("timeout", util.EnvInt("OCI_SYNC_TIMEOUT", 120),
 	"the max number of seconds allowed for a complete sync")
 var flOneTime = flag.Bool("one-time", util.EnvBool("OCI_SYNC_ONE_TIME", false),
 	"exit after the first sync")
+var flVerificationProvider = flag.String("verification-provider", util.EnvString(reconcilermanager.OciSyncVerificationProvider, ""),
+	fmt.Sprintf("the tool used to sign the OCI image, either %s or %s. The signature is not verified if it is empty",
+		configsync.VerificationProviderCosign, configsync.VerificationProviderNotation))
+var flVerificationKeysDir = flag.String("verification-keys-dir", util.EnvString("OCI_SYNC_VERIFICATION_KEYS_DIR", "/etc/verification-keys"),
+	"the directory that holds the trusted PEM-encoded public keys or root certificates")
+var flTokenExchangeURL = flag.String("token-exchange-url", util.EnvString(reconcilermanager.TokenExchangeURL, ""),
+	fmt.Sprintf("the token endpoint that exchanges the Kubernetes Service Account token for credentials, when --auth is %s", configsync.AuthK8sServiceAccount))
+var flTokenExchangeUsername = flag.String("token-exchange-username", util.EnvString(reconcilermanager.TokenExchangeUsername, ""),
+	"the username that goes with the exchanged token")
 var flMaxSyncFailures = flag.Int("max-sync-failures", util.EnvInt("OCI_SYNC_MAX_SYNC_FAILURES", 0),
 	"the number of consecutive failures allowed before aborting (the first sync must succeed, -1 will retry forever after the initial sync)")
 
+// The webhook token is read from the environment only, to avoid leaking it
+// through the process arguments.
+var webhookToken = os.Getenv(reconcilermanager.WebhookToken)
+
 func main() {
 	utillog.Setup()
 	log := utillog.NewLogger(klogr.New(), *flRoot, *flErrorFile)
@@ -59,7 +74,8 @@ func main() {
 	log.Info("pulling OCI image with arguments", "--image", *flImage,
 		"--auth", *flAuth, "--root", *flRoot, "--dest", *flDest, "--wait", *flWait,
 		"--error-file", *flErrorFile, "--timeout", *flSyncTimeout,
-		"--one-time", *flOneTime, "--max-sync-failures", *flMaxSyncFailures)
+		"--one-time", *flOneTime, "--max-sync-failures", *flMaxSyncFailures,
+		"--verification-provider", *flVerificationProvider, "--verification-keys-dir", *flVerificationKeysDir)
 
 	if *flImage == "" {
 		utillog.HandleError(log, true, "ERROR: --image must be specified")
@@ -92,15 +108,45 @@ func main() {
 			utillog.HandleError(log, true, "ERROR: failed to get the authentication with type %q: %v", *flAuth, err)
 		}
 		auth = a
+	case configsync.AuthK8sServiceAccount:
+		if *flTokenExchangeURL == "" {
+			utillog.HandleError(log, true, "ERROR: --token-exchange-url must be specified when --auth is %s", *flAuth)
+		}
+		provider := credentials.NewTokenExchange(*flTokenExchangeURL, *flTokenExchangeUsername)
+		auth = credentials.Authenticator(context.Background(), credentials.Cached(provider))
 	default:
 		utillog.HandleError(log, true, "ERROR: unsupported authentication type %q", *flAuth)
 	}
 
+	var verifier *oci.Verifier
+	if *flVerificationProvider != "" {
+		verifier = &oci.Verifier{
+			Provider: configsync.VerificationProvider(*flVerificationProvider),
+			KeysDir:  *flVerificationKeysDir,
+		}
+	}
+
+	// Fetch immediately when the reconciler receives a source change
+	// notification. Only listen on the loopback interface, because the
+	// notifications are forwarded by the hydration-controller in the same Pod.
+	var triggers <-chan struct{}
+	if webhookToken != "" {
+		webhookServer := trigger.NewServer(webhookToken)
+		triggers = webhookServer.Triggers()
+		go func() {
+			addr := fmt.Sprintf("127.0.0.1:%d", reconcilermanager.SourceWebhookPort)
+			if err := webhookServer.Run(context.Background(), addr); err != nil {
+				// Keep polling for source changes, so don't exit.
+				log.Error(err, "webhook server exited")
+			}
+		}()
+	}
+
 	initialSync := true
 	failCount := 0
 	for {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(*flSyncTimeout))
-		if err := oci.FetchPackage(ctx, *flImage, *flRoot, *flDest, auth); err != nil {
+		if err := oci.FetchPackage(ctx, *flImage, *flRoot, *flDest, auth, verifier); err != nil {
 			if *flMaxSyncFailures != -1 && failCount >= *flMaxSyncFailures {
 				// Exit after too many retries, maybe the error is not recoverable.
 				log.Error(err, "too many failures, aborting", "failCount", failCount)
@@ -111,7 +157,7 @@ func main() {
 			log.Error(err, "unexpected error fetching package, will retry")
 			log.Info("waiting before retrying", "waitTime", util.WaitTime(*flWait))
 			cancel()
-			time.Sleep(util.WaitTime(*flWait))
+			trigger.Wait(util.WaitTime(*flWait), triggers)
 			continue
 		}
 
@@ -127,7 +173,7 @@ func main() {
 		log.DeleteErrorFile()
 		log.Info("next sync", "wait_time", util.WaitTime(*flWait))
 		cancel()
-		time.Sleep(util.WaitTime(*flWait))
+		trigger.Wait(util.WaitTime(*flWait), triggers)
 	}
 
 }
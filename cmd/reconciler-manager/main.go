@@ -32,6 +32,7 @@ import (
 	"kpt.dev/configsync/pkg/profiler"
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/reconcilermanager/controllers"
+	"kpt.dev/configsync/pkg/util"
 	ctrl "sigs.k8s.io/controller-runtime"
 	// +kubebuilder:scaffold:imports
 )
@@ -48,6 +49,9 @@ var (
 		controllers.PollingPeriod(reconcilermanager.HydrationPollingPeriod, configsync.DefaultHydrationPollingPeriod),
 		"Period of time between checking the filesystem for source updates to render.")
 
+	prometheusPort = flag.Int("prometheus-port", util.EnvInt(reconcilermanager.PrometheusPort, 0),
+		"Port on which the reconciler-manager and the reconcilers serve their metrics in the Prometheus exposition format. The metrics are not served if it is 0.")
+
 	setupLog = ctrl.Log.WithName("setup")
 )
 
@@ -83,7 +87,7 @@ func main() {
 	}
 	watchFleetMembership := fleetMembershipCRDExists(dynamicClient, mgr.GetRESTMapper())
 
-	repoSync := controllers.NewRepoSyncReconciler(*clusterName, *reconcilerPollingPeriod, *hydrationPollingPeriod, mgr.GetClient(), dynamicClient,
+	repoSync := controllers.NewRepoSyncReconciler(*clusterName, *reconcilerPollingPeriod, *hydrationPollingPeriod, *prometheusPort, mgr.GetClient(), dynamicClient, mgr.GetEventRecorderFor(reconcilermanager.ManagerName),
 		ctrl.Log.WithName("controllers").WithName(configsync.RepoSyncKind),
 		mgr.GetScheme())
 	if err := repoSync.SetupWithManager(mgr, watchFleetMembership); err != nil {
@@ -91,7 +95,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	rootSync := controllers.NewRootSyncReconciler(*clusterName, *reconcilerPollingPeriod, *hydrationPollingPeriod, mgr.GetClient(), dynamicClient,
+	rootSync := controllers.NewRootSyncReconciler(*clusterName, *reconcilerPollingPeriod, *hydrationPollingPeriod, *prometheusPort, mgr.GetClient(), dynamicClient, mgr.GetEventRecorderFor(reconcilermanager.ManagerName),
 		ctrl.Log.WithName("controllers").WithName(configsync.RootSyncKind),
 		mgr.GetScheme())
 	if err := rootSync.SetupWithManager(mgr, watchFleetMembership); err != nil {
@@ -107,6 +111,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	prometheusService := controllers.NewPrometheusServiceReconciler(mgr.GetAPIReader(), mgr.GetClient(), *prometheusPort,
+		ctrl.Log.WithName("controllers").WithName("PrometheusService"))
+	if err := mgr.Add(prometheusService); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PrometheusService")
+		os.Exit(1)
+	}
+
 	// Register the OpenCensus views
 	if err := metrics.RegisterReconcilerManagerMetricsViews(); err != nil {
 		setupLog.Error(err, "failed to register OpenCensus views")
@@ -125,6 +136,13 @@ func main() {
 		}
 	}()
 
+	if *prometheusPort > 0 {
+		if err := metrics.ServePrometheus(*prometheusPort); err != nil {
+			setupLog.Error(err, "failed to serve the Prometheus metrics")
+			os.Exit(1)
+		}
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
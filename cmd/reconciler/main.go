@@ -15,6 +15,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"os"
 	"strings"
@@ -26,12 +27,14 @@ import (
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/importer/filesystem"
 	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
+	"kpt.dev/configsync/pkg/metadata"
 	ocmetrics "kpt.dev/configsync/pkg/metrics"
 	"kpt.dev/configsync/pkg/profiler"
 	"kpt.dev/configsync/pkg/reconciler"
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/reconcilermanager/controllers"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/util"
 	"kpt.dev/configsync/pkg/util/log"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -90,6 +93,34 @@ var (
 
 	apiServerTimeout = flag.String("api-server-timeout", os.Getenv(reconcilermanager.APIServerTimeout), "The client-side timeout for requests to the API server")
 
+	// The webhook token is read from the environment only, to avoid leaking it
+	// through the process arguments.
+	webhookToken = os.Getenv(reconcilermanager.WebhookToken)
+
+	suspend = flag.Bool("suspend", util.EnvBool(reconcilermanager.Suspend, false),
+		"Suspend syncing, regardless of the sync windows.")
+	syncWindows = flag.String("sync-windows", os.Getenv(reconcilermanager.SyncWindows),
+		"The JSON encoded sync windows that allow or deny syncing on a schedule.")
+	driftPolicy = flag.String("drift-policy", util.EnvString(reconcilermanager.DriftPolicy, string(configsync.DriftPolicyRemediate)),
+		"Whether the remediator reverts drift, or only reports it in the sync status. Must be remediate or report.")
+	breakGlassMaxDuration = flag.Duration("break-glass-max-duration", metadata.DefaultBreakGlassMaxDuration,
+		"The longest duration for which the remediator honors the break-glass exemption of a managed object.")
+	autoRollback = flag.Bool("auto-rollback", util.EnvBool(reconcilermanager.AutoRollback, false),
+		"Re-apply the last healthy commit when a new commit fails to apply or its objects do not become healthy.")
+	dryRun = flag.Bool("dry-run", util.EnvBool(reconcilermanager.DryRun, false),
+		"Apply and prune the objects with a server-side dry-run, and report the changes in the sync status without mutating the cluster.")
+	dependsOn = flag.String("depends-on", os.Getenv(reconcilermanager.DependsOn),
+		"The JSON encoded RootSyncs and RepoSyncs that must sync their latest commit without errors before the source is parsed and applied.")
+	notifications = flag.String("notifications", os.Getenv(reconcilermanager.Notifications),
+		"The JSON encoded HTTP endpoints notified when a commit is synced, or fails to sync.")
+	notificationSecretsDir = flag.String("notification-secrets-dir", util.EnvString(reconcilermanager.NotificationSecretsDir, "/etc/notification-secrets"),
+		"The directory where the Secret referenced by the i-th notification is mounted, in the <i> subdirectory.")
+	sources = flag.String("sources", os.Getenv(reconcilermanager.Sources),
+		"The JSON encoded additional sources of a RootSync, whose objects are merged with the objects of the primary source.")
+
+	prometheusPort = flag.Int("prometheus-port", util.EnvInt(reconcilermanager.PrometheusPort, 0),
+		"Port on which to serve the metrics in the Prometheus exposition format. The metrics are not served if it is 0.")
+
 	debug = flag.Bool("debug", false,
 		"Enable debug mode, panicking in many scenarios where normally an InternalError would be logged. "+
 			"Do not use in production.")
@@ -139,6 +170,12 @@ func main() {
 		}
 	}()
 
+	if *prometheusPort > 0 {
+		if err := ocmetrics.ServePrometheus(*prometheusPort); err != nil {
+			klog.Fatalf("Failed to serve the Prometheus metrics: %v", err)
+		}
+	}
+
 	absRepoRoot, err := cmpath.AbsoluteOS(*repoRootDir)
 	if err != nil {
 		klog.Fatalf("%s must be an absolute path: %v", flags.repoRootDir, err)
@@ -160,6 +197,27 @@ func main() {
 		klog.Fatal(err)
 	}
 
+	var windows []v1beta1.SyncWindow
+	if *syncWindows != "" {
+		if err := json.Unmarshal([]byte(*syncWindows), &windows); err != nil {
+			klog.Fatalf("Failed to parse the sync windows %q: %v", *syncWindows, err)
+		}
+	}
+
+	var dependencies []v1beta1.Dependency
+	if *dependsOn != "" {
+		if err := json.Unmarshal([]byte(*dependsOn), &dependencies); err != nil {
+			klog.Fatalf("Failed to parse the dependencies %q: %v", *dependsOn, err)
+		}
+	}
+
+	var notificationList []v1beta1.Notification
+	if *notifications != "" {
+		if err := json.Unmarshal([]byte(*notifications), &notificationList); err != nil {
+			klog.Fatalf("Failed to parse the notifications %q: %v", *notifications, err)
+		}
+	}
+
 	opts := reconciler.Options{
 		ClusterName:             *clusterName,
 		FightDetectionThreshold: *fightDetectionThreshold,
@@ -183,6 +241,16 @@ func main() {
 		StatusMode:              *statusMode,
 		ReconcileTimeout:        *reconcileTimeout,
 		APIServerTimeout:        *apiServerTimeout,
+		WebhookToken:            webhookToken,
+		Suspend:                 *suspend,
+		SyncWindows:             windows,
+		DriftPolicy:             configsync.DriftPolicy(*driftPolicy),
+		BreakGlassMaxDuration:   *breakGlassMaxDuration,
+		AutoRollback:            *autoRollback,
+		DryRun:                  *dryRun,
+		DependsOn:               dependencies,
+		Notifications:           notificationList,
+		NotificationSecretsDir:  *notificationSecretsDir,
 	}
 
 	if declared.Scope(*scope) == declared.RootReconciler {
@@ -192,9 +260,17 @@ func main() {
 			format = filesystem.SourceFormatHierarchy
 		}
 
+		var sourceList []reconcilermanager.Source
+		if *sources != "" {
+			if err := json.Unmarshal([]byte(*sources), &sourceList); err != nil {
+				klog.Fatalf("Failed to parse the sources %q: %v", *sources, err)
+			}
+		}
+
 		klog.Info("Starting reconciler for: root")
 		opts.RootOptions = &reconciler.RootOptions{
 			SourceFormat: format,
+			Sources:      sourceList,
 		}
 	} else {
 		klog.Infof("Starting reconciler for: %s", *scope)
@@ -203,6 +279,10 @@ func main() {
 			klog.Fatalf("Flag %s and Environment variable%q must not be passed to a Namespace reconciler",
 				flags.sourceFormat, filesystem.SourceFormatKey)
 		}
+		if *sources != "" {
+			klog.Fatalf("Flag sources and Environment variable %q must not be passed to a Namespace reconciler",
+				reconcilermanager.Sources)
+		}
 	}
 	reconciler.Run(opts)
 }
@@ -109,6 +109,18 @@ const (
 	HierarchyModeDefault = HierarchyModeType("")
 )
 
+// NSSelectorModeType defines how a NamespaceSelector selects namespaces.
+type NSSelectorModeType string
+
+const (
+	// NSSelectorStaticMode indicates that the NamespaceSelector only selects
+	// the Namespaces declared in the source of truth. This is the default.
+	NSSelectorStaticMode = NSSelectorModeType("static")
+	// NSSelectorDynamicMode indicates that the NamespaceSelector also selects
+	// the live Namespaces on the cluster, by their current labels.
+	NSSelectorDynamicMode = NSSelectorModeType("dynamic")
+)
+
 // ACM-specific reasons for recorded Kubernetes Events.
 const (
 	// EventReasonReconcileComplete reports that reconcile succeeded.
@@ -268,6 +268,13 @@ type NamespaceSelectorSpec struct {
 	// This field is NOT optional and follows standard label selector semantics. An empty selector
 	// matches all namespaces.
 	Selector metav1.LabelSelector `json:"selector"`
+	// Mode specifies whether the selector is evaluated against the Namespaces
+	// declared in the source of truth only ("static"), or also against the
+	// live Namespaces on the cluster ("dynamic"). The "dynamic" mode is only
+	// supported by the root reconciler with an unstructured source.
+	// Default: static.
+	// +optional
+	Mode NSSelectorModeType `json:"mode,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -42,6 +42,26 @@ const (
 	RootSyncKind = "RootSync"
 )
 
+// Cluster metadata constants
+const (
+	// ClusterMetadataName is the name of the ConfigMap in the
+	// ControllerNamespace which declares the labels of the cluster used to
+	// evaluate ClusterSelectors.
+	ClusterMetadataName = "cluster-metadata"
+	// ClusterMetadataLabelsKey is the key of the cluster labels in the
+	// cluster metadata ConfigMap, formatted as `key1=value1,key2=value2`.
+	ClusterMetadataLabelsKey = "labels"
+	// ClusterMetadataClusterNameKey is the key of the optional cluster name in
+	// the cluster metadata ConfigMap. It is only used if the reconciler has no
+	// cluster name.
+	ClusterMetadataClusterNameKey = "clusterName"
+)
+
+// ValidationPoliciesName is the name of the ConfigMap in the
+// ControllerNamespace which declares the validation policies evaluated by every
+// reconciler, in addition to the ones declared in its source.
+const ValidationPoliciesName = "validation-policies"
+
 const (
 	// DefaultHydrationPollingPeriod is the time delay between polling the
 	// filesystem for source updates to render.
@@ -83,6 +103,9 @@ const (
 
 	// DefaultHelmReleaseNamespace is the default namespace for a Helm Release which does not have a namespace specified
 	DefaultHelmReleaseNamespace = "default"
+
+	// DefaultHelmValuesFileDataKey is the default ConfigMap data key of a Helm values file
+	DefaultHelmValuesFileDataKey = "values.yaml"
 )
 
 // AuthType specifies the type to authenticate to a repository.
@@ -102,4 +125,61 @@ const (
 	// AuthGCPServiceAccount indicates using a GCP service account to authenticate to
 	// Git or OCI or Helm, when GKE Workload Identity or Fleet Workload Identity is enabled.
 	AuthGCPServiceAccount AuthType = "gcpserviceaccount"
+	// AuthGitHubApp indicates using the installation token of a GitHub App to
+	// authenticate to Git. It doesn't apply to OCI and Helm.
+	AuthGitHubApp AuthType = "githubapp"
+	// AuthK8sServiceAccount indicates exchanging a projected token of the
+	// reconciler Kubernetes Service Account for credentials to Git or OCI or Helm.
+	AuthK8sServiceAccount AuthType = "k8sserviceaccount"
+)
+
+// VerificationProvider specifies the tool used to sign an OCI artifact.
+type VerificationProvider string
+
+const (
+	// VerificationProviderCosign indicates verifying signatures created by cosign.
+	VerificationProviderCosign VerificationProvider = "cosign"
+	// VerificationProviderNotation indicates verifying signatures created by notation.
+	VerificationProviderNotation VerificationProvider = "notation"
+)
+
+// SyncWindowKind specifies whether syncing is allowed or denied during a sync
+// window.
+type SyncWindowKind string
+
+const (
+	// SyncWindowAllow indicates that syncing is only allowed during the window.
+	SyncWindowAllow SyncWindowKind = "allow"
+	// SyncWindowDeny indicates that syncing is suspended during the window.
+	SyncWindowDeny SyncWindowKind = "deny"
+)
+
+const (
+	// PromotionCommitKey is the key of the ConfigMap data, referenced by
+	// spec.promotion.sourceRef, that holds the commit synced without errors by
+	// the sync to follow, e.g. a sync in a canary cluster.
+	PromotionCommitKey = "commit"
+)
+
+// NotificationEvent specifies a sync outcome that is notified.
+// +kubebuilder:validation:Enum=synced;failed
+type NotificationEvent string
+
+const (
+	// NotificationSynced indicates that a commit was synced without errors.
+	NotificationSynced NotificationEvent = "synced"
+	// NotificationFailed indicates that a commit failed to sync.
+	NotificationFailed NotificationEvent = "failed"
+)
+
+// DriftPolicy specifies what the remediator does when a managed object
+// diverges from its declaration.
+type DriftPolicy string
+
+const (
+	// DriftPolicyRemediate indicates that the remediator reverts drift.
+	DriftPolicyRemediate DriftPolicy = "remediate"
+	// DriftPolicyReport indicates that the remediator only reports drift in the
+	// RootSync/RepoSync status, without reverting it.
+	DriftPolicyReport DriftPolicy = "report"
 )
@@ -48,10 +48,11 @@ type Git struct {
 	Period metav1.Duration `json:"period,omitempty"`
 
 	// auth is the type of secret configured for access to the Git repo.
-	// Must be one of ssh, cookiefile, gcenode, token, or none.
+	// Must be one of ssh, cookiefile, gcenode, gcpserviceaccount, githubapp,
+	// k8sserviceaccount, token, or none.
 	// The validation of this is case-sensitive. Required.
 	//
-	// +kubebuilder:validation:Enum=ssh;cookiefile;gcenode;gcpserviceaccount;token;none
+	// +kubebuilder:validation:Enum=ssh;cookiefile;gcenode;gcpserviceaccount;githubapp;k8sserviceaccount;token;none
 	Auth configsync.AuthType `json:"auth"`
 
 	// gcpServiceAccountEmail specifies the GCP service account used to annotate
@@ -59,6 +60,12 @@ type Git struct {
 	// Note: The field is used when spec.git.auth: gcpserviceaccount.
 	GCPServiceAccountEmail string `json:"gcpServiceAccountEmail,omitempty"`
 
+	// tokenExchange specifies how the reconciler Kubernetes Service Account
+	// token is exchanged for credentials to the Git repo.
+	// Note: The field is used when spec.git.auth: k8sserviceaccount.
+	// +optional
+	TokenExchange *TokenExchange `json:"tokenExchange,omitempty"`
+
 	// proxy specifies an HTTPS proxy for accessing the Git repo.
 	// Only has an effect when secretType is one of ("cookiefile", "none", "token").
 	// When secretType is "cookiefile" or "token", if your HTTPS proxy URL contains sensitive information
@@ -50,10 +50,18 @@ type HelmBase struct {
 	// +optional
 	ReleaseName string `json:"releaseName,omitempty"`
 
-	// values to use instead of default values that accompany the chart
+	// values to use instead of default values that accompany the chart.
+	// They override the values of valuesSources.
 	// +optional
 	Values *apiextensionsv1.JSON `json:"values,omitempty"`
 
+	// valuesSources is the ordered list of values to use instead of default
+	// values that accompany the chart. Each entry is a values file in the
+	// chart, a values file in a ConfigMap, or inline values. They are merged
+	// in order, so that later entries override earlier ones.
+	// +optional
+	ValuesSources []ValuesSource `json:"valuesSources,omitempty"`
+
 	// includeCRDs specifies if Helm template should also generate CustomResourceDefinitions.
 	// If IncludeCRDs is set to false, no CustomeResourceDefinition will be generated.
 	// Default: false.
@@ -69,9 +77,9 @@ type HelmBase struct {
 	Period metav1.Duration `json:"period,omitempty"`
 
 	// auth specifies the type to authenticate to the Helm repository.
-	// Must be one of token, gcpserviceaccount, gcenode or none.
+	// Must be one of token, gcpserviceaccount, gcenode, k8sserviceaccount or none.
 	// The validation of this is case-sensitive. Required.
-	// +kubebuilder:validation:Enum=none;gcpserviceaccount;token;gcenode
+	// +kubebuilder:validation:Enum=none;gcpserviceaccount;token;gcenode;k8sserviceaccount
 	Auth configsync.AuthType `json:"auth"`
 
 	// gcpServiceAccountEmail specifies the GCP service account used to annotate
@@ -80,9 +88,50 @@ type HelmBase struct {
 	// +optional
 	GCPServiceAccountEmail string `json:"gcpServiceAccountEmail,omitempty"`
 
+	// tokenExchange specifies how the reconciler Kubernetes Service Account
+	// token is exchanged for credentials to the Helm repository.
+	// Note: The field is used when spec.helm.auth: k8sserviceaccount.
+	// +optional
+	TokenExchange *TokenExchange `json:"tokenExchange,omitempty"`
+
 	// secretRef holds the authentication secret for accessing
 	// the Helm repository.
 	// +nullable
 	// +optional
 	SecretRef *SecretReference `json:"secretRef,omitempty"`
+
+	// verification specifies how the signature of the chart is verified
+	// before it is rendered. It only applies to charts stored in an OCI
+	// registry. If unset, the signature is not verified.
+	// +optional
+	Verification *Verification `json:"verification,omitempty"`
+}
+
+// ValuesSource is a source of Helm values. Exactly one of chartFile,
+// configMapRef and inline must be set.
+type ValuesSource struct {
+	// chartFile is the path of a values file in the chart, relative to the
+	// root of the chart, e.g. "values-prod.yaml".
+	// +optional
+	ChartFile string `json:"chartFile,omitempty"`
+
+	// configMapRef references a ConfigMap in the same namespace as the
+	// RootSync/RepoSync, whose data contains a values file.
+	// +optional
+	ConfigMapRef *ValuesFileRef `json:"configMapRef,omitempty"`
+
+	// inline holds the values, like the values field.
+	// +optional
+	Inline *apiextensionsv1.JSON `json:"inline,omitempty"`
+}
+
+// ValuesFileRef references a Helm values file stored in a ConfigMap.
+type ValuesFileRef struct {
+	// name is the name of the ConfigMap. Required.
+	Name string `json:"name"`
+
+	// dataKey is the key in the ConfigMap data that holds the values file.
+	// Default: "values.yaml".
+	// +optional
+	DataKey string `json:"dataKey,omitempty"`
 }
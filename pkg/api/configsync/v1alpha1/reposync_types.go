@@ -72,6 +72,43 @@ type RepoSyncSpec struct {
 	// +optional
 	Helm *HelmRepoSync `json:"helm,omitempty"`
 
+	// webhook configures an endpoint that accepts source change notifications
+	// and triggers a sync immediately, instead of waiting for polling.
+	// +nullable
+	// +optional
+	Webhook *Webhook `json:"webhook,omitempty"`
+
+	// suspend stops the reconciler from syncing new commits and correcting
+	// drift, without deleting the RepoSync. Default: false.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// syncWindows restricts when the reconciler syncs new commits and corrects
+	// drift. Syncing is suspended during any deny window and, if there is any
+	// allow window, outside of all the allow windows.
+	// +optional
+	SyncWindows []SyncWindow `json:"syncWindows,omitempty"`
+
+	// promotion pins the revision to the commit synced by another sync, once
+	// it has soaked, instead of spec.git.revision or spec.oci.image. Only
+	// supported for git and oci sources.
+	// +optional
+	Promotion *Promotion `json:"promotion,omitempty"`
+
+	// dependsOn lists the RootSyncs and RepoSyncs that must sync their latest
+	// commit without errors before the reconciler parses and applies the
+	// source. Until then, the Syncing condition has the WaitingForDependency
+	// reason. A cycle of dependencies is reported as a sync error.
+	// +optional
+	DependsOn []Dependency `json:"dependsOn,omitempty"`
+
+	// notifications lists the HTTP endpoints that are notified when the
+	// reconciler finishes syncing a commit, or fails to fetch, render, parse
+	// or sync it. Each outcome is notified once per commit, and again only
+	// after the outcome changed.
+	// +optional
+	Notifications []Notification `json:"notifications,omitempty"`
+
 	// override allows to override the settings for a reconciler.
 	// +nullable
 	// +optional
@@ -105,6 +142,9 @@ const (
 	RepoSyncStalled RepoSyncConditionType = "Stalled"
 	// RepoSyncSyncing means that the namespace reconciler is processing a hash (git commit hash or OCI image digest).
 	RepoSyncSyncing RepoSyncConditionType = "Syncing"
+	// RepoSyncSuspended means that the reconciler does not sync new commits nor
+	// correct drift, because of spec.suspend or spec.syncWindows.
+	RepoSyncSuspended RepoSyncConditionType = "Suspended"
 )
 
 // RepoSyncCondition describes the state of a RepoSync at a certain point.
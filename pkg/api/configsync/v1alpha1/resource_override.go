@@ -17,6 +17,7 @@ package v1alpha1
 import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kpt.dev/configsync/pkg/api/configsync"
 )
 
 // OverrideSpec allows to override the settings for a reconciler pod
@@ -66,6 +67,44 @@ type OverrideSpec struct {
 	// support pulling remote bases from public repositories.
 	// +optional
 	EnableShellInRendering *bool `json:"enableShellInRendering,omitempty"`
+
+	// driftPolicy specifies what the remediator does when a managed object
+	// diverges from its declaration in the source of truth.
+	// Must be "remediate" or "report". Default: remediate.
+	// If set to "remediate", the remediator reverts the drift.
+	// If set to "report", the remediator records the drift in status.sync.drift
+	// without reverting it. The applier does not revert the drift either,
+	// unless the declaration of the object changes.
+	// The configsync.gke.io/drift-policy annotation of a managed object
+	// overrides this field for the object.
+	//
+	// +kubebuilder:validation:Enum=remediate;report
+	// +optional
+	DriftPolicy configsync.DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// autoRollback specifies whether to re-apply the last healthy commit when a
+	// new commit fails to apply, or its resources fail to become healthy
+	// within the reconcileTimeout. Default: false.
+	// A commit is rolled back once 3 attempts in a row failed, or it has been
+	// failing for 5 minutes. The Syncing condition of a commit that was rolled
+	// back has the RolledBack reason. The commit is retried on the next
+	// resync, and rolled back again if it still fails.
+	// +optional
+	AutoRollback *bool `json:"autoRollback,omitempty"`
+
+	// dryRun specifies whether to apply and prune the objects with a
+	// server-side dry-run, without mutating the cluster. Default: false.
+	// If set to true, the changes that syncing each commit would make are
+	// recorded in status.sync.dryRun, admission errors are reported as sync
+	// errors, status.lastSyncedCommit is not updated, and drift is neither
+	// reverted nor reported.
+	// +optional
+	DryRun *bool `json:"dryRun,omitempty"`
+
+	// rendering allows one to override the options of `kustomize build` in the
+	// rendering process.
+	// +optional
+	Rendering *RenderingSpec `json:"rendering,omitempty"`
 }
 
 // ContainerResourcesSpec allows to override the resource requirements for a container
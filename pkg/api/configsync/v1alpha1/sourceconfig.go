@@ -0,0 +1,55 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// RootSyncSource is an additional source of a RootSync. Its objects are merged
+// with the objects of the primary source into a single set of declared
+// objects, and applied together.
+type RootSyncSource struct {
+	// name identifies the source within the RootSync, and names the sidecar
+	// container that fetches it. Must be a DNS label. Required.
+	Name string `json:"name"`
+
+	// sourceType specifies the type of the source. Must be one of git, oci,
+	// helm. Required.
+	// +kubebuilder:validation:Pattern=^(git|oci|helm)$
+	SourceType string `json:"sourceType"`
+
+	// git contains configuration specific to importing resources from a Git
+	// repo. Only the none and token auth types are supported.
+	// +optional
+	Git *Git `json:"git,omitempty"`
+
+	// oci contains configuration specific to importing resources from an OCI
+	// package. Only the none and gcenode auth types are supported.
+	// +optional
+	Oci *Oci `json:"oci,omitempty"`
+
+	// helm contains configuration specific to importing resources from a Helm
+	// repo. Only the none, token and gcenode auth types are supported.
+	// +optional
+	Helm *HelmRootSync `json:"helm,omitempty"`
+}
+
+// SourceCommit is the commit of an additional source of a RootSync.
+type SourceCommit struct {
+	// name is the name of the source in spec.sources.
+	Name string `json:"name"`
+
+	// commit is the hash of the most recent commit of the source, or the
+	// digest of the OCI image, or the version of the Helm chart.
+	// +optional
+	Commit string `json:"commit,omitempty"`
+}
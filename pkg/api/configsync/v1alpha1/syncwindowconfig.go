@@ -0,0 +1,43 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kpt.dev/configsync/pkg/api/configsync"
+)
+
+// SyncWindow is a recurring time window during which the reconciler is allowed,
+// or denied, to sync new commits and correct drift.
+type SyncWindow struct {
+	// kind specifies whether syncing is allowed or denied during the window.
+	// Must be one of allow or deny. Required.
+	// +kubebuilder:validation:Enum=allow;deny
+	Kind configsync.SyncWindowKind `json:"kind"`
+
+	// schedule is the cron expression of the start times of the window, in
+	// the standard five-field format (minute, hour, day of month, month, day
+	// of week), e.g. `0 18 * * 5` for every Friday at 18:00. Required.
+	Schedule string `json:"schedule"`
+
+	// duration is how long the window lasts after each start time, e.g. "2h"
+	// or "60h". Required.
+	Duration metav1.Duration `json:"duration"`
+
+	// timeZone is the IANA time zone of the schedule, e.g. `Europe/Paris`.
+	// Default: UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
@@ -0,0 +1,34 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// TokenExchange configures how the projected token of the reconciler
+// Kubernetes Service Account is exchanged for credentials, following the
+// OAuth 2.0 Token Exchange protocol (RFC 8693).
+type TokenExchange struct {
+	// url is the token endpoint that exchanges the Kubernetes Service Account
+	// token for an access token. Required.
+	URL string `json:"url"`
+
+	// audience is the audience of the projected Kubernetes Service Account
+	// token, which the token endpoint expects. Default: the url.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+
+	// username is the username sent along with the access token to the Git
+	// server or the registry. Default: oauth2accesstoken.
+	// +optional
+	Username string `json:"username,omitempty"`
+}
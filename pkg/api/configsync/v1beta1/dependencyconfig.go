@@ -0,0 +1,34 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+// Dependency references a RootSync or RepoSync that must sync its latest
+// commit without errors before the dependent sync parses and applies its
+// source, e.g. a RootSync that provides the CRDs or Namespaces used by a
+// RepoSync.
+type Dependency struct {
+	// kind is the kind of the referenced sync: RootSync or RepoSync. Required.
+	// +kubebuilder:validation:Enum=RootSync;RepoSync
+	Kind string `json:"kind"`
+
+	// name is the name of the referenced sync. Required.
+	Name string `json:"name"`
+
+	// namespace is the namespace of the referenced RepoSync. Defaults to the
+	// namespace of the dependent sync. A RootSync is always looked up in the
+	// config-management-system namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
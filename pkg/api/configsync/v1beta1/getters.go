@@ -15,6 +15,8 @@
 package v1beta1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/client/restconfig"
@@ -37,6 +39,35 @@ func GetSecretName(secretRef *SecretReference) string {
 	return ""
 }
 
+// GetDataKey returns the ConfigMap data key of the values file, defaulting to
+// values.yaml if empty.
+func (ref ValuesFileRef) GetDataKey() string {
+	if ref.DataKey == "" {
+		return configsync.DefaultHelmValuesFileDataKey
+	}
+	return ref.DataKey
+}
+
+// GetValuesFileRefs returns the ConfigMaps referenced by the values sources, in
+// order.
+func (h *HelmBase) GetValuesFileRefs() []ValuesFileRef {
+	var refs []ValuesFileRef
+	for _, src := range h.ValuesSources {
+		if src.ConfigMapRef != nil {
+			refs = append(refs, *src.ConfigMapRef)
+		}
+	}
+	return refs
+}
+
+// GetSoakTime returns the soak time of the promotion, defaulting to 0 if empty.
+func (p *Promotion) GetSoakTime() time.Duration {
+	if p.SoakTime == nil {
+		return 0
+	}
+	return p.SoakTime.Duration
+}
+
 // SafeOverride creates an override or returns an existing one
 // use it if you need to ensure that you are assigning
 // to an object, but not to test for nil (current existance)
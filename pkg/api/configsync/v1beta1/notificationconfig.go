@@ -0,0 +1,48 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import "kpt.dev/configsync/pkg/api/configsync"
+
+// Notification configures an HTTP endpoint that receives a CloudEvent when the
+// reconciler finishes syncing a commit, or fails to fetch, render, parse or
+// sync it.
+type Notification struct {
+	// url is the HTTP or HTTPS endpoint that receives the notifications, e.g. a
+	// generic webhook or a Slack incoming webhook. Required, unless the Secret
+	// referenced by secretRef has a `url` key.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// on lists the sync outcomes that are notified. Must be synced or failed.
+	// Default: both.
+	// +optional
+	On []configsync.NotificationEvent `json:"on,omitempty"`
+
+	// template is a Go template that renders the request body, e.g.
+	// `{"text": "{{.Data.Name}} synced {{.Data.Commit}}"}` for Slack. The
+	// template is executed with the CloudEvent, whose attributes are also sent
+	// as ce- headers. Default: the CloudEvent in the structured JSON format.
+	// +optional
+	Template string `json:"template,omitempty"`
+
+	// secretRef holds the name of a Secret, in the namespace of the RootSync or
+	// RepoSync, with the confidential parts of the request. The `url` key
+	// overrides url, and each key prefixed with `header.` sets a request
+	// header, e.g. `header.Authorization`. The Secret is read before each
+	// request.
+	// +optional
+	SecretRef *SecretReference `json:"secretRef,omitempty"`
+}
@@ -27,6 +27,11 @@ type Oci struct {
 	// - Pull by tag: `LOCATION-docker.pkg.dev/PROJECT_ID/REPOSITORY_NAME/PACKAGE_NAME:TAG`.
 	// - Pull by digest: `LOCATION-docker.pkg.dev/PROJECT_ID/REPOSITORY_NAME/PACKAGE_NAME@sha256:DIGEST`.
 	// If neither TAG nor DIGEST is specified, it pulls with the `latest` tag by default.
+	// The TAG can also be a semver constraint, e.g. `PACKAGE_NAME:~1.4` or
+	// `PACKAGE_NAME:>=2.0.0 <3.0.0`, or `PACKAGE_NAME:*` for the latest semver
+	// tag. Only tags that are not valid OCI tags are parsed as constraints. The
+	// tags are listed every period, and the highest tag that satisfies the
+	// constraint is pulled, so the package rolls forward automatically.
 	// Required
 	Image string `json:"image"`
 
@@ -45,14 +50,25 @@ type Oci struct {
 	Period metav1.Duration `json:"period,omitempty"`
 
 	// auth is the type of secret configured for access to the OCI package.
-	// Must be one of gcenode, gcpserviceaccount, or none.
+	// Must be one of gcenode, gcpserviceaccount, k8sserviceaccount, or none.
 	// The validation of this is case-sensitive. Required.
 	//
-	// +kubebuilder:validation:Enum=gcenode;gcpserviceaccount;none
+	// +kubebuilder:validation:Enum=gcenode;gcpserviceaccount;k8sserviceaccount;none
 	Auth configsync.AuthType `json:"auth"`
 
 	// gcpServiceAccountEmail specifies the GCP service account used to annotate
 	// the RootSync/RepoSync controller Kubernetes Service Account.
 	// Note: The field is used when secretType: gcpServiceAccount.
 	GCPServiceAccountEmail string `json:"gcpServiceAccountEmail,omitempty"`
+
+	// tokenExchange specifies how the reconciler Kubernetes Service Account
+	// token is exchanged for credentials to the OCI registry.
+	// Note: The field is used when spec.oci.auth: k8sserviceaccount.
+	// +optional
+	TokenExchange *TokenExchange `json:"tokenExchange,omitempty"`
+
+	// verification specifies how the signature of the image is verified
+	// before it is synced. If unset, the signature is not verified.
+	// +optional
+	Verification *Verification `json:"verification,omitempty"`
 }
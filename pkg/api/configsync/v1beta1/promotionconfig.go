@@ -0,0 +1,64 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Promotion pins the revision of a RootSync/RepoSync to the commit synced by
+// another sync, once that sync has synced the commit without errors for the
+// soak time.
+type Promotion struct {
+	// sourceRef references the sync to follow. Required.
+	SourceRef PromotionSourceRef `json:"sourceRef"`
+
+	// soakTime is how long the sync to follow must have synced a commit without
+	// errors before the commit is promoted, e.g. "1h". Default: 0s.
+	// +optional
+	SoakTime *metav1.Duration `json:"soakTime,omitempty"`
+}
+
+// PromotionSourceRef references the sync to follow, either directly or through
+// a ConfigMap that holds its exported status.
+type PromotionSourceRef struct {
+	// kind is the kind of the referenced object: RootSync, RepoSync, or
+	// ConfigMap. A RootSync is looked up in the config-management-system
+	// namespace. A RepoSync or a ConfigMap is looked up in the namespace of the
+	// RootSync/RepoSync. A ConfigMap holds the commit to follow in the `commit`
+	// key, e.g. the status of a sync in a canary cluster. Required.
+	// +kubebuilder:validation:Enum=RootSync;RepoSync;ConfigMap
+	Kind string `json:"kind"`
+
+	// name is the name of the referenced object. Required.
+	Name string `json:"name"`
+}
+
+// PromotionStatus describes the commits promoted from the sync to follow.
+type PromotionStatus struct {
+	// commit is the last promoted commit, which the reconciler syncs.
+	// +optional
+	Commit string `json:"commit,omitempty"`
+
+	// pendingCommit is the commit synced by the sync to follow, which is
+	// promoted once it has soaked.
+	// +optional
+	PendingCommit string `json:"pendingCommit,omitempty"`
+
+	// pendingSince is when the sync to follow was first seen to have synced
+	// pendingCommit without errors.
+	// +optional
+	PendingSince metav1.Time `json:"pendingSince,omitempty"`
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+// RenderingSpec controls how the hydration-controller runs `kustomize build`.
+type RenderingSpec struct {
+	// enableHelm specifies whether to render the helmCharts field of a
+	// kustomization with the Helm chart inflation generator. Default: true.
+	// +optional
+	EnableHelm *bool `json:"enableHelm,omitempty"`
+
+	// enableAlphaPlugins specifies whether to enable the Kustomize alpha
+	// plugins, including exec and KRM function plugins. Default: true.
+	// +optional
+	EnableAlphaPlugins *bool `json:"enableAlphaPlugins,omitempty"`
+
+	// loadRestrictor controls whether a kustomization may load files from
+	// outside of its own directory.
+	// Must be "LoadRestrictionsRootOnly" or "LoadRestrictionsNone".
+	// Default: LoadRestrictionsRootOnly.
+	//
+	// +kubebuilder:validation:Enum=LoadRestrictionsRootOnly;LoadRestrictionsNone
+	// +optional
+	LoadRestrictor string `json:"loadRestrictor,omitempty"`
+
+	// helmCommand is the path of the Helm binary used to inflate the
+	// helmCharts field of a kustomization. Default: helm.
+	// +optional
+	HelmCommand string `json:"helmCommand,omitempty"`
+}
@@ -35,6 +35,14 @@ type Status struct {
 	// +optional
 	LastSyncedCommit string `json:"lastSyncedCommit,omitempty"`
 
+	// lastHealthyCommit is the most recent hash that was synced without
+	// errors, and whose objects became healthy, while autoRollback is enabled.
+	// It is the commit autoRollback rolls back to. The objects of the commit
+	// are only kept in memory, so a reconciler that restarts cannot roll back
+	// until a commit becomes healthy again.
+	// +optional
+	LastHealthyCommit string `json:"lastHealthyCommit,omitempty"`
+
 	// source contains fields describing the status of a *Sync's source of
 	// truth.
 	// +optional
@@ -49,6 +57,11 @@ type Status struct {
 	// source of truth to the cluster.
 	// +optional
 	Sync SyncStatus `json:"sync,omitempty"`
+
+	// promotion contains fields describing the commits promoted from the sync
+	// referenced by spec.promotion.
+	// +optional
+	Promotion *PromotionStatus `json:"promotion,omitempty"`
 }
 
 // SourceStatus describes the source status of a source-of-truth.
@@ -141,6 +154,11 @@ type SyncStatus struct {
 	// +optional
 	Commit string `json:"commit,omitempty"`
 
+	// sources is a list of the commits of the additional sources in
+	// spec.sources that are synced along with Commit.
+	// +optional
+	Sources []SourceCommit `json:"sources,omitempty"`
+
 	// lastUpdate is the timestamp of when this status was last updated by a
 	// reconciler.
 	// +nullable
@@ -155,6 +173,17 @@ type SyncStatus struct {
 	// errorSummary summarizes the errors encountered during the process of syncing the resources.
 	// +optional
 	ErrorSummary *ErrorSummary `json:"errorSummary,omitempty"`
+
+	// drift is a list of the managed objects that diverged from their
+	// declaration, and that the remediator did not revert because of the
+	// report drift policy.
+	// +optional
+	Drift []ResourceDrift `json:"drift,omitempty"`
+
+	// dryRun is the outcome of the server-side dry-run of the commit, when
+	// spec.override.dryRun is true.
+	// +optional
+	DryRun *DryRunResult `json:"dryRun,omitempty"`
 }
 
 // GitStatus describes the status of a Git source of truth.
@@ -181,6 +210,15 @@ type OciStatus struct {
 	// dir is the absolute path of the directory that contains the local resources.
 	// Default: the root directory of the repository
 	Dir string `json:"dir"`
+
+	// tag is the tag of the image being synced. When the tag of the image is a
+	// semver constraint, it is the highest tag that satisfies the constraint.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// digest is the digest of the image being synced.
+	// +optional
+	Digest string `json:"digest,omitempty"`
 }
 
 // HelmStatus describes the status of a Helm source of truth.
@@ -208,6 +246,10 @@ type ConfigSyncError struct {
 	// errorResources describes the resources associated with this error, if any.
 	// +optional
 	Resources []ResourceRef `json:"errorResources,omitempty"`
+
+	// hint describes how to fix the error, according to the error catalog.
+	// +optional
+	Hint string `json:"hint,omitempty"`
 }
 
 // ErrorSummary summarizes the errors encountered.
@@ -249,6 +291,63 @@ type ResourceRef struct {
 	GVK metav1.GroupVersionKind `json:"gvk,omitempty"`
 }
 
+// ResourceDrift describes a managed object that diverged from its declaration.
+type ResourceDrift struct {
+	// resource is the object that diverged from its declaration.
+	Resource ResourceRef `json:"resource"`
+
+	// operation is the operation that the remediator would run to revert the
+	// drift: create, update, or delete.
+	Operation string `json:"operation"`
+
+	// fields is a list of the declared fields whose values differ on the
+	// cluster. It is empty if the object must be created or deleted.
+	// +optional
+	Fields []FieldDrift `json:"fields,omitempty"`
+
+	// lastUpdate is the timestamp of when the drift was detected.
+	// +nullable
+	// +optional
+	LastUpdate metav1.Time `json:"lastUpdate,omitempty"`
+}
+
+// DryRunResult describes the changes that syncing a commit would make, as
+// computed by a server-side dry-run.
+type DryRunResult struct {
+	// creates is a list of the objects that would be created.
+	// +optional
+	Creates []ResourceRef `json:"creates,omitempty"`
+
+	// updates is a list of the objects whose declared fields would be updated.
+	// +optional
+	Updates []ResourceRef `json:"updates,omitempty"`
+
+	// prunes is a list of the objects that would be deleted, because they are
+	// no longer declared.
+	// +optional
+	Prunes []ResourceRef `json:"prunes,omitempty"`
+
+	// truncated indicates whether the lists were truncated, to keep the
+	// RootSync or RepoSync under the object size limit.
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// FieldDrift describes a field whose value on the cluster differs from its
+// declared value.
+type FieldDrift struct {
+	// path is the path of the field, e.g. .spec.replicas.
+	Path string `json:"path"`
+
+	// declared is the JSON encoded value of the field in the source of truth.
+	Declared string `json:"declared"`
+
+	// actual is the JSON encoded value of the field on the cluster. It is empty
+	// if the field is not set on the cluster.
+	// +optional
+	Actual string `json:"actual,omitempty"`
+}
+
 // SourceType specifies the type of the source of truth.
 type SourceType string
 
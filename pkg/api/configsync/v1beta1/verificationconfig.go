@@ -0,0 +1,45 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import "kpt.dev/configsync/pkg/api/configsync"
+
+// Verification contains the configuration to verify the signature of an OCI
+// image or a Helm chart stored in an OCI registry before it is synced.
+type Verification struct {
+	// provider is the tool used to sign the artifact.
+	// Must be one of cosign or notation. Required.
+	//
+	// +kubebuilder:validation:Enum=cosign;notation
+	Provider configsync.VerificationProvider `json:"provider"`
+
+	// publicKeys is the list of Secrets and ConfigMaps that hold the trusted
+	// keys, in the namespace of the RootSync or RepoSync. Every data entry
+	// holds PEM-encoded public keys for cosign, or PEM-encoded root
+	// certificates for notation. The artifact is synced only if its digest
+	// carries a signature that is valid for one of the keys. Required.
+	PublicKeys []PublicKeyRef `json:"publicKeys"`
+}
+
+// PublicKeyRef references a Secret or a ConfigMap that holds trusted keys.
+type PublicKeyRef struct {
+	// kind is the kind of the referenced object: Secret or ConfigMap. Required.
+	//
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	Kind string `json:"kind"`
+
+	// name is the name of the referenced object. Required.
+	Name string `json:"name"`
+}
@@ -0,0 +1,32 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+// Webhook contains the configuration of the endpoint that accepts source change
+// notifications, such as Git push or OCI registry events, and triggers a new
+// sync without waiting for the next polling period.
+//
+// Each notification is forwarded to the container that fetches the source, so
+// that the source is fetched immediately too. oci-sync and helm-sync serve the
+// same endpoint on the loopback interface. git-sync is sent a SIGHUP, so the
+// containers of the reconciler Pod share their process namespace when the
+// source type is git.
+type Webhook struct {
+	// secretRef holds the name of the Secret used to authenticate webhook
+	// requests. The Secret must contain a `token` key, which is compared with
+	// the bearer token, the `X-Gitlab-Token` header, or used as the HMAC key
+	// to verify the `X-Hub-Signature-256` header of each request. Required.
+	SecretRef *SecretReference `json:"secretRef"`
+}
@@ -18,6 +18,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -30,6 +32,7 @@ import (
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/api/configmanagement"
 	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/applier/stats"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
@@ -77,6 +80,13 @@ type Applier interface {
 	// This method may be called while Destroy is running, to get the set of
 	// errors encounted so far.
 	Errors() status.MultiError
+	// UnhealthyErrors returns an error for each object that failed to
+	// reconcile, or did not reconcile within the reconcile timeout, during the
+	// last apply. These errors are not included in Errors.
+	UnhealthyErrors() status.MultiError
+	// DryRunResult returns the changes that the last apply would have made,
+	// or nil if the applier does not run a server-side dry-run.
+	DryRunResult() *v1beta1.DryRunResult
 }
 
 // Destroyer is a bulk client for deleting all the managed resource objects
@@ -123,6 +133,12 @@ type supervisor struct {
 	syncNamespace string
 	// reconcileTimeout controls the reconcile and prune timeout
 	reconcileTimeout time.Duration
+	// dryRun applies, prunes and deletes the objects with a server-side
+	// dry-run, without mutating the cluster.
+	dryRun bool
+	// driftPolicy is the drift policy of the RootSync/RepoSync. The applier
+	// preserves the drift of the objects with the report drift policy.
+	driftPolicy configsync.DriftPolicy
 
 	// execMux prevents concurrent Apply/Destroy calls
 	execMux sync.Mutex
@@ -131,6 +147,11 @@ type supervisor struct {
 	// errs recieved from the current (if running) or previous Apply/Destroy.
 	// These errors is cleared at the start of the Apply/Destroy methods.
 	errs status.MultiError
+	// unhealthyErrs recieved from the previous Apply, for the objects that did
+	// not become healthy.
+	unhealthyErrs status.MultiError
+	// dryRunResult is the outcome of the previous Apply, if dryRun is true.
+	dryRunResult *v1beta1.DryRunResult
 }
 
 var _ Applier = &supervisor{}
@@ -139,25 +160,27 @@ var _ Supervisor = &supervisor{}
 
 // NewSupervisor constructs either a cluster-level or namespace-level Supervisor,
 // based on the specified scope.
-func NewSupervisor(cs *ClientSet, scope declared.Scope, syncName string, reconcileTimeout time.Duration) (Supervisor, error) {
+func NewSupervisor(cs *ClientSet, scope declared.Scope, syncName string, reconcileTimeout time.Duration, dryRun bool, driftPolicy configsync.DriftPolicy) (Supervisor, error) {
 	if scope == declared.RootReconciler {
-		return NewRootSupervisor(cs, syncName, reconcileTimeout)
+		return NewRootSupervisor(cs, syncName, reconcileTimeout, dryRun, driftPolicy)
 	}
-	return NewNamespaceSupervisor(cs, scope, syncName, reconcileTimeout)
+	return NewNamespaceSupervisor(cs, scope, syncName, reconcileTimeout, dryRun, driftPolicy)
 }
 
 // NewNamespaceSupervisor constructs a Supervisor that can manage resource
 // objects in a single namespace.
-func NewNamespaceSupervisor(cs *ClientSet, namespace declared.Scope, syncName string, reconcileTimeout time.Duration) (Supervisor, error) {
+func NewNamespaceSupervisor(cs *ClientSet, namespace declared.Scope, syncName string, reconcileTimeout time.Duration, dryRun bool, driftPolicy configsync.DriftPolicy) (Supervisor, error) {
 	syncKind := configsync.RepoSyncKind
 	invObj := newInventoryUnstructured(syncKind, syncName, string(namespace), cs.StatusMode)
 	// If the ResourceGroup object exists, annotate the status mode on the
-	// existing object.
-	if err := annotateStatusMode(context.TODO(), cs.Client, invObj, cs.StatusMode); err != nil {
-		klog.Errorf("failed to annotate the ResourceGroup object with the status mode %s", cs.StatusMode)
-		return nil, err
+	// existing object, unless the cluster must not be mutated.
+	if !dryRun {
+		if err := annotateStatusMode(context.TODO(), cs.Client, invObj, cs.StatusMode); err != nil {
+			klog.Errorf("failed to annotate the ResourceGroup object with the status mode %s", cs.StatusMode)
+			return nil, err
+		}
+		klog.Infof("successfully annotate the ResourceGroup object with the status mode %s", cs.StatusMode)
 	}
-	klog.Infof("successfully annotate the ResourceGroup object with the status mode %s", cs.StatusMode)
 	inv, err := wrapInventoryObj(invObj)
 	if err != nil {
 		return nil, err
@@ -170,6 +193,8 @@ func NewNamespaceSupervisor(cs *ClientSet, namespace declared.Scope, syncName st
 		syncName:         syncName,
 		syncNamespace:    string(namespace),
 		reconcileTimeout: reconcileTimeout,
+		dryRun:           dryRun,
+		driftPolicy:      driftPolicy,
 	}
 	klog.V(4).Infof("Namespace Supervisor %s/%s is initialized", namespace, syncName)
 	return a, nil
@@ -177,16 +202,18 @@ func NewNamespaceSupervisor(cs *ClientSet, namespace declared.Scope, syncName st
 
 // NewRootSupervisor constructs a Supervisor that can manage both cluster-level
 // and namespace-level resource objects in a single cluster.
-func NewRootSupervisor(cs *ClientSet, syncName string, reconcileTimeout time.Duration) (Supervisor, error) {
+func NewRootSupervisor(cs *ClientSet, syncName string, reconcileTimeout time.Duration, dryRun bool, driftPolicy configsync.DriftPolicy) (Supervisor, error) {
 	syncKind := configsync.RootSyncKind
 	u := newInventoryUnstructured(syncKind, syncName, configmanagement.ControllerNamespace, cs.StatusMode)
 	// If the ResourceGroup object exists, annotate the status mode on the
-	// existing object.
-	if err := annotateStatusMode(context.TODO(), cs.Client, u, cs.StatusMode); err != nil {
-		klog.Errorf("failed to annotate the ResourceGroup object with the status mode %s", cs.StatusMode)
-		return nil, err
+	// existing object, unless the cluster must not be mutated.
+	if !dryRun {
+		if err := annotateStatusMode(context.TODO(), cs.Client, u, cs.StatusMode); err != nil {
+			klog.Errorf("failed to annotate the ResourceGroup object with the status mode %s", cs.StatusMode)
+			return nil, err
+		}
+		klog.Infof("successfully annotate the ResourceGroup object with the status mode %s", cs.StatusMode)
 	}
-	klog.Infof("successfully annotate the ResourceGroup object with the status mode %s", cs.StatusMode)
 	inv, err := wrapInventoryObj(u)
 	if err != nil {
 		return nil, err
@@ -199,6 +226,8 @@ func NewRootSupervisor(cs *ClientSet, syncName string, reconcileTimeout time.Dur
 		syncName:         syncName,
 		syncNamespace:    string(configmanagement.ControllerNamespace),
 		reconcileTimeout: reconcileTimeout,
+		dryRun:           dryRun,
+		driftPolicy:      driftPolicy,
 	}
 	klog.V(4).Infof("Root Supervisor %s is initialized and synced with the API server", syncName)
 	return a, nil
@@ -487,7 +516,9 @@ func (a *supervisor) applyInner(ctx context.Context, objs []client.Object) (map[
 	// disabledObjs are objects for which the management are disabled
 	// through annotation.
 	enabledObjs, disabledObjs := partitionObjs(objs)
-	if len(disabledObjs) > 0 {
+	if len(disabledObjs) > 0 && a.dryRun {
+		klog.Infof("%v objects would be disabled: %v", len(disabledObjs), core.GKNNs(disabledObjs))
+	} else if len(disabledObjs) > 0 {
 		klog.Infof("%v objects to be disabled: %v", len(disabledObjs), core.GKNNs(disabledObjs))
 		disabledCount, err := a.handleDisabledObjects(ctx, a.inventory, disabledObjs)
 		if err != nil {
@@ -505,6 +536,24 @@ func (a *supervisor) applyInner(ctx context.Context, objs []client.Object) (map[
 		a.addError(err)
 		return nil, a.Errors()
 	}
+	// deletedResources are objects with the report drift policy that were
+	// deleted from the cluster, and are neither applied nor pruned.
+	var deletedResources []*unstructured.Unstructured
+	if hasReportDriftPolicy(resources, a.driftPolicy) {
+		applied, err := a.inventoryObjects(a.inventory)
+		if err != nil {
+			a.addError(err)
+			return nil, a.Errors()
+		}
+		var errs status.MultiError
+		resources, deletedResources, errs = preserveReportedDrift(ctx, a.clientSet.Client, resources, a.driftPolicy, applied)
+		if errs != nil {
+			for _, err := range errs.Errors() {
+				a.addError(err)
+			}
+			return nil, a.Errors()
+		}
+	}
 
 	unknownTypeResources := make(map[core.ID]struct{})
 	options := apply.ApplierOptions{
@@ -529,11 +578,62 @@ func (a *supervisor) applyInner(ctx context.Context, objs []client.Object) (map[
 		// TODO: Switch to "Foreground" after the reconciler-manager finalizer is added.
 		PrunePropagationPolicy: metav1.DeletePropagationBackground,
 	}
+	if a.dryRun {
+		options.DryRunStrategy = common.DryRunServer
+	}
 
 	// Reset shared mapper before each apply to invalidate the discovery cache.
 	// This allows for picking up CRD changes.
 	meta.MaybeResetRESTMapper(a.clientSet.Mapper)
 
+	// A dry-run doesn't wait for the objects to become healthy, so the waves
+	// are applied together.
+	waves := applyWaves(resources)
+	if len(waves) <= 1 || a.dryRun {
+		a.runApply(ctx, resources, options, s, objStatusMap, unknownTypeResources)
+	} else {
+		a.runApplyWaves(ctx, waves, options, s, objStatusMap, unknownTypeResources)
+	}
+	if len(deletedResources) > 0 && !a.dryRun {
+		// The apply dropped them from the inventory.
+		if err := a.addToInventory(a.inventory, asObjects(deletedResources)); err != nil {
+			a.addError(err)
+		}
+	}
+
+	gvks := make(map[schema.GroupVersionKind]struct{})
+	for _, resource := range objs {
+		id := core.IDOf(resource)
+		if _, found := unknownTypeResources[id]; found {
+			continue
+		}
+		gvks[resource.GetObjectKind().GroupVersionKind()] = struct{}{}
+	}
+	a.setUnhealthyErrors(objStatusMap)
+	if a.dryRun {
+		result, err := a.computeDryRunResult(ctx, resources, objStatusMap)
+		if err != nil {
+			a.addError(err)
+		}
+		a.setDryRunResult(result)
+	}
+
+	errs := a.Errors()
+	if errs == nil {
+		klog.V(4).Infof("Apply completed without error: all resources are up to date.")
+	}
+	if s.Empty() {
+		klog.V(4).Infof("Applier made no new progress")
+	} else {
+		klog.Infof("Applier made new progress: %s", s.String())
+		objStatusMap.Log(klog.V(0))
+	}
+	return gvks, errs
+}
+
+// runApply applies the resources with a single run of the kpt applier, and
+// records the events in s and objStatusMap.
+func (a *supervisor) runApply(ctx context.Context, resources []*unstructured.Unstructured, options apply.ApplierOptions, s *stats.SyncStats, objStatusMap ObjectStatusMap, unknownTypeResources map[core.ID]struct{}) {
 	events := a.clientSet.KptApplier.Run(ctx, a.inventory, object.UnstructuredSet(resources), options)
 	for e := range events {
 		switch e.Type {
@@ -579,27 +679,112 @@ func (a *supervisor) applyInner(ctx context.Context, objs []client.Object) (map[
 			klog.Infof("Unhandled event (%s): %v", e.Type, e)
 		}
 	}
+}
 
-	gvks := make(map[schema.GroupVersionKind]struct{})
-	for _, resource := range objs {
-		id := core.IDOf(resource)
-		if _, found := unknownTypeResources[id]; found {
-			continue
+// runApplyWaves applies the waves in order, with a run of the kpt applier for
+// each wave. Each run applies the objects of the wave and of all the previous
+// waves, so that none of them is pruned, and waits for them to become healthy.
+// Objects that are no longer declared are only pruned by the run of the last
+// wave. The next waves are skipped if an object of a wave does not become
+// healthy.
+//
+// Each run replaces the inventory with the objects it applied, so the objects
+// of the previous inventory are added back after every run but the last one.
+// Otherwise the last run would not prune the objects that are no longer
+// declared, and skipping the next waves would orphan them.
+func (a *supervisor) runApplyWaves(ctx context.Context, waves []applyWave, options apply.ApplierOptions, s *stats.SyncStats, objStatusMap ObjectStatusMap, unknownTypeResources map[core.ID]struct{}) {
+	prevInventory, _, err := a.loadInventory(a.inventory)
+	if err != nil {
+		a.addError(err)
+		return
+	}
+	var resources []*unstructured.Unstructured
+	for i, w := range waves {
+		last := i == len(waves)-1
+		klog.Infof("Applying wave %d: %v", w.wave, core.GKNNs(asObjects(w.objects)))
+		resources = append(resources, w.objects...)
+		waveOptions := options
+		waveOptions.NoPrune = !last
+		ws := &stats.WaveStats{
+			Wave:    w.wave,
+			Objects: uint64(len(w.objects)),
+			Events:  stats.NewSyncStats(),
+		}
+		s.Waves = append(s.Waves, ws)
+		a.runApply(ctx, resources, waveOptions, ws.Events, objStatusMap, unknownTypeResources)
+		if last {
+			return
+		}
+		if err := a.unionInventory(a.inventory, prevInventory); err != nil {
+			a.addError(err)
+			return
+		}
+		if unhealthy := unhealthyIDs(w.objects, objStatusMap); len(unhealthy) > 0 {
+			a.addError(WaveError(w.wave, unhealthy))
+			return
 		}
-		gvks[resource.GetObjectKind().GroupVersionKind()] = struct{}{}
 	}
+}
 
-	errs := a.Errors()
-	if errs == nil {
-		klog.V(4).Infof("Apply completed without error: all resources are up to date.")
+// applyWave is a set of objects with the same apply-wave annotation.
+type applyWave struct {
+	wave    int
+	objects []*unstructured.Unstructured
+}
+
+// applyWaves groups the resources by the value of their apply-wave
+// annotation, in ascending order. Resources without the annotation are in
+// wave 0.
+func applyWaves(resources []*unstructured.Unstructured) []applyWave {
+	byWave := make(map[int][]*unstructured.Unstructured)
+	for _, u := range resources {
+		wave := 0
+		if value, found := u.GetAnnotations()[metadata.ApplyWaveAnnotationKey]; found {
+			var err error
+			wave, err = strconv.Atoi(value)
+			if err != nil {
+				// This should never happen, since the parser validates the annotation.
+				klog.Warningf("Invalid %s annotation %q on %v, using wave 0", metadata.ApplyWaveAnnotationKey, value, core.IDOf(u))
+				wave = 0
+			}
+		}
+		byWave[wave] = append(byWave[wave], u)
 	}
-	if s.Empty() {
-		klog.V(4).Infof("Applier made no new progress")
-	} else {
-		klog.Infof("Applier made new progress: %s", s.String())
-		objStatusMap.Log(klog.V(0))
+	var waves []applyWave
+	for wave, objs := range byWave {
+		waves = append(waves, applyWave{wave: wave, objects: objs})
 	}
-	return gvks, errs
+	sort.Slice(waves, func(i, j int) bool {
+		return waves[i].wave < waves[j].wave
+	})
+	return waves
+}
+
+// unhealthyIDs returns the IDs of the objects that were not applied
+// successfully, or did not reconcile successfully.
+func unhealthyIDs(objs []*unstructured.Unstructured, objStatusMap ObjectStatusMap) []core.ID {
+	var ids []core.ID
+	for _, u := range objs {
+		id := core.IDOf(u)
+		objStatus, found := objStatusMap[id]
+		if !found || objStatus == nil ||
+			objStatus.Actuation != actuation.ActuationSucceeded ||
+			objStatus.Reconcile != actuation.ReconcileSucceeded {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+	return ids
+}
+
+func asObjects(objs []*unstructured.Unstructured) []client.Object {
+	result := make([]client.Object, len(objs))
+	for i, u := range objs {
+		result[i] = u
+	}
+	return result
 }
 
 // Errors returns the errors encountered during the last apply or current apply
@@ -613,6 +798,53 @@ func (a *supervisor) Errors() status.MultiError {
 	return status.Append(nil, a.errs)
 }
 
+// UnhealthyErrors returns the errors for the objects that did not become
+// healthy during the last apply.
+// UnhealthyErrors implements the Applier interface.
+func (a *supervisor) UnhealthyErrors() status.MultiError {
+	a.errorMux.RLock()
+	defer a.errorMux.RUnlock()
+
+	// Return a copy to avoid persisting caller modifications
+	return status.Append(nil, a.unhealthyErrs)
+}
+
+// DryRunResult returns the changes that the last apply would have made, or nil
+// if the applier does not run a server-side dry-run.
+// DryRunResult implements the Applier interface.
+func (a *supervisor) DryRunResult() *v1beta1.DryRunResult {
+	a.errorMux.RLock()
+	defer a.errorMux.RUnlock()
+
+	// Return a copy to avoid persisting caller modifications
+	return a.dryRunResult.DeepCopy()
+}
+
+func (a *supervisor) setDryRunResult(result *v1beta1.DryRunResult) {
+	a.errorMux.Lock()
+	defer a.errorMux.Unlock()
+	a.dryRunResult = result
+}
+
+// setUnhealthyErrors records an error for each applied object that failed to
+// reconcile or timed out.
+func (a *supervisor) setUnhealthyErrors(objStatusMap ObjectStatusMap) {
+	var errs status.MultiError
+	for _, reconcileStatus := range []actuation.ReconcileStatus{actuation.ReconcileFailed, actuation.ReconcileTimeout} {
+		ids := objStatusMap.Filter(actuation.ActuationStrategyApply, -1, reconcileStatus)
+		sort.Slice(ids, func(i, j int) bool {
+			return ids[i].String() < ids[j].String()
+		})
+		for _, id := range ids {
+			errs = status.Append(errs, UnhealthyErrorForResource(reconcileStatus, id))
+		}
+	}
+
+	a.errorMux.Lock()
+	defer a.errorMux.Unlock()
+	a.unhealthyErrs = errs
+}
+
 func (a *supervisor) addError(err error) {
 	a.errorMux.Lock()
 	defer a.errorMux.Unlock()
@@ -630,6 +862,7 @@ func (a *supervisor) invalidateErrors() {
 	defer a.errorMux.Unlock()
 
 	a.errs = nil
+	a.unhealthyErrs = nil
 }
 
 // destroyInner triggers a kpt live destroy library call to destroy a set of resources.
@@ -649,6 +882,9 @@ func (a *supervisor) destroyInner(ctx context.Context) status.MultiError {
 		// are deleted before the Namespace that contains them.
 		DeletePropagationPolicy: metav1.DeletePropagationForeground,
 	}
+	if a.dryRun {
+		options.DryRunStrategy = common.DryRunServer
+	}
 
 	// Reset shared mapper before each destroy to invalidate the discovery cache.
 	// This allows for picking up CRD changes.
@@ -785,23 +1021,72 @@ func (a *supervisor) handleDisabledObjects(ctx context.Context, rg *live.Invento
 	return disabledCount, errs
 }
 
-// removeFromInventory removes the specified objects from the inventory, if it
-// exists.
-func (a *supervisor) removeFromInventory(rg *live.InventoryResourceGroup, objs []client.Object) error {
+// loadInventory returns the objects in the inventory, and false if the
+// inventory does not exist.
+func (a *supervisor) loadInventory(rg *live.InventoryResourceGroup) (object.ObjMetadataSet, bool, error) {
 	clusterInv, err := a.clientSet.InvClient.GetClusterInventoryInfo(rg)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 	if clusterInv == nil {
-		// If inventory does not exist, there is nothing to remove
-		return nil
+		return nil, false, nil
 	}
 	wrappedInv, err := wrapInventoryObj(clusterInv)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
-	oldObjs, err := wrappedInv.Load()
+	objs, err := wrappedInv.Load()
+	if err != nil {
+		return nil, false, err
+	}
+	return objs, true, nil
+}
+
+// inventoryObjects returns the set of objects in the inventory.
+func (a *supervisor) inventoryObjects(rg *live.InventoryResourceGroup) (map[object.ObjMetadata]bool, error) {
+	objs, _, err := a.loadInventory(rg)
 	if err != nil {
+		return nil, err
+	}
+	result := make(map[object.ObjMetadata]bool, len(objs))
+	for _, obj := range objs {
+		result[obj] = true
+	}
+	return result, nil
+}
+
+// addToInventory adds the specified objects to the inventory, if it exists.
+func (a *supervisor) addToInventory(rg *live.InventoryResourceGroup, objs []client.Object) error {
+	var added object.ObjMetadataSet
+	for _, obj := range objs {
+		added = append(added, ObjMetaFromObject(obj))
+	}
+	return a.unionInventory(rg, added)
+}
+
+// unionInventory adds the specified object metadata to the inventory, if it
+// exists.
+func (a *supervisor) unionInventory(rg *live.InventoryResourceGroup, added object.ObjMetadataSet) error {
+	if len(added) == 0 {
+		return nil
+	}
+	oldObjs, found, err := a.loadInventory(rg)
+	if err != nil || !found {
+		return err
+	}
+	newObjs := oldObjs.Union(added)
+	if err := rg.Store(newObjs, nil); err != nil {
+		return err
+	}
+	return a.clientSet.InvClient.Replace(rg, newObjs, nil, common.DryRunNone)
+}
+
+// removeFromInventory removes the specified objects from the inventory, if it
+// exists.
+func (a *supervisor) removeFromInventory(rg *live.InventoryResourceGroup, objs []client.Object) error {
+	oldObjs, found, err := a.loadInventory(rg)
+	if err != nil || !found {
+		// If inventory does not exist, there is nothing to remove
 		return err
 	}
 	newObjs := removeFrom(oldObjs, objs)
@@ -829,6 +1114,10 @@ func (a *supervisor) abandonObject(ctx context.Context, obj client.Object) error
 		}
 		return err
 	}
+	if a.dryRun {
+		klog.Infof("Object would be abandoned: %s", core.IDOf(obj))
+		return nil
+	}
 	klog.Infof("Abandoning object: %s", core.IDOf(obj))
 	if metadata.HasConfigSyncMetadata(uObj) {
 		// Use minimal before & after objects to simplify DeepCopy and building
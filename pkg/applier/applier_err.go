@@ -58,6 +58,23 @@ func SkipErrorForResource(err error, id core.ID, strategy actuation.ActuationStr
 		strings.ToLower(strategy.String()), id, err)).Build()
 }
 
+// UnhealthyErrorForResource indicates that the given resource was applied, but
+// did not become healthy.
+func UnhealthyErrorForResource(reconcileStatus actuation.ReconcileStatus, id core.ID) status.Error {
+	reason := "failed to reconcile"
+	if reconcileStatus == actuation.ReconcileTimeout {
+		reason = "did not reconcile before the reconcile timeout"
+	}
+	return applierErrorBuilder.Sprintf("%v was applied but is not healthy: %s", id, reason).Build()
+}
+
+// WaveError indicates that the applier skipped the apply waves after the
+// given wave, because some objects of the wave did not become healthy.
+func WaveError(wave int, ids []core.ID) status.Error {
+	return applierErrorBuilder.Sprintf("skipped the apply waves after wave %d, because %d objects of wave %d did not become healthy: %v",
+		wave, len(ids), wave, ids).Build()
+}
+
 // largeResourceGroupError indicates that the source repo has too many objects
 // to manage with a single resource group.
 func largeResourceGroupError(err error, id core.ID) status.Error {
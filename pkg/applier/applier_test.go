@@ -26,8 +26,11 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/applier/stats"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
@@ -51,6 +54,8 @@ import (
 
 type fakeKptApplier struct {
 	events []event.Event
+	// options are the options of the last run.
+	options apply.ApplierOptions
 }
 
 var _ KptApplier = &fakeKptApplier{}
@@ -61,7 +66,8 @@ func newFakeKptApplier(events []event.Event) *fakeKptApplier {
 	}
 }
 
-func (a *fakeKptApplier) Run(_ context.Context, _ inventory.Info, _ object.UnstructuredSet, _ apply.ApplierOptions) <-chan event.Event {
+func (a *fakeKptApplier) Run(_ context.Context, _ inventory.Info, _ object.UnstructuredSet, options apply.ApplierOptions) <-chan event.Event {
+	a.options = options
 	events := make(chan event.Event, len(a.events))
 	go func() {
 		for _, e := range a.events {
@@ -321,7 +327,7 @@ func TestApply(t *testing.T) {
 				Mapper:     fakeClient.RESTMapper(),
 				// TODO: Add tests to cover status mode
 			}
-			applier, err := NewNamespaceSupervisor(cs, syncScope, syncName, 5*time.Minute)
+			applier, err := NewNamespaceSupervisor(cs, syncScope, syncName, 5*time.Minute, false, configsync.DriftPolicyRemediate)
 			require.NoError(t, err)
 
 			gvks, errs := applier.Apply(context.Background(), objs)
@@ -598,12 +604,237 @@ func TestProcessWaitEvent(t *testing.T) {
 	testutil.AssertEqual(t, expectedObjStatusMap, objStatusMap, "expected object status to match")
 }
 
+func TestApplyUnhealthyErrors(t *testing.T) {
+	syncScope := declared.Scope("test-namespace")
+	syncName := "rs"
+
+	deploymentObj := newDeploymentObj()
+	deploymentID := object.UnstructuredToObjMetadata(deploymentObj)
+	testObj := newTestObj("test-1")
+	testID := object.UnstructuredToObjMetadata(testObj)
+	testObj2 := newTestObj("test-2")
+	testID2 := object.UnstructuredToObjMetadata(testObj2)
+
+	rsObj := &unstructured.Unstructured{}
+	rsObj.SetGroupVersionKind(kinds.RepoSyncV1Beta1())
+	rsObj.SetNamespace(string(syncScope))
+	rsObj.SetName(syncName)
+	fakeClient := testingfake.NewClient(t, core.Scheme, rsObj)
+	cs := &ClientSet{
+		KptApplier: newFakeKptApplier([]event.Event{
+			formApplyEvent(event.ApplySuccessful, deploymentObj, nil),
+			formApplyEvent(event.ApplySuccessful, testObj, nil),
+			formApplyEvent(event.ApplySuccessful, testObj2, nil),
+			formWaitEvent(event.ReconcileTimeout, &testID),
+			formWaitEvent(event.ReconcileSuccessful, &testID2),
+			formWaitEvent(event.ReconcileFailed, &deploymentID),
+		}),
+		Client: fakeClient,
+		Mapper: fakeClient.RESTMapper(),
+	}
+	applier, err := NewNamespaceSupervisor(cs, syncScope, syncName, 5*time.Minute, false, configsync.DriftPolicyRemediate)
+	require.NoError(t, err)
+
+	_, errs := applier.Apply(context.Background(), []client.Object{deploymentObj, testObj, testObj2})
+	assert.Nil(t, errs, "expected objects that did not become healthy NOT to be reported by Apply")
+
+	expectedErrs := status.Append(
+		UnhealthyErrorForResource(actuation.ReconcileFailed, idFrom(deploymentID)),
+		UnhealthyErrorForResource(actuation.ReconcileTimeout, idFrom(testID)))
+	testutil.AssertEqual(t, expectedErrs, applier.UnhealthyErrors(), "expected unhealthy errors to match")
+}
+
+// fakeInventoryClient is an inventory.FakeClient which also returns the
+// inventory object holding its objects.
+type fakeInventoryClient struct {
+	*inventory.FakeClient
+}
+
+var _ inventory.Client = &fakeInventoryClient{}
+
+func (c *fakeInventoryClient) GetClusterInventoryInfo(inv inventory.Info) (*unstructured.Unstructured, error) {
+	rg, err := wrapInventoryObj(newInventoryUnstructured(configsync.RepoSyncKind, inv.Name(), inv.Namespace(), StatusEnabled))
+	if err != nil {
+		return nil, err
+	}
+	if err := rg.Store(c.Objs, nil); err != nil {
+		return nil, err
+	}
+	return rg.GetObject()
+}
+
+// wavesKptApplier records the objects and the options of each run, and
+// reports every object as applied, and as reconciled unless it is unhealthy.
+// Like the cli-utils applier, each run prunes the objects of the inventory
+// that are not applied, unless NoPrune is set, and then replaces the inventory
+// with the applied objects.
+type wavesKptApplier struct {
+	invClient *fakeInventoryClient
+	unhealthy map[object.ObjMetadata]bool
+	runs      [][]string
+	noPrune   []bool
+	pruned    []string
+}
+
+var _ KptApplier = &wavesKptApplier{}
+
+func (a *wavesKptApplier) Run(_ context.Context, _ inventory.Info, objs object.UnstructuredSet, options apply.ApplierOptions) <-chan event.Event {
+	var names []string
+	var applied object.ObjMetadataSet
+	events := make(chan event.Event, 2*len(objs))
+	for _, obj := range objs {
+		names = append(names, obj.GetName())
+		id := object.UnstructuredToObjMetadata(obj)
+		applied = append(applied, id)
+		events <- formApplyEvent(event.ApplySuccessful, obj, nil)
+		if a.unhealthy[id] {
+			events <- formWaitEvent(event.ReconcileTimeout, &id)
+		} else {
+			events <- formWaitEvent(event.ReconcileSuccessful, &id)
+		}
+	}
+	close(events)
+	if !options.NoPrune {
+		for _, id := range a.invClient.Objs.Diff(applied) {
+			a.pruned = append(a.pruned, id.Name)
+		}
+	}
+	a.invClient.Objs = applied
+	a.runs = append(a.runs, names)
+	a.noPrune = append(a.noPrune, options.NoPrune)
+	return events
+}
+
+func TestApplyWaves(t *testing.T) {
+	syncScope := declared.Scope("test-namespace")
+	syncName := "rs"
+
+	crdObj := newTestObj("crd")
+	crdObj.SetAnnotations(map[string]string{metadata.ApplyWaveAnnotationKey: "-1"})
+	crdID := object.UnstructuredToObjMetadata(crdObj)
+	defaultObj := newTestObj("default")
+	appObj := newTestObj("app")
+	appObj.SetAnnotations(map[string]string{metadata.ApplyWaveAnnotationKey: "1"})
+	// The object was declared by the previous commit only.
+	staleID := object.UnstructuredToObjMetadata(newTestObj("stale"))
+	prevInventory := object.ObjMetadataSet{
+		crdID,
+		object.UnstructuredToObjMetadata(defaultObj),
+		object.UnstructuredToObjMetadata(appObj),
+		staleID,
+	}
+
+	testCases := []struct {
+		name          string
+		unhealthy     map[object.ObjMetadata]bool
+		wantRuns      [][]string
+		wantNoPrune   []bool
+		wantPruned    []string
+		wantInventory object.ObjMetadataSet
+		wantErrs      status.MultiError
+	}{
+		{
+			name:          "all waves are applied in order",
+			wantRuns:      [][]string{{"crd"}, {"crd", "default"}, {"crd", "default", "app"}},
+			wantNoPrune:   []bool{true, true, false},
+			wantPruned:    []string{"stale"},
+			wantInventory: prevInventory.Diff(object.ObjMetadataSet{staleID}),
+		},
+		{
+			name:          "later waves are skipped after an unhealthy wave",
+			unhealthy:     map[object.ObjMetadata]bool{crdID: true},
+			wantRuns:      [][]string{{"crd"}},
+			wantNoPrune:   []bool{true},
+			wantInventory: prevInventory,
+			wantErrs:      WaveError(-1, []core.ID{idFrom(crdID)}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rsObj := &unstructured.Unstructured{}
+			rsObj.SetGroupVersionKind(kinds.RepoSyncV1Beta1())
+			rsObj.SetNamespace(string(syncScope))
+			rsObj.SetName(syncName)
+			fakeClient := testingfake.NewClient(t, core.Scheme, rsObj)
+			invClient := &fakeInventoryClient{FakeClient: inventory.NewFakeClient(prevInventory)}
+			kptApplier := &wavesKptApplier{invClient: invClient, unhealthy: tc.unhealthy}
+			cs := &ClientSet{
+				KptApplier: kptApplier,
+				Client:     fakeClient,
+				Mapper:     fakeClient.RESTMapper(),
+				InvClient:  invClient,
+			}
+			applier, err := NewNamespaceSupervisor(cs, syncScope, syncName, 5*time.Minute, false, configsync.DriftPolicyRemediate)
+			require.NoError(t, err)
+
+			_, errs := applier.Apply(context.Background(), []client.Object{appObj, defaultObj, crdObj})
+			testutil.AssertEqual(t, tc.wantErrs, errs, "expected errors to match")
+			testutil.AssertEqual(t, tc.wantRuns, kptApplier.runs, "expected the objects of each run to match")
+			testutil.AssertEqual(t, tc.wantNoPrune, kptApplier.noPrune, "expected NoPrune of each run to match")
+			testutil.AssertEqual(t, tc.wantPruned, kptApplier.pruned, "expected the pruned objects to match")
+			testutil.AssertEqual(t, tc.wantInventory.ToMap(), invClient.Objs.ToMap(), "expected the inventory objects to match")
+		})
+	}
+}
+
 func indent(in string, indentation uint) string {
 	indent := strings.Repeat("\t", int(indentation))
 	lines := strings.Split(in, "\n")
 	return indent + strings.Join(lines, fmt.Sprintf("\n%s", indent))
 }
 
+func TestApplyDryRun(t *testing.T) {
+	syncScope := declared.Scope("test-namespace")
+	syncName := "rs"
+
+	createdObj := fake.UnstructuredObject(kinds.ConfigMap(),
+		core.Namespace("test-namespace"), core.Name("created"))
+	updatedObj := fake.UnstructuredObject(kinds.ConfigMap(),
+		core.Namespace("test-namespace"), core.Name("updated"), core.Label("app", "web"))
+	unchangedObj := fake.UnstructuredObject(kinds.ConfigMap(),
+		core.Namespace("test-namespace"), core.Name("unchanged"), core.Label("app", "web"))
+	prunedObj := fake.UnstructuredObject(kinds.ConfigMap(),
+		core.Namespace("test-namespace"), core.Name("pruned"))
+
+	rsObj := &unstructured.Unstructured{}
+	rsObj.SetGroupVersionKind(kinds.RepoSyncV1Beta1())
+	rsObj.SetNamespace(string(syncScope))
+	rsObj.SetName(syncName)
+	fakeClient := testingfake.NewClient(t, core.Scheme, rsObj,
+		fake.ConfigMapObject(core.Namespace("test-namespace"), core.Name("updated")),
+		fake.ConfigMapObject(core.Namespace("test-namespace"), core.Name("unchanged"), core.Label("app", "web")),
+		fake.ConfigMapObject(core.Namespace("test-namespace"), core.Name("pruned")))
+	kptApplier := newFakeKptApplier([]event.Event{
+		formApplyEvent(event.ApplySuccessful, createdObj, nil),
+		formApplyEvent(event.ApplySuccessful, updatedObj, nil),
+		formApplyEvent(event.ApplySuccessful, unchangedObj, nil),
+		formPruneEvent(event.PruneSuccessful, prunedObj, nil),
+	})
+	cs := &ClientSet{
+		KptApplier: kptApplier,
+		Client:     fakeClient,
+		Mapper:     fakeClient.RESTMapper(),
+	}
+	applier, err := NewNamespaceSupervisor(cs, syncScope, syncName, 5*time.Minute, true, configsync.DriftPolicyRemediate)
+	require.NoError(t, err)
+
+	_, errs := applier.Apply(context.Background(), []client.Object{createdObj, updatedObj, unchangedObj})
+	assert.Nil(t, errs)
+	assert.Equal(t, common.DryRunServer, kptApplier.options.DryRunStrategy)
+
+	expected := &v1beta1.DryRunResult{
+		Creates: []v1beta1.ResourceRef{status.ToResourceRef(createdObj)},
+		Updates: []v1beta1.ResourceRef{status.ToResourceRef(updatedObj)},
+		Prunes: []v1beta1.ResourceRef{{
+			Name:      "pruned",
+			Namespace: "test-namespace",
+			GVK:       metav1.GroupVersionKind{Kind: "ConfigMap"},
+		}},
+	}
+	testutil.AssertEqual(t, expected, applier.DryRunResult(), "expected dry-run result to match")
+}
+
 func newDeploymentObj() *unstructured.Unstructured {
 	return fake.UnstructuredObject(kinds.Deployment(),
 		core.Namespace("test-namespace"), core.Name("random-name"))
@@ -0,0 +1,172 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/status"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// objectDriftPolicy returns the value of the drift policy annotation of obj,
+// if any, or the drift policy of the RootSync/RepoSync.
+func objectDriftPolicy(obj client.Object, syncPolicy configsync.DriftPolicy) configsync.DriftPolicy {
+	if policy, found := obj.GetAnnotations()[metadata.DriftPolicyAnnotationKey]; found {
+		return configsync.DriftPolicy(policy)
+	}
+	return syncPolicy
+}
+
+// hasReportDriftPolicy returns true if any of the objects only has its drift
+// reported.
+func hasReportDriftPolicy(objs []*unstructured.Unstructured, syncPolicy configsync.DriftPolicy) bool {
+	for _, obj := range objs {
+		if objectDriftPolicy(obj, syncPolicy) == configsync.DriftPolicyReport {
+			return true
+		}
+	}
+	return false
+}
+
+// preserveReportedDrift keeps the applier from reverting the drift of the
+// objects with the report drift policy, which the remediator only reports.
+//
+// Each of these objects is annotated with the hash of its declaration. If the
+// object was applied before, and its declaration has not changed since, the
+// object is applied with the values its declared fields have on the cluster,
+// so that the apply does not change it. An object deleted from the cluster is
+// not applied, and is returned separately, so that it can be kept in the
+// inventory. Other objects are returned unchanged.
+//
+// applied is the set of objects in the inventory before the apply.
+func preserveReportedDrift(ctx context.Context, c client.Client, resources []*unstructured.Unstructured, syncPolicy configsync.DriftPolicy, applied map[object.ObjMetadata]bool) ([]*unstructured.Unstructured, []*unstructured.Unstructured, status.MultiError) {
+	var toApply, deleted []*unstructured.Unstructured
+	var errs status.MultiError
+	for _, u := range resources {
+		if objectDriftPolicy(u, syncPolicy) != configsync.DriftPolicyReport {
+			toApply = append(toApply, u)
+			continue
+		}
+		hash, err := declarationHash(u)
+		if err != nil {
+			errs = status.Append(errs, status.InternalErrorBuilder.Wrap(err).
+				Sprintf("hashing the declaration of %v", core.IDOf(u)).Build())
+			continue
+		}
+		core.SetAnnotation(u, metadata.DeclarationHashKey, hash)
+		if !applied[ObjMetaFromObject(u)] {
+			// The object is new, or adopted.
+			toApply = append(toApply, u)
+			continue
+		}
+		actual := &unstructured.Unstructured{}
+		actual.SetGroupVersionKind(u.GroupVersionKind())
+		err = c.Get(ctx, client.ObjectKeyFromObject(u), actual)
+		switch {
+		case apierrors.IsNotFound(err):
+			klog.V(3).Infof("Applier not recreating object %v with the %s drift policy", core.IDOf(u), configsync.DriftPolicyReport)
+			deleted = append(deleted, u)
+		case meta.IsNoMatchError(err):
+			// The type is not registered yet, so neither is the object.
+			toApply = append(toApply, u)
+		case err != nil:
+			errs = status.Append(errs, ErrorForResource(err, core.IDOf(u)))
+		case actual.GetAnnotations()[metadata.DeclarationHashKey] != hash:
+			// The declaration changed since the last apply.
+			toApply = append(toApply, u)
+		default:
+			klog.V(3).Infof("Applier preserving object %v with the %s drift policy", core.IDOf(u), configsync.DriftPolicyReport)
+			toApply = append(toApply, preservedObject(u, actual))
+		}
+	}
+	return toApply, deleted, errs
+}
+
+// declarationHash returns the hash of the declaration of an object, which does
+// not change with the commit it is synced from.
+func declarationHash(u *unstructured.Unstructured) (string, error) {
+	u = u.DeepCopy()
+	core.RemoveAnnotations(u, metadata.SyncTokenAnnotationKey, metadata.DeclarationHashKey)
+	data, err := json.Marshal(u.Object)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// preservedObject returns the declared object with the values its declared
+// fields have on the cluster. Declared fields missing on the cluster are left
+// out. The Config Sync metadata stays as declared.
+func preservedObject(declared, actual *unstructured.Unstructured) *unstructured.Unstructured {
+	result := &unstructured.Unstructured{Object: preservedFields(declared.Object, actual.Object)}
+	result.SetGroupVersionKind(declared.GroupVersionKind())
+	result.SetName(declared.GetName())
+	result.SetNamespace(declared.GetNamespace())
+
+	annotations := result.GetAnnotations()
+	for k, v := range declared.GetAnnotations() {
+		if metadata.IsConfigSyncAnnotationKey(k) {
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[k] = v
+		}
+	}
+	result.SetAnnotations(annotations)
+	labels := result.GetLabels()
+	for k, v := range declared.GetLabels() {
+		if metadata.IsConfigSyncLabelKey(k) {
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[k] = v
+		}
+	}
+	result.SetLabels(labels)
+	return result
+}
+
+// preservedFields returns the fields of declared with their values in actual.
+// Maps are merged field by field, and other values are taken as a whole.
+func preservedFields(declared, actual map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(declared))
+	for k, declaredValue := range declared {
+		actualValue, found := actual[k]
+		if !found {
+			continue
+		}
+		declaredMap, declaredIsMap := declaredValue.(map[string]interface{})
+		actualMap, actualIsMap := actualValue.(map[string]interface{})
+		if declaredIsMap && actualIsMap {
+			result[k] = preservedFields(declaredMap, actualMap)
+		} else {
+			result[k] = runtime.DeepCopyJSONValue(actualValue)
+		}
+	}
+	return result
+}
@@ -0,0 +1,199 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
+	testingfake "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+	"kpt.dev/configsync/pkg/testing/fake"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func driftConfigMap(name string, data map[string]interface{}, opts ...core.MetaMutator) *unstructured.Unstructured {
+	opts = append([]core.MetaMutator{core.Namespace("test-namespace"), core.Name(name)}, opts...)
+	u := fake.UnstructuredObject(kinds.ConfigMap(), opts...)
+	if data != nil {
+		u.Object["data"] = data
+	}
+	return u
+}
+
+func TestPreserveReportedDrift(t *testing.T) {
+	declared := func(name string, opts ...core.MetaMutator) *unstructured.Unstructured {
+		opts = append([]core.MetaMutator{
+			core.Annotation(metadata.SyncTokenAnnotationKey, "def456"),
+			core.Label("app", "declared"),
+		}, opts...)
+		return driftConfigMap(name, map[string]interface{}{"key": "declared", "other": "declared"}, opts...)
+	}
+	hash := func(u *unstructured.Unstructured) string {
+		result, err := declarationHash(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	withHash := func(u *unstructured.Unstructured) *unstructured.Unstructured {
+		u = u.DeepCopy()
+		core.SetAnnotation(u, metadata.DeclarationHashKey, hash(u))
+		return u
+	}
+	// live returns the object on the cluster, last applied at commit abc123,
+	// with the given declaration hash, a drifted value, a field removed, and a
+	// field that is not declared.
+	live := func(name, declarationHash string) *unstructured.Unstructured {
+		return driftConfigMap(name, map[string]interface{}{"key": "drifted", "extra": "live"},
+			core.Annotation(metadata.SyncTokenAnnotationKey, "abc123"),
+			core.Annotation(metadata.DeclarationHashKey, declarationHash),
+			core.Label("app", "live"))
+	}
+	applied := func(names ...string) map[object.ObjMetadata]bool {
+		result := map[object.ObjMetadata]bool{}
+		for _, name := range names {
+			result[ObjMetaFromObject(driftConfigMap(name, nil))] = true
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name        string
+		syncPolicy  configsync.DriftPolicy
+		resources   []*unstructured.Unstructured
+		applied     map[object.ObjMetadata]bool
+		serverObjs  []client.Object
+		wantApply   []*unstructured.Unstructured
+		wantDeleted []*unstructured.Unstructured
+	}{
+		{
+			name:       "remediate policy",
+			syncPolicy: configsync.DriftPolicyRemediate,
+			resources:  []*unstructured.Unstructured{declared("cm")},
+			applied:    applied("cm"),
+			serverObjs: []client.Object{live("cm", hash(declared("cm")))},
+			wantApply:  []*unstructured.Unstructured{declared("cm")},
+		},
+		{
+			name:       "annotation overrides the report policy of the sync",
+			syncPolicy: configsync.DriftPolicyReport,
+			resources: []*unstructured.Unstructured{
+				declared("cm", core.Annotation(metadata.DriftPolicyAnnotationKey, string(configsync.DriftPolicyRemediate))),
+			},
+			applied:    applied("cm"),
+			serverObjs: []client.Object{live("cm", "")},
+			wantApply: []*unstructured.Unstructured{
+				declared("cm", core.Annotation(metadata.DriftPolicyAnnotationKey, string(configsync.DriftPolicyRemediate))),
+			},
+		},
+		{
+			name:       "new object is applied",
+			syncPolicy: configsync.DriftPolicyReport,
+			resources:  []*unstructured.Unstructured{declared("cm")},
+			applied:    applied(),
+			serverObjs: []client.Object{live("cm", hash(declared("cm")))},
+			wantApply:  []*unstructured.Unstructured{withHash(declared("cm"))},
+		},
+		{
+			name:       "changed declaration is applied",
+			syncPolicy: configsync.DriftPolicyReport,
+			resources:  []*unstructured.Unstructured{declared("cm")},
+			applied:    applied("cm"),
+			serverObjs: []client.Object{live("cm", "old-hash")},
+			wantApply:  []*unstructured.Unstructured{withHash(declared("cm"))},
+		},
+		{
+			name:       "drift is preserved",
+			syncPolicy: configsync.DriftPolicyReport,
+			resources:  []*unstructured.Unstructured{declared("cm")},
+			applied:    applied("cm"),
+			serverObjs: []client.Object{live("cm", hash(declared("cm")))},
+			wantApply: []*unstructured.Unstructured{
+				driftConfigMap("cm", map[string]interface{}{"key": "drifted"},
+					core.Annotation(metadata.SyncTokenAnnotationKey, "def456"),
+					core.Annotation(metadata.DeclarationHashKey, hash(declared("cm"))),
+					core.Label("app", "live")),
+			},
+		},
+		{
+			name:       "drift is preserved with the annotation",
+			syncPolicy: configsync.DriftPolicyRemediate,
+			resources: []*unstructured.Unstructured{
+				declared("cm", core.Annotation(metadata.DriftPolicyAnnotationKey, string(configsync.DriftPolicyReport))),
+			},
+			applied: applied("cm"),
+			serverObjs: []client.Object{
+				live("cm", hash(declared("cm", core.Annotation(metadata.DriftPolicyAnnotationKey, string(configsync.DriftPolicyReport))))),
+			},
+			wantApply: []*unstructured.Unstructured{
+				driftConfigMap("cm", map[string]interface{}{"key": "drifted"},
+					core.Annotation(metadata.SyncTokenAnnotationKey, "def456"),
+					core.Annotation(metadata.DriftPolicyAnnotationKey, string(configsync.DriftPolicyReport)),
+					core.Annotation(metadata.DeclarationHashKey, hash(declared("cm", core.Annotation(metadata.DriftPolicyAnnotationKey, string(configsync.DriftPolicyReport))))),
+					core.Label("app", "live")),
+			},
+		},
+		{
+			name:        "deleted object is not recreated",
+			syncPolicy:  configsync.DriftPolicyReport,
+			resources:   []*unstructured.Unstructured{declared("cm")},
+			applied:     applied("cm"),
+			wantDeleted: []*unstructured.Unstructured{withHash(declared("cm"))},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := testingfake.NewClient(t, core.Scheme, tc.serverObjs...)
+			gotApply, gotDeleted, errs := preserveReportedDrift(context.Background(), fakeClient, tc.resources, tc.syncPolicy, tc.applied)
+			if errs != nil {
+				t.Fatal(errs)
+			}
+			if diff := cmp.Diff(tc.wantApply, gotApply); diff != "" {
+				t.Errorf("preserveReportedDrift() got diff in the objects to apply (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantDeleted, gotDeleted); diff != "" {
+				t.Errorf("preserveReportedDrift() got diff in the deleted objects (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeclarationHash(t *testing.T) {
+	obj := driftConfigMap("cm", map[string]interface{}{"key": "value"},
+		core.Annotation(metadata.SyncTokenAnnotationKey, "abc123"))
+	sameAtOtherCommit := driftConfigMap("cm", map[string]interface{}{"key": "value"},
+		core.Annotation(metadata.SyncTokenAnnotationKey, "def456"))
+	changed := driftConfigMap("cm", map[string]interface{}{"key": "changed"},
+		core.Annotation(metadata.SyncTokenAnnotationKey, "abc123"))
+
+	hash, err := declarationHash(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other, _ := declarationHash(sameAtOtherCommit); other != hash {
+		t.Errorf("declarationHash() changed with the commit: %s != %s", other, hash)
+	}
+	if other, _ := declarationHash(changed); other == hash {
+		t.Errorf("declarationHash() did not change with the declaration")
+	}
+}
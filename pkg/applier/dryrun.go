@@ -0,0 +1,115 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/diff"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/status"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// computeDryRunResult classifies the objects that the server-side dry-run
+// accepted into the objects that would be created, updated and pruned.
+//
+// The dry-run does not report whether an applied object changed, so each
+// applied object is compared with the object on the cluster. Objects that do
+// not exist yet would be created, and objects with declared fields that differ
+// from the cluster would be updated.
+func (a *supervisor) computeDryRunResult(ctx context.Context, resources []*unstructured.Unstructured, objStatusMap ObjectStatusMap) (*v1beta1.DryRunResult, status.Error) {
+	result := &v1beta1.DryRunResult{}
+	declared := make(map[core.ID]*unstructured.Unstructured, len(resources))
+	for _, u := range resources {
+		declared[core.IDOf(u)] = u
+	}
+	for _, id := range sortIDs(objStatusMap.Filter(actuation.ActuationStrategyApply, actuation.ActuationSucceeded, -1)) {
+		u, found := declared[id]
+		if !found {
+			continue
+		}
+		created, updated, err := a.dryRunChange(ctx, u)
+		if err != nil {
+			return result, err
+		}
+		if created {
+			result.Creates = append(result.Creates, status.ToResourceRef(u))
+		} else if updated {
+			result.Updates = append(result.Updates, status.ToResourceRef(u))
+		}
+	}
+	for _, id := range sortIDs(objStatusMap.Filter(actuation.ActuationStrategyDelete, actuation.ActuationSucceeded, -1)) {
+		result.Prunes = append(result.Prunes, idToResourceRef(id))
+	}
+	return result, nil
+}
+
+// dryRunChange returns whether applying the declared object would create the
+// object, or update the object on the cluster.
+func (a *supervisor) dryRunChange(ctx context.Context, declared *unstructured.Unstructured) (created, updated bool, _ status.Error) {
+	actual := &unstructured.Unstructured{}
+	actual.SetGroupVersionKind(declared.GroupVersionKind())
+	err := a.clientSet.Client.Get(ctx, client.ObjectKeyFromObject(declared), actual)
+	switch {
+	case apierrors.IsNotFound(err), meta.IsNoMatchError(err):
+		// The object, or its type, would be created by the apply.
+		return true, false, nil
+	case err != nil:
+		return false, false, status.APIServerError(err, "failed to get object for dry-run", declared)
+	}
+
+	// The sync token and declared fields change with every commit, and are not
+	// part of the declared configuration.
+	declaredCopy := declared.DeepCopy()
+	core.RemoveAnnotations(declaredCopy, metadata.SyncTokenAnnotationKey, metadata.DeclaredFieldsKey)
+	if len(declaredCopy.GetAnnotations()) == 0 {
+		declaredCopy.SetAnnotations(nil)
+	}
+	fieldDiffs, diffErr := diff.Diff{Declared: declaredCopy, Actual: actual}.FieldDiffs()
+	if diffErr != nil {
+		return false, false, diffErr
+	}
+	return false, len(fieldDiffs) > 0, nil
+}
+
+// idToResourceRef returns the ResourceRef for an object that is no longer
+// declared, and has no source path.
+func idToResourceRef(id core.ID) v1beta1.ResourceRef {
+	return v1beta1.ResourceRef{
+		Name:      id.Name,
+		Namespace: id.Namespace,
+		GVK: metav1.GroupVersionKind{
+			Group: id.Group,
+			Kind:  id.Kind,
+		},
+	}
+}
+
+// sortIDs sorts the IDs in place, and returns them.
+func sortIDs(ids []core.ID) []core.ID {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+	return ids
+}
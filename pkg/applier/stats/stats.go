@@ -209,6 +209,26 @@ func (s *DisabledObjStats) Empty() bool {
 	return s == nil || s.Total == 0
 }
 
+// WaveStats tracks the stats for the events of an apply wave
+type WaveStats struct {
+	// Wave is the value of the apply-wave annotation of the objects in the wave
+	Wave int
+	// Objects tracks the number of objects in the wave
+	Objects uint64
+	// Events tracks the stats for the events of the wave
+	Events *SyncStats
+}
+
+// String returns the stats as a human readable string.
+func (s WaveStats) String() string {
+	return fmt.Sprintf("Wave %d (%d objects): %s", s.Wave, s.Objects, s.Events.String())
+}
+
+// Empty returns true if no events were recorded.
+func (s *WaveStats) Empty() bool {
+	return s == nil || s.Events.Empty()
+}
+
 // SyncStats tracks the stats for all the events
 type SyncStats struct {
 	ApplyEvent  *ApplyEventStats
@@ -216,6 +236,10 @@ type SyncStats struct {
 	DeleteEvent *DeleteEventStats
 	WaitEvent   *WaitEventStats
 	DisableObjs *DisabledObjStats
+	// Waves tracks the stats for each apply wave, if the objects are applied
+	// in more than one wave. The events of the waves are not included in the
+	// other fields.
+	Waves []*WaveStats
 	// ErrorTypeEvents tracks the number of ErrorType events
 	ErrorTypeEvents uint64
 }
@@ -238,6 +262,11 @@ func (s SyncStats) String() string {
 	if !s.DisableObjs.Empty() {
 		strs = append(strs, s.DisableObjs.String())
 	}
+	for _, wave := range s.Waves {
+		if !wave.Empty() {
+			strs = append(strs, wave.String())
+		}
+	}
 	if s.ErrorTypeEvents > 0 {
 		strs = append(strs, fmt.Sprintf("ErrorEvents: %d", s.ErrorTypeEvents))
 	}
@@ -246,7 +275,15 @@ func (s SyncStats) String() string {
 
 // Empty returns true if no events were recorded.
 func (s *SyncStats) Empty() bool {
-	return s == nil || s.ErrorTypeEvents == 0 && s.PruneEvent.Empty() && s.DeleteEvent.Empty() && s.ApplyEvent.Empty() && s.WaitEvent.Empty() && s.DisableObjs.Empty()
+	if s == nil {
+		return true
+	}
+	for _, wave := range s.Waves {
+		if !wave.Empty() {
+			return false
+		}
+	}
+	return s.ErrorTypeEvents == 0 && s.PruneEvent.Empty() && s.DeleteEvent.Empty() && s.ApplyEvent.Empty() && s.WaitEvent.Empty() && s.DisableObjs.Empty()
 }
 
 // NewSyncStats constructs a SyncStats with empty event maps.
@@ -215,6 +215,42 @@ func TestApplyStats(t *testing.T) {
 			wantEmpty:  false,
 			wantString: "ApplyEvents: 3 (Successful: 1, Skipped: 2), PruneEvents: 3 (Failed: 3), ErrorEvents: 4",
 		},
+		{
+			name: "applyStats with waves",
+			stats: &SyncStats{
+				Waves: []*WaveStats{
+					{
+						Wave:    0,
+						Objects: 2,
+						Events: &SyncStats{
+							ApplyEvent: &ApplyEventStats{
+								EventByOp: map[event.ApplyEventStatus]uint64{
+									event.ApplySuccessful: 2,
+								},
+							},
+						},
+					},
+					{
+						Wave:    1,
+						Objects: 1,
+						Events:  NewSyncStats(),
+					},
+					{
+						Wave:    2,
+						Objects: 1,
+						Events: &SyncStats{
+							WaitEvent: &WaitEventStats{
+								EventByOp: map[event.WaitEventStatus]uint64{
+									event.ReconcileTimeout: 1,
+								},
+							},
+						},
+					},
+				},
+			},
+			wantEmpty:  false,
+			wantString: "Wave 0 (2 objects): ApplyEvents: 2 (Successful: 2), Wave 2 (1 objects): WaitEvents: 1 (Timeout: 1)",
+		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
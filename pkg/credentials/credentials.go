@@ -0,0 +1,105 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package credentials mints short-lived credentials to Git servers and OCI
+// registries, for the auth types that don't store a long-lived token in a
+// Secret.
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+)
+
+// expiryDelta is how long before their expiry cached credentials are renewed.
+const expiryDelta = 5 * time.Minute
+
+// Credentials are a username and a password that are valid until Expiry.
+type Credentials struct {
+	Username string
+	Password string
+	Expiry   time.Time
+}
+
+// Provider mints credentials.
+type Provider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// Cached returns a Provider that reuses the credentials minted by p until they
+// are about to expire.
+func Cached(p Provider) Provider {
+	return &cachedProvider{provider: p, now: time.Now}
+}
+
+type cachedProvider struct {
+	provider Provider
+	now      func() time.Time
+
+	mux   sync.Mutex
+	creds *Credentials
+}
+
+// Credentials implements Provider.
+func (c *cachedProvider) Credentials(ctx context.Context) (Credentials, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.creds != nil && c.now().Add(expiryDelta).Before(c.creds.Expiry) {
+		return *c.creds, nil
+	}
+	creds, err := c.provider.Credentials(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	c.creds = &creds
+	return creds, nil
+}
+
+// AskpassHandler serves the credentials minted by p in the format that
+// git-sync expects from the GIT_ASKPASS_URL endpoint.
+func AskpassHandler(p Provider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		creds, err := p.Credentials(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, "username=%s\npassword=%s\n", creds.Username, creds.Password)
+	})
+}
+
+// Authenticator returns an authenticator to OCI registries that uses the
+// credentials minted by p.
+func Authenticator(ctx context.Context, p Provider) authn.Authenticator {
+	return &authenticator{ctx: ctx, provider: p}
+}
+
+type authenticator struct {
+	ctx      context.Context
+	provider Provider
+}
+
+// Authorization implements authn.Authenticator.
+func (a *authenticator) Authorization() (*authn.AuthConfig, error) {
+	creds, err := a.provider.Credentials(a.ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &authn.AuthConfig{Username: creds.Username, Password: creds.Password}, nil
+}
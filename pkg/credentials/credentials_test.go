@@ -0,0 +1,83 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeProvider struct {
+	calls  int
+	expiry time.Time
+}
+
+func (p *fakeProvider) Credentials(_ context.Context) (Credentials, error) {
+	p.calls++
+	return Credentials{
+		Username: "user",
+		Password: fmt.Sprintf("token-%d", p.calls),
+		Expiry:   p.expiry,
+	}, nil
+}
+
+func TestCached(t *testing.T) {
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name         string
+		expiry       time.Time
+		wantPassword string
+	}{
+		{
+			name:         "valid credentials are reused",
+			expiry:       now.Add(time.Hour),
+			wantPassword: "token-1",
+		},
+		{
+			name:         "credentials about to expire are renewed",
+			expiry:       now.Add(time.Minute),
+			wantPassword: "token-2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &fakeProvider{expiry: tc.expiry}
+			cached := &cachedProvider{provider: p, now: func() time.Time { return now }}
+			if _, err := cached.Credentials(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			creds, err := cached.Credentials(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if creds.Password != tc.wantPassword {
+				t.Errorf("Credentials() got password %q, want %q", creds.Password, tc.wantPassword)
+			}
+		})
+	}
+}
+
+func TestAskpassHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	AskpassHandler(&fakeProvider{}).ServeHTTP(rec, httptest.NewRequest("GET", "/git_askpass", nil))
+	want := "username=user\npassword=token-1\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("AskpassHandler() got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// GitHubAppIDKey is the key of the Secret that holds the ID of the GitHub App.
+	GitHubAppIDKey = "github-app-id"
+	// GitHubAppInstallationIDKey is the key of the Secret that holds the ID of
+	// the installation of the GitHub App.
+	GitHubAppInstallationIDKey = "github-app-installation-id"
+	// GitHubAppPrivateKeyKey is the key of the Secret that holds the PEM-encoded
+	// private key of the GitHub App.
+	GitHubAppPrivateKeyKey = "github-app-private-key"
+	// GitHubAppBaseURLKey is the optional key of the Secret that holds the URL
+	// of the GitHub API, for GitHub Enterprise Server.
+	GitHubAppBaseURLKey = "github-app-base-url"
+
+	// defaultGitHubAPIURL is the URL of the GitHub API.
+	defaultGitHubAPIURL = "https://api.github.com"
+	// gitHubAppUsername is the username that goes with installation tokens.
+	gitHubAppUsername = "x-access-token"
+	// gitHubAppJWTLifetime is the validity of the JWTs that authenticate as the
+	// GitHub App. GitHub rejects JWTs that are valid for more than 10 minutes.
+	gitHubAppJWTLifetime = 9 * time.Minute
+)
+
+// GitHubApp mints installation tokens of a GitHub App.
+type GitHubApp struct {
+	// AppID is the ID of the GitHub App.
+	AppID string
+	// InstallationID is the ID of the installation of the GitHub App on the
+	// organization or the repository.
+	InstallationID string
+	// PrivateKey is the private key of the GitHub App.
+	PrivateKey *rsa.PrivateKey
+	// BaseURL is the URL of the GitHub API.
+	BaseURL string
+	// Client sends the requests to the GitHub API.
+	Client *http.Client
+
+	now func() time.Time
+}
+
+var _ Provider = &GitHubApp{}
+
+// NewGitHubAppFromDir returns a GitHubApp configured from the files of a
+// mounted Secret.
+func NewGitHubAppFromDir(dir string) (*GitHubApp, error) {
+	read := func(key string, required bool) (string, error) {
+		content, err := os.ReadFile(filepath.Join(dir, key))
+		if err != nil {
+			if os.IsNotExist(err) && !required {
+				return "", nil
+			}
+			return "", fmt.Errorf("failed to read the %s key of the GitHub App secret: %w", key, err)
+		}
+		return strings.TrimSpace(string(content)), nil
+	}
+	appID, err := read(GitHubAppIDKey, true)
+	if err != nil {
+		return nil, err
+	}
+	installationID, err := read(GitHubAppInstallationIDKey, true)
+	if err != nil {
+		return nil, err
+	}
+	pemKey, err := read(GitHubAppPrivateKeyKey, true)
+	if err != nil {
+		return nil, err
+	}
+	baseURL, err := read(GitHubAppBaseURLKey, false)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := parsePrivateKey([]byte(pemKey))
+	if err != nil {
+		return nil, err
+	}
+	if baseURL == "" {
+		baseURL = defaultGitHubAPIURL
+	}
+	return &GitHubApp{
+		AppID:          appID,
+		InstallationID: installationID,
+		PrivateKey:     privateKey,
+		BaseURL:        baseURL,
+		Client:         http.DefaultClient,
+	}, nil
+}
+
+// parsePrivateKey parses a PEM-encoded RSA private key, in either the PKCS #1
+// format that GitHub generates, or the PKCS #8 format.
+func parsePrivateKey(pemKey []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, fmt.Errorf("the private key of the GitHub App is not PEM-encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key of the GitHub App: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the private key of the GitHub App is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// Credentials implements Provider. It creates an installation token, which is
+// valid for one hour.
+func (a *GitHubApp) Credentials(ctx context.Context) (Credentials, error) {
+	jwt, err := a.jwt()
+	if err != nil {
+		return Credentials{}, err
+	}
+	url := fmt.Sprintf("%s/app/installations/%s/access_tokens", strings.TrimSuffix(a.BaseURL, "/"), a.InstallationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return Credentials{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to create an installation token of the GitHub App: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusCreated {
+		return Credentials{}, fmt.Errorf("failed to create an installation token of the GitHub App: %s", resp.Status)
+	}
+	var token struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return Credentials{}, fmt.Errorf("failed to decode the installation token of the GitHub App: %w", err)
+	}
+	return Credentials{
+		Username: gitHubAppUsername,
+		Password: token.Token,
+		Expiry:   token.ExpiresAt,
+	}, nil
+}
+
+// jwt returns a JWT that authenticates as the GitHub App, signed with its
+// private key.
+func (a *GitHubApp) jwt() (string, error) {
+	now := time.Now
+	if a.now != nil {
+		now = a.now
+	}
+	issuedAt := now().Add(-time.Minute)
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		// Backdate the token to allow for clock drift.
+		"iat": issuedAt.Unix(),
+		"exp": issuedAt.Add(gitHubAppJWTLifetime).Unix(),
+		"iss": a.AppID,
+	})
+	if err != nil {
+		return "", err
+	}
+	var signed bytes.Buffer
+	signed.WriteString(base64.RawURLEncoding.EncodeToString(header))
+	signed.WriteString(".")
+	signed.WriteString(base64.RawURLEncoding.EncodeToString(claims))
+	digest := sha256.Sum256(signed.Bytes())
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign the JWT of the GitHub App: %w", err)
+	}
+	signed.WriteString(".")
+	signed.WriteString(base64.RawURLEncoding.EncodeToString(signature))
+	return signed.String(), nil
+}
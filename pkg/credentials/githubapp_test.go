@@ -0,0 +1,120 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitHubApp(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Hour)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/456/access_tokens" {
+			http.NotFound(w, r)
+			return
+		}
+		parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), ".")
+		if len(parts) != 3 {
+			http.Error(w, "invalid JWT", http.StatusUnauthorized)
+			return
+		}
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		var c struct {
+			Iss string `json:"iss"`
+			Iat int64  `json:"iat"`
+			Exp int64  `json:"exp"`
+		}
+		if err := json.Unmarshal(claims, &c); err != nil || c.Iss != "123" || c.Iat >= now.Unix() || c.Exp-c.Iat > 600 {
+			http.Error(w, "invalid claims", http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"token": "ghs_abc", "expires_at": expiresAt})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	files := map[string]string{
+		GitHubAppIDKey:             "123",
+		GitHubAppInstallationIDKey: "456\n",
+		GitHubAppPrivateKeyKey:     string(keyPEM),
+		GitHubAppBaseURLKey:        server.URL,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app, err := NewGitHubAppFromDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.now = func() time.Time { return now }
+	creds, err := app.Credentials(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Credentials{Username: gitHubAppUsername, Password: "ghs_abc", Expiry: expiresAt}
+	if creds != want {
+		t.Errorf("Credentials() = %+v, want %+v", creds, want)
+	}
+}
+
+func TestNewGitHubAppFromDir_MissingKey(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, GitHubAppIDKey), []byte("123"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewGitHubAppFromDir(dir); err == nil {
+		t.Error("NewGitHubAppFromDir() got no error, want an error for the missing installation ID")
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// ServiceAccountTokenDir is the directory where the projected token of the
+	// reconciler Kubernetes Service Account is mounted.
+	ServiceAccountTokenDir = "/var/run/secrets/tokens/k8s-sa"
+	// ServiceAccountTokenFile is the name of the projected token file.
+	ServiceAccountTokenFile = "token"
+
+	// DefaultTokenExchangeUsername is the username that goes with the
+	// exchanged access token by default.
+	DefaultTokenExchangeUsername = "oauth2accesstoken"
+
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	jwtTokenType           = "urn:ietf:params:oauth:token-type:jwt"
+	accessTokenType        = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// TokenExchange exchanges the projected token of the reconciler Kubernetes
+// Service Account for an access token, following the OAuth 2.0 Token Exchange
+// protocol (RFC 8693).
+type TokenExchange struct {
+	// URL is the token endpoint.
+	URL string
+	// Username is the username that goes with the access token.
+	Username string
+	// TokenPath is the path of the projected Kubernetes Service Account token.
+	// The kubelet rotates the token, so it is read again for every exchange.
+	TokenPath string
+	// Client sends the requests to the token endpoint.
+	Client *http.Client
+
+	now func() time.Time
+}
+
+var _ Provider = &TokenExchange{}
+
+// NewTokenExchange returns a TokenExchange that exchanges the token mounted
+// in ServiceAccountTokenDir.
+func NewTokenExchange(tokenURL, username string) *TokenExchange {
+	if username == "" {
+		username = DefaultTokenExchangeUsername
+	}
+	return &TokenExchange{
+		URL:       tokenURL,
+		Username:  username,
+		TokenPath: filepath.Join(ServiceAccountTokenDir, ServiceAccountTokenFile),
+		Client:    http.DefaultClient,
+	}
+}
+
+// Credentials implements Provider.
+func (e *TokenExchange) Credentials(ctx context.Context) (Credentials, error) {
+	subjectToken, err := os.ReadFile(e.TokenPath)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read the Kubernetes Service Account token: %w", err)
+	}
+	form := url.Values{
+		"grant_type":           {tokenExchangeGrantType},
+		"subject_token":        {strings.TrimSpace(string(subjectToken))},
+		"subject_token_type":   {jwtTokenType},
+		"requested_token_type": {accessTokenType},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Credentials{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to exchange the Kubernetes Service Account token: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil && resp.StatusCode == http.StatusOK {
+		return Credentials{}, fmt.Errorf("failed to decode the exchanged token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Credentials{}, fmt.Errorf("failed to exchange the Kubernetes Service Account token: %s: %s %s",
+			resp.Status, token.Error, token.ErrorDescription)
+	}
+	if token.AccessToken == "" {
+		return Credentials{}, fmt.Errorf("the token endpoint returned no access token")
+	}
+	now := time.Now
+	if e.now != nil {
+		now = e.now
+	}
+	return Credentials{
+		Username: e.Username,
+		Password: token.AccessToken,
+		Expiry:   now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTokenExchange(t *testing.T) {
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("grant_type") != tokenExchangeGrantType ||
+			r.PostForm.Get("subject_token_type") != jwtTokenType {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "unsupported_grant_type"})
+			return
+		}
+		if r.PostForm.Get("subject_token") != "ksa-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_request", "error_description": "unknown subject token"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":      "access-token",
+			"issued_token_type": accessTokenType,
+			"token_type":        "Bearer",
+			"expires_in":        3600,
+		})
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name     string
+		token    string
+		username string
+		want     Credentials
+		wantErr  bool
+	}{
+		{
+			name:  "default username",
+			token: "ksa-token\n",
+			want: Credentials{
+				Username: DefaultTokenExchangeUsername,
+				Password: "access-token",
+				Expiry:   now.Add(time.Hour),
+			},
+		},
+		{
+			name:     "custom username",
+			token:    "ksa-token",
+			username: "00000000-0000-0000-0000-000000000000",
+			want: Credentials{
+				Username: "00000000-0000-0000-0000-000000000000",
+				Password: "access-token",
+				Expiry:   now.Add(time.Hour),
+			},
+		},
+		{
+			name:    "rejected token",
+			token:   "other-token",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenPath := filepath.Join(t.TempDir(), ServiceAccountTokenFile)
+			if err := os.WriteFile(tokenPath, []byte(tc.token), 0644); err != nil {
+				t.Fatal(err)
+			}
+			e := NewTokenExchange(server.URL, tc.username)
+			e.TokenPath = tokenPath
+			e.now = func() time.Time { return now }
+			creds, err := e.Credentials(context.Background())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Credentials() got error %v, want error %t", err, tc.wantErr)
+			}
+			if creds != tc.want {
+				t.Errorf("Credentials() = %+v, want %+v", creds, tc.want)
+			}
+		})
+	}
+}
@@ -41,6 +41,11 @@ type Resources struct {
 	objectSet map[core.ID]*unstructured.Unstructured
 	// commit of the source in which the resources were declared
 	commit string
+	// healthySet is the objectSet of the last commit that was applied and
+	// became healthy. It is restored by RollBack.
+	healthySet map[core.ID]*unstructured.Unstructured
+	// healthyCommit is the commit of healthySet.
+	healthyCommit string
 }
 
 // Update performs an atomic update on the resource declaration set.
@@ -135,6 +140,36 @@ func (r *Resources) DeclaredGVKs() (map[schema.GroupVersionKind]struct{}, string
 	return gvkSet, commit
 }
 
+// MarkHealthy records the current resource declaration set as the last one
+// that was applied and became healthy.
+func (r *Resources) MarkHealthy() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.healthySet = r.objectSet
+	r.healthyCommit = r.commit
+}
+
+// HealthyCommit returns the commit of the last resource declaration set that
+// was marked healthy, if any.
+func (r *Resources) HealthyCommit() string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.healthyCommit
+}
+
+// RollBack restores the last resource declaration set that was marked healthy,
+// and returns its commit. Returns false if no set was marked healthy.
+func (r *Resources) RollBack() (string, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.healthySet == nil {
+		return "", false
+	}
+	r.objectSet = r.healthySet
+	r.commit = r.healthyCommit
+	return r.commit, true
+}
+
 func (r *Resources) getObjectSet() (map[core.ID]*unstructured.Unstructured, string) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
@@ -204,6 +204,36 @@ func TestGVKSet(t *testing.T) {
 	}
 }
 
+func TestRollBack(t *testing.T) {
+	dr := Resources{}
+	if _, rolledBack := dr.RollBack(); rolledBack {
+		t.Fatal("got rolled back, want no healthy declarations to roll back to")
+	}
+
+	if _, err := dr.Update(context.Background(), []client.Object{obj1}, "healthy"); err != nil {
+		t.Fatal(err)
+	}
+	dr.MarkHealthy()
+	if _, err := dr.Update(context.Background(), testSet, "unhealthy"); err != nil {
+		t.Fatal(err)
+	}
+	require.Equal(t, "healthy", dr.HealthyCommit())
+
+	commit, rolledBack := dr.RollBack()
+	if !rolledBack {
+		t.Fatal("got not rolled back, want rolled back")
+	}
+	require.Equal(t, "healthy", commit)
+	_, gotCommit, found := dr.Get(core.IDOf(obj1))
+	require.Equal(t, "healthy", gotCommit)
+	if !found {
+		t.Errorf("got %v not found, want found", core.IDOf(obj1))
+	}
+	if _, _, found := dr.Get(core.IDOf(obj2)); found {
+		t.Errorf("got %v found, want not found after roll back", core.IDOf(obj2))
+	}
+}
+
 func TestResources_InternalErrorMetricValidation(t *testing.T) {
 	m := testmetrics.RegisterMetrics(metrics.InternalErrorsView)
 	dr := Resources{}
@@ -0,0 +1,226 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dependency decides whether a reconciler waits for the RootSyncs and
+// RepoSyncs listed in the spec.dependsOn field of a RootSync or RepoSync.
+package dependency
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/reposync"
+	"kpt.dev/configsync/pkg/rootsync"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReasonWaitingForDependency is the reason of the Syncing condition while a
+// dependency has not synced its latest commit without errors.
+const ReasonWaitingForDependency = "WaitingForDependency"
+
+// State describes whether the reconciler waits for a dependency, and why.
+type State struct {
+	// Waiting is true if the reconciler must not parse nor apply the source.
+	Waiting bool
+	// Message is the message of the Syncing condition.
+	Message string
+}
+
+// CycleError reports that the dependencies of a sync form a cycle, so that the
+// syncs in the cycle would wait for each other forever.
+type CycleError struct {
+	// Cycle lists the syncs of the cycle, starting and ending with the same
+	// sync.
+	Cycle []v1beta1.Dependency
+}
+
+// Error implements error.
+func (e *CycleError) Error() string {
+	var syncs []string
+	for _, dep := range e.Cycle {
+		syncs = append(syncs, fmt.Sprintf("%s %s", dep.Kind, key(dep)))
+	}
+	return fmt.Sprintf("spec.dependsOn forms a cycle: %s", strings.Join(syncs, " -> "))
+}
+
+// Check returns the state of the dependencies of the sync self. The reconciler
+// waits for the first dependency that does not exist or that has not synced its
+// latest commit without errors. The namespace of a RepoSync dependency must be
+// set.
+//
+// Check returns a *CycleError if the dependencies form a cycle, see
+// CheckCycle.
+func Check(ctx context.Context, c client.Reader, self v1beta1.Dependency, dependencies []v1beta1.Dependency) (State, error) {
+	if err := CheckCycle(ctx, c, self, dependencies); err != nil {
+		return State{}, err
+	}
+	for _, dep := range dependencies {
+		ready, err := synced(ctx, c, dep)
+		if err != nil {
+			return State{}, err
+		}
+		if !ready {
+			return State{
+				Waiting: true,
+				Message: fmt.Sprintf("Waiting for %s %s to sync its latest commit without errors", dep.Kind, key(dep)),
+			}, nil
+		}
+	}
+	return State{}, nil
+}
+
+// CheckCycle returns a *CycleError if the dependencies of the sync self,
+// followed through the spec.dependsOn of each dependency, lead back to a sync
+// on the way. Dependencies that do not exist yet end the path, as do the
+// dependencies the client is not allowed to get: a namespace reconciler may
+// only get the syncs it depends on directly, and the reconciler-manager checks
+// the whole path.
+func CheckCycle(ctx context.Context, c client.Reader, self v1beta1.Dependency, dependencies []v1beta1.Dependency) error {
+	cycle, err := findCycle(ctx, c, self, dependencies)
+	if err != nil {
+		return err
+	}
+	if cycle != nil {
+		return &CycleError{Cycle: cycle}
+	}
+	return nil
+}
+
+// syncRef identifies a RootSync or RepoSync.
+type syncRef struct {
+	kind string
+	types.NamespacedName
+}
+
+func refOf(dep v1beta1.Dependency) syncRef {
+	return syncRef{kind: dep.Kind, NamespacedName: key(dep)}
+}
+
+// findCycle follows the dependencies of self depth first, and returns the first
+// cycle found, or nil if there is none. Each sync is only visited once.
+func findCycle(ctx context.Context, c client.Reader, self v1beta1.Dependency, dependencies []v1beta1.Dependency) ([]v1beta1.Dependency, error) {
+	var path []v1beta1.Dependency
+	visited := map[syncRef]bool{}
+	var visit func(dep v1beta1.Dependency, dependencies []v1beta1.Dependency) ([]v1beta1.Dependency, error)
+	visit = func(dep v1beta1.Dependency, dependencies []v1beta1.Dependency) ([]v1beta1.Dependency, error) {
+		visited[refOf(dep)] = true
+		path = append(path, dep)
+		for _, next := range dependencies {
+			for i, prev := range path {
+				if refOf(prev) == refOf(next) {
+					return append(append([]v1beta1.Dependency{}, path[i:]...), next), nil
+				}
+			}
+			if visited[refOf(next)] {
+				continue
+			}
+			nextDependencies, err := dependsOn(ctx, c, next)
+			if err != nil {
+				return nil, err
+			}
+			if cycle, err := visit(next, nextDependencies); cycle != nil || err != nil {
+				return cycle, err
+			}
+		}
+		path = path[:len(path)-1]
+		return nil, nil
+	}
+	return visit(self, dependencies)
+}
+
+// dependsOn returns the dependencies of the dependency, with the namespace of
+// RepoSync dependencies defaulted as the reconciler-manager does, or nothing if
+// the dependency does not exist yet.
+func dependsOn(ctx context.Context, c client.Reader, dep v1beta1.Dependency) ([]v1beta1.Dependency, error) {
+	var dependencies []v1beta1.Dependency
+	var err error
+	switch dep.Kind {
+	case configsync.RootSyncKind:
+		rs := &v1beta1.RootSync{}
+		if err = c.Get(ctx, key(dep), rs); err == nil {
+			dependencies = rs.Spec.DependsOn
+		}
+	case configsync.RepoSyncKind:
+		rs := &v1beta1.RepoSync{}
+		if err = c.Get(ctx, key(dep), rs); err == nil {
+			dependencies = rs.Spec.DependsOn
+		}
+	default:
+		return nil, fmt.Errorf("unsupported dependency kind %q: must be one of %s or %s", dep.Kind, configsync.RootSyncKind, configsync.RepoSyncKind)
+	}
+	if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the %s %s: %w", dep.Kind, key(dep), err)
+	}
+	var result []v1beta1.Dependency
+	for _, next := range dependencies {
+		if next.Kind == configsync.RepoSyncKind && next.Namespace == "" {
+			next.Namespace = key(dep).Namespace
+		}
+		result = append(result, next)
+	}
+	return result, nil
+}
+
+// synced returns true if the dependency has synced its latest commit without
+// errors, or false if it has not, or if it does not exist yet.
+func synced(ctx context.Context, c client.Reader, dep v1beta1.Dependency) (bool, error) {
+	var err error
+	switch dep.Kind {
+	case configsync.RootSyncKind:
+		rs := &v1beta1.RootSync{}
+		if err = c.Get(ctx, key(dep), rs); err == nil {
+			syncing := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncSyncing)
+			return syncing != nil && syncing.Status == metav1.ConditionFalse && rootsync.ConditionHasNoErrors(*syncing) &&
+				latestCommitSynced(syncing.Commit, rs.Status.Status), nil
+		}
+	case configsync.RepoSyncKind:
+		rs := &v1beta1.RepoSync{}
+		if err = c.Get(ctx, key(dep), rs); err == nil {
+			syncing := reposync.GetCondition(rs.Status.Conditions, v1beta1.RepoSyncSyncing)
+			return syncing != nil && syncing.Status == metav1.ConditionFalse && reposync.ConditionHasNoErrors(*syncing) &&
+				latestCommitSynced(syncing.Commit, rs.Status.Status), nil
+		}
+	default:
+		return false, fmt.Errorf("unsupported dependency kind %q: must be one of %s or %s", dep.Kind, configsync.RootSyncKind, configsync.RepoSyncKind)
+	}
+	if apierrors.IsNotFound(err) {
+		// The dependency may not be created yet.
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to get the %s %s: %w", dep.Kind, key(dep), err)
+}
+
+// latestCommitSynced returns true if the commit of the Syncing condition is
+// both the last synced commit and the latest commit fetched from the source.
+func latestCommitSynced(commit string, s v1beta1.Status) bool {
+	return commit != "" && commit == s.LastSyncedCommit && commit == s.Source.Commit
+}
+
+// key returns the key of the dependency. A RootSync is always in the
+// config-management-system namespace.
+func key(dep v1beta1.Dependency) types.NamespacedName {
+	if dep.Kind == configsync.RootSyncKind {
+		return types.NamespacedName{Namespace: configsync.ControllerNamespace, Name: dep.Name}
+	}
+	return types.NamespacedName{Namespace: dep.Namespace, Name: dep.Name}
+}
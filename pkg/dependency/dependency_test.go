@@ -0,0 +1,303 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependency
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	syncerFake "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+	"kpt.dev/configsync/pkg/testing/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func rootSync(sourceCommit, lastSyncedCommit string, syncing v1beta1.RootSyncCondition) *v1beta1.RootSync {
+	rs := fake.RootSyncObjectV1Beta1("root-sync")
+	rs.Status.Source.Commit = sourceCommit
+	rs.Status.LastSyncedCommit = lastSyncedCommit
+	rs.Status.Conditions = []v1beta1.RootSyncCondition{syncing}
+	return rs
+}
+
+func repoSync(sourceCommit, lastSyncedCommit string, syncing v1beta1.RepoSyncCondition) *v1beta1.RepoSync {
+	rs := fake.RepoSyncObjectV1Beta1("bookinfo", "repo-sync")
+	rs.Status.Source.Commit = sourceCommit
+	rs.Status.LastSyncedCommit = lastSyncedCommit
+	rs.Status.Conditions = []v1beta1.RepoSyncCondition{syncing}
+	return rs
+}
+
+func TestCheck(t *testing.T) {
+	rootSynced := v1beta1.RootSyncCondition{
+		Type:         v1beta1.RootSyncSyncing,
+		Status:       metav1.ConditionFalse,
+		Commit:       "abc123",
+		ErrorSummary: &v1beta1.ErrorSummary{},
+	}
+	rootFailed := v1beta1.RootSyncCondition{
+		Type:         v1beta1.RootSyncSyncing,
+		Status:       metav1.ConditionFalse,
+		Commit:       "abc123",
+		ErrorSummary: &v1beta1.ErrorSummary{TotalCount: 1},
+	}
+	rootSyncing := v1beta1.RootSyncCondition{
+		Type:         v1beta1.RootSyncSyncing,
+		Status:       metav1.ConditionTrue,
+		Commit:       "def456",
+		ErrorSummary: &v1beta1.ErrorSummary{},
+	}
+	repoSynced := v1beta1.RepoSyncCondition{
+		Type:         v1beta1.RepoSyncSyncing,
+		Status:       metav1.ConditionFalse,
+		Commit:       "abc123",
+		ErrorSummary: &v1beta1.ErrorSummary{},
+	}
+	rootDep := v1beta1.Dependency{Kind: configsync.RootSyncKind, Name: "root-sync"}
+	repoDep := v1beta1.Dependency{Kind: configsync.RepoSyncKind, Name: "repo-sync", Namespace: "bookinfo"}
+	waitingForRoot := State{
+		Waiting: true,
+		Message: "Waiting for RootSync config-management-system/root-sync to sync its latest commit without errors",
+	}
+
+	testCases := []struct {
+		name         string
+		dependencies []v1beta1.Dependency
+		objs         []client.Object
+		want         State
+	}{
+		{
+			name: "no dependencies",
+		},
+		{
+			name:         "dependency not found",
+			dependencies: []v1beta1.Dependency{rootDep},
+			want:         waitingForRoot,
+		},
+		{
+			name:         "latest commit synced",
+			dependencies: []v1beta1.Dependency{rootDep},
+			objs:         []client.Object{rootSync("abc123", "abc123", rootSynced)},
+		},
+		{
+			name:         "latest commit failed to sync",
+			dependencies: []v1beta1.Dependency{rootDep},
+			objs:         []client.Object{rootSync("abc123", "", rootFailed)},
+			want:         waitingForRoot,
+		},
+		{
+			name:         "new commit is syncing",
+			dependencies: []v1beta1.Dependency{rootDep},
+			objs:         []client.Object{rootSync("def456", "abc123", rootSyncing)},
+			want:         waitingForRoot,
+		},
+		{
+			name:         "new commit not synced yet",
+			dependencies: []v1beta1.Dependency{rootDep},
+			objs:         []client.Object{rootSync("def456", "abc123", rootSynced)},
+			want:         waitingForRoot,
+		},
+		{
+			name:         "all dependencies synced",
+			dependencies: []v1beta1.Dependency{rootDep, repoDep},
+			objs:         []client.Object{rootSync("abc123", "abc123", rootSynced), repoSync("abc123", "abc123", repoSynced)},
+		},
+		{
+			name:         "second dependency not found",
+			dependencies: []v1beta1.Dependency{rootDep, repoDep},
+			objs:         []client.Object{rootSync("abc123", "abc123", rootSynced)},
+			want: State{
+				Waiting: true,
+				Message: "Waiting for RepoSync bookinfo/repo-sync to sync its latest commit without errors",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := syncerFake.NewClient(t, core.Scheme, tc.objs...)
+			self := v1beta1.Dependency{Kind: configsync.RepoSyncKind, Name: "self", Namespace: "bookinfo"}
+			got, err := Check(context.Background(), fakeClient, self, tc.dependencies)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Check() got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func dependentRootSync(name string, dependencies ...v1beta1.Dependency) *v1beta1.RootSync {
+	rs := fake.RootSyncObjectV1Beta1(name)
+	rs.Spec.DependsOn = dependencies
+	return rs
+}
+
+func dependentRepoSync(namespace, name string, dependencies ...v1beta1.Dependency) *v1beta1.RepoSync {
+	rs := fake.RepoSyncObjectV1Beta1(namespace, name)
+	rs.Spec.DependsOn = dependencies
+	return rs
+}
+
+func TestCheckCycle(t *testing.T) {
+	rootA := v1beta1.Dependency{Kind: configsync.RootSyncKind, Name: "a"}
+	rootB := v1beta1.Dependency{Kind: configsync.RootSyncKind, Name: "b"}
+	rootC := v1beta1.Dependency{Kind: configsync.RootSyncKind, Name: "c"}
+	repoD := v1beta1.Dependency{Kind: configsync.RepoSyncKind, Name: "d", Namespace: "bookinfo"}
+	// RepoSync dependencies default to the namespace of the dependent.
+	repoDDefaulted := v1beta1.Dependency{Kind: configsync.RepoSyncKind, Name: "d"}
+
+	testCases := []struct {
+		name         string
+		self         v1beta1.Dependency
+		dependencies []v1beta1.Dependency
+		objs         []client.Object
+		want         []v1beta1.Dependency
+	}{
+		{
+			name: "no dependencies",
+			self: rootA,
+		},
+		{
+			name:         "dependency not found",
+			self:         rootA,
+			dependencies: []v1beta1.Dependency{rootB},
+		},
+		{
+			name:         "chain",
+			self:         rootA,
+			dependencies: []v1beta1.Dependency{rootB},
+			objs: []client.Object{
+				dependentRootSync("b", rootC),
+				dependentRootSync("c"),
+			},
+		},
+		{
+			name:         "diamond",
+			self:         rootA,
+			dependencies: []v1beta1.Dependency{rootB, repoD},
+			objs: []client.Object{
+				dependentRootSync("b", rootC),
+				dependentRepoSync("bookinfo", "d", rootC),
+				dependentRootSync("c"),
+			},
+		},
+		{
+			name:         "cycle through self",
+			self:         rootA,
+			dependencies: []v1beta1.Dependency{rootB},
+			objs: []client.Object{
+				dependentRootSync("b", rootA),
+			},
+			want: []v1beta1.Dependency{rootA, rootB, rootA},
+		},
+		{
+			name:         "longer cycle through self",
+			self:         repoD,
+			dependencies: []v1beta1.Dependency{rootB},
+			objs: []client.Object{
+				dependentRootSync("b", rootC),
+				dependentRootSync("c", repoD),
+			},
+			want: []v1beta1.Dependency{repoD, rootB, rootC, repoD},
+		},
+		{
+			name:         "cycle through defaulted RepoSync namespace",
+			self:         repoD,
+			dependencies: []v1beta1.Dependency{{Kind: configsync.RepoSyncKind, Name: "e", Namespace: "bookinfo"}},
+			objs: []client.Object{
+				dependentRepoSync("bookinfo", "e", repoDDefaulted),
+			},
+			want: []v1beta1.Dependency{repoD, {Kind: configsync.RepoSyncKind, Name: "e", Namespace: "bookinfo"}, repoD},
+		},
+		{
+			name:         "cycle not through self",
+			self:         rootA,
+			dependencies: []v1beta1.Dependency{rootB},
+			objs: []client.Object{
+				dependentRootSync("b", rootC),
+				dependentRootSync("c", rootB),
+			},
+			want: []v1beta1.Dependency{rootB, rootC, rootB},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := syncerFake.NewClient(t, core.Scheme, tc.objs...)
+			err := CheckCycle(context.Background(), fakeClient, tc.self, tc.dependencies)
+			var cycleErr *CycleError
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("CheckCycle() got error %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &cycleErr) {
+				t.Fatalf("CheckCycle() got error %v, want a *CycleError", err)
+			}
+			if diff := cmp.Diff(tc.want, cycleErr.Cycle); diff != "" {
+				t.Errorf("CheckCycle() got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckCycleBlocksCheck(t *testing.T) {
+	self := v1beta1.Dependency{Kind: configsync.RootSyncKind, Name: "a"}
+	fakeClient := syncerFake.NewClient(t, core.Scheme, dependentRootSync("b", self))
+	_, err := Check(context.Background(), fakeClient, self, []v1beta1.Dependency{{Kind: configsync.RootSyncKind, Name: "b"}})
+	want := "spec.dependsOn forms a cycle: RootSync config-management-system/a -> RootSync config-management-system/b -> RootSync config-management-system/a"
+	if err == nil || err.Error() != want {
+		t.Errorf("Check() got error %v, want %q", err, want)
+	}
+}
+
+// forbiddenReader returns a Forbidden error for the forbidden syncs, as the API
+// server does for the syncs a namespace reconciler may not get.
+type forbiddenReader struct {
+	client.Reader
+	forbidden map[client.ObjectKey]bool
+}
+
+func (r *forbiddenReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if r.forbidden[key] {
+		return apierrors.NewForbidden(schema.GroupResource{Group: configsync.GroupName, Resource: "rootsyncs"}, key.Name, errors.New("access denied"))
+	}
+	return r.Reader.Get(ctx, key, obj)
+}
+
+func TestCheckCycleForbiddenDependency(t *testing.T) {
+	self := v1beta1.Dependency{Kind: configsync.RepoSyncKind, Name: "d", Namespace: "bookinfo"}
+	fakeClient := syncerFake.NewClient(t, core.Scheme,
+		dependentRootSync("b", v1beta1.Dependency{Kind: configsync.RootSyncKind, Name: "c"}),
+		dependentRootSync("c", self))
+	reader := &forbiddenReader{
+		Reader:    fakeClient,
+		forbidden: map[client.ObjectKey]bool{{Namespace: configsync.ControllerNamespace, Name: "c"}: true},
+	}
+	// The cycle through c is left to the reconciler-manager.
+	if err := CheckCycle(context.Background(), reader, self, []v1beta1.Dependency{{Kind: configsync.RootSyncKind, Name: "b"}}); err != nil {
+		t.Errorf("CheckCycle() got error %v, want nil", err)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"kpt.dev/configsync/pkg/status"
+)
+
+// simpleFieldName matches the field names that can be written after a dot in
+// a field path. Other names, e.g. annotation keys, are quoted in brackets.
+var simpleFieldName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// FieldDiff is a declared field whose value differs on the cluster.
+type FieldDiff struct {
+	// Path is the path of the field, e.g. .spec.replicas or
+	// .metadata.labels["app.kubernetes.io/name"].
+	Path string
+	// Declared is the value of the field in the source of truth.
+	Declared interface{}
+	// Actual is the value of the field on the cluster, or nil if the field is
+	// not set.
+	Actual interface{}
+}
+
+// FieldDiffs returns the declared fields whose values differ on the cluster,
+// sorted by path.
+//
+// Fields that are only set on the cluster, e.g. defaulted by the API server or
+// set by another controller, are ignored. Lists of the same length are compared
+// item by item, other lists are compared as a whole.
+func (d Diff) FieldDiffs() ([]FieldDiff, status.Error) {
+	declared, err := d.UnstructuredDeclared()
+	if err != nil || declared == nil {
+		return nil, err
+	}
+	actual, err := d.UnstructuredActual()
+	if err != nil || actual == nil {
+		return nil, err
+	}
+	var diffs []FieldDiff
+	compareFields("", declared.Object, actual.Object, &diffs)
+	return diffs, nil
+}
+
+// compareFields appends the differences between the declared and the actual
+// values of the field at path to diffs.
+func compareFields(path string, declared, actual interface{}, diffs *[]FieldDiff) {
+	switch declaredValue := declared.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(declaredValue))
+		for k := range declaredValue {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			compareFields(fieldPath(path, k), declaredValue[k], actualValue[k], diffs)
+		}
+		return
+	case []interface{}:
+		actualValue, ok := actual.([]interface{})
+		if !ok || len(actualValue) != len(declaredValue) {
+			break
+		}
+		for i := range declaredValue {
+			compareFields(fmt.Sprintf("%s[%d]", path, i), declaredValue[i], actualValue[i], diffs)
+		}
+		return
+	default:
+		if scalarEqual(declared, actual) {
+			return
+		}
+	}
+	*diffs = append(*diffs, FieldDiff{
+		Path:     path,
+		Declared: declared,
+		Actual:   actual,
+	})
+}
+
+func fieldPath(parent, name string) string {
+	if simpleFieldName.MatchString(name) {
+		return parent + "." + name
+	}
+	return fmt.Sprintf("%s[%q]", parent, name)
+}
+
+// scalarEqual returns true if the scalar values are equal. Numbers are equal
+// if they have the same value, e.g. a declared float 1.0 and an actual integer
+// 1.
+func scalarEqual(declared, actual interface{}) bool {
+	if reflect.DeepEqual(declared, actual) {
+		return true
+	}
+	declaredNumber, ok := toFloat(declared)
+	if !ok {
+		return false
+	}
+	actualNumber, ok := toFloat(actual)
+	return ok && declaredNumber == actualNumber
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
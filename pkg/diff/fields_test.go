@@ -0,0 +1,136 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func deployment(replicas int64, image string, labels map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "web",
+			"namespace": "shop",
+			"labels":    labels,
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "web",
+							"image": image,
+						},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestFieldDiffs(t *testing.T) {
+	labels := map[string]interface{}{"app.kubernetes.io/name": "web"}
+
+	testCases := []struct {
+		name     string
+		declared *unstructured.Unstructured
+		actual   *unstructured.Unstructured
+		want     []FieldDiff
+	}{
+		{
+			name:     "no drift",
+			declared: deployment(3, "web:v1", labels),
+			actual:   deployment(3, "web:v1", labels),
+		},
+		{
+			name:     "fields only set on the cluster are ignored",
+			declared: deployment(3, "web:v1", nil),
+			actual: func() *unstructured.Unstructured {
+				u := deployment(3, "web:v1", labels)
+				_ = unstructured.SetNestedField(u.Object, "RollingUpdate", "spec", "strategy", "type")
+				containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+				containers[0].(map[string]interface{})["imagePullPolicy"] = "Always"
+				_ = unstructured.SetNestedSlice(u.Object, containers, "spec", "template", "spec", "containers")
+				return u
+			}(),
+		},
+		{
+			name:     "scalar drift",
+			declared: deployment(3, "web:v1", labels),
+			actual:   deployment(5, "web:v2", map[string]interface{}{"app.kubernetes.io/name": "api"}),
+			want: []FieldDiff{
+				{Path: `.metadata.labels["app.kubernetes.io/name"]`, Declared: "web", Actual: "api"},
+				{Path: ".spec.replicas", Declared: int64(3), Actual: int64(5)},
+				{Path: ".spec.template.spec.containers[0].image", Declared: "web:v1", Actual: "web:v2"},
+			},
+		},
+		{
+			name:     "removed field",
+			declared: deployment(3, "web:v1", labels),
+			actual: func() *unstructured.Unstructured {
+				u := deployment(3, "web:v1", labels)
+				unstructured.RemoveNestedField(u.Object, "spec", "replicas")
+				return u
+			}(),
+			want: []FieldDiff{
+				{Path: ".spec.replicas", Declared: int64(3)},
+			},
+		},
+		{
+			name:     "list of a different length",
+			declared: deployment(3, "web:v1", labels),
+			actual: func() *unstructured.Unstructured {
+				u := deployment(3, "web:v1", labels)
+				_ = unstructured.SetNestedSlice(u.Object, nil, "spec", "template", "spec", "containers")
+				return u
+			}(),
+			want: []FieldDiff{
+				{
+					Path:     ".spec.template.spec.containers",
+					Declared: []interface{}{map[string]interface{}{"name": "web", "image": "web:v1"}},
+					Actual:   []interface{}(nil),
+				},
+			},
+		},
+		{
+			name:     "numbers of different types",
+			declared: deployment(3, "web:v1", labels),
+			actual: func() *unstructured.Unstructured {
+				u := deployment(3, "web:v1", labels)
+				u.Object["spec"].(map[string]interface{})["replicas"] = float64(3)
+				return u
+			}(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := Diff{Declared: tc.declared, Actual: tc.actual}
+			got, err := d.FieldDiffs()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
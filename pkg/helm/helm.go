@@ -15,26 +15,57 @@
 package helm
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/credentials"
+	"kpt.dev/configsync/pkg/oci"
 	"kpt.dev/configsync/pkg/util"
 )
 
 const (
-	// valuesFile is the name of the file created to override defualt chart values.
-	valuesFile = "chart-values.yaml"
+	// valuesFilePattern is the name of the files created for the inline values
+	// that override default chart values.
+	valuesFilePattern = "chart-values-%d.yaml"
+
+	// valuesHashLength is the number of hex characters of the values hash that
+	// are appended to the rendered chart directory.
+	valuesHashLength = 8
+
+	// chartLayerMediaType is the media type of the layer holding the chart
+	// archive in an OCI registry.
+	chartLayerMediaType types.MediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
 )
 
+// ValuesSource is a source of Helm values. Exactly one of File and Inline is
+// set.
+type ValuesSource struct {
+	// File is the path of a values file. Relative paths refer to files in the
+	// chart, and absolute paths to files mounted in the container.
+	File string `json:"file,omitempty"`
+	// Inline holds the values in YAML or JSON.
+	Inline string `json:"inline,omitempty"`
+}
+
 // Hydrator runs the helm hydration process.
 type Hydrator struct {
 	Chart       string
@@ -42,28 +73,36 @@ type Hydrator struct {
 	Version     string
 	ReleaseName string
 	Namespace   string
-	Values      string
-	IncludeCRDs string
-	HydrateRoot string
-	Dest        string
-	Auth        configsync.AuthType
-	UserName    string
-	Password    string
+	// ValuesSources is the ordered list of values. Later values override
+	// earlier ones.
+	ValuesSources []ValuesSource
+	IncludeCRDs   string
+	HydrateRoot   string
+	Dest          string
+	Auth          configsync.AuthType
+	UserName      string
+	Password      string
+	// Credentials mints the credentials to the Helm repository when Auth is
+	// k8sserviceaccount.
+	Credentials credentials.Provider
+	// Verifier verifies the signature of charts stored in an OCI registry
+	// before they are rendered. The signature is not verified if it is nil.
+	Verifier *oci.Verifier
 }
 
-func (h *Hydrator) templateArgs(ctx context.Context, destDir string) ([]string, error) {
+// templateArgs returns the arguments of helm template. If chartDir is set, the
+// chart is rendered from that local directory instead of the repository.
+func (h *Hydrator) templateArgs(ctx context.Context, destDir, chartDir string) ([]string, error) {
 	args := []string{"template"}
 	var err error
 
 	if h.ReleaseName != "" {
 		args = append(args, h.ReleaseName)
 	}
-	if h.isOCI() {
-		args = append(args, h.Repo+"/"+h.Chart)
+	if chartDir != "" {
+		args = append(args, chartDir)
 	} else {
-		args = append(args, h.Chart)
-		args = append(args, "--repo", h.Repo)
-		args, err = h.appendAuthArgs(ctx, args)
+		args, err = h.appendChartArgs(ctx, args)
 		if err != nil {
 			return nil, err
 		}
@@ -73,14 +112,12 @@ func (h *Hydrator) templateArgs(ctx context.Context, destDir string) ([]string,
 	} else {
 		args = append(args, "--namespace", configsync.DefaultHelmReleaseNamespace)
 	}
-	if h.Version != "" {
+	if h.Version != "" && chartDir == "" {
 		args = append(args, "--version", h.Version)
 	}
-	if len(h.Values) > 0 {
-		args, err = h.appendValuesArgs(args)
-		if err != nil {
-			return nil, err
-		}
+	args, err = h.appendValuesArgs(args, chartDir)
+	if err != nil {
+		return nil, err
 	}
 	includeCRDs, _ := strconv.ParseBool(h.IncludeCRDs)
 	if includeCRDs {
@@ -90,15 +127,91 @@ func (h *Hydrator) templateArgs(ctx context.Context, destDir string) ([]string,
 	return args, nil
 }
 
-func (h *Hydrator) appendValuesArgs(args []string) ([]string, error) {
-	valuesPath := filepath.Join(os.TempDir(), valuesFile)
-	if err := os.WriteFile(valuesPath, []byte(h.Values), 0644); err != nil {
-		return nil, fmt.Errorf("failed to create values file: %w", err)
+// appendChartArgs appends the arguments that locate the chart in the repository.
+func (h *Hydrator) appendChartArgs(ctx context.Context, args []string) ([]string, error) {
+	if h.isOCI() {
+		return append(args, h.Repo+"/"+h.Chart), nil
+	}
+	args = append(args, h.Chart)
+	args = append(args, "--repo", h.Repo)
+	return h.appendAuthArgs(ctx, args)
+}
+
+// appendValuesArgs appends the values in order, so that later values override
+// earlier ones. Inline values are written to files first.
+func (h *Hydrator) appendValuesArgs(args []string, chartDir string) ([]string, error) {
+	for i, src := range h.ValuesSources {
+		switch {
+		case src.Inline != "":
+			valuesPath := filepath.Join(os.TempDir(), fmt.Sprintf(valuesFilePattern, i))
+			if err := os.WriteFile(valuesPath, []byte(src.Inline), 0644); err != nil {
+				return nil, fmt.Errorf("failed to create values file: %w", err)
+			}
+			args = append(args, "--values", valuesPath)
+		case src.File == "":
+			return nil, fmt.Errorf("values source %d sets neither a file nor inline values", i)
+		case filepath.IsAbs(src.File):
+			args = append(args, "--values", src.File)
+		case chartDir == "":
+			return nil, fmt.Errorf("unable to locate the values file %q without pulling the chart", src.File)
+		default:
+			args = append(args, "--values", filepath.Join(chartDir, filepath.Clean(src.File)))
+		}
 	}
-	args = append(args, "--values", valuesPath)
 	return args, nil
 }
 
+// hasChartValuesFiles returns true if any values file is read from the chart.
+func (h *Hydrator) hasChartValuesFiles() bool {
+	for _, src := range h.ValuesSources {
+		if src.Inline == "" && src.File != "" && !filepath.IsAbs(src.File) {
+			return true
+		}
+	}
+	return false
+}
+
+// pullArgs returns the arguments of helm pull to download and unpack the chart
+// into untarDir.
+func (h *Hydrator) pullArgs(ctx context.Context, untarDir string) ([]string, error) {
+	args, err := h.appendChartArgs(ctx, []string{"pull"})
+	if err != nil {
+		return nil, err
+	}
+	if h.Version != "" {
+		args = append(args, "--version", h.Version)
+	}
+	args = append(args, "--untar", "--untardir", untarDir)
+	return args, nil
+}
+
+// valuesHash returns a hash of the mounted values files and the inline values.
+// Unlike the chart, the mounted values files may change without a new chart
+// version, so the hash is used to render the chart again when they change.
+// It returns an empty string if no values files are mounted.
+func (h *Hydrator) valuesHash() (string, error) {
+	hash := sha256.New()
+	mounted := false
+	for _, src := range h.ValuesSources {
+		if src.Inline != "" || !filepath.IsAbs(src.File) {
+			hash.Write([]byte(src.File))
+			hash.Write([]byte(src.Inline))
+			continue
+		}
+		mounted = true
+		content, err := os.ReadFile(src.File)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the values file %q: %w", src.File, err)
+		}
+		hash.Write([]byte(src.File))
+		hash.Write(content)
+	}
+	if !mounted {
+		return "", nil
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:valuesHashLength], nil
+}
+
 func (h *Hydrator) registryLoginArgs(ctx context.Context) ([]string, error) {
 	args := []string{"registry", "login"}
 	args, err := h.appendAuthArgs(ctx, args)
@@ -125,14 +238,22 @@ func fetchNewToken(ctx context.Context) (*oauth2.Token, error) {
 // HelmTemplate runs helm template with args
 func (h *Hydrator) HelmTemplate(ctx context.Context) error {
 	//TODO: add logic to handle "latest" version
-	destDir := filepath.Join(h.HydrateRoot, h.Chart+":"+h.Version)
+	renderedName := h.Chart + ":" + h.Version
+	valuesHash, err := h.valuesHash()
+	if err != nil {
+		return err
+	}
+	if valuesHash != "" {
+		renderedName += ":" + valuesHash
+	}
+	destDir := filepath.Join(h.HydrateRoot, renderedName)
 	linkPath := filepath.Join(h.HydrateRoot, h.Dest)
 	oldDir, err := filepath.EvalSymlinks(linkPath)
 	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to evaluate the symbolic path %q to the Helm chart: %w", linkPath, err)
 	}
 	if oldDir == destDir {
-		klog.Infof("no update required with the same helm chart version %q", h.Version)
+		klog.Infof("no update required with the same helm chart version %q and values", h.Version)
 		return nil
 	}
 	if h.Auth != configsync.AuthNone && h.isOCI() {
@@ -145,7 +266,36 @@ func (h *Hydrator) HelmTemplate(ctx context.Context) error {
 			return fmt.Errorf("failed to authenticate to helm registry: %w, stdout: %s", err, string(out))
 		}
 	}
-	args, err := h.templateArgs(ctx, destDir)
+	var chartDir string
+	if h.Verifier != nil || h.hasChartValuesFiles() {
+		untarDir, err := os.MkdirTemp("", "helm-chart-")
+		if err != nil {
+			return fmt.Errorf("failed to create a directory to pull the helm chart: %w", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(untarDir); err != nil {
+				klog.Warningf("failed to remove the pulled helm chart %q: %v", untarDir, err)
+			}
+		}()
+		if h.Verifier != nil {
+			// The verified chart is rendered from its local copy, rather than
+			// pulled again by tag, which may have been repointed since.
+			if err := h.pullVerifiedChart(ctx, untarDir); err != nil {
+				return err
+			}
+		} else {
+			args, err := h.pullArgs(ctx, untarDir)
+			if err != nil {
+				return err
+			}
+			out, err := exec.CommandContext(ctx, "helm", args...).CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("failed to pull the helm chart: %w, stdout: %s", err, string(out))
+			}
+		}
+		chartDir = filepath.Join(untarDir, h.chartName())
+	}
+	args, err := h.templateArgs(ctx, destDir, chartDir)
 	if err != nil {
 		return err
 	}
@@ -157,6 +307,151 @@ func (h *Hydrator) HelmTemplate(ctx context.Context) error {
 	return util.UpdateSymlink(h.HydrateRoot, linkPath, destDir, oldDir)
 }
 
+// pullVerifiedChart verifies the signature of the chart stored in an OCI
+// registry, and unpacks the chart of the verified digest into untarDir.
+func (h *Hydrator) pullVerifiedChart(ctx context.Context, untarDir string) error {
+	if !h.isOCI() {
+		return fmt.Errorf("signature verification is only supported for charts in an OCI registry")
+	}
+	if h.Version == "" {
+		return fmt.Errorf("signature verification requires the chart version")
+	}
+	// Helm replaces the "+" character of the chart version with "_" in the
+	// OCI tag, because "+" is not allowed in tags.
+	imageName := strings.TrimPrefix(h.Repo, "oci://") + "/" + h.Chart + ":" + strings.ReplaceAll(h.Version, "+", "_")
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return fmt.Errorf("failed to parse reference %q: %w", imageName, err)
+	}
+	auth, err := h.authenticator(ctx)
+	if err != nil {
+		return err
+	}
+	options := []remote.Option{remote.WithContext(ctx), remote.WithAuth(auth)}
+	desc, err := remote.Head(ref, options...)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the digest of the helm chart %s: %w", ref, err)
+	}
+	digest := ref.Context().Digest(desc.Digest.String())
+	if err := h.Verifier.Verify(digest, options...); err != nil {
+		return err
+	}
+	klog.Infof("verified the signature of helm chart digest %q", desc.Digest)
+	// The manifest and the layers pulled by digest are checked against their
+	// digests.
+	image, err := remote.Image(digest, options...)
+	if err != nil {
+		return fmt.Errorf("failed to pull the helm chart %s: %w", digest, err)
+	}
+	if err := extractChart(image, untarDir); err != nil {
+		return fmt.Errorf("failed to extract the helm chart %s: %w", digest, err)
+	}
+	return nil
+}
+
+// extractChart unpacks the chart archive layer of the OCI image into dir.
+func extractChart(image v1.Image, dir string) error {
+	manifest, err := image.Manifest()
+	if err != nil {
+		return err
+	}
+	for _, desc := range manifest.Layers {
+		if desc.MediaType != chartLayerMediaType {
+			continue
+		}
+		layer, err := image.LayerByDigest(desc.Digest)
+		if err != nil {
+			return err
+		}
+		rc, err := layer.Compressed()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := rc.Close(); err != nil {
+				klog.Warningf("failed to close the helm chart layer: %v", err)
+			}
+		}()
+		return untarChart(rc, dir)
+	}
+	return fmt.Errorf("no layer with the media type %s", chartLayerMediaType)
+}
+
+// untarChart unpacks the gzipped tar archive of a chart into dir. Only the
+// directories and regular files are unpacked, and only inside dir.
+func untarChart(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, hdr.Name)
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path %q in the chart archive", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := writeFile(path, tr); err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader:
+		default:
+			return fmt.Errorf("unsupported type of %q in the chart archive", hdr.Name)
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// authenticator returns the authenticator to the OCI registry of the chart.
+func (h *Hydrator) authenticator(ctx context.Context) (authn.Authenticator, error) {
+	switch h.Auth {
+	case configsync.AuthToken:
+		return &authn.Basic{Username: h.UserName, Password: h.Password}, nil
+	case configsync.AuthGCPServiceAccount, configsync.AuthGCENode:
+		token, err := fetchNewToken(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch new token: %w", err)
+		}
+		return &authn.Basic{Username: "oauth2accesstoken", Password: token.AccessToken}, nil
+	case configsync.AuthK8sServiceAccount:
+		return credentials.Authenticator(ctx, h.Credentials), nil
+	default:
+		return authn.Anonymous, nil
+	}
+}
+
+// chartName returns the name of the directory that helm pull unpacks the chart
+// into, which is the last element of the chart reference.
+func (h *Hydrator) chartName() string {
+	return filepath.Base(h.Chart)
+}
+
 func (h *Hydrator) isOCI() bool {
 	return strings.HasPrefix(h.Repo, "oci://")
 }
@@ -173,6 +468,13 @@ func (h *Hydrator) appendAuthArgs(ctx context.Context, args []string) ([]string,
 		}
 		args = append(args, "--username", "oauth2accesstoken")
 		args = append(args, "--password", token.AccessToken)
+	case configsync.AuthK8sServiceAccount:
+		creds, err := h.Credentials.Credentials(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch new credentials: %w", err)
+		}
+		args = append(args, "--username", creds.Username)
+		args = append(args, "--password", creds.Password)
 	}
 	return args, nil
 }
@@ -0,0 +1,295 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"kpt.dev/configsync/pkg/api/configsync"
+)
+
+func TestTemplateArgs(t *testing.T) {
+	inlineValues := func(i int) string {
+		return filepath.Join(os.TempDir(), fmt.Sprintf(valuesFilePattern, i))
+	}
+	testCases := []struct {
+		name     string
+		hydrator *Hydrator
+		chartDir string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "remote chart without values",
+			hydrator: &Hydrator{
+				Chart:   "my-chart",
+				Repo:    "https://example.com/charts",
+				Version: "1.0.0",
+				Auth:    configsync.AuthNone,
+			},
+			want: []string{"template", "my-chart", "--repo", "https://example.com/charts",
+				"--namespace", "default", "--version", "1.0.0", "--output-dir", "/dest"},
+		},
+		{
+			name: "mounted values files before inline values",
+			hydrator: &Hydrator{
+				Chart:   "my-chart",
+				Repo:    "oci://example.com/charts",
+				Version: "1.0.0",
+				ValuesSources: []ValuesSource{
+					{File: "/etc/helm-values/0-values.yaml"},
+					{File: "/etc/helm-values/1-prod.yaml"},
+					{Inline: "replicas: 3"},
+				},
+			},
+			want: []string{"template", "oci://example.com/charts/my-chart",
+				"--namespace", "default", "--version", "1.0.0",
+				"--values", "/etc/helm-values/0-values.yaml",
+				"--values", "/etc/helm-values/1-prod.yaml",
+				"--values", inlineValues(2),
+				"--output-dir", "/dest"},
+		},
+		{
+			name: "values sources in the declared order",
+			hydrator: &Hydrator{
+				Chart:   "my-chart",
+				Repo:    "oci://example.com/charts",
+				Version: "1.0.0",
+				ValuesSources: []ValuesSource{
+					{Inline: "replicas: 3"},
+					{File: "/etc/helm-values/1-prod.yaml"},
+					{File: "values-prod.yaml"},
+					{Inline: "image: {tag: v2}"},
+				},
+			},
+			chartDir: "/tmp/chart/my-chart",
+			want: []string{"template", "/tmp/chart/my-chart",
+				"--namespace", "default",
+				"--values", inlineValues(0),
+				"--values", "/etc/helm-values/1-prod.yaml",
+				"--values", "/tmp/chart/my-chart/values-prod.yaml",
+				"--values", inlineValues(3),
+				"--output-dir", "/dest"},
+		},
+		{
+			name: "chart values files from the pulled chart",
+			hydrator: &Hydrator{
+				Chart:     "my-chart",
+				Repo:      "oci://example.com/charts",
+				Version:   "1.0.0",
+				Namespace: "prod",
+				ValuesSources: []ValuesSource{
+					{File: "values-prod.yaml"},
+					{File: "/etc/helm-values/0-values.yaml"},
+				},
+			},
+			chartDir: "/tmp/chart/my-chart",
+			want: []string{"template", "/tmp/chart/my-chart",
+				"--namespace", "prod",
+				"--values", "/tmp/chart/my-chart/values-prod.yaml",
+				"--values", "/etc/helm-values/0-values.yaml",
+				"--output-dir", "/dest"},
+		},
+		{
+			name: "chart values files without the pulled chart",
+			hydrator: &Hydrator{
+				Chart:         "my-chart",
+				Repo:          "oci://example.com/charts",
+				ValuesSources: []ValuesSource{{File: "values-prod.yaml"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty values source",
+			hydrator: &Hydrator{
+				Chart:         "my-chart",
+				Repo:          "oci://example.com/charts",
+				ValuesSources: []ValuesSource{{}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.hydrator.templateArgs(context.Background(), "/dest", tc.chartDir)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("templateArgs() got error %v, want error %t", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("templateArgs() diff (- want, + got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValuesHash(t *testing.T) {
+	dir := t.TempDir()
+	valuesPath := filepath.Join(dir, "0-values.yaml")
+	if err := os.WriteFile(valuesPath, []byte("replicas: 1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &Hydrator{ValuesSources: []ValuesSource{{File: "values-prod.yaml"}, {Inline: "replicas: 3"}}}
+	if got, err := h.valuesHash(); err != nil || got != "" {
+		t.Errorf("valuesHash() = %q, %v, want no hash without mounted values files", got, err)
+	}
+
+	h.ValuesSources = append(h.ValuesSources, ValuesSource{File: valuesPath})
+	first, err := h.valuesHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != valuesHashLength {
+		t.Errorf("valuesHash() = %q, want %d characters", first, valuesHashLength)
+	}
+
+	if err := os.WriteFile(valuesPath, []byte("replicas: 2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	second, err := h.valuesHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Errorf("valuesHash() = %q after the values file changed, want a different hash", second)
+	}
+
+	h.ValuesSources[1].Inline = "replicas: 4"
+	third, err := h.valuesHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third == second {
+		t.Errorf("valuesHash() = %q after the inline values changed, want a different hash", third)
+	}
+
+	h.ValuesSources = append(h.ValuesSources, ValuesSource{File: filepath.Join(dir, "missing.yaml")})
+	if _, err := h.valuesHash(); err == nil {
+		t.Error("valuesHash() got no error for a missing values file, want error")
+	}
+}
+
+// chartArchive returns a gzipped tar archive of the files.
+func chartArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// chartImage returns an OCI image with the archive as a layer of the media
+// type.
+func chartImage(t *testing.T, archive []byte, mediaType types.MediaType) v1.Image {
+	t.Helper()
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(archive)), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	image, err := mutate.Append(empty.Image, mutate.Addendum{Layer: layer, MediaType: mediaType})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return image
+}
+
+func TestExtractChart(t *testing.T) {
+	chart := map[string]string{
+		"mychart/Chart.yaml":            "name: mychart",
+		"mychart/values-prod.yaml":      "replicas: 3",
+		"mychart/templates/deploy.yaml": "kind: Deployment",
+	}
+	testCases := []struct {
+		name      string
+		image     v1.Image
+		wantFiles map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "chart layer",
+			image:     chartImage(t, chartArchive(t, chart), chartLayerMediaType),
+			wantFiles: chart,
+		},
+		{
+			name:    "no chart layer",
+			image:   chartImage(t, chartArchive(t, chart), types.DockerLayer),
+			wantErr: true,
+		},
+		{
+			name:    "path outside of the directory",
+			image:   chartImage(t, chartArchive(t, map[string]string{"../escaped.yaml": "kind: Secret"}), chartLayerMediaType),
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parent := t.TempDir()
+			dir := filepath.Join(parent, "untar")
+			err := extractChart(tc.image, dir)
+			if tc.wantErr {
+				if err == nil {
+					t.Error("extractChart() got no error, want error")
+				}
+				if _, err := os.Stat(filepath.Join(parent, "escaped.yaml")); err == nil {
+					t.Error("extractChart() wrote a file outside of the directory")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractChart() = %v", err)
+			}
+			for name, want := range tc.wantFiles {
+				got, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != want {
+					t.Errorf("got %s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hydrate
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// BuildOptions are the options of `kustomize build`.
+// The zero value enables all the plugins, and uses the default load restrictor
+// and Helm binary of Kustomize.
+type BuildOptions struct {
+	// DisableHelm disables the Helm chart inflator generator, which renders the
+	// helmCharts field of a kustomization.
+	DisableHelm bool
+	// DisableAlphaPlugins disables the alpha plugins, including exec plugins
+	// and the Helm inflation function.
+	DisableAlphaPlugins bool
+	// LoadRestrictor is the value of the `--load-restrictor` flag.
+	LoadRestrictor string
+	// HelmCommand is the path of the Helm binary.
+	HelmCommand string
+}
+
+// args returns the `kustomize build` flags for the options.
+func (o BuildOptions) args() []string {
+	var args []string
+	// The `--enable-alpha-plugins` and `--enable-exec` flags are to support rendering
+	// Helm charts using the Helm inflation function.
+	// The `--enable-helm` flag is to enable use of the Helm chart inflator generator.
+	// Both are enabled by default so that both the Helm plugin and Helm
+	// inflation function are supported. This provides us with a fallback plan
+	// if the new Helm inflation function is having issues.
+	// It has no side-effect if no Helm chart in the DRY configs.
+	if !o.DisableAlphaPlugins {
+		args = append(args, "--enable-alpha-plugins", "--enable-exec")
+	}
+	if !o.DisableHelm {
+		args = append(args, "--enable-helm")
+		if o.HelmCommand != "" {
+			args = append(args, "--helm-command", o.HelmCommand)
+		}
+	}
+	if o.LoadRestrictor != "" {
+		args = append(args, "--load-restrictor", o.LoadRestrictor)
+	}
+	return args
+}
+
+// checkBuildOptions returns a KustomizeError for the first kustomization
+// reachable from input that uses a feature which is not available with opts.
+func checkBuildOptions(input string, opts BuildOptions) HydrationError {
+	visited := make(map[string]bool)
+	var check func(dir string) HydrationError
+	check = func(dir string) HydrationError {
+		if visited[dir] {
+			return nil
+		}
+		visited[dir] = true
+		file := kustomizationFile(dir)
+		if file == "" {
+			return nil
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return NewInternalError(errors.Wrapf(err, "unable to read %s", file))
+		}
+		kt := &types.Kustomization{}
+		if err := yaml.Unmarshal(b, kt); err != nil {
+			// Leave it to `kustomize build` to report a better error message.
+			return nil
+		}
+		if len(kt.HelmCharts) > 0 {
+			if opts.DisableHelm {
+				return newKustomizeError(input, file, fmt.Errorf("the helmCharts field requires Helm rendering, which is disabled by spec.override.rendering.enableHelm"))
+			}
+			if opts.HelmCommand != "" {
+				if _, err := exec.LookPath(opts.HelmCommand); err != nil {
+					return newKustomizeError(input, file, errors.Wrapf(err, "the helmCharts field requires the Helm binary %q set by spec.override.rendering.helmCommand", opts.HelmCommand))
+				}
+			}
+		}
+		refs := append(append(append([]string{}, kt.Resources...), kt.Components...), kt.Bases...)
+		for _, ref := range refs {
+			refDir := filepath.Join(dir, ref)
+			if fi, err := os.Stat(refDir); err != nil || !fi.IsDir() {
+				// Skip the files and the remote bases.
+				continue
+			}
+			if err := check(refDir); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return check(input)
+}
+
+// kustomizationFile returns the path of the kustomization file in dir, or an
+// empty string if dir does not have one.
+func kustomizationFile(dir string) string {
+	for _, name := range validKustomizationFiles {
+		file := filepath.Join(dir, name)
+		if fi, err := os.Stat(file); err == nil && !fi.IsDir() {
+			return file
+		}
+	}
+	return ""
+}
+
+// newKustomizeError returns a KustomizeError for the kustomization file, with
+// the source path relative to input.
+func newKustomizeError(input, file string, err error) KustomizeError {
+	rel, relErr := filepath.Rel(input, file)
+	if relErr != nil {
+		rel = file
+	}
+	return NewKustomizeError(err, filepath.ToSlash(rel))
+}
+
+// kustomizeBuildError maps the error of a failed `kustomize build` in input to
+// a HydrationError. The error is a KustomizeError for the innermost
+// kustomization under input that is referred to by the error message. The
+// absolute paths in the message are made relative to input.
+func kustomizeBuildError(input string, err error) HydrationError {
+	msg := err.Error()
+	var lines []string
+	for _, line := range strings.Split(msg, "\n") {
+		if strings.HasPrefix(line, "Error: ") {
+			lines = append(lines, strings.TrimPrefix(line, "Error: "))
+		}
+	}
+	if len(lines) > 0 {
+		msg = strings.Join(lines, "; ")
+	}
+	msg = strings.TrimSpace(msg)
+
+	roots := []string{filepath.Clean(input)}
+	if resolved, err := filepath.EvalSymlinks(input); err == nil && resolved != roots[0] {
+		roots = append(roots, resolved)
+	}
+
+	file := ""
+	last := -1
+	for _, root := range roots {
+		re := regexp.MustCompile(regexp.QuoteMeta(root) + `(/[^\s'":]*)?`)
+		for _, loc := range re.FindAllStringIndex(msg, -1) {
+			if loc[0] < last {
+				continue
+			}
+			if f := enclosingKustomization(root, msg[loc[0]:loc[1]]); f != "" {
+				last = loc[0]
+				file = filepath.Join(input, strings.TrimPrefix(f, root))
+			}
+		}
+	}
+	for _, root := range roots {
+		msg = strings.ReplaceAll(msg, root+"/", "")
+		msg = strings.ReplaceAll(msg, root, ".")
+	}
+
+	buildErr := errors.Errorf("failed to run kustomize build: %s", msg)
+	if file == "" {
+		file = kustomizationFile(input)
+	}
+	if file == "" {
+		return NewActionableError(buildErr)
+	}
+	return newKustomizeError(input, file, buildErr)
+}
+
+// enclosingKustomization returns the kustomization file of the closest
+// directory of p, up to root, that has one.
+func enclosingKustomization(root, p string) string {
+	dir := filepath.Clean(p)
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	for dir == root || strings.HasPrefix(dir, root+string(filepath.Separator)) {
+		if f := kustomizationFile(dir); f != "" {
+			return f
+		}
+		if dir == root {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hydrate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBuildOptionsArgs(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts BuildOptions
+		want []string
+	}{
+		{
+			name: "default options",
+			want: []string{"--enable-alpha-plugins", "--enable-exec", "--enable-helm"},
+		},
+		{
+			name: "helm and alpha plugins disabled",
+			opts: BuildOptions{DisableHelm: true, DisableAlphaPlugins: true, HelmCommand: "/usr/local/bin/helm"},
+			want: nil,
+		},
+		{
+			name: "load restrictor and helm command",
+			opts: BuildOptions{DisableAlphaPlugins: true, LoadRestrictor: "LoadRestrictionsNone", HelmCommand: "/usr/local/bin/helm"},
+			want: []string{"--enable-helm", "--helm-command", "/usr/local/bin/helm", "--load-restrictor", "LoadRestrictionsNone"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.opts.args()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+// writeKustomizations writes the kustomization.yaml files under a temporary
+// directory, keyed by their directory, and returns the directory.
+func writeKustomizations(t *testing.T, kustomizations map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for d, content := range kustomizations {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, d, "kustomization.yaml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCheckBuildOptions(t *testing.T) {
+	kustomizations := map[string]string{
+		".":               "resources:\n- base\n- https://github.com/example/remote\n",
+		"base":            "components:\n- ../components/helm\n",
+		"components/helm": "kind: Component\nhelmCharts:\n- name: nginx\n",
+	}
+	testCases := []struct {
+		name     string
+		opts     BuildOptions
+		wantPath string
+	}{
+		{
+			name: "helm enabled",
+		},
+		{
+			name:     "helm disabled",
+			opts:     BuildOptions{DisableHelm: true},
+			wantPath: "components/helm/kustomization.yaml",
+		},
+		{
+			name:     "helm command not found",
+			opts:     BuildOptions{HelmCommand: "/does/not/exist/helm"},
+			wantPath: "components/helm/kustomization.yaml",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeKustomizations(t, kustomizations)
+			err := checkBuildOptions(dir, tc.opts)
+			if tc.wantPath == "" {
+				if err != nil {
+					t.Fatalf("got unexpected error: %v", err)
+				}
+				return
+			}
+			kustomizeErr, ok := err.(KustomizeError)
+			if !ok {
+				t.Fatalf("got error %v, want a KustomizeError", err)
+			}
+			if kustomizeErr.SourcePath != tc.wantPath {
+				t.Errorf("got source path %q, want %q", kustomizeErr.SourcePath, tc.wantPath)
+			}
+		})
+	}
+}
+
+func TestKustomizeBuildError(t *testing.T) {
+	dir := writeKustomizations(t, map[string]string{
+		".":             "resources:\n- overlays/prod\n",
+		"overlays/prod": "resources:\n- deployment.yaml\n",
+	})
+	testCases := []struct {
+		name     string
+		stderr   string
+		wantPath string
+		wantMsg  string
+	}{
+		{
+			name:     "file in a nested kustomization",
+			stderr:   "Error: accumulating resources: accumulation err='accumulating resources from 'overlays/prod': '" + dir + "/overlays/prod/deployment.yaml' must resolve to a file'\n",
+			wantPath: "overlays/prod/kustomization.yaml",
+			wantMsg:  "failed to run kustomize build: accumulating resources: accumulation err='accumulating resources from 'overlays/prod': 'overlays/prod/deployment.yaml' must resolve to a file'",
+		},
+		{
+			name:     "no path in the error",
+			stderr:   "Error: invalid Kustomization: json: unknown field \"resourcez\"\n",
+			wantPath: "kustomization.yaml",
+			wantMsg:  "failed to run kustomize build: invalid Kustomization: json: unknown field \"resourcez\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := kustomizeBuildError(dir, errors.New(tc.stderr))
+			kustomizeErr, ok := err.(KustomizeError)
+			if !ok {
+				t.Fatalf("got error %v, want a KustomizeError", err)
+			}
+			if kustomizeErr.SourcePath != tc.wantPath {
+				t.Errorf("got source path %q, want %q", kustomizeErr.SourcePath, tc.wantPath)
+			}
+			if kustomizeErr.Error() != tc.wantMsg {
+				t.Errorf("got message %q, want %q", kustomizeErr.Error(), tc.wantMsg)
+			}
+		})
+	}
+}
@@ -65,6 +65,11 @@ type Hydrator struct {
 	RehydratePeriod time.Duration
 	// ReconcilerName is the name of the reconciler.
 	ReconcilerName string
+	// Triggers receives a value whenever the webhook endpoint accepts a source
+	// change notification. A nil channel disables webhook triggers.
+	Triggers <-chan struct{}
+	// BuildOptions are the options of `kustomize build`.
+	BuildOptions BuildOptions
 }
 
 // Run runs the hydration process periodically.
@@ -90,19 +95,28 @@ func (h *Hydrator) Run(ctx context.Context) {
 			}
 			rehydrateTimer.Reset(h.RehydratePeriod) // Schedule rehydrate attempt
 		case <-runTimer.C:
-			commit, syncDir, err := SourceCommitAndDir(h.SourceType, absSourceDir, h.SyncDir, h.ReconcilerName)
-			if err != nil {
-				klog.Errorf("failed to get the commit hash and sync directory from the source directory %s: %v", absSourceDir.OSPath(), err)
-			} else if DoneCommit(h.DonePath.OSPath()) != commit {
-				// If the commit has been processed before, regardless of success or failure,
-				// skip the hydration to avoid repeated execution.
-				// The rehydrate ticker will retry on the failed commit.
-				hydrateErr := h.hydrate(commit, syncDir.OSPath())
-				if err := h.complete(commit, hydrateErr); err != nil {
-					klog.Errorf("failed to complete the rendering execution for commit %q: %v", commit, err)
-				}
-			}
+			h.runOnce(absSourceDir)
 			runTimer.Reset(h.PollingPeriod) // Schedule re-run attempt
+		case <-h.Triggers:
+			klog.Infof("Source change notification received from the webhook")
+			h.runOnce(absSourceDir)
+			runTimer.Reset(h.PollingPeriod) // Schedule re-run attempt
+		}
+	}
+}
+
+// runOnce renders the latest source commit, unless it has been processed before.
+func (h *Hydrator) runOnce(absSourceDir cmpath.Absolute) {
+	commit, syncDir, err := SourceCommitAndDir(h.SourceType, absSourceDir, h.SyncDir, h.ReconcilerName)
+	if err != nil {
+		klog.Errorf("failed to get the commit hash and sync directory from the source directory %s: %v", absSourceDir.OSPath(), err)
+	} else if DoneCommit(h.DonePath.OSPath()) != commit {
+		// If the commit has been processed before, regardless of success or failure,
+		// skip the hydration to avoid repeated execution.
+		// The rehydrate ticker will retry on the failed commit.
+		hydrateErr := h.hydrate(commit, syncDir.OSPath())
+		if err := h.complete(commit, hydrateErr); err != nil {
+			klog.Errorf("failed to complete the rendering execution for commit %q: %v", commit, err)
 		}
 	}
 }
@@ -112,7 +126,12 @@ func (h *Hydrator) runHydrate(sourceCommit, syncDir string) HydrationError {
 	newHydratedDir := h.HydratedRoot.Join(cmpath.RelativeOS(sourceCommit))
 	dest := newHydratedDir.Join(h.SyncDir).OSPath()
 
-	if err := kustomizeBuild(syncDir, dest, true); err != nil {
+	if err := kustomizeBuild(syncDir, dest, h.BuildOptions, true); err != nil {
+		if kustomizeErr, ok := err.(KustomizeError); ok {
+			// Make the source path relative to the root of the repository.
+			kustomizeErr.SourcePath = path.Join(h.SyncDir.SlashPath(), kustomizeErr.SourcePath)
+			return kustomizeErr
+		}
 		return err
 	}
 
@@ -294,6 +313,9 @@ func exportError(commit, root, errorFile string, hydrationError HydrationError)
 		Code:  hydrationError.Code(),
 		Error: hydrationError.Error(),
 	}
+	if kustomizeErr, ok := hydrationError.(KustomizeError); ok {
+		payload.SourcePath = kustomizeErr.SourcePath
+	}
 
 	jb, err := json.Marshal(payload)
 	if err != nil {
@@ -322,6 +344,18 @@ func deleteErrorFile(file string) error {
 	return nil
 }
 
+// sourceErrorCode returns the error code exported in the error file of the
+// oci-sync or helm-sync container, if any.
+func sourceErrorCode(content []byte) string {
+	payload := struct {
+		Code string
+	}{}
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return ""
+	}
+	return payload.Code
+}
+
 // SourceCommitAndDir returns the source hash (a git commit hash or an OCI image digest or a helm chart version), the absolute path of the sync directory, and source errors.
 func SourceCommitAndDir(sourceType v1beta1.SourceType, sourceRevDir cmpath.Absolute, syncDir cmpath.Relative, reconcilerName string) (string, cmpath.Absolute, status.Error) {
 	// Check if the source root directory is mounted
@@ -355,6 +389,9 @@ func SourceCommitAndDir(sourceType v1beta1.SourceType, sourceRevDir cmpath.Absol
 			errFilePath, containerName, configsync.ControllerNamespace,
 			metadata.ReconcilerLabel, reconcilerName).Build()
 	} else if err == nil {
+		if sourceErrorCode(content) == status.SignatureVerificationErrorCode {
+			return "", "", status.SignatureVerificationErrorBuilder.Sprintf("error in the %s container: %s", containerName, string(content)).Build()
+		}
 		return "", "", status.SourceError.Sprintf("error in the %s container: %s", containerName, string(content)).Build()
 	}
 
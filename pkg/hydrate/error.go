@@ -38,6 +38,19 @@ func (e ActionableError) Code() string {
 	return status.ActionableHydrationErrorCode
 }
 
+// KustomizeError represents the user actionable error of a kustomization.
+type KustomizeError struct {
+	ActionableError
+	// SourcePath is the slash path of the failing kustomization file.
+	SourcePath string
+}
+
+// NewKustomizeError returns the wrapper of the user actionable error of the
+// kustomization file at sourcePath.
+func NewKustomizeError(e error, sourcePath string) KustomizeError {
+	return KustomizeError{ActionableError: NewActionableError(e), SourcePath: sourcePath}
+}
+
 // InternalError represents the internal hydration error.
 type InternalError struct {
 	error
@@ -74,4 +87,7 @@ type HydrationErrorPayload struct {
 	Code string
 	// Error is the message of the hydration error.
 	Error string
+	// SourcePath is the slash path of the failing kustomization file within
+	// the repository, if known.
+	SourcePath string `json:",omitempty"`
 }
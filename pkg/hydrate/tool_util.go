@@ -27,9 +27,15 @@ import (
 
 	"github.com/Masterminds/semver"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/cmd/nomos/flags"
 	nomosparse "kpt.dev/configsync/cmd/nomos/parse"
+	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/client/restconfig"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/importer/filesystem"
@@ -38,7 +44,9 @@ import (
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/util/discovery"
 	"kpt.dev/configsync/pkg/validate"
+	"kpt.dev/configsync/pkg/validate/policy"
 	"kpt.dev/configsync/pkg/vet"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -66,15 +74,15 @@ func needsKustomize(dir string) (bool, error) {
 		return false, errors.Wrapf(err, "unable to traverse the directory: %s", dir)
 	}
 	for _, f := range files {
-		if hasKustomization(filepath.Base(f.Name())) {
+		if HasKustomization(filepath.Base(f.Name())) {
 			return true, nil
 		}
 	}
 	return false, nil
 }
 
-// hasKustomization checks if the file is a Kustomize configuration file.
-func hasKustomization(filename string) bool {
+// HasKustomization checks if the file is a Kustomize configuration file.
+func HasKustomization(filename string) bool {
 	for _, kustomization := range validKustomizationFiles {
 		if filename == kustomization {
 			return true
@@ -98,7 +106,7 @@ func hasKustomizeSubdir(dir string) (bool, error) {
 			if fi.IsDir() {
 				return nil
 			}
-			if hasKustomization(fi.Name()) {
+			if HasKustomization(fi.Name()) {
 				found = true
 			}
 			return nil
@@ -125,15 +133,11 @@ func mustDeleteOutput(err error, output string) {
 }
 
 // kustomizeBuild runs the 'kustomize build' command to render the configs.
-func kustomizeBuild(input, output string, sendMetrics bool) HydrationError {
-	// The `--enable-alpha-plugins` and `--enable-exec` flags are to support rendering
-	// Helm charts using the Helm inflation function.
-	// The `--enable-helm` flag is to enable use of the Helm chart inflator generator.
-	// We decided to enable all the flags so that both the Helm plugin and Helm
-	// inflation function are supported. This provides us with a fallback plan
-	// if the new Helm inflation function is having issues.
-	// It has no side-effect if no Helm chart in the DRY configs.
-	args := []string{"--enable-alpha-plugins", "--enable-exec", "--enable-helm", "--output", output}
+func kustomizeBuild(input, output string, opts BuildOptions, sendMetrics bool) HydrationError {
+	if err := checkBuildOptions(input, opts); err != nil {
+		return err
+	}
+	args := append(opts.args(), "--output", output)
 
 	if _, err := os.Stat(output); err == nil {
 		mustDeleteOutput(err, output)
@@ -149,7 +153,7 @@ func kustomizeBuild(input, output string, sendMetrics bool) HydrationError {
 	if err != nil {
 		kustomizeErr := errors.Wrapf(err, "failed to run kustomize build in %s, stdout: %s", input, out)
 		mustDeleteOutput(kustomizeErr, output)
-		return NewActionableError(kustomizeErr)
+		return kustomizeBuildError(input, err)
 	}
 
 	return nil
@@ -238,7 +242,7 @@ func ValidateAndRunKustomize(sourcePath string) (cmpath.Absolute, error) {
 		return output, err
 	}
 
-	if err := kustomizeBuild(sourcePath, tmpHydratedDir, false); err != nil {
+	if err := kustomizeBuild(sourcePath, tmpHydratedDir, BuildOptions{}, false); err != nil {
 		return output, errors.Wrapf(err, "unable to render the source configs in %s", sourcePath)
 	}
 
@@ -284,6 +288,20 @@ func ValidateHydrateFlags(sourceFormat filesystem.SourceFormat) (cmpath.Absolute
 // ValidateOptions returns the validate options for nomos hydrate and vet commands.
 func ValidateOptions(ctx context.Context, rootDir cmpath.Absolute, apiServerTimeout time.Duration) (validate.Options, error) {
 	var options = validate.Options{}
+	if flags.ClusterLabels != "" {
+		clusterLabels, err := labels.ConvertSelectorToLabelsMap(flags.ClusterLabels)
+		if err != nil {
+			return options, fmt.Errorf("invalid --cluster-labels: %w", err)
+		}
+		options.ClusterLabels = clusterLabels
+	}
+
+	policies, err := validationPolicies(ctx, apiServerTimeout)
+	if err != nil {
+		return options, err
+	}
+	options.Policies = policies
+
 	syncedCRDs, err := nomosparse.GetSyncedCRDs(ctx, flags.SkipAPIServer, apiServerTimeout)
 	if err != nil {
 		return options, err
@@ -324,3 +342,42 @@ func ValidateOptions(ctx context.Context, rootDir cmpath.Absolute, apiServerTime
 	options.AllowUnknownKinds = flags.SkipAPIServer
 	return options, nil
 }
+
+// validationPolicies returns the validation policies declared in the
+// --policy-file flag, or else in the validation policies ConfigMap on the
+// cluster if the API server checks are enabled.
+func validationPolicies(ctx context.Context, apiServerTimeout time.Duration) ([]*policy.Policy, error) {
+	cm := &corev1.ConfigMap{}
+	switch {
+	case flags.PolicyFile != "":
+		data, err := ioutil.ReadFile(flags.PolicyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --policy-file: %w", err)
+		}
+		if err := yaml.UnmarshalStrict(data, cm); err != nil {
+			return nil, fmt.Errorf("invalid --policy-file: %w", err)
+		}
+	case flags.SkipAPIServer:
+		return nil, nil
+	default:
+		cfg, err := restconfig.NewRestConfig(apiServerTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create rest config: %w", err)
+		}
+		cs, err := kubernetes.NewForConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client: %w", err)
+		}
+		cm, err = cs.CoreV1().ConfigMaps(configsync.ControllerNamespace).Get(ctx, configsync.ValidationPoliciesName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get the validation policies ConfigMap: %w", err)
+		}
+	}
+	policies, errs := policy.FromConfigMap(cm)
+	if errs != nil {
+		return nil, errs
+	}
+	return policies, nil
+}
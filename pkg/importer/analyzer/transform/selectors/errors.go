@@ -15,6 +15,8 @@
 package selectors
 
 import (
+	v1 "kpt.dev/configsync/pkg/api/configmanagement/v1"
+	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/status"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -43,6 +45,28 @@ func EmptySelectorError(selector client.Object) status.Error {
 	return invalidSelectorError.Sprintf("%ss MUST define `spec.selector`", selector.GetObjectKind().GroupVersionKind().Kind).BuildWithResources(selector)
 }
 
+// InvalidNamespaceSelectorModeError reports that a NamespaceSelector has an
+// unknown `spec.mode`.
+func InvalidNamespaceSelectorModeError(selector client.Object, mode string) status.Error {
+	return invalidSelectorError.Sprintf("NamespaceSelectors MUST set `spec.mode` to %q or %q, but got %q",
+		v1.NSSelectorStaticMode, v1.NSSelectorDynamicMode, mode).BuildWithResources(selector)
+}
+
+// UnsupportedDynamicNamespaceSelectorError reports that a NamespaceSelector
+// uses the dynamic mode in a hierarchical repo, where Namespaces are selected
+// by their directory.
+func UnsupportedDynamicNamespaceSelectorError(selector client.Object) status.Error {
+	return invalidSelectorError.Sprintf("NamespaceSelectors in hierarchical repos MUST NOT set `spec.mode` to %q. Use the unstructured source format to select live Namespaces.",
+		v1.NSSelectorDynamicMode).BuildWithResources(selector)
+}
+
+// InvalidClusterMetadataError reports that the cluster metadata ConfigMap
+// declares invalid cluster labels.
+func InvalidClusterMetadataError(cm client.Object, cause error) status.Error {
+	return invalidSelectorError.Sprintf("the %q key of the cluster metadata ConfigMap MUST be formatted as `key1=value1,key2=value2`",
+		configsync.ClusterMetadataLabelsKey).Wrap(cause).BuildWithResources(cm)
+}
+
 // ClusterSelectorAnnotationConflictErrorCode is the error code for ClusterSelectorAnnotationConflictError
 const ClusterSelectorAnnotationConflictErrorCode = "1066"
 
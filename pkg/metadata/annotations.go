@@ -137,6 +137,42 @@ const (
 	// RootSync/RepoSync objects to indicate what do do with the managed
 	// resources when the RootSync/RepoSync object is deleted.
 	DeletionPropagationPolicyAnnotationKey = configsync.ConfigSyncPrefix + "deletion-propagation-policy"
+
+	// DriftPolicyAnnotationKey is the annotation key set on managed resources
+	// to override the spec.override.driftPolicy of the RootSync/RepoSync, i.e.
+	// whether the remediator reverts or only reports the drift of the resource.
+	// This annotation is set by Config Sync users on a managed resource.
+	DriftPolicyAnnotationKey = configsync.ConfigSyncPrefix + "drift-policy"
+
+	// DeclarationHashKey is the annotation key that stores the hash of the
+	// declaration of a resource with the report drift policy, which the
+	// applier uses to tell a change of the declaration from drift.
+	// This annotation is set by Config Sync on a managed resource.
+	DeclarationHashKey = configsync.ConfigSyncPrefix + "declaration-hash"
+
+	// ApplyWaveAnnotationKey is the annotation key set on managed resources to
+	// apply them in ordered waves. Resources in a wave are applied, and waited
+	// on to become healthy, before the resources of the next wave. The value is
+	// an integer, and resources without the annotation are in wave 0.
+	// This annotation is set by Config Sync users on a managed resource.
+	ApplyWaveAnnotationKey = configsync.ConfigSyncPrefix + "apply-wave"
+
+	// BreakGlassUntilAnnotationKey is the annotation key set on managed
+	// resources to exempt them from the admission webhook and the remediator
+	// until the RFC 3339 time of its value.
+	// This annotation is set by break-glass users on a live resource, and
+	// cannot be declared in the source.
+	BreakGlassUntilAnnotationKey = configsync.ConfigSyncPrefix + "break-glass-until"
+
+	// ValidationPolicyAnnotationKey is the annotation key set on ConfigMaps in
+	// the source to declare validation policies in their data. The policies
+	// are evaluated against the objects of the source before they are applied.
+	// This annotation is set by Config Sync users on a ConfigMap.
+	ValidationPolicyAnnotationKey = configsync.ConfigSyncPrefix + "validation-policy"
+
+	// ValidationPolicyAnnotationValue is the value of
+	// ValidationPolicyAnnotationKey which enables the validation policies.
+	ValidationPolicyAnnotationValue = "true"
 )
 
 // Lifecycle annotations
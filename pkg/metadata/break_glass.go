@@ -0,0 +1,47 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultBreakGlassMaxDuration is the default longest duration of a
+// break-glass exemption.
+const DefaultBreakGlassMaxDuration = time.Hour
+
+// BreakGlassUntil returns the time until which the object is exempted by the
+// break-glass annotation. It returns false if the annotation is missing or is
+// not a valid RFC 3339 time.
+func BreakGlassUntil(obj client.Object) (time.Time, bool) {
+	value, found := obj.GetAnnotations()[BreakGlassUntilAnnotationKey]
+	if !found {
+		return time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// BreakGlassExempted returns true if the object is exempted by the
+// break-glass annotation at the given time.
+func BreakGlassExempted(obj client.Object, now time.Time) bool {
+	until, ok := BreakGlassUntil(obj)
+	return ok && now.Before(until)
+}
@@ -53,6 +53,11 @@ const (
 	// This is used to enable selecting pods by label, primarily for printing logs.
 	// Example: kubectl logs deployment/<deploy-name> <container-name> -n config-management-system
 	DeploymentNameLabel = configsync.ConfigSyncPrefix + "deployment-name"
+
+	// PrometheusMetricsLabel is set on the reconciler and reconciler-manager
+	// pods, and selected by the Service exposing their metrics in the
+	// Prometheus exposition format.
+	PrometheusMetricsLabel = configsync.ConfigSyncPrefix + "prometheus-metrics"
 )
 
 // DepthSuffix is a label suffix for hierarchical namespace depth.
@@ -54,6 +54,9 @@ var sourceAnnotations = map[string]bool{
 	ResourceManagementKey:                  true,
 	LifecycleMutationAnnotation:            true,
 	DeletionPropagationPolicyAnnotationKey: true,
+	DriftPolicyAnnotationKey:               true,
+	ApplyWaveAnnotationKey:                 true,
+	ValidationPolicyAnnotationKey:          true,
 }
 
 // IsSourceAnnotation returns true if the annotation is a ConfigSync source
@@ -0,0 +1,168 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/metric/metricproducer"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// PrometheusNamespace prefixes the names of the metrics served in the
+	// Prometheus exposition format, matching the namespace of the Prometheus
+	// exporter of the otel-collector.
+	PrometheusNamespace = "config_sync"
+
+	// PrometheusPortName is the name of the container and Service port serving
+	// the metrics in the Prometheus exposition format.
+	PrometheusPortName = "prom-metrics"
+
+	// PrometheusPath is the path of the metrics endpoint.
+	PrometheusPath = "/metrics"
+)
+
+// prometheusCollector is a prometheus.Collector reading the metrics of the
+// registered OpenCensus views.
+type prometheusCollector struct{}
+
+var _ prometheus.Collector = prometheusCollector{}
+
+// Describe implements prometheus.Collector. It sends no descriptors, which
+// makes it an unchecked collector, as the views may be registered after the
+// collector.
+func (prometheusCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (prometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, producer := range metricproducer.GlobalManager().GetAll() {
+		for _, m := range producer.Read() {
+			for _, pm := range toPrometheusMetrics(m) {
+				ch <- pm
+			}
+		}
+	}
+}
+
+// toPrometheusMetrics converts an OpenCensus metric into one Prometheus
+// metric per time series.
+func toPrometheusMetrics(m *metricdata.Metric) []prometheus.Metric {
+	var labelNames []string
+	for _, k := range m.Descriptor.LabelKeys {
+		labelNames = append(labelNames, sanitizeName(k.Key))
+	}
+	desc := prometheus.NewDesc(sanitizeName(PrometheusNamespace+"_"+m.Descriptor.Name), m.Descriptor.Description, labelNames, nil)
+
+	var result []prometheus.Metric
+	for _, ts := range m.TimeSeries {
+		if len(ts.Points) == 0 {
+			continue
+		}
+		labelValues := make([]string, len(labelNames))
+		for i, v := range ts.LabelValues {
+			if i < len(labelValues) && v.Present {
+				labelValues[i] = v.Value
+			}
+		}
+		// The views export a single point per time series.
+		pm, err := toPrometheusMetric(desc, m.Descriptor.Type, ts.Points[len(ts.Points)-1], labelValues)
+		if err != nil {
+			klog.Warningf("Failed to convert metric %q to the Prometheus format: %v", m.Descriptor.Name, err)
+			continue
+		}
+		result = append(result, pm)
+	}
+	return result
+}
+
+func toPrometheusMetric(desc *prometheus.Desc, metricType metricdata.Type, point metricdata.Point, labelValues []string) (prometheus.Metric, error) {
+	switch v := point.Value.(type) {
+	case int64:
+		return prometheus.NewConstMetric(desc, valueType(metricType), float64(v), labelValues...)
+	case float64:
+		return prometheus.NewConstMetric(desc, valueType(metricType), v, labelValues...)
+	case *metricdata.Distribution:
+		buckets := make(map[float64]uint64)
+		var cumulativeCount uint64
+		for i, b := range v.Buckets {
+			cumulativeCount += uint64(b.Count)
+			// The last bucket counts the values above the largest bound, which
+			// Prometheus infers from the total count.
+			if v.BucketOptions != nil && i < len(v.BucketOptions.Bounds) {
+				buckets[v.BucketOptions.Bounds[i]] = cumulativeCount
+			}
+		}
+		return prometheus.NewConstHistogram(desc, uint64(v.Count), v.Sum, buckets, labelValues...)
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", point.Value)
+	}
+}
+
+// valueType returns the Prometheus type of a scalar OpenCensus metric.
+func valueType(metricType metricdata.Type) prometheus.ValueType {
+	switch metricType {
+	case metricdata.TypeCumulativeInt64, metricdata.TypeCumulativeFloat64:
+		return prometheus.CounterValue
+	case metricdata.TypeGaugeInt64, metricdata.TypeGaugeFloat64:
+		return prometheus.GaugeValue
+	default:
+		return prometheus.UntypedValue
+	}
+}
+
+// sanitizeName replaces the characters which are not valid in a Prometheus
+// metric or label name with underscores.
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == ':' || r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// NewPrometheusHandler returns an HTTP handler serving the metrics of the
+// registered OpenCensus views in the Prometheus exposition format.
+func NewPrometheusHandler() (http.Handler, error) {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(prometheusCollector{}); err != nil {
+		return nil, err
+	}
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
+
+// ServePrometheus serves the metrics of the registered OpenCensus views in the
+// Prometheus exposition format on the given port, in a goroutine.
+func ServePrometheus(port int) error {
+	handler, err := NewPrometheusHandler()
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(PrometheusPath, handler)
+	go func() {
+		if err := http.ListenAndServe(fmt.Sprintf(":%d", port), mux); err != nil {
+			klog.Errorf("Failed to serve Prometheus metrics on port %d: %v", port, err)
+		}
+	}()
+	return nil
+}
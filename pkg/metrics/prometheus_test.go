@@ -0,0 +1,82 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func TestNewPrometheusHandler(t *testing.T) {
+	count := stats.Int64("test/prometheus_count", "Test count", stats.UnitDimensionless)
+	duration := stats.Float64("test/prometheus_duration_seconds", "Test duration", stats.UnitSeconds)
+	countView := &view.View{
+		Name:        count.Name(),
+		Measure:     count,
+		Description: "The test count",
+		TagKeys:     []tag.Key{KeyOperation},
+		Aggregation: view.Count(),
+	}
+	durationView := &view.View{
+		Name:        duration.Name(),
+		Measure:     duration,
+		Description: "The test duration",
+		Aggregation: view.Distribution(1, 10),
+	}
+	if err := view.Register(countView, durationView); err != nil {
+		t.Fatal(err)
+	}
+	defer view.Unregister(countView, durationView)
+
+	ctx, err := tag.New(context.Background(), tag.Upsert(KeyOperation, "update"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats.Record(ctx, count.M(1), count.M(1), duration.M(0.5), duration.M(5), duration.M(50))
+
+	handler, err := NewPrometheusHandler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", PrometheusPath, nil))
+	body, err := ioutil.ReadAll(recorder.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"# HELP config_sync_test_prometheus_count The test count",
+		"# TYPE config_sync_test_prometheus_count counter",
+		`config_sync_test_prometheus_count{operation="update"} 2`,
+		"# TYPE config_sync_test_prometheus_duration_seconds histogram",
+		`config_sync_test_prometheus_duration_seconds_bucket{le="1"} 1`,
+		`config_sync_test_prometheus_duration_seconds_bucket{le="10"} 2`,
+		`config_sync_test_prometheus_duration_seconds_bucket{le="+Inf"} 3`,
+		"config_sync_test_prometheus_duration_seconds_sum 55.5",
+		"config_sync_test_prometheus_duration_seconds_count 3",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("got metrics:\n%s\nwant them to contain %q", body, want)
+		}
+	}
+}
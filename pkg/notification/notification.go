@@ -0,0 +1,421 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notification posts CloudEvents to the HTTP endpoints listed in the
+// spec.notifications field of a RootSync or RepoSync, when the reconciler
+// finishes syncing a commit, or fails to fetch, render, parse or sync it.
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+)
+
+const (
+	// EventTypeSynced is the type of the CloudEvent posted when a commit is
+	// synced without errors.
+	EventTypeSynced = "dev.configsync.sync.synced"
+	// EventTypeFailed is the type of the CloudEvent posted when a commit fails
+	// to sync.
+	EventTypeFailed = "dev.configsync.sync.failed"
+
+	specVersion = "1.0"
+
+	// structuredContentType is the content type of a CloudEvent in the
+	// structured JSON format.
+	structuredContentType = "application/cloudevents+json"
+	jsonContentType       = "application/json"
+
+	requestTimeout = 10 * time.Second
+
+	// SecretKeyURL is the key of the notification Secret that overrides the
+	// url of the notification.
+	SecretKeyURL = "url"
+	// SecretKeyHeaderPrefix is the prefix of the keys of the notification
+	// Secret that set request headers.
+	SecretKeyHeaderPrefix = "header."
+)
+
+// Event is a CloudEvent, in the structured JSON format.
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            Data      `json:"data"`
+}
+
+// Data is the data of the CloudEvent.
+type Data struct {
+	// Kind is the kind of the sync: RootSync or RepoSync.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the sync.
+	Namespace string `json:"namespace"`
+	// Name is the name of the sync.
+	Name string `json:"name"`
+	// Commit is the synced commit. It is empty if the source failed to be
+	// fetched before the commit was known.
+	Commit string `json:"commit"`
+	// Errors are the errors of the sync, if it failed.
+	Errors []v1beta1.ConfigSyncError `json:"errors,omitempty"`
+}
+
+// Notifier posts the outcome of each sync to the notification endpoints.
+type Notifier struct {
+	kind      string
+	namespace string
+	name      string
+	targets   []*target
+
+	client  *http.Client
+	backoff wait.Backoff
+
+	// mux guards the commits notified to the targets.
+	mux sync.Mutex
+}
+
+type target struct {
+	url      string
+	on       map[configsync.NotificationEvent]bool
+	template *template.Template
+	// secretDir is the directory where the Secret referenced by secretRef is
+	// mounted, if any.
+	secretDir string
+	// name identifies the target in the logs, without leaking the url of the
+	// Secret.
+	name string
+
+	// notified is the last commit whose outcome was notified, for each outcome.
+	notified map[configsync.NotificationEvent]string
+}
+
+// New returns a Notifier for the sync kind namespace/name. The Secret
+// referenced by the i-th notification is read from the secretsDir/<i>
+// directory.
+func New(kind, namespace, name string, notifications []v1beta1.Notification, secretsDir string) (*Notifier, error) {
+	n := &Notifier{
+		kind:      kind,
+		namespace: namespace,
+		name:      name,
+		client:    &http.Client{Timeout: requestTimeout},
+		backoff: wait.Backoff{
+			Duration: time.Second,
+			Factor:   2,
+			Jitter:   0.1,
+			Steps:    6,
+		},
+	}
+	for i, notification := range notifications {
+		if err := Validate(notification); err != nil {
+			return nil, err
+		}
+		t := &target{
+			url:      notification.URL,
+			name:     notification.URL,
+			on:       map[configsync.NotificationEvent]bool{},
+			notified: map[configsync.NotificationEvent]string{},
+		}
+		if notification.SecretRef != nil {
+			t.secretDir = filepath.Join(secretsDir, strconv.Itoa(i))
+			t.name = fmt.Sprintf("notification %d (Secret %s)", i, notification.SecretRef.Name)
+		}
+		if len(notification.On) == 0 {
+			t.on[configsync.NotificationSynced] = true
+			t.on[configsync.NotificationFailed] = true
+		}
+		for _, e := range notification.On {
+			t.on[e] = true
+		}
+		if notification.Template != "" {
+			// The template was parsed by Validate.
+			t.template = template.Must(template.New("notification").Parse(notification.Template))
+		}
+		n.targets = append(n.targets, t)
+	}
+	return n, nil
+}
+
+// Validate returns an error if the notification is invalid. The url may only
+// be empty if the notification references a Secret, which is expected to hold
+// it.
+func Validate(notification v1beta1.Notification) error {
+	if notification.SecretRef != nil && notification.SecretRef.Name == "" {
+		return fmt.Errorf("invalid secretRef: must have a name")
+	}
+	if notification.URL != "" || notification.SecretRef == nil {
+		if err := validateURL(notification.URL); err != nil {
+			return err
+		}
+	}
+	for _, e := range notification.On {
+		switch e {
+		case configsync.NotificationSynced, configsync.NotificationFailed:
+		default:
+			return fmt.Errorf("invalid outcome %q: must be %s or %s", e, configsync.NotificationSynced, configsync.NotificationFailed)
+		}
+	}
+	if notification.Template != "" {
+		if _, err := template.New("notification").Parse(notification.Template); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateURL returns an error if rawURL is not an absolute http or https URL.
+// The url is not part of the error, as it may come from a Secret.
+func validateURL(rawURL string) error {
+	if rawURL == "" {
+		return fmt.Errorf("invalid url: must be set in the url field or in the %s key of the Secret", SecretKeyURL)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url: must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// Notify posts the outcome of syncing commit to the endpoints that have not
+// been notified of it since the outcome last changed. The commit is synced without errors if errs is
+// empty. The commit may be empty if the source failed to be fetched. The
+// requests are sent in the background, and retried with exponential backoff on
+// network errors, 429 and 5xx responses. Notify is a no-op on a nil Notifier.
+func (n *Notifier) Notify(ctx context.Context, commit string, errs []v1beta1.ConfigSyncError) {
+	if n == nil {
+		return
+	}
+	outcome, opposite := configsync.NotificationSynced, configsync.NotificationFailed
+	if len(errs) > 0 {
+		outcome, opposite = configsync.NotificationFailed, configsync.NotificationSynced
+	}
+
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	var targets []*target
+	for _, t := range n.targets {
+		// The outcome changed, so the opposite outcome is notified again even
+		// for the same commit, for example when a synced commit fails to apply
+		// after drift, or when a failed commit recovers.
+		delete(t.notified, opposite)
+		if !t.on[outcome] {
+			continue
+		}
+		if notified, found := t.notified[outcome]; found && notified == commit {
+			continue
+		}
+		t.notified[outcome] = commit
+		targets = append(targets, t)
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	event := n.event(outcome, commit, errs)
+	for _, t := range targets {
+		go func(t *target) {
+			if err := n.deliver(ctx, t, event); err != nil {
+				klog.Errorf("Failed to notify %s of the %s outcome of commit %q: %v", t.name, outcome, commit, err)
+				n.forget(t, outcome, commit)
+			}
+		}(t)
+	}
+}
+
+// forget allows the outcome of commit to be notified again to t, after its
+// delivery failed.
+func (n *Notifier) forget(t *target, outcome configsync.NotificationEvent, commit string) {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	if t.notified[outcome] == commit {
+		delete(t.notified, outcome)
+	}
+}
+
+// event returns the CloudEvent of the outcome of syncing commit. The ID only
+// depends on the sync, the outcome and the commit, so that receivers can
+// deduplicate the events too. Without a commit, the ID depends on the time.
+func (n *Notifier) event(outcome configsync.NotificationEvent, commit string, errs []v1beta1.ConfigSyncError) Event {
+	eventType := EventTypeSynced
+	if outcome == configsync.NotificationFailed {
+		eventType = EventTypeFailed
+	}
+	source := fmt.Sprintf("//configsync.gke.io/namespaces/%s/%ss/%s", n.namespace, strings.ToLower(n.kind), n.name)
+	now := time.Now().UTC()
+	idKey := commit
+	if commit == "" {
+		idKey = now.Format(time.RFC3339Nano)
+	}
+	return Event{
+		SpecVersion:     specVersion,
+		ID:              fmt.Sprintf("%x", sha256.Sum256([]byte(source+"/"+eventType+"/"+idKey))),
+		Source:          source,
+		Type:            eventType,
+		Subject:         commit,
+		Time:            now,
+		DataContentType: jsonContentType,
+		Data: Data{
+			Kind:      n.kind,
+			Namespace: n.namespace,
+			Name:      n.name,
+			Commit:    commit,
+			Errors:    errs,
+		},
+	}
+}
+
+// deliver posts the event to the target, retrying with exponential backoff.
+func (n *Notifier) deliver(ctx context.Context, t *target, event Event) error {
+	return retry.OnError(n.backoff, isRetriable, func() error {
+		req, err := newRequest(ctx, t, event)
+		if err != nil {
+			return err
+		}
+		resp, err := n.client.Do(req)
+		if err != nil {
+			// Keep the url of the Secret out of the logs.
+			if urlErr, ok := err.(*url.Error); ok && t.secretDir != "" {
+				err = fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+			}
+			return &deliveryError{err: err}
+		}
+		defer func() {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return &deliveryError{
+				err:        fmt.Errorf("unexpected response status %s", resp.Status),
+				statusCode: resp.StatusCode,
+			}
+		}
+		return nil
+	})
+}
+
+// secret returns the url and the headers of the target, read from its Secret
+// if it references one. The Secret is read for each request, so that the
+// rotated values are used as soon as the kubelet updates the volume.
+func (t *target) secret() (string, http.Header, error) {
+	headers := http.Header{}
+	if t.secretDir == "" {
+		return t.url, headers, nil
+	}
+	rawURL := t.url
+	entries, err := os.ReadDir(t.secretDir)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read the Secret: %w", err)
+	}
+	for _, entry := range entries {
+		key := entry.Name()
+		if key != SecretKeyURL && !strings.HasPrefix(key, SecretKeyHeaderPrefix) {
+			continue
+		}
+		value, err := os.ReadFile(filepath.Join(t.secretDir, key))
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read the %s key of the Secret: %w", key, err)
+		}
+		if key == SecretKeyURL {
+			rawURL = strings.TrimSpace(string(value))
+		} else {
+			headers.Set(strings.TrimPrefix(key, SecretKeyHeaderPrefix), strings.TrimSpace(string(value)))
+		}
+	}
+	if err := validateURL(rawURL); err != nil {
+		return "", nil, err
+	}
+	return rawURL, headers, nil
+}
+
+// newRequest returns the request that posts the event to the target. Without
+// a template, the event is sent in the structured JSON format. With a
+// template, the body is the rendered template, and the event attributes are
+// sent as headers, as in the binary format.
+func newRequest(ctx context.Context, t *target, event Event) (*http.Request, error) {
+	rawURL, headers, err := t.secret()
+	if err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	contentType := structuredContentType
+	if t.template == nil {
+		if err := json.NewEncoder(&body).Encode(event); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := t.template.Execute(&body, event); err != nil {
+			return nil, fmt.Errorf("failed to render the template: %w", err)
+		}
+		contentType = jsonContentType
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = headers
+	req.Header.Set("Content-Type", contentType)
+	if t.template != nil {
+		req.Header.Set("ce-specversion", event.SpecVersion)
+		req.Header.Set("ce-id", event.ID)
+		req.Header.Set("ce-source", event.Source)
+		req.Header.Set("ce-type", event.Type)
+		req.Header.Set("ce-subject", event.Subject)
+		req.Header.Set("ce-time", event.Time.Format(time.RFC3339))
+	}
+	return req, nil
+}
+
+// deliveryError is returned when a request fails, or gets an unexpected
+// response.
+type deliveryError struct {
+	err        error
+	statusCode int
+}
+
+func (e *deliveryError) Error() string {
+	return e.err.Error()
+}
+
+// isRetriable returns true for network errors, 429 and 5xx responses.
+func isRetriable(err error) bool {
+	de, ok := err.(*deliveryError)
+	if !ok {
+		return false
+	}
+	return de.statusCode == 0 || de.statusCode == http.StatusTooManyRequests || de.statusCode >= 500
+}
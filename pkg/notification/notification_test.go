@@ -0,0 +1,453 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+)
+
+type request struct {
+	contentType   string
+	ceType        string
+	authorization string
+	body          string
+}
+
+// receiver records the requests it receives, and responds with the statuses,
+// then with 200.
+type receiver struct {
+	mux      sync.Mutex
+	statuses []int
+	requests []request
+	received chan struct{}
+}
+
+func newReceiver(statuses ...int) *receiver {
+	return &receiver{statuses: statuses, received: make(chan struct{}, 10)}
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mux.Lock()
+	r.requests = append(r.requests, request{
+		contentType:   req.Header.Get("Content-Type"),
+		ceType:        req.Header.Get("ce-type"),
+		authorization: req.Header.Get("Authorization"),
+		body:          string(body),
+	})
+	code := http.StatusOK
+	if len(r.statuses) > 0 {
+		code, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	r.mux.Unlock()
+	w.WriteHeader(code)
+	r.received <- struct{}{}
+}
+
+func (r *receiver) wait(t *testing.T, count int) []request {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		select {
+		case <-r.received:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for request %d", i+1)
+		}
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return append([]request(nil), r.requests...)
+}
+
+func newTestNotifier(t *testing.T, notifications ...v1beta1.Notification) *Notifier {
+	t.Helper()
+	n, err := New("RepoSync", "bookinfo", "repo-sync", notifications, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.backoff = wait.Backoff{Duration: time.Millisecond, Steps: 3}
+	return n
+}
+
+func TestNotify_Structured(t *testing.T) {
+	r := newReceiver()
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	n := newTestNotifier(t, v1beta1.Notification{URL: srv.URL})
+	errs := []v1beta1.ConfigSyncError{{Code: "1021", ErrorMessage: "unknown kind"}}
+	n.Notify(context.Background(), "abc123", errs)
+
+	got := r.wait(t, 1)
+	if got[0].contentType != structuredContentType {
+		t.Errorf("got Content-Type %q, want %q", got[0].contentType, structuredContentType)
+	}
+	var event Event
+	if err := json.Unmarshal([]byte(got[0].body), &event); err != nil {
+		t.Fatal(err)
+	}
+	want := Event{
+		SpecVersion:     specVersion,
+		ID:              event.ID,
+		Source:          "//configsync.gke.io/namespaces/bookinfo/reposyncs/repo-sync",
+		Type:            EventTypeFailed,
+		Subject:         "abc123",
+		Time:            event.Time,
+		DataContentType: jsonContentType,
+		Data: Data{
+			Kind:      "RepoSync",
+			Namespace: "bookinfo",
+			Name:      "repo-sync",
+			Commit:    "abc123",
+			Errors:    errs,
+		},
+	}
+	if diff := cmp.Diff(want, event); diff != "" {
+		t.Errorf("got diff (-want +got):\n%s", diff)
+	}
+	if event.ID == "" {
+		t.Error("got empty event ID")
+	}
+}
+
+func TestNotify_Template(t *testing.T) {
+	r := newReceiver()
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	n := newTestNotifier(t, v1beta1.Notification{
+		URL:      srv.URL,
+		Template: `{"text": "{{.Data.Name}} synced {{.Data.Commit}}"}`,
+	})
+	n.Notify(context.Background(), "abc123", nil)
+
+	got := r.wait(t, 1)
+	want := request{
+		contentType: jsonContentType,
+		ceType:      EventTypeSynced,
+		body:        `{"text": "repo-sync synced abc123"}`,
+	}
+	if diff := cmp.Diff(want, got[0], cmp.AllowUnexported(request{})); diff != "" {
+		t.Errorf("got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNotify_DeduplicatesPerCommit(t *testing.T) {
+	r := newReceiver()
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	n := newTestNotifier(t, v1beta1.Notification{
+		URL:      srv.URL,
+		Template: `{{.Type}} {{.Subject}}`,
+	})
+	ctx := context.Background()
+	n.Notify(ctx, "abc123", nil)
+	r.wait(t, 1)
+	n.Notify(ctx, "abc123", nil)
+	n.Notify(ctx, "def456", []v1beta1.ConfigSyncError{{Code: "2009"}})
+	r.wait(t, 1)
+	n.Notify(ctx, "def456", []v1beta1.ConfigSyncError{{Code: "2009"}, {Code: "1021"}})
+	n.Notify(ctx, "def456", nil)
+	got := r.wait(t, 1)
+
+	var bodies []string
+	for _, req := range got {
+		bodies = append(bodies, req.body)
+	}
+	want := []string{
+		EventTypeSynced + " abc123",
+		EventTypeFailed + " def456",
+		EventTypeSynced + " def456",
+	}
+	if diff := cmp.Diff(want, bodies); diff != "" {
+		t.Errorf("got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNotify_OutcomeChangesOnSameCommit(t *testing.T) {
+	applyErrs := []v1beta1.ConfigSyncError{{Code: "2009", ErrorMessage: "failed to apply"}}
+	// The commit fails to apply, syncs, then fails again after drift and
+	// recovers.
+	outcomes := [][]v1beta1.ConfigSyncError{applyErrs, applyErrs, nil, nil, applyErrs, nil}
+
+	testCases := []struct {
+		name string
+		on   []configsync.NotificationEvent
+		// wantSent is whether each outcome is notified.
+		wantSent []bool
+	}{
+		{
+			name:     "every outcome",
+			wantSent: []bool{true, false, true, false, true, true},
+		},
+		{
+			name:     "failures only",
+			on:       []configsync.NotificationEvent{configsync.NotificationFailed},
+			wantSent: []bool{true, false, false, false, true, false},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newReceiver()
+			srv := httptest.NewServer(r)
+			defer srv.Close()
+
+			n := newTestNotifier(t, v1beta1.Notification{
+				URL:      srv.URL,
+				On:       tc.on,
+				Template: `{{.Type}} {{.Subject}}`,
+			})
+			ctx := context.Background()
+			var want []string
+			for i, errs := range outcomes {
+				n.Notify(ctx, "abc123", errs)
+				if !tc.wantSent[i] {
+					continue
+				}
+				// Wait for the notification before the next one, so that they
+				// are received in order.
+				r.wait(t, 1)
+				if errs != nil {
+					want = append(want, EventTypeFailed+" abc123")
+				} else {
+					want = append(want, EventTypeSynced+" abc123")
+				}
+			}
+			// Let an unexpected notification of the last outcome arrive.
+			time.Sleep(100 * time.Millisecond)
+
+			r.mux.Lock()
+			defer r.mux.Unlock()
+			var bodies []string
+			for _, req := range r.requests {
+				bodies = append(bodies, req.body)
+			}
+			if diff := cmp.Diff(want, bodies); diff != "" {
+				t.Errorf("got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNotify_WithoutCommit(t *testing.T) {
+	r := newReceiver()
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	n := newTestNotifier(t, v1beta1.Notification{
+		URL:      srv.URL,
+		Template: `{{.Type}} {{.Subject}}`,
+	})
+	ctx := context.Background()
+	fetchErrs := []v1beta1.ConfigSyncError{{Code: "2004", ErrorMessage: "failed to fetch"}}
+	n.Notify(ctx, "", fetchErrs)
+	r.wait(t, 1)
+	n.Notify(ctx, "", fetchErrs)
+	n.Notify(ctx, "abc123", nil)
+	r.wait(t, 1)
+	// A failure to fetch is notified again once a commit was synced.
+	n.Notify(ctx, "", fetchErrs)
+	got := r.wait(t, 1)
+
+	var bodies []string
+	for _, req := range got {
+		bodies = append(bodies, req.body)
+	}
+	want := []string{
+		EventTypeFailed + " ",
+		EventTypeSynced + " abc123",
+		EventTypeFailed + " ",
+	}
+	if diff := cmp.Diff(want, bodies); diff != "" {
+		t.Errorf("got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNotify_Secret(t *testing.T) {
+	r := newReceiver()
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	secretsDir := t.TempDir()
+	secretDir := filepath.Join(secretsDir, "1")
+	writeKey := func(key, value string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(secretDir, key), []byte(value), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(secretDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeKey(SecretKeyURL, srv.URL+"\n")
+	writeKey(SecretKeyHeaderPrefix+"Authorization", "Bearer abc")
+	writeKey("unrelated", "ignored")
+
+	n, err := New("RepoSync", "bookinfo", "repo-sync", []v1beta1.Notification{
+		{URL: "https://example.com/unused", On: []configsync.NotificationEvent{configsync.NotificationFailed}},
+		{SecretRef: &v1beta1.SecretReference{Name: "slack"}, Template: `{{.Subject}}`},
+	}, secretsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	n.Notify(ctx, "abc123", nil)
+	r.wait(t, 1)
+	// The rotated header is used by the next request.
+	writeKey(SecretKeyHeaderPrefix+"Authorization", "Bearer def")
+	n.Notify(ctx, "def456", nil)
+	got := r.wait(t, 1)
+
+	want := []request{
+		{contentType: jsonContentType, ceType: EventTypeSynced, authorization: "Bearer abc", body: "abc123"},
+		{contentType: jsonContentType, ceType: EventTypeSynced, authorization: "Bearer def", body: "def456"},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(request{})); diff != "" {
+		t.Errorf("got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNotify_On(t *testing.T) {
+	r := newReceiver()
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	n := newTestNotifier(t, v1beta1.Notification{
+		URL:      srv.URL,
+		On:       []configsync.NotificationEvent{configsync.NotificationFailed},
+		Template: `{{.Type}} {{.Subject}}`,
+	})
+	ctx := context.Background()
+	n.Notify(ctx, "abc123", nil)
+	n.Notify(ctx, "def456", []v1beta1.ConfigSyncError{{Code: "2009"}})
+
+	got := r.wait(t, 1)
+	if len(got) != 1 || got[0].body != EventTypeFailed+" def456" {
+		t.Errorf("got requests %+v, want only the failure of def456", got)
+	}
+}
+
+func TestNotify_Retries(t *testing.T) {
+	r := newReceiver(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	n := newTestNotifier(t, v1beta1.Notification{URL: srv.URL})
+	n.Notify(context.Background(), "abc123", nil)
+
+	got := r.wait(t, 3)
+	if len(got) != 3 {
+		t.Errorf("got %d requests, want 3", len(got))
+	}
+}
+
+func TestNotify_DoesNotRetryClientErrors(t *testing.T) {
+	r := newReceiver(http.StatusBadRequest)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	n := newTestNotifier(t, v1beta1.Notification{URL: srv.URL})
+	n.Notify(context.Background(), "abc123", nil)
+	r.wait(t, 1)
+
+	// The failed delivery is forgotten, so that the next sync notifies again.
+	if err := wait.PollImmediate(time.Millisecond, 10*time.Second, func() (bool, error) {
+		n.mux.Lock()
+		defer n.mux.Unlock()
+		return n.targets[0].notified[configsync.NotificationSynced] == "", nil
+	}); err != nil {
+		t.Fatal("the failed delivery was not forgotten")
+	}
+	n.Notify(context.Background(), "abc123", nil)
+	got := r.wait(t, 1)
+	if len(got) != 2 {
+		t.Errorf("got %d requests, want 2", len(got))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		notification v1beta1.Notification
+		wantErr      bool
+	}{
+		{
+			name:         "valid",
+			notification: v1beta1.Notification{URL: "https://example.com/hook", On: []configsync.NotificationEvent{configsync.NotificationSynced}},
+		},
+		{
+			name:         "missing url",
+			notification: v1beta1.Notification{},
+			wantErr:      true,
+		},
+		{
+			name:         "relative url",
+			notification: v1beta1.Notification{URL: "example.com/hook"},
+			wantErr:      true,
+		},
+		{
+			name:         "unsupported scheme",
+			notification: v1beta1.Notification{URL: "ftp://example.com/hook"},
+			wantErr:      true,
+		},
+		{
+			name:         "url in the Secret",
+			notification: v1beta1.Notification{SecretRef: &v1beta1.SecretReference{Name: "slack"}},
+		},
+		{
+			name:         "invalid url with a Secret",
+			notification: v1beta1.Notification{URL: "example.com/hook", SecretRef: &v1beta1.SecretReference{Name: "slack"}},
+			wantErr:      true,
+		},
+		{
+			name:         "Secret without a name",
+			notification: v1beta1.Notification{URL: "https://example.com/hook", SecretRef: &v1beta1.SecretReference{}},
+			wantErr:      true,
+		},
+		{
+			name:         "invalid outcome",
+			notification: v1beta1.Notification{URL: "https://example.com/hook", On: []configsync.NotificationEvent{"started"}},
+			wantErr:      true,
+		},
+		{
+			name:         "invalid template",
+			notification: v1beta1.Notification{URL: "https://example.com/hook", Template: "{{.Data"},
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.notification)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate() got error %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
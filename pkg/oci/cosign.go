@@ -0,0 +1,174 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// cosignSignatureAnnotation is the annotation of a cosign signature layer
+	// that holds the base64-encoded signature of the layer.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	// cosignSignatureTagSuffix is the suffix of the tag that cosign pushes the
+	// signatures of an artifact to.
+	cosignSignatureTagSuffix = ".sig"
+)
+
+// cosignPayload is the simple signing payload signed by cosign.
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// verifyCosign verifies that one of the cosign signatures of the artifact is
+// valid for one of the public keys.
+func verifyCosign(digest name.Digest, blocks []*pem.Block, options ...remote.Option) error {
+	keys, err := publicKeys(blocks)
+	if err != nil {
+		return err
+	}
+	sigTag := digest.Context().Tag(strings.Replace(digest.DigestStr(), ":", "-", 1) + cosignSignatureTagSuffix)
+	sigImage, err := remote.Image(sigTag, options...)
+	if err != nil {
+		return fmt.Errorf("failed to pull the cosign signatures %s: %w", sigTag, err)
+	}
+	manifest, err := sigImage.Manifest()
+	if err != nil {
+		return fmt.Errorf("failed to read the cosign signatures %s: %w", sigTag, err)
+	}
+	for _, desc := range manifest.Layers {
+		sig, found := desc.Annotations[cosignSignatureAnnotation]
+		if !found {
+			continue
+		}
+		layer, err := sigImage.LayerByDigest(desc.Digest)
+		if err != nil {
+			return fmt.Errorf("failed to pull the cosign signature %s: %w", desc.Digest, err)
+		}
+		payload, err := readLayer(layer.Compressed)
+		if err != nil {
+			return fmt.Errorf("failed to read the cosign signature %s: %w", desc.Digest, err)
+		}
+		if err := verifyCosignSignature(digest.DigestStr(), payload, sig, keys); err != nil {
+			klog.Infof("skipping the cosign signature %s: %v", desc.Digest, err)
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("no cosign signature is valid for the trusted public keys")
+}
+
+// verifyCosignSignature verifies the base64-encoded signature of the simple
+// signing payload, and that the payload refers to the given digest.
+func verifyCosignSignature(digest string, payload []byte, b64Sig string, keys []crypto.PublicKey) error {
+	sig, err := base64.StdEncoding.DecodeString(b64Sig)
+	if err != nil {
+		return fmt.Errorf("failed to decode the signature: %w", err)
+	}
+	verified := false
+	for _, key := range keys {
+		if verifySignature(key, payload, sig) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return errors.New("the signature does not match any trusted public key")
+	}
+	p := &cosignPayload{}
+	if err := json.Unmarshal(payload, p); err != nil {
+		return fmt.Errorf("failed to parse the signed payload: %w", err)
+	}
+	if p.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf("the signed payload refers to %q instead of %q", p.Critical.Image.DockerManifestDigest, digest)
+	}
+	return nil
+}
+
+// verifySignature verifies the signature of the payload with the given key,
+// using the SHA-256 digest of the payload like cosign does.
+func verifySignature(key crypto.PublicKey, payload, sig []byte) error {
+	hash := sha256.Sum256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, hash[:], sig) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], sig)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, payload, sig) {
+			return errors.New("invalid Ed25519 signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// publicKeys parses the PEM-encoded public keys.
+func publicKeys(blocks []*pem.Block) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for _, block := range blocks {
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the public key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM-encoded public key found")
+	}
+	return keys, nil
+}
+
+// readLayer reads the whole content returned by the given layer accessor.
+func readLayer(open func() (io.ReadCloser, error)) ([]byte, error) {
+	rc, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rc.Close(); err != nil {
+			klog.Warningf("failed to close the layer reader: %v", err)
+		}
+	}()
+	return io.ReadAll(rc)
+}
@@ -32,8 +32,18 @@ import (
 )
 
 // FetchPackage fetches the package from the OCI repository and write it to the destination.
-func FetchPackage(ctx context.Context, imageName, ociRoot, rev string, auth authn.Authenticator) error {
-	image, err := PullImage(imageName, remote.WithContext(ctx), remote.WithAuth(auth))
+// If verifier is not nil, the package is only written if its signature is valid.
+func FetchPackage(ctx context.Context, imageName, ociRoot, rev string, auth authn.Authenticator, verifier *Verifier) error {
+	options := []remote.Option{remote.WithContext(ctx), remote.WithAuth(auth)}
+	resolvedName, err := ResolveImage(imageName, options...)
+	if err != nil {
+		return err
+	}
+	if resolvedName != imageName {
+		klog.Infof("resolved image %q to %q", imageName, resolvedName)
+		imageName = resolvedName
+	}
+	image, err := PullImage(imageName, options...)
 	if err != nil {
 		return err
 	}
@@ -51,11 +61,25 @@ func FetchPackage(ctx context.Context, imageName, ociRoot, rev string, auth auth
 	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to evaluate the symbolic path %q to the OCI package: %w", linkPath, err)
 	}
+	if err := writeTag(ociRoot, imageDigestHash.Hex, imageName); err != nil {
+		return err
+	}
 	if oldDir == destDir {
 		klog.Infof("no update required with the same image digest hash %q", imageDigestHash)
 		return nil
 	}
 
+	if verifier != nil {
+		ref, err := name.ParseReference(imageName)
+		if err != nil {
+			return fmt.Errorf("failed to parse reference %q: %v", imageName, err)
+		}
+		if err := verifier.Verify(ref.Context().Digest(imageDigestHash.String()), options...); err != nil {
+			return err
+		}
+		klog.Infof("verified the signature of image digest %q", imageDigestHash)
+	}
+
 	if _, err = os.Stat(destDir); os.IsNotExist(err) {
 		fileMode := os.FileMode(0755)
 		if err = os.MkdirAll(destDir, fileMode); err != nil {
@@ -71,7 +95,13 @@ func FetchPackage(ctx context.Context, imageName, ociRoot, rev string, auth auth
 	}
 
 	klog.Infof("pulled image digest %q", imageDigestHash)
-	return util.UpdateSymlink(ociRoot, linkPath, destDir, oldDir)
+	if err := util.UpdateSymlink(ociRoot, linkPath, destDir, oldDir); err != nil {
+		return err
+	}
+	if oldDir != "" {
+		return removeTag(ociRoot, filepath.Base(oldDir))
+	}
+	return nil
 }
 
 // PullImage pulls image from source using provided options for auth credentials
@@ -0,0 +1,313 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// notationSignatureMediaType is the artifact type of notation signatures.
+	notationSignatureMediaType = "application/vnd.cncf.notary.signature"
+
+	// notationJWSMediaType is the media type of JWS signature envelopes.
+	notationJWSMediaType = "application/jose+json"
+
+	// notationPayloadMediaType is the content type of the signed payload.
+	notationPayloadMediaType = "application/vnd.cncf.notary.payload.v1+json"
+
+	// notationSigningSchemeHeader is the protected header of the signing
+	// scheme of a notation signature.
+	notationSigningSchemeHeader = "io.cncf.notary.signingScheme"
+
+	// notationExpiryHeader is the protected header of the expiry of a
+	// notation signature.
+	notationExpiryHeader = "io.cncf.notary.expiry"
+
+	// notationSigningSchemeX509 is the signing scheme of signatures that are
+	// verified against the current time. The notary.x509.signingAuthority
+	// scheme requires an authenticated signing time, which is not supported.
+	notationSigningSchemeX509 = "notary.x509"
+)
+
+// notationCriticalHeaders are the headers that may be listed in the crit
+// header of a notation signature, because they are understood and verified.
+var notationCriticalHeaders = map[string]bool{
+	notationSigningSchemeHeader: true,
+	notationExpiryHeader:        true,
+}
+
+// jwsEnvelope is a notation signature envelope in the JWS JSON serialization.
+type jwsEnvelope struct {
+	Payload   string `json:"payload"`
+	Protected string `json:"protected"`
+	Header    struct {
+		CertChain [][]byte `json:"x5c"`
+	} `json:"header"`
+	Signature string `json:"signature"`
+}
+
+// jwsProtectedHeader is the protected header of a notation JWS envelope.
+type jwsProtectedHeader struct {
+	Algorithm     string    `json:"alg"`
+	ContentType   string    `json:"cty"`
+	Critical      []string  `json:"crit"`
+	SigningScheme string    `json:"io.cncf.notary.signingScheme"`
+	Expiry        time.Time `json:"io.cncf.notary.expiry"`
+}
+
+// notationPayload is the payload signed by notation.
+type notationPayload struct {
+	TargetArtifact struct {
+		Digest string `json:"digest"`
+	} `json:"targetArtifact"`
+}
+
+// verifyNotation verifies that one of the notation signatures of the artifact
+// is valid and issued by one of the root certificates.
+//
+// The signatures are discovered with the referrers tag schema of the OCI
+// distribution spec, which notation falls back to when the registry does not
+// support the referrers API.
+func verifyNotation(digest name.Digest, blocks []*pem.Block, options ...remote.Option) error {
+	roots, err := certPool(blocks)
+	if err != nil {
+		return err
+	}
+	referrersTag := digest.Context().Tag(strings.Replace(digest.DigestStr(), ":", "-", 1))
+	referrers, err := remote.Index(referrersTag, options...)
+	if err != nil {
+		return fmt.Errorf("failed to pull the referrers %s: %w", referrersTag, err)
+	}
+	index, err := referrers.IndexManifest()
+	if err != nil {
+		return fmt.Errorf("failed to read the referrers %s: %w", referrersTag, err)
+	}
+	for _, desc := range index.Manifests {
+		sigImage, err := remote.Image(digest.Context().Digest(desc.Digest.String()), options...)
+		if err != nil {
+			return fmt.Errorf("failed to pull the referrer %s: %w", desc.Digest, err)
+		}
+		manifest, err := sigImage.Manifest()
+		if err != nil {
+			return fmt.Errorf("failed to read the referrer %s: %w", desc.Digest, err)
+		}
+		if string(manifest.Config.MediaType) != notationSignatureMediaType {
+			continue
+		}
+		for _, layerDesc := range manifest.Layers {
+			if string(layerDesc.MediaType) != notationJWSMediaType {
+				klog.Infof("skipping the notation signature %s: unsupported envelope %q", desc.Digest, layerDesc.MediaType)
+				continue
+			}
+			layer, err := sigImage.LayerByDigest(layerDesc.Digest)
+			if err != nil {
+				return fmt.Errorf("failed to pull the notation signature %s: %w", layerDesc.Digest, err)
+			}
+			envelope, err := readLayer(layer.Compressed)
+			if err != nil {
+				return fmt.Errorf("failed to read the notation signature %s: %w", layerDesc.Digest, err)
+			}
+			if err := verifyNotationEnvelope(digest.DigestStr(), envelope, roots, time.Now()); err != nil {
+				klog.Infof("skipping the notation signature %s: %v", desc.Digest, err)
+				continue
+			}
+			return nil
+		}
+	}
+	return errors.New("no notation signature is valid for the trusted certificates")
+}
+
+// verifyNotationEnvelope verifies the JWS envelope of a notation signature:
+// the certificate chain must be issued by one of the roots at the current
+// time, the signature must be valid for the leaf certificate, and the payload
+// must refer to the given digest.
+//
+// The signing time is set by the signer, so it is not trusted to verify the
+// certificate chain.
+func verifyNotationEnvelope(digest string, content []byte, roots *x509.CertPool, now time.Time) error {
+	envelope := &jwsEnvelope{}
+	if err := json.Unmarshal(content, envelope); err != nil {
+		return fmt.Errorf("failed to parse the signature envelope: %w", err)
+	}
+	header := &jwsProtectedHeader{}
+	if err := decodeJWSPart(envelope.Protected, header); err != nil {
+		return fmt.Errorf("failed to parse the protected header: %w", err)
+	}
+	if err := verifyCriticalHeaders(envelope.Protected, header.Critical); err != nil {
+		return err
+	}
+	if header.SigningScheme != "" && header.SigningScheme != notationSigningSchemeX509 {
+		return fmt.Errorf("unsupported signing scheme %q", header.SigningScheme)
+	}
+	if header.ContentType != notationPayloadMediaType {
+		return fmt.Errorf("unsupported payload content type %q", header.ContentType)
+	}
+	if !header.Expiry.IsZero() && now.After(header.Expiry) {
+		return fmt.Errorf("the signature expired at %s", header.Expiry)
+	}
+
+	if len(envelope.Header.CertChain) == 0 {
+		return errors.New("the signature envelope has no certificate chain")
+	}
+	var certs []*x509.Certificate
+	for _, der := range envelope.Header.CertChain {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return fmt.Errorf("failed to parse the certificate chain: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return fmt.Errorf("the certificate chain is not trusted: %w", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(envelope.Signature)
+	if err != nil {
+		return fmt.Errorf("failed to decode the signature: %w", err)
+	}
+	signingInput := envelope.Protected + "." + envelope.Payload
+	if err := verifyJWSSignature(header.Algorithm, certs[0].PublicKey, []byte(signingInput), sig); err != nil {
+		return err
+	}
+
+	payload := &notationPayload{}
+	if err := decodeJWSPart(envelope.Payload, payload); err != nil {
+		return fmt.Errorf("failed to parse the signed payload: %w", err)
+	}
+	if payload.TargetArtifact.Digest != digest {
+		return fmt.Errorf("the signed payload refers to %q instead of %q", payload.TargetArtifact.Digest, digest)
+	}
+	return nil
+}
+
+// verifyCriticalHeaders verifies that the headers listed in the crit header
+// are understood, and present in the protected header, as required by the JWS
+// specification.
+func verifyCriticalHeaders(protected string, critical []string) error {
+	if len(critical) == 0 {
+		return nil
+	}
+	headers := map[string]json.RawMessage{}
+	if err := decodeJWSPart(protected, &headers); err != nil {
+		return fmt.Errorf("failed to parse the protected header: %w", err)
+	}
+	for _, name := range critical {
+		if !notationCriticalHeaders[name] {
+			return fmt.Errorf("unsupported critical header %q", name)
+		}
+		if _, found := headers[name]; !found {
+			return fmt.Errorf("critical header %q is missing", name)
+		}
+	}
+	return nil
+}
+
+// verifyJWSSignature verifies a JWS signature with the algorithms allowed by
+// notation.
+func verifyJWSSignature(alg string, key crypto.PublicKey, signingInput, sig []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "PS256", "ES256":
+		hash = crypto.SHA256
+	case "PS384", "ES384":
+		hash = crypto.SHA384
+	case "PS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write(signingInput)
+	hashed := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "PS") {
+			return fmt.Errorf("signature algorithm %q does not match the RSA certificate", alg)
+		}
+		return rsa.VerifyPSS(k, hash, hashed, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return fmt.Errorf("signature algorithm %q does not match the ECDSA certificate", alg)
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return errors.New("invalid ECDSA signature length")
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, hashed, r, s) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported certificate key type %T", key)
+	}
+}
+
+// decodeJWSPart decodes a base64url-encoded JSON part of a JWS envelope.
+func decodeJWSPart(part string, v interface{}) error {
+	content, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, v)
+}
+
+// certPool returns the pool of PEM-encoded root certificates.
+func certPool(blocks []*pem.Block) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	found := false
+	for _, block := range blocks {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the root certificate: %w", err)
+		}
+		pool.AddCert(cert)
+		found = true
+	}
+	if !found {
+		return nil, errors.New("no PEM-encoded certificate found")
+	}
+	return pool, nil
+}
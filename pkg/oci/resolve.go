@@ -0,0 +1,141 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// tagFileSuffix is the suffix of the file, next to the directory of an image
+// digest, that records the tag the image was pulled with.
+const tagFileSuffix = ".tag"
+
+// andSeparator matches the whitespace between two comparisons of a semver range.
+var andSeparator = regexp.MustCompile(`([0-9A-Za-z*])\s+([<>=!~^v0-9*])`)
+
+// ResolveImage returns the image to pull for imageName.
+//
+// If the tag of imageName is not a valid tag but a semver constraint, e.g.
+// `pkg:~1.4` or `pkg:>=2.0.0 <3.0.0`, the tags of the repository are listed and
+// the image with the highest tag that satisfies the constraint is returned.
+// Otherwise, imageName is returned as is.
+func ResolveImage(imageName string, options ...remote.Option) (string, error) {
+	repo, constraint, err := tagConstraint(imageName)
+	if err != nil || constraint == nil {
+		return imageName, err
+	}
+	tags, err := remote.List(repo, options...)
+	if err != nil {
+		return "", fmt.Errorf("failed to list the tags of %s: %w", repo, err)
+	}
+	tag, found := highestTag(tags, constraint)
+	if !found {
+		return "", fmt.Errorf("no tag of %s satisfies the semver constraint in %q", repo, imageName)
+	}
+	return repo.Tag(tag).String(), nil
+}
+
+// tagConstraint returns the repository and the semver constraint of imageName,
+// or a nil constraint if imageName is referenced by a valid tag or by digest.
+func tagConstraint(imageName string) (name.Repository, *semver.Constraints, error) {
+	i := strings.LastIndex(imageName, ":")
+	if strings.Contains(imageName, "@") || i <= strings.LastIndex(imageName, "/") {
+		return name.Repository{}, nil, nil
+	}
+	if _, err := name.NewTag(imageName); err == nil {
+		return name.Repository{}, nil, nil
+	}
+	repo, err := name.NewRepository(imageName[:i])
+	if err != nil {
+		return name.Repository{}, nil, fmt.Errorf("failed to parse reference %q: %v", imageName, err)
+	}
+	constraint, err := parseConstraint(imageName[i+1:])
+	if err != nil {
+		return name.Repository{}, nil, fmt.Errorf("%q is neither a valid tag nor a semver constraint: %v", imageName[i+1:], err)
+	}
+	return repo, constraint, nil
+}
+
+// parseConstraint parses a semver constraint. The semver library expects a
+// comma between the comparisons of a range, e.g. `>=2.0.0, <3.0.0`, but they
+// are commonly separated with a space.
+func parseConstraint(constraint string) (*semver.Constraints, error) {
+	return semver.NewConstraint(andSeparator.ReplaceAllString(constraint, "$1, $2"))
+}
+
+// highestTag returns the highest semver tag that satisfies the constraint.
+// Tags that are not semver versions are ignored.
+func highestTag(tags []string, constraint *semver.Constraints) (string, bool) {
+	var highest *semver.Version
+	for _, tag := range tags {
+		v, err := semver.NewVersion(tag)
+		if err != nil || !constraint.Check(v) {
+			continue
+		}
+		if highest == nil || v.GreaterThan(highest) {
+			highest = v
+		}
+	}
+	if highest == nil {
+		return "", false
+	}
+	return highest.Original(), true
+}
+
+// writeTag records the tag that the image with the given digest was pulled
+// with, so that the reconciler can report it in the RootSync/RepoSync status.
+// The record is removed if the image was pulled by digest.
+func writeTag(ociRoot, digestHex, imageName string) error {
+	path := filepath.Join(ociRoot, digestHex+tagFileSuffix)
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return fmt.Errorf("failed to parse reference %q: %v", imageName, err)
+	}
+	tag, isTag := ref.(name.Tag)
+	if !isTag {
+		return removeTag(ociRoot, digestHex)
+	}
+	if err := os.WriteFile(path, []byte(tag.TagStr()), 0644); err != nil {
+		return fmt.Errorf("failed to write the tag file %q: %w", path, err)
+	}
+	return nil
+}
+
+// removeTag removes the tag recorded for the image with the given digest.
+func removeTag(ociRoot, digestHex string) error {
+	path := filepath.Join(ociRoot, digestHex+tagFileSuffix)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove the tag file %q: %w", path, err)
+	}
+	return nil
+}
+
+// ReadTag returns the tag that the image with the given digest was pulled
+// with, or an empty string if it was pulled by digest.
+func ReadTag(ociRoot, digestHex string) string {
+	content, err := os.ReadFile(filepath.Join(ociRoot, digestHex+tagFileSuffix))
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"testing"
+)
+
+func TestTagConstraint(t *testing.T) {
+	testCases := []struct {
+		name           string
+		image          string
+		wantRepo       string
+		wantConstraint bool
+		wantErr        bool
+	}{
+		{
+			name:  "no tag",
+			image: "us-docker.pkg.dev/my-project/my-repo/pkg",
+		},
+		{
+			name:  "valid tag",
+			image: "us-docker.pkg.dev/my-project/my-repo/pkg:v1.4.2",
+		},
+		{
+			name:  "registry with a port",
+			image: "localhost:5000/pkg",
+		},
+		{
+			name:  "digest",
+			image: "us-docker.pkg.dev/my-project/my-repo/pkg@sha256:2b8c0fdd5f5e4d85b0d7dc8b3d4f2a1a62a3a6c7ac0c4b5bfb6a9a9b0e0d1c2f",
+		},
+		{
+			name:           "tilde constraint",
+			image:          "us-docker.pkg.dev/my-project/my-repo/pkg:~1.4",
+			wantRepo:       "us-docker.pkg.dev/my-project/my-repo/pkg",
+			wantConstraint: true,
+		},
+		{
+			name:           "range constraint",
+			image:          "localhost:5000/pkg:>=2.0.0 <3.0.0",
+			wantRepo:       "localhost:5000/pkg",
+			wantConstraint: true,
+		},
+		{
+			name:           "latest semver tag",
+			image:          "us-docker.pkg.dev/my-project/my-repo/pkg:*",
+			wantRepo:       "us-docker.pkg.dev/my-project/my-repo/pkg",
+			wantConstraint: true,
+		},
+		{
+			name:    "invalid constraint",
+			image:   "us-docker.pkg.dev/my-project/my-repo/pkg:>>1",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, constraint, err := tagConstraint(tc.image)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("tagConstraint() got error %v, want error %t", err, tc.wantErr)
+			}
+			if (constraint != nil) != tc.wantConstraint {
+				t.Errorf("tagConstraint() got constraint %v, want constraint %t", constraint, tc.wantConstraint)
+			}
+			if tc.wantConstraint && repo.Name() != tc.wantRepo {
+				t.Errorf("tagConstraint() got repository %q, want %q", repo.Name(), tc.wantRepo)
+			}
+		})
+	}
+}
+
+func TestHighestTag(t *testing.T) {
+	tags := []string{"latest", "v1.3.9", "v1.4.0", "v1.4.11", "v1.4.2", "v1.5.0", "v2.0.0-rc.1", "2.1.0", "main"}
+	testCases := []struct {
+		constraint string
+		wantTag    string
+		wantFound  bool
+	}{
+		{
+			constraint: "~1.4",
+			wantTag:    "v1.4.11",
+			wantFound:  true,
+		},
+		{
+			constraint: "^1.3",
+			wantTag:    "v1.5.0",
+			wantFound:  true,
+		},
+		{
+			constraint: ">=2.0.0 <3.0.0",
+			wantTag:    "2.1.0",
+			wantFound:  true,
+		},
+		{
+			constraint: "*",
+			wantTag:    "2.1.0",
+			wantFound:  true,
+		},
+		{
+			constraint: ">=3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.constraint, func(t *testing.T) {
+			constraint, err := parseConstraint(tc.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tag, found := highestTag(tags, constraint)
+			if tag != tc.wantTag || found != tc.wantFound {
+				t.Errorf("highestTag() got (%q, %t), want (%q, %t)", tag, found, tc.wantTag, tc.wantFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/status"
+)
+
+// Verifier verifies the signature of an OCI artifact before it is synced.
+type Verifier struct {
+	// Provider is the tool used to sign the artifact.
+	Provider configsync.VerificationProvider
+	// KeysDir is the directory that holds the trusted PEM-encoded keys. It is
+	// read on every verification, so rotated keys are picked up without a
+	// restart.
+	KeysDir string
+}
+
+// VerificationError is the error returned when an artifact does not carry a
+// valid signature.
+type VerificationError struct {
+	Digest string
+	Err    error
+}
+
+// Error implements error.
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("failed to verify the signature of %s: %v", e.Digest, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *VerificationError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the error code reported in the RootSync and RepoSync status.
+func (e *VerificationError) Code() string {
+	return status.SignatureVerificationErrorCode
+}
+
+// Verify returns a VerificationError if the artifact with the given digest
+// does not carry a signature that is valid for one of the trusted keys.
+func (v *Verifier) Verify(digest name.Digest, options ...remote.Option) error {
+	blocks, err := readPEMBlocks(v.KeysDir)
+	if err != nil {
+		return &VerificationError{Digest: digest.String(), Err: err}
+	}
+	switch v.Provider {
+	case configsync.VerificationProviderCosign:
+		err = verifyCosign(digest, blocks, options...)
+	case configsync.VerificationProviderNotation:
+		err = verifyNotation(digest, blocks, options...)
+	default:
+		err = fmt.Errorf("unsupported verification provider %q", v.Provider)
+	}
+	if err != nil {
+		return &VerificationError{Digest: digest.String(), Err: err}
+	}
+	return nil
+}
+
+// readPEMBlocks returns the PEM blocks of all the files under dir. The hidden
+// files and directories created by the kubelet for projected volumes are
+// skipped.
+func readPEMBlocks(dir string) ([]*pem.Block, error) {
+	var blocks []*pem.Block
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for {
+			var block *pem.Block
+			block, content = pem.Decode(content)
+			if block == nil {
+				break
+			}
+			blocks = append(blocks, block)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the trusted keys in %q: %w", dir, err)
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no PEM-encoded key found in %q", dir)
+	}
+	return blocks, nil
+}
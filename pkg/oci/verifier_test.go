@@ -0,0 +1,314 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+)
+
+const (
+	testDigest  = "sha256:2b8c0fdd5f5e4d85b0d7dc8b3d4f2a1a62a3a6c7ac0c4b5bfb6a9a9b0e0d1c2f"
+	otherDigest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+)
+
+func cosignSign(t *testing.T, key *ecdsa.PrivateKey, digest string) ([]byte, string) {
+	t.Helper()
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"example.com/app"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, digest))
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload, base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestVerifyCosignSignature(t *testing.T) {
+	trusted, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	untrusted, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []crypto.PublicKey{trusted.Public()}
+
+	testCases := []struct {
+		name    string
+		key     *ecdsa.PrivateKey
+		digest  string
+		wantErr bool
+	}{
+		{
+			name:   "signed with a trusted key",
+			key:    trusted,
+			digest: testDigest,
+		},
+		{
+			name:    "signed with an untrusted key",
+			key:     untrusted,
+			digest:  testDigest,
+			wantErr: true,
+		},
+		{
+			name:    "signature of another digest",
+			key:     trusted,
+			digest:  otherDigest,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, sig := cosignSign(t, tc.key, tc.digest)
+			err := verifyCosignSignature(testDigest, payload, sig, keys)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("verifyCosignSignature() got error %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func newCertificate(t *testing.T, template, parent *x509.Certificate, pub crypto.PublicKey, priv crypto.Signer) *x509.Certificate {
+	t.Helper()
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// notationSign returns a notation signature envelope. The protected header
+// fields are overridden by the given header fields, and removed if nil.
+func notationSign(t *testing.T, key *ecdsa.PrivateKey, chain []*x509.Certificate, digest string, header map[string]interface{}) []byte {
+	t.Helper()
+	fields := map[string]interface{}{
+		"alg":                        "ES256",
+		"cty":                        notationPayloadMediaType,
+		"crit":                       []string{notationSigningSchemeHeader},
+		notationSigningSchemeHeader:  notationSigningSchemeX509,
+		"io.cncf.notary.signingTime": time.Now(),
+	}
+	for k, v := range header {
+		if v == nil {
+			delete(fields, k)
+		} else {
+			fields[k] = v
+		}
+	}
+	protected, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"targetArtifact": map[string]interface{}{
+			"mediaType": "application/vnd.oci.image.manifest.v1+json",
+			"digest":    digest,
+			"size":      1024,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encodedProtected := base64.RawURLEncoding.EncodeToString(protected)
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	hash := sha256.Sum256([]byte(encodedProtected + "." + encodedPayload))
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	envelope := &jwsEnvelope{
+		Payload:   encodedPayload,
+		Protected: encodedProtected,
+		Signature: base64.RawURLEncoding.EncodeToString(sig),
+	}
+	for _, cert := range chain {
+		envelope.Header.CertChain = append(envelope.Header.CertChain, cert.Raw)
+	}
+	content, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func TestVerifyNotationEnvelope(t *testing.T) {
+	now := time.Now()
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test root"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	root := newCertificate(t, rootTemplate, rootTemplate, rootKey.Public(), rootKey)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf := newCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test signer"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}, root, leafKey.Public(), rootKey)
+
+	selfSigned := newCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "untrusted signer"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}, rootTemplate, leafKey.Public(), leafKey)
+
+	expiredLeaf := newCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(4),
+		Subject:      pkix.Name{CommonName: "expired signer"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(-time.Minute),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}, root, leafKey.Public(), rootKey)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+
+	testCases := []struct {
+		name    string
+		key     *ecdsa.PrivateKey
+		chain   []*x509.Certificate
+		digest  string
+		header  map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:   "signed with a trusted certificate",
+			key:    leafKey,
+			chain:  []*x509.Certificate{leaf, root},
+			digest: testDigest,
+		},
+		{
+			name:    "signed with an untrusted certificate",
+			key:     leafKey,
+			chain:   []*x509.Certificate{selfSigned},
+			digest:  testDigest,
+			wantErr: true,
+		},
+		{
+			name:    "signed with another key than the certificate",
+			key:     rootKey,
+			chain:   []*x509.Certificate{leaf, root},
+			digest:  testDigest,
+			wantErr: true,
+		},
+		{
+			name:    "signature of another digest",
+			key:     leafKey,
+			chain:   []*x509.Certificate{leaf, root},
+			digest:  otherDigest,
+			wantErr: true,
+		},
+		{
+			name:    "signing time backdated into the validity of an expired certificate",
+			key:     leafKey,
+			chain:   []*x509.Certificate{expiredLeaf, root},
+			digest:  testDigest,
+			header:  map[string]interface{}{"io.cncf.notary.signingTime": now.Add(-30 * time.Minute)},
+			wantErr: true,
+		},
+		{
+			name:   "critical expiry in the future",
+			key:    leafKey,
+			chain:  []*x509.Certificate{leaf, root},
+			digest: testDigest,
+			header: map[string]interface{}{
+				"crit":               []string{notationSigningSchemeHeader, notationExpiryHeader},
+				notationExpiryHeader: now.Add(time.Hour),
+			},
+		},
+		{
+			name:    "expired signature",
+			key:     leafKey,
+			chain:   []*x509.Certificate{leaf, root},
+			digest:  testDigest,
+			header:  map[string]interface{}{notationExpiryHeader: now.Add(-time.Minute)},
+			wantErr: true,
+		},
+		{
+			name:    "unknown critical header",
+			key:     leafKey,
+			chain:   []*x509.Certificate{leaf, root},
+			digest:  testDigest,
+			header:  map[string]interface{}{"crit": []string{notationSigningSchemeHeader, "io.example.policy"}, "io.example.policy": "strict"},
+			wantErr: true,
+		},
+		{
+			name:    "missing critical header",
+			key:     leafKey,
+			chain:   []*x509.Certificate{leaf, root},
+			digest:  testDigest,
+			header:  map[string]interface{}{notationSigningSchemeHeader: nil},
+			wantErr: true,
+		},
+		{
+			name:    "signing authority scheme",
+			key:     leafKey,
+			chain:   []*x509.Certificate{leaf, root},
+			digest:  testDigest,
+			header:  map[string]interface{}{notationSigningSchemeHeader: "notary.x509.signingAuthority"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			envelope := notationSign(t, tc.key, tc.chain, tc.digest, tc.header)
+			err := verifyNotationEnvelope(testDigest, envelope, roots, now)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("verifyNotationEnvelope() got error %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
 	"kpt.dev/configsync/pkg/metadata"
@@ -59,6 +60,14 @@ type cacheForCommit struct {
 	// declared resources.
 	applied bool
 
+	// rolledBack indicates whether the commit failed to sync, and the last
+	// healthy commit was re-applied instead.
+	rolledBack bool
+
+	// dryRun is the result of the server-side dry-run of the declared
+	// resources, if the updater runs a dry-run.
+	dryRun *v1beta1.DryRunResult
+
 	// watchesUpdated indicates whether the remediator watches have been updated
 	// for the latest declared resources.
 	watchesUpdated bool
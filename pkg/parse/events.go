@@ -0,0 +1,99 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/status"
+)
+
+// Reasons of the Events the reconciler emits on its RootSync or RepoSync.
+//
+// Events are only emitted on transitions, e.g. when a new error is reported,
+// and the event recorder rate-limits the Events of each object, so that retry
+// loops don't flood the API server.
+const (
+	// EventReasonSyncStarted is the reason of the Normal Event emitted when the
+	// reconciler starts applying a new commit.
+	EventReasonSyncStarted = "SyncStarted"
+
+	// EventReasonRenderingFailed is the reason of the Warning Event emitted when
+	// the rendering of a commit fails.
+	EventReasonRenderingFailed = "RenderingFailed"
+
+	// EventReasonManagementConflict is the reason of the Warning Event emitted
+	// when an object declared in the source is managed by another sync.
+	EventReasonManagementConflict = "ManagementConflict"
+
+	// EventReasonDeleteAllNamespacesBlocked is the reason of the Warning Event
+	// emitted when a commit is not synced because it would delete all the
+	// Namespaces.
+	EventReasonDeleteAllNamespacesBlocked = "DeleteAllNamespacesBlocked"
+)
+
+// recordRenderingFailedEvent emits a Warning Event if errs is not empty.
+func recordRenderingFailedEvent(recorder record.EventRecorder, obj runtime.Object, commit string, errs []v1beta1.ConfigSyncError) {
+	if len(errs) == 0 {
+		return
+	}
+	recorder.Eventf(obj, corev1.EventTypeWarning, EventReasonRenderingFailed,
+		"Failed to render commit %s with %d error(s): %s", commit, len(errs), errorCodes(errs))
+}
+
+// recordSyncErrorEvents emits a Warning Event for each management conflict
+// error and Namespace safeguard error in newErrs which is not in oldErrs, so
+// that the same error is not reported again by every status update.
+func recordSyncErrorEvents(recorder record.EventRecorder, obj runtime.Object, commit string, oldErrs, newErrs []v1beta1.ConfigSyncError) {
+	reported := make(map[string]bool)
+	for _, err := range oldErrs {
+		reported[err.Code+err.ErrorMessage] = true
+	}
+	for _, err := range newErrs {
+		if reported[err.Code+err.ErrorMessage] {
+			continue
+		}
+		reported[err.Code+err.ErrorMessage] = true
+		switch err.Code {
+		case status.ManagementConflictErrorCode:
+			recorder.Eventf(obj, corev1.EventTypeWarning, EventReasonManagementConflict,
+				"Failed to sync commit %s: %s", commit, err.ErrorMessage)
+		case status.EmptySourceErrorCode:
+			recorder.Eventf(obj, corev1.EventTypeWarning, EventReasonDeleteAllNamespacesBlocked,
+				"Blocked syncing commit %s: %s", commit, err.ErrorMessage)
+		}
+	}
+}
+
+// errorCodes returns the sorted, distinct KNV codes of errs.
+func errorCodes(errs []v1beta1.ConfigSyncError) string {
+	seen := make(map[string]bool)
+	var codes []string
+	for _, err := range errs {
+		code := fmt.Sprintf("KNV%s", err.Code)
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return strings.Join(codes, ", ")
+}
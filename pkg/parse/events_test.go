@@ -0,0 +1,101 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/tools/record"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+func TestRecordSyncErrorEvents(t *testing.T) {
+	conflictErr := v1beta1.ConfigSyncError{
+		Code:         status.ManagementConflictErrorCode,
+		ErrorMessage: "KNV1060: The root reconciler detects a management conflict for a resource declared in another repository.",
+	}
+	safeguardErr := v1beta1.ConfigSyncError{
+		Code:         status.EmptySourceErrorCode,
+		ErrorMessage: "KNV2006: New commit would delete all Namespaces [bar foo].",
+	}
+	applyErr := v1beta1.ConfigSyncError{
+		Code:         status.APIServerErrorCode,
+		ErrorMessage: "KNV2002: failed to apply",
+	}
+
+	testCases := []struct {
+		name    string
+		oldErrs []v1beta1.ConfigSyncError
+		newErrs []v1beta1.ConfigSyncError
+		want    []string
+	}{
+		{
+			name: "no errors",
+		},
+		{
+			name:    "new errors",
+			newErrs: []v1beta1.ConfigSyncError{applyErr, conflictErr, safeguardErr},
+			want: []string{
+				"Warning ManagementConflict Failed to sync commit abc123: " + conflictErr.ErrorMessage,
+				"Warning DeleteAllNamespacesBlocked Blocked syncing commit abc123: " + safeguardErr.ErrorMessage,
+			},
+		},
+		{
+			name:    "already reported errors",
+			oldErrs: []v1beta1.ConfigSyncError{conflictErr},
+			newErrs: []v1beta1.ConfigSyncError{conflictErr, safeguardErr},
+			want: []string{
+				"Warning DeleteAllNamespacesBlocked Blocked syncing commit abc123: " + safeguardErr.ErrorMessage,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			recordSyncErrorEvents(recorder, fake.RootSyncObjectV1Beta1(rootSyncName), "abc123", tc.oldErrs, tc.newErrs)
+			close(recorder.Events)
+			var got []string
+			for event := range recorder.Events {
+				got = append(got, event)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("recordSyncErrorEvents() got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecordRenderingFailedEvent(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	rs := fake.RootSyncObjectV1Beta1(rootSyncName)
+	recordRenderingFailedEvent(recorder, rs, "abc123", nil)
+	recordRenderingFailedEvent(recorder, rs, "abc123", []v1beta1.ConfigSyncError{
+		{Code: status.InternalHydrationErrorCode, ErrorMessage: "KNV2015: unable to read the done file"},
+		{Code: status.ActionableHydrationErrorCode, ErrorMessage: "KNV1068: invalid kustomization"},
+	})
+	close(recorder.Events)
+	var got []string
+	for event := range recorder.Events {
+		got = append(got, event)
+	}
+	want := []string{"Warning RenderingFailed Failed to render commit abc123 with 2 error(s): KNV1068, KNV2015"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("recordRenderingFailedEvent() got diff (-want +got):\n%s", diff)
+	}
+}
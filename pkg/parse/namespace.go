@@ -17,23 +17,20 @@ package parse
 import (
 	"context"
 	"fmt"
-	"sync"
-	"time"
 
 	"github.com/google/go-cmp/cmp"
-	"k8s.io/client-go/discovery"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
-	"kpt.dev/configsync/pkg/applier"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/dependency"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
-	"kpt.dev/configsync/pkg/importer/filesystem"
 	"kpt.dev/configsync/pkg/importer/reader"
 	"kpt.dev/configsync/pkg/metrics"
-	"kpt.dev/configsync/pkg/remediator"
 	"kpt.dev/configsync/pkg/reposync"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/syncwindow"
 	"kpt.dev/configsync/pkg/util/compare"
 	utildiscovery "kpt.dev/configsync/pkg/util/discovery"
 	"kpt.dev/configsync/pkg/validate"
@@ -41,36 +38,12 @@ import (
 )
 
 // NewNamespaceRunner creates a new runnable parser for parsing a Namespace repo.
-func NewNamespaceRunner(clusterName, syncName, reconcilerName string, scope declared.Scope, fileReader reader.Reader, c client.Client, pollingPeriod, resyncPeriod, retryPeriod, statusUpdatePeriod time.Duration, fs FileSource, dc discovery.DiscoveryInterface, resources *declared.Resources, app applier.Applier, rem remediator.Interface) (Parser, error) {
-	converter, err := declared.NewValueConverter(dc)
-	if err != nil {
+func NewNamespaceRunner(o RunnerOptions, scope declared.Scope) (Parser, error) {
+	p := &namespace{scope: scope}
+	if err := p.opts.init(o, scope, nil); err != nil {
 		return nil, err
 	}
-
-	return &namespace{
-		opts: opts{
-			clusterName:        clusterName,
-			client:             c,
-			syncName:           syncName,
-			reconcilerName:     reconcilerName,
-			pollingPeriod:      pollingPeriod,
-			resyncPeriod:       resyncPeriod,
-			retryPeriod:        retryPeriod,
-			statusUpdatePeriod: statusUpdatePeriod,
-			files:              files{FileSource: fs},
-			parser:             filesystem.NewParser(fileReader),
-			updater: updater{
-				scope:      scope,
-				resources:  resources,
-				applier:    app,
-				remediator: rem,
-			},
-			discoveryInterface: dc,
-			converter:          converter,
-			mux:                &sync.Mutex{},
-		},
-		scope: scope,
-	}, nil
+	return p, nil
 }
 
 type namespace struct {
@@ -89,7 +62,7 @@ func (p *namespace) options() *opts {
 }
 
 // parseSource implements the Parser interface
-func (p *namespace) parseSource(_ context.Context, state sourceState) ([]ast.FileObject, status.MultiError) {
+func (p *namespace) parseSource(ctx context.Context, state sourceState) ([]ast.FileObject, status.MultiError) {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 
@@ -110,13 +83,24 @@ func (p *namespace) parseSource(_ context.Context, state sourceState) ([]ast.Fil
 		return nil, err
 	}
 
+	clusterName, clusterLabels, err := p.liveClusterMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := p.livePolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	options := validate.Options{
-		ClusterName:    p.clusterName,
+		ClusterName:    clusterName,
+		ClusterLabels:  clusterLabels,
 		ReconcilerName: p.reconcilerName,
 		PolicyDir:      p.SyncDir,
 		PreviousCRDs:   crds,
 		BuildScoper:    builder,
 		Converter:      p.converter,
+		Policies:       policies,
 	}
 	options = OptionsForScope(options, p.scope)
 
@@ -259,9 +243,67 @@ func (p *namespace) setRenderingStatusWithRetires(ctx context.Context, newStatus
 		}
 		return status.APIServerError(err, "failed to update RepoSync rendering status from parser")
 	}
+	recordRenderingFailedEvent(p.recorder, &rs, newStatus.commit, csErrs)
 	return nil
 }
 
+// setSuspendedCondition implements the Parser interface
+func (p *namespace) setSuspendedCondition(ctx context.Context, suspension syncwindow.State) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	var rs v1beta1.RepoSync
+	if err := p.client.Get(ctx, reposync.ObjectKey(p.scope, p.syncName), &rs); err != nil {
+		return status.APIServerError(err, "failed to get RepoSync for parser")
+	}
+
+	var updated bool
+	if suspension.Suspended {
+		updated = reposync.SetSuspended(&rs, suspension.Reason, suspension.Message)
+	} else {
+		updated = reposync.RemoveCondition(&rs, v1beta1.RepoSyncSuspended)
+	}
+	if !updated {
+		return nil
+	}
+
+	if err := p.client.Status().Update(ctx, &rs); err != nil {
+		return status.APIServerError(err, "failed to update RepoSync suspended condition from parser")
+	}
+	return nil
+}
+
+// setWaitingForDependencyCondition implements the Parser interface
+func (p *namespace) setWaitingForDependencyCondition(ctx context.Context, message string) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	var rs v1beta1.RepoSync
+	if err := p.client.Get(ctx, reposync.ObjectKey(p.scope, p.syncName), &rs); err != nil {
+		return status.APIServerError(err, "failed to get RepoSync for parser")
+	}
+
+	updated, _ := reposync.SetSyncing(&rs, true, dependency.ReasonWaitingForDependency, message, rs.Status.Source.Commit, nil, &v1beta1.ErrorSummary{}, metav1.Now())
+	if !updated {
+		return nil
+	}
+
+	if err := p.client.Status().Update(ctx, &rs); err != nil {
+		return status.APIServerError(err, "failed to update RepoSync syncing condition from parser")
+	}
+	return nil
+}
+
+// recordEvent implements the Parser interface
+func (p *namespace) recordEvent(ctx context.Context, eventType, reason, messageFmt string, args ...interface{}) {
+	rs := &v1beta1.RepoSync{}
+	if err := p.client.Get(ctx, reposync.ObjectKey(p.scope, p.syncName), rs); err != nil {
+		klog.Warningf("Failed to get RepoSync to record the %s event: %v", reason, err)
+		return
+	}
+	p.recorder.Eventf(rs, eventType, reason, messageFmt, args...)
+}
+
 // SetSyncStatus implements the Parser interface
 // SetSyncStatus sets the RepoSync sync status.
 // `errs` includes the errors encountered during the apply step;
@@ -289,10 +331,15 @@ func (p *namespace) setSyncStatusWithRetries(ctx context.Context, newStatus sync
 	if newStatus.syncing {
 		reposync.SetSyncing(rs, true, "Sync", "Syncing", rs.Status.Sync.Commit, errorSources, errorSummary, rs.Status.Sync.LastUpdate)
 	} else {
-		if errorSummary.TotalCount == 0 {
+		// A dry-run does not sync the commit.
+		if errorSummary.TotalCount == 0 && newStatus.dryRun == nil {
 			rs.Status.LastSyncedCommit = rs.Status.Sync.Commit
 		}
-		reposync.SetSyncing(rs, false, "Sync", "Sync Completed", rs.Status.Sync.Commit, errorSources, errorSummary, rs.Status.Sync.LastUpdate)
+		if newStatus.healthyCommit != "" {
+			rs.Status.LastHealthyCommit = newStatus.healthyCommit
+		}
+		reason, message := syncCompletedReason(newStatus)
+		reposync.SetSyncing(rs, false, reason, message, rs.Status.Sync.Commit, errorSources, errorSummary, rs.Status.Sync.LastUpdate)
 	}
 
 	// Avoid unnecessary status updates.
@@ -325,6 +372,7 @@ func (p *namespace) setSyncStatusWithRetries(ctx context.Context, newStatus sync
 		}
 		return status.APIServerError(err, fmt.Sprintf("failed to update the RepoSync sync status for the %v namespace", p.scope))
 	}
+	recordSyncErrorEvents(p.recorder, rs, newStatus.commit, currentRS.Status.Sync.Errors, rs.Status.Sync.Errors)
 	return nil
 }
 
@@ -0,0 +1,135 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	v1 "kpt.dev/configsync/pkg/api/configmanagement/v1"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// namespaceSelection tracks the live Namespaces selected by the dynamic
+// NamespaceSelectors of the last parsed source, so that the source is only
+// parsed again when a change of the live Namespaces changes the selection.
+type namespaceSelection struct {
+	// selectors are the dynamic NamespaceSelectors, keyed by name.
+	selectors map[string]labels.Selector
+	// declared are the Namespaces declared in the source. They are selected by
+	// their declared labels, so their live labels are ignored.
+	declared map[string]bool
+	// selected are the live Namespaces selected by each NamespaceSelector
+	// when the source was last parsed.
+	selected map[string][]string
+}
+
+// newNamespaceSelection returns the namespaceSelection of the dynamic
+// NamespaceSelectors declared in the objects.
+func newNamespaceSelection(objs []ast.FileObject) namespaceSelection {
+	result := namespaceSelection{
+		selectors: map[string]labels.Selector{},
+		declared:  map[string]bool{},
+	}
+	for _, obj := range objs {
+		switch obj.GetObjectKind().GroupVersionKind() {
+		case kinds.Namespace():
+			result.declared[obj.GetName()] = true
+		case kinds.NamespaceSelector():
+			s, err := obj.Structured()
+			if err != nil {
+				// The validation reports the error.
+				continue
+			}
+			nss := s.(*v1.NamespaceSelector)
+			if nss.Spec.Mode != v1.NSSelectorDynamicMode {
+				continue
+			}
+			selector, selErr := metav1.LabelSelectorAsSelector(&nss.Spec.Selector)
+			if selErr != nil {
+				// The validation reports the error.
+				continue
+			}
+			result.selectors[obj.GetName()] = selector
+		}
+	}
+	return result
+}
+
+// dynamic returns true if the source declares dynamic NamespaceSelectors.
+func (s *namespaceSelection) dynamic() bool {
+	return len(s.selectors) > 0
+}
+
+// selectNamespaces returns the live Namespaces that are not declared in the
+// source, selected by each NamespaceSelector.
+func (s *namespaceSelection) selectNamespaces(live map[string]map[string]string) map[string][]string {
+	result := make(map[string][]string, len(s.selectors))
+	for name, selector := range s.selectors {
+		var selected []string
+		for ns, nsLabels := range live {
+			if !s.declared[ns] && selector.Matches(labels.Set(nsLabels)) {
+				selected = append(selected, ns)
+			}
+		}
+		sort.Strings(selected)
+		result[name] = selected
+	}
+	return result
+}
+
+// changed returns true if the live Namespaces select other Namespaces than
+// when the source was last parsed.
+func (s *namespaceSelection) changed(live map[string]map[string]string) bool {
+	selected := s.selectNamespaces(live)
+	if len(selected) != len(s.selected) {
+		return true
+	}
+	for name, namespaces := range selected {
+		previous, found := s.selected[name]
+		if !found || len(previous) != len(namespaces) {
+			return true
+		}
+		for i := range namespaces {
+			if previous[i] != namespaces[i] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// liveNamespaceLabels returns the labels of the live Namespaces on the
+// cluster, keyed by name. Namespaces being deleted are skipped.
+func liveNamespaceLabels(ctx context.Context, c client.Reader) (map[string]map[string]string, status.MultiError) {
+	nsList := &corev1.NamespaceList{}
+	if err := c.List(ctx, nsList); err != nil {
+		return nil, status.APIServerError(err, "failed to list the Namespaces for the dynamic NamespaceSelectors")
+	}
+	result := make(map[string]map[string]string, len(nsList.Items))
+	for _, ns := range nsList.Items {
+		if ns.DeletionTimestamp != nil {
+			continue
+		}
+		result[ns.Name] = ns.Labels
+	}
+	return result, nil
+}
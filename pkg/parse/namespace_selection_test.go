@@ -0,0 +1,125 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"context"
+	"testing"
+
+	v1 "kpt.dev/configsync/pkg/api/configmanagement/v1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	syncertest "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+func TestNamespaceSelectionChanged(t *testing.T) {
+	dynamic := fake.NamespaceSelectorObject(core.Name("dev"))
+	dynamic.Spec.Selector.MatchLabels = map[string]string{"environment": "dev"}
+	dynamic.Spec.Mode = v1.NSSelectorDynamicMode
+	static := fake.NamespaceSelectorObject(core.Name("prod"))
+	static.Spec.Selector.MatchLabels = map[string]string{"environment": "prod"}
+	objs := []ast.FileObject{
+		fake.FileObject(dynamic, "nss-dev.yaml"),
+		fake.FileObject(static, "nss-prod.yaml"),
+		fake.Namespace("namespaces/declared", core.Label("environment", "prod")),
+	}
+	initial := map[string]map[string]string{
+		"tenant-a": {"environment": "dev"},
+		"tenant-b": {"environment": "prod"},
+		"declared": {"environment": "prod"},
+	}
+
+	testCases := []struct {
+		name string
+		live map[string]map[string]string
+		want bool
+	}{
+		{
+			name: "unchanged",
+			live: initial,
+			want: false,
+		},
+		{
+			name: "labels changed without changing the selection",
+			live: map[string]map[string]string{
+				"tenant-a": {"environment": "dev", "team": "a"},
+				"tenant-b": {"environment": "staging"},
+				"declared": {"environment": "prod"},
+			},
+			want: false,
+		},
+		{
+			name: "unselected Namespace created",
+			live: map[string]map[string]string{
+				"tenant-a": {"environment": "dev"},
+				"tenant-b": {"environment": "prod"},
+				"tenant-c": {"environment": "prod"},
+				"declared": {"environment": "prod"},
+			},
+			want: false,
+		},
+		{
+			name: "declared Namespace labeled with live labels",
+			live: map[string]map[string]string{
+				"tenant-a": {"environment": "dev"},
+				"tenant-b": {"environment": "prod"},
+				"declared": {"environment": "dev"},
+			},
+			want: false,
+		},
+		{
+			name: "Namespace selected",
+			live: map[string]map[string]string{
+				"tenant-a": {"environment": "dev"},
+				"tenant-b": {"environment": "dev"},
+				"declared": {"environment": "prod"},
+			},
+			want: true,
+		},
+		{
+			name: "selected Namespace deleted",
+			live: map[string]map[string]string{
+				"tenant-b": {"environment": "prod"},
+				"declared": {"environment": "prod"},
+			},
+			want: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newNamespaceSelection(objs)
+			if !s.dynamic() {
+				t.Fatal("dynamic() got false, want true")
+			}
+			s.selected = s.selectNamespaces(initial)
+			if got := s.changed(tc.live); got != tc.want {
+				t.Errorf("changed() got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNamespaceSelectionChanged_Static(t *testing.T) {
+	static := fake.NamespaceSelectorObject(core.Name("prod"))
+	static.Spec.Selector.MatchLabels = map[string]string{"environment": "prod"}
+	o := &opts{
+		client:      syncertest.NewClient(t, core.Scheme, fake.NamespaceObject("tenant-a", core.Label("environment", "prod"))),
+		nsSelection: newNamespaceSelection([]ast.FileObject{fake.FileObject(static, "nss.yaml")}),
+	}
+	if namespaceSelectionChanged(context.Background(), o) {
+		t.Error("namespaceSelectionChanged() got true without dynamic NamespaceSelectors, want false")
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
+	syncertest "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+	"kpt.dev/configsync/pkg/testing/fake"
+	"kpt.dev/configsync/pkg/testing/openapitest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestNamespace_ParseLiveClusterLabels(t *testing.T) {
+	clusterMetadata := func(data map[string]string) *corev1.ConfigMap {
+		cm := fake.ConfigMapObject(core.Name(configsync.ClusterMetadataName), core.Namespace(configsync.ControllerNamespace))
+		cm.Data = data
+		return cm
+	}
+	// parsed returns new objects for each test case since parseSource mutates
+	// them.
+	parsed := func() []ast.FileObject {
+		csObj := fake.ClusterSelectorObject(core.Name("prod"))
+		csObj.Spec.Selector.MatchLabels = map[string]string{"environment": "prod"}
+		return []ast.FileObject{
+			fake.FileObject(csObj, "acme/cs.yaml"),
+			fake.RoleAtPath("acme/prod.yaml", core.Name("prod"), core.Namespace("foo"),
+				core.Annotation(metadata.LegacyClusterSelectorAnnotationKey, "prod")),
+			fake.RoleAtPath("acme/us.yaml", core.Name("us"), core.Namespace("foo"),
+				core.Annotation(metadata.ClusterNameSelectorAnnotationKey, "us-cluster")),
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		clusterName string
+		liveObjs    []client.Object
+		wantNames   []string
+	}{
+		{
+			name: "without live metadata",
+		},
+		{
+			name: "labels and cluster name from the cluster metadata ConfigMap",
+			liveObjs: []client.Object{
+				clusterMetadata(map[string]string{
+					configsync.ClusterMetadataLabelsKey:      "environment=prod",
+					configsync.ClusterMetadataClusterNameKey: "us-cluster",
+				}),
+			},
+			wantNames: []string{"prod", "us"},
+		},
+		{
+			name:        "live Cluster object is not read",
+			clusterName: "us-cluster",
+			liveObjs: []client.Object{
+				fake.ClusterObject(core.Name("us-cluster"), core.Label("environment", "prod")),
+			},
+			wantNames: []string{"us"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			converter, err := openapitest.ValueConverterForTest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			parser := &namespace{
+				scope: "foo",
+				opts: opts{
+					clusterName:        tc.clusterName,
+					parser:             &fakeParser{parse: parsed()},
+					syncName:           "repo-sync",
+					reconcilerName:     "ns-reconciler-foo",
+					client:             syncertest.NewClient(t, core.Scheme, tc.liveObjs...),
+					discoveryInterface: syncertest.NewDiscoveryClient(kinds.Namespace(), kinds.Role()),
+					converter:          converter,
+					files: files{FileSource: FileSource{
+						SyncDir: cmpath.RelativeSlash("acme"),
+					}},
+					updater: updater{
+						scope:     "foo",
+						resources: &declared.Resources{},
+					},
+					mux: &sync.Mutex{},
+				},
+			}
+
+			objs, errs := parser.parseSource(context.Background(), sourceState{commit: "abc123"})
+			if errs != nil {
+				t.Fatalf("got parseSource() errors %v, want nil", errs)
+			}
+			var gotNames []string
+			for _, obj := range objs {
+				if obj.GetObjectKind().GroupVersionKind() == kinds.Role() {
+					gotNames = append(gotNames, obj.GetName())
+				}
+			}
+			if diff := cmp.Diff(tc.wantNames, gotNames); diff != "" {
+				t.Errorf("parseSource() diff (- want, + got): %s", diff)
+			}
+		})
+	}
+}
@@ -19,14 +19,120 @@ import (
 	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
+	clusterregistry "k8s.io/cluster-registry/pkg/apis/clusterregistry/v1alpha1"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/applier"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/importer/analyzer/transform/selectors"
 	"kpt.dev/configsync/pkg/importer/filesystem"
+	"kpt.dev/configsync/pkg/importer/reader"
+	"kpt.dev/configsync/pkg/notification"
+	"kpt.dev/configsync/pkg/remediator"
 	"kpt.dev/configsync/pkg/status"
-	"kpt.dev/configsync/pkg/util/discovery"
+	"kpt.dev/configsync/pkg/syncwindow"
+	utildiscovery "kpt.dev/configsync/pkg/util/discovery"
+	"kpt.dev/configsync/pkg/validate/policy"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// RunnerOptions holds the settings shared by the root and namespace parsers.
+type RunnerOptions struct {
+	// ClusterName is the name of the cluster we're syncing configuration to.
+	ClusterName string
+	// SyncName is the name of the RootSync or RepoSync object.
+	SyncName string
+	// ReconcilerName is the name of the reconciler Deployment.
+	ReconcilerName string
+	// FileReader reads the files in the source.
+	FileReader reader.Reader
+	// Client reads objects from the cluster and updates the sync status.
+	Client client.Client
+	// Recorder emits Events on the RootSync or RepoSync.
+	Recorder record.EventRecorder
+	// PollingPeriod is the period of time between checking the filesystem for
+	// source updates to sync.
+	PollingPeriod time.Duration
+	// ResyncPeriod is the period of time between forced re-sync from source
+	// (even without a new commit).
+	ResyncPeriod time.Duration
+	// RetryPeriod is how long the parser waits between retries, after an error.
+	RetryPeriod time.Duration
+	// StatusUpdatePeriod is how long the parser waits between updates of the
+	// sync status.
+	StatusUpdatePeriod time.Duration
+	// WebhookTriggers receives a value whenever the webhook endpoint accepts a
+	// source change notification. A nil channel disables webhook triggers.
+	WebhookTriggers <-chan struct{}
+	// SyncWindows decides when the reconciler is suspended.
+	SyncWindows *syncwindow.Windows
+	// DependsOn lists the RootSyncs and RepoSyncs that must be synced first.
+	DependsOn []v1beta1.Dependency
+	// Notifier notifies the HTTP endpoints of the outcome of each sync.
+	Notifier *notification.Notifier
+	// Files locates the source and hydrated configs on the filesystem.
+	Files FileSource
+	// DiscoveryClient is how the parser learns what types are available on the
+	// cluster.
+	DiscoveryClient discovery.DiscoveryInterface
+	// Resources holds the declared resources of the last applied source.
+	Resources *declared.Resources
+	// Applier applies the declared resources.
+	Applier applier.Applier
+	// Remediator corrects the drift of the declared resources.
+	Remediator remediator.Interface
+	// AutoRollback re-applies the last healthy commit when a new commit fails
+	// to sync.
+	AutoRollback bool
+	// DryRun applies the objects with a server-side dry-run.
+	DryRun bool
+}
+
+// init sets the options of a parser for the given scope.
+func (p *opts) init(o RunnerOptions, scope declared.Scope, namespaceTriggers <-chan struct{}) error {
+	converter, err := declared.NewValueConverter(o.DiscoveryClient)
+	if err != nil {
+		return err
+	}
+	*p = opts{
+		clusterName:        o.ClusterName,
+		syncName:           o.SyncName,
+		reconcilerName:     o.ReconcilerName,
+		client:             o.Client,
+		recorder:           o.Recorder,
+		pollingPeriod:      o.PollingPeriod,
+		resyncPeriod:       o.ResyncPeriod,
+		retryPeriod:        o.RetryPeriod,
+		statusUpdatePeriod: o.StatusUpdatePeriod,
+		webhookTriggers:    o.WebhookTriggers,
+		namespaceTriggers:  namespaceTriggers,
+		syncWindows:        o.SyncWindows,
+		dependsOn:          o.DependsOn,
+		notifier:           o.Notifier,
+		files:              files{FileSource: o.Files},
+		parser:             filesystem.NewParser(o.FileReader),
+		updater: updater{
+			scope:        scope,
+			resources:    o.Resources,
+			applier:      o.Applier,
+			remediator:   o.Remediator,
+			autoRollback: o.AutoRollback,
+			dryRun:       o.DryRun,
+		},
+		discoveryInterface: o.DiscoveryClient,
+		converter:          converter,
+		mux:                &sync.Mutex{},
+	}
+	return nil
+}
+
 // opts holds configuration and core functionality required by all parsers.
 type opts struct {
 	parser filesystem.ConfigParser
@@ -38,6 +144,9 @@ type opts struct {
 	// status.
 	client client.Client
 
+	// recorder emits Events on the RootSync or RepoSync.
+	recorder record.EventRecorder
+
 	// reconcilerName is the name of the reconciler resources, such as service
 	// account, service, deployment and etc.
 	reconcilerName string
@@ -60,9 +169,32 @@ type opts struct {
 	// sync status, to account for management conflict errors from the remediator.
 	statusUpdatePeriod time.Duration
 
+	// webhookTriggers receives a value whenever the webhook endpoint accepts a
+	// source change notification. A nil channel disables webhook triggers.
+	webhookTriggers <-chan struct{}
+
+	// namespaceTriggers receives a value whenever a live Namespace is created
+	// or deleted, or its labels change. A nil channel disables these triggers.
+	namespaceTriggers <-chan struct{}
+
+	// nsSelection tracks the live Namespaces selected by the dynamic
+	// NamespaceSelectors of the last parsed source.
+	nsSelection namespaceSelection
+
+	// syncWindows decides when the reconciler is suspended, from spec.suspend
+	// and spec.syncWindows.
+	syncWindows *syncwindow.Windows
+
+	// dependsOn lists the RootSyncs and RepoSyncs that must sync their latest
+	// commit without errors before the source is parsed and applied.
+	dependsOn []v1beta1.Dependency
+
+	// notifier notifies the HTTP endpoints of the outcome of each sync.
+	notifier *notification.Notifier
+
 	// discoveryInterface is how the parser learns what types are currently
 	// available on the cluster.
-	discoveryInterface discovery.ServerResourcer
+	discoveryInterface utildiscovery.ServerResourcer
 
 	// converter uses the discoveryInterface to encode the declared fields of
 	// objects in Git.
@@ -80,6 +212,10 @@ type Parser interface {
 	parseSource(ctx context.Context, state sourceState) ([]ast.FileObject, status.MultiError)
 	setSourceStatus(ctx context.Context, newStatus sourceStatus) error
 	setRenderingStatus(ctx context.Context, oldStatus, newStatus renderingStatus) error
+	setSuspendedCondition(ctx context.Context, suspension syncwindow.State) error
+	setWaitingForDependencyCondition(ctx context.Context, message string) error
+	// recordEvent emits an Event on the RootSync or RepoSync.
+	recordEvent(ctx context.Context, eventType, reason, messageFmt string, args ...interface{})
 	SetSyncStatus(ctx context.Context, newStatus syncStatus) error
 	options() *opts
 	// SyncErrors returns all the sync errors, including remediator errors,
@@ -95,6 +231,68 @@ func (o *opts) k8sClient() client.Client {
 	return o.client
 }
 
-func (o *opts) discoveryClient() discovery.ServerResourcer {
+func (o *opts) discoveryClient() utildiscovery.ServerResourcer {
 	return o.discoveryInterface
 }
+
+// liveClusterMetadata returns the name and the labels of the cluster used to
+// evaluate the cluster selectors.
+//
+// The labels are read from the cluster metadata ConfigMap if it exists, and
+// otherwise, for the root reconciler, from the live Cluster object named after
+// the cluster. Namespace reconcilers can't read cluster-scoped objects. The
+// returned labels are nil if neither exists, in which case the Cluster objects
+// declared in the source are used instead. Neither is watched, so a change is
+// only picked up the next time the source is parsed.
+func (o *opts) liveClusterMetadata(ctx context.Context) (string, map[string]string, status.MultiError) {
+	clusterName := o.clusterName
+
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: configsync.ControllerNamespace, Name: configsync.ClusterMetadataName}
+	err := o.k8sClient().Get(ctx, key, cm)
+	switch {
+	case err == nil:
+		if clusterName == "" {
+			clusterName = cm.Data[configsync.ClusterMetadataClusterNameKey]
+		}
+		clusterLabels, err := labels.ConvertSelectorToLabelsMap(cm.Data[configsync.ClusterMetadataLabelsKey])
+		if err != nil {
+			return "", nil, selectors.InvalidClusterMetadataError(cm, err)
+		}
+		return clusterName, clusterLabels, nil
+	case !apierrors.IsNotFound(err):
+		return "", nil, status.APIServerError(err, "failed to get the cluster metadata ConfigMap")
+	}
+
+	if clusterName == "" || o.scope != declared.RootReconciler {
+		return clusterName, nil, nil
+	}
+	cluster := &clusterregistry.Cluster{}
+	err = o.k8sClient().Get(ctx, client.ObjectKey{Name: clusterName}, cluster)
+	switch {
+	case err == nil:
+		if cluster.Labels == nil {
+			return clusterName, map[string]string{}, nil
+		}
+		return clusterName, cluster.Labels, nil
+	case apierrors.IsNotFound(err) || meta.IsNoMatchError(err):
+		// The Cluster CRD is usually not installed on the cluster.
+		return clusterName, nil, nil
+	default:
+		return "", nil, status.APIServerError(err, "failed to get the live Cluster object")
+	}
+}
+
+// livePolicies returns the validation policies declared in the validation
+// policies ConfigMap on the cluster, or nil if it does not exist.
+func (o *opts) livePolicies(ctx context.Context) ([]*policy.Policy, status.MultiError) {
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: configsync.ControllerNamespace, Name: configsync.ValidationPoliciesName}
+	if err := o.k8sClient().Get(ctx, key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, status.APIServerError(err, "failed to get the validation policies ConfigMap")
+	}
+	return policy.FromConfigMap(cm)
+}
@@ -17,8 +17,7 @@ package parse
 import (
 	"context"
 	"fmt"
-	"sync"
-	"time"
+	"path"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
@@ -27,12 +26,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/discovery"
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
-	"kpt.dev/configsync/pkg/applier"
+	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/dependency"
 	"kpt.dev/configsync/pkg/diff"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
 	"kpt.dev/configsync/pkg/importer/filesystem"
@@ -40,9 +39,10 @@ import (
 	"kpt.dev/configsync/pkg/importer/reader"
 	"kpt.dev/configsync/pkg/kinds"
 	"kpt.dev/configsync/pkg/metrics"
-	"kpt.dev/configsync/pkg/remediator"
+	"kpt.dev/configsync/pkg/oci"
 	"kpt.dev/configsync/pkg/rootsync"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/syncwindow"
 	"kpt.dev/configsync/pkg/util/compare"
 	utildiscovery "kpt.dev/configsync/pkg/util/discovery"
 	"kpt.dev/configsync/pkg/validate"
@@ -51,36 +51,14 @@ import (
 )
 
 // NewRootRunner creates a new runnable parser for parsing a Root repository.
-func NewRootRunner(clusterName, syncName, reconcilerName string, format filesystem.SourceFormat, fileReader reader.Reader, c client.Client, pollingPeriod, resyncPeriod, retryPeriod, statusUpdatePeriod time.Duration, fs FileSource, dc discovery.DiscoveryInterface, resources *declared.Resources, app applier.Applier, rem remediator.Interface) (Parser, error) {
-	converter, err := declared.NewValueConverter(dc)
-	if err != nil {
+// namespaceTriggers receives a value whenever the live Namespaces change; a nil
+// channel disables these triggers.
+func NewRootRunner(o RunnerOptions, format filesystem.SourceFormat, namespaceTriggers <-chan struct{}) (Parser, error) {
+	p := &root{sourceFormat: format}
+	if err := p.opts.init(o, declared.RootReconciler, namespaceTriggers); err != nil {
 		return nil, err
 	}
-
-	return &root{
-		opts: opts{
-			clusterName:        clusterName,
-			syncName:           syncName,
-			reconcilerName:     reconcilerName,
-			client:             c,
-			pollingPeriod:      pollingPeriod,
-			resyncPeriod:       resyncPeriod,
-			retryPeriod:        retryPeriod,
-			statusUpdatePeriod: statusUpdatePeriod,
-			files:              files{FileSource: fs},
-			parser:             filesystem.NewParser(fileReader),
-			updater: updater{
-				scope:      declared.RootReconciler,
-				resources:  resources,
-				applier:    app,
-				remediator: rem,
-			},
-			discoveryInterface: dc,
-			converter:          converter,
-			mux:                &sync.Mutex{},
-		},
-		sourceFormat: format,
-	}, nil
+	return p, nil
 }
 
 type root struct {
@@ -99,7 +77,7 @@ func (p *root) options() *opts {
 }
 
 // parseSource implements the Parser interface
-func (p *root) parseSource(_ context.Context, state sourceState) ([]ast.FileObject, status.MultiError) {
+func (p *root) parseSource(ctx context.Context, state sourceState) ([]ast.FileObject, status.MultiError) {
 	wantFiles := state.files
 	if p.sourceFormat == filesystem.SourceFormatHierarchy {
 		// We're using hierarchical mode for the root repository, so ignore files
@@ -125,17 +103,56 @@ func (p *root) parseSource(_ context.Context, state sourceState) ([]ast.FileObje
 		return nil, err
 	}
 
+	// Merge the objects of the additional sources before validating them, so
+	// that conflicts between the sources are reported as duplicate names. The
+	// source path of each object is prefixed with the name of its source.
+	sourceIndex := make(map[string]int)
+	for i, src := range state.sources {
+		klog.Infof("Parsing files from source %q dir: %s", src.source.Name, src.syncDir.OSPath())
+		srcObjs, err := p.parser.Parse(reader.FilePaths{
+			RootDir:   src.syncDir,
+			PolicyDir: cmpath.RelativeSlash(src.source.Name),
+			Files:     src.files,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range srcObjs {
+			sourceIndex[core.GKNN(obj)] = i
+		}
+		objs = append(objs, srcObjs...)
+	}
+
+	clusterName, clusterLabels, err := p.liveClusterMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := p.livePolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	options := validate.Options{
-		ClusterName:    p.clusterName,
+		ClusterName:    clusterName,
+		ClusterLabels:  clusterLabels,
 		ReconcilerName: p.reconcilerName,
 		PolicyDir:      p.SyncDir,
 		PreviousCRDs:   crds,
 		BuildScoper:    builder,
 		Converter:      p.converter,
+		Policies:       policies,
 	}
 	options = OptionsForScope(options, p.scope)
 
 	if p.sourceFormat == filesystem.SourceFormatUnstructured {
+		p.nsSelection = newNamespaceSelection(objs)
+		if p.nsSelection.dynamic() {
+			options.LiveNamespaceLabels, err = liveNamespaceLabels(ctx, p.k8sClient())
+			if err != nil {
+				return nil, err
+			}
+			p.nsSelection.selected = p.nsSelection.selectNamespaces(options.LiveNamespaceLabels)
+		}
 		options.Visitors = append(options.Visitors, p.addImplicitNamespaces)
 		objs, err = validate.Unstructured(objs, options)
 	} else {
@@ -148,6 +165,9 @@ func (p *root) parseSource(_ context.Context, state sourceState) ([]ast.FileObje
 
 	// Duplicated with namespace.go.
 	e := addAnnotationsAndLabels(objs, declared.RootReconciler, p.syncName, p.sourceContext(), state.commit)
+	if e == nil && len(state.sources) > 0 {
+		e = p.addSourceContexts(objs, state, sourceIndex)
+	}
 	if e != nil {
 		err = status.Append(err, status.InternalErrorf("unable to add annotations and labels: %v", e))
 		return nil, err
@@ -155,6 +175,29 @@ func (p *root) parseSource(_ context.Context, state sourceState) ([]ast.FileObje
 	return objs, err
 }
 
+// addSourceContexts annotates the objects of the additional sources with the
+// source they are synced from. All the objects keep the sync token of the
+// commit of the primary source.
+func (p *root) addSourceContexts(objs []ast.FileObject, state sourceState, sourceIndex map[string]int) error {
+	bySource := make([][]ast.FileObject, len(state.sources))
+	for _, obj := range objs {
+		if i, found := sourceIndex[core.GKNN(obj)]; found {
+			bySource[i] = append(bySource[i], obj)
+		}
+	}
+	for i, src := range state.sources {
+		sc := sourceContext{
+			Repo:   src.source.Repo,
+			Branch: src.source.Branch,
+			Rev:    src.source.Rev,
+		}
+		if err := addAnnotationsAndLabels(bySource[i], declared.RootReconciler, p.syncName, sc, state.commit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // setSourceStatus implements the Parser interface
 func (p *root) setSourceStatus(ctx context.Context, newStatus sourceStatus) error {
 	p.mux.Lock()
@@ -227,10 +270,7 @@ func setSourceStatusFields(source *v1beta1.SourceStatus, p Parser, newStatus sou
 		source.Oci = nil
 		source.Helm = nil
 	case v1beta1.OciSource:
-		source.Oci = &v1beta1.OciStatus{
-			Image: p.options().SourceRepo,
-			Dir:   p.options().SyncDir.SlashPath(),
-		}
+		source.Oci = ociStatus(p, newStatus.commit)
 		source.Git = nil
 		source.Helm = nil
 	case v1beta1.HelmSource:
@@ -252,6 +292,20 @@ func setSourceStatusFields(source *v1beta1.SourceStatus, p Parser, newStatus sou
 	source.LastUpdate = newStatus.lastUpdate
 }
 
+// ociStatus returns the status of an OCI source, with the tag and the digest of
+// the image that the oci-sync container pulled for the commit.
+func ociStatus(p Parser, commit string) *v1beta1.OciStatus {
+	ociStatus := &v1beta1.OciStatus{
+		Image: p.options().SourceRepo,
+		Dir:   p.options().SyncDir.SlashPath(),
+	}
+	if commit != "" {
+		ociStatus.Tag = oci.ReadTag(path.Dir(p.options().SourceDir.OSPath()), commit)
+		ociStatus.Digest = "sha256:" + commit
+	}
+	return ociStatus
+}
+
 // setRenderingStatus implements the Parser interface
 func (p *root) setRenderingStatus(ctx context.Context, oldStatus, newStatus renderingStatus) error {
 	if oldStatus.equal(newStatus) {
@@ -311,6 +365,7 @@ func (p *root) setRenderingStatusWithRetires(ctx context.Context, newStatus rend
 		}
 		return status.APIServerError(err, "failed to update RootSync rendering status from parser")
 	}
+	recordRenderingFailedEvent(p.recorder, &rs, newStatus.commit, csErrs)
 	return nil
 }
 
@@ -328,10 +383,7 @@ func setRenderingStatusFields(rendering *v1beta1.RenderingStatus, p Parser, newS
 		rendering.Oci = nil
 		rendering.Helm = nil
 	case v1beta1.OciSource:
-		rendering.Oci = &v1beta1.OciStatus{
-			Image: p.options().SourceRepo,
-			Dir:   p.options().SyncDir.SlashPath(),
-		}
+		rendering.Oci = ociStatus(p, newStatus.commit)
 		rendering.Git = nil
 		rendering.Helm = nil
 	case v1beta1.HelmSource:
@@ -353,6 +405,63 @@ func setRenderingStatusFields(rendering *v1beta1.RenderingStatus, p Parser, newS
 	rendering.LastUpdate = newStatus.lastUpdate
 }
 
+// setSuspendedCondition implements the Parser interface
+func (p *root) setSuspendedCondition(ctx context.Context, suspension syncwindow.State) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	var rs v1beta1.RootSync
+	if err := p.client.Get(ctx, rootsync.ObjectKey(p.syncName), &rs); err != nil {
+		return status.APIServerError(err, "failed to get RootSync for parser")
+	}
+
+	var updated bool
+	if suspension.Suspended {
+		updated = rootsync.SetSuspended(&rs, suspension.Reason, suspension.Message)
+	} else {
+		updated = rootsync.RemoveCondition(&rs, v1beta1.RootSyncSuspended)
+	}
+	if !updated {
+		return nil
+	}
+
+	if err := p.client.Status().Update(ctx, &rs); err != nil {
+		return status.APIServerError(err, "failed to update RootSync suspended condition from parser")
+	}
+	return nil
+}
+
+// setWaitingForDependencyCondition implements the Parser interface
+func (p *root) setWaitingForDependencyCondition(ctx context.Context, message string) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	var rs v1beta1.RootSync
+	if err := p.client.Get(ctx, rootsync.ObjectKey(p.syncName), &rs); err != nil {
+		return status.APIServerError(err, "failed to get RootSync for parser")
+	}
+
+	updated, _ := rootsync.SetSyncing(&rs, true, dependency.ReasonWaitingForDependency, message, rs.Status.Source.Commit, nil, &v1beta1.ErrorSummary{}, metav1.Now())
+	if !updated {
+		return nil
+	}
+
+	if err := p.client.Status().Update(ctx, &rs); err != nil {
+		return status.APIServerError(err, "failed to update RootSync syncing condition from parser")
+	}
+	return nil
+}
+
+// recordEvent implements the Parser interface
+func (p *root) recordEvent(ctx context.Context, eventType, reason, messageFmt string, args ...interface{}) {
+	rs := &v1beta1.RootSync{}
+	if err := p.client.Get(ctx, rootsync.ObjectKey(p.syncName), rs); err != nil {
+		klog.Warningf("Failed to get RootSync to record the %s event: %v", reason, err)
+		return
+	}
+	p.recorder.Eventf(rs, eventType, reason, messageFmt, args...)
+}
+
 // SetSyncStatus implements the Parser interface
 // SetSyncStatus sets the RootSync sync status.
 // `errs` includes the errors encountered during the apply step;
@@ -380,10 +489,15 @@ func (p *root) setSyncStatusWithRetries(ctx context.Context, newStatus syncStatu
 	if newStatus.syncing {
 		rootsync.SetSyncing(rs, true, "Sync", "Syncing", rs.Status.Sync.Commit, errorSources, errorSummary, rs.Status.Sync.LastUpdate)
 	} else {
-		if errorSummary.TotalCount == 0 {
+		// A dry-run does not sync the commit.
+		if errorSummary.TotalCount == 0 && newStatus.dryRun == nil {
 			rs.Status.LastSyncedCommit = rs.Status.Sync.Commit
 		}
-		rootsync.SetSyncing(rs, false, "Sync", "Sync Completed", rs.Status.Sync.Commit, errorSources, errorSummary, rs.Status.Sync.LastUpdate)
+		if newStatus.healthyCommit != "" {
+			rs.Status.LastHealthyCommit = newStatus.healthyCommit
+		}
+		reason, message := syncCompletedReason(newStatus)
+		rootsync.SetSyncing(rs, false, reason, message, rs.Status.Sync.Commit, errorSources, errorSummary, rs.Status.Sync.LastUpdate)
 	}
 
 	// Avoid unnecessary status updates.
@@ -416,16 +530,20 @@ func (p *root) setSyncStatusWithRetries(ctx context.Context, newStatus syncStatu
 		}
 		return status.APIServerError(err, "failed to update RootSync sync status")
 	}
+	recordSyncErrorEvents(p.recorder, rs, newStatus.commit, currentRS.Status.Sync.Errors, rs.Status.Sync.Errors)
 	return nil
 }
 
 func setSyncStatusFields(syncStatus *v1beta1.Status, newStatus syncStatus, denominator int) {
 	cse := status.ToCSE(newStatus.errs)
 	syncStatus.Sync.Commit = newStatus.commit
+	syncStatus.Sync.Sources = newStatus.sources
 	syncStatus.Sync.Git = syncStatus.Source.Git
 	syncStatus.Sync.Oci = syncStatus.Source.Oci
 	syncStatus.Sync.Helm = syncStatus.Source.Helm
 	setSyncStatusErrors(syncStatus, cse, denominator)
+	syncStatus.Sync.Drift = newStatus.drift
+	syncStatus.Sync.DryRun = truncateDryRunResult(newStatus.dryRun, denominator)
 	syncStatus.Sync.LastUpdate = newStatus.lastUpdate
 }
 
@@ -437,6 +555,20 @@ func setSyncStatusErrors(syncStatus *v1beta1.Status, cse []v1beta1.ConfigSyncErr
 	syncStatus.Sync.Errors = cse[0 : len(cse)/denominator]
 }
 
+// truncateDryRunResult keeps the first 1/denominator of the objects to create,
+// update and prune, so that the status fits in the RootSync/RepoSync.
+func truncateDryRunResult(result *v1beta1.DryRunResult, denominator int) *v1beta1.DryRunResult {
+	if result == nil || denominator == 1 {
+		return result
+	}
+	return &v1beta1.DryRunResult{
+		Creates:   result.Creates[0 : len(result.Creates)/denominator],
+		Updates:   result.Updates[0 : len(result.Updates)/denominator],
+		Prunes:    result.Prunes[0 : len(result.Prunes)/denominator],
+		Truncated: true,
+	}
+}
+
 // summarizeErrors summarizes the errors from `sourceStatus` and `syncStatus`, and returns an ErrorSource slice and an ErrorSummary.
 func summarizeErrors(sourceStatus v1beta1.SourceStatus, syncStatus v1beta1.SyncStatus) ([]v1beta1.ErrorSource, *v1beta1.ErrorSummary) {
 	var errorSources []v1beta1.ErrorSource
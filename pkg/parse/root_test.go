@@ -17,6 +17,7 @@ package parse
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 
@@ -25,12 +26,15 @@ import (
 	"github.com/pkg/errors"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	"kpt.dev/configsync/pkg/api/configmanagement"
+	v1 "kpt.dev/configsync/pkg/api/configmanagement/v1"
 	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/applier"
@@ -38,17 +42,22 @@ import (
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/diff/difftest"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/importer/analyzer/transform/selectors"
+	"kpt.dev/configsync/pkg/importer/analyzer/validation/nonhierarchical"
 	"kpt.dev/configsync/pkg/importer/filesystem"
+	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
 	"kpt.dev/configsync/pkg/importer/reader"
 	"kpt.dev/configsync/pkg/kinds"
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/metrics"
+	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/status"
 	syncertest "kpt.dev/configsync/pkg/syncer/syncertest/fake"
 	"kpt.dev/configsync/pkg/testing/fake"
 	"kpt.dev/configsync/pkg/testing/openapitest"
 	"kpt.dev/configsync/pkg/testing/testmetrics"
 	discoveryutil "kpt.dev/configsync/pkg/util/discovery"
+	"kpt.dev/configsync/pkg/validate/policy"
 	"sigs.k8s.io/cli-utils/pkg/testutil"
 
 	"sigs.k8s.io/cli-utils/pkg/common"
@@ -77,6 +86,10 @@ func (r *noOpRemediator) FightErrors() []status.Error {
 	return nil
 }
 
+func (r *noOpRemediator) Drift() []v1beta1.ResourceDrift {
+	return nil
+}
+
 func (r *noOpRemediator) NeedsUpdate() bool {
 	return r.needsUpdate
 }
@@ -438,6 +451,7 @@ func TestRoot_Parse(t *testing.T) {
 					syncName:           rootSyncName,
 					reconcilerName:     rootReconcilerName,
 					client:             syncertest.NewClient(t, core.Scheme, fake.RootSyncObjectV1Beta1(rootSyncName)),
+					recorder:           &record.FakeRecorder{},
 					discoveryInterface: syncertest.NewDiscoveryClient(kinds.Namespace(), kinds.Role()),
 					converter:          converter,
 					updater: updater{
@@ -466,6 +480,363 @@ func TestRoot_Parse(t *testing.T) {
 	}
 }
 
+func TestRoot_ParseSources(t *testing.T) {
+	sources := []additionalSourceState{{
+		source: reconcilermanager.Source{Name: "base", SourceType: string(v1beta1.GitSource), Repo: "https://github.com/test/base", Branch: "main", Rev: "HEAD"},
+		commit: "abc123",
+	}}
+	primary := func() []ast.FileObject {
+		return []ast.FileObject{
+			fake.Namespace("acme/namespaces/shared"),
+			fake.FileObject(fake.ConfigMapObject(core.Name("shared"), core.Namespace("shared")), "acme/cm.yaml"),
+		}
+	}
+	testCases := []struct {
+		name        string
+		sourceObjs  []ast.FileObject
+		wantIDs     []core.ID
+		wantErrCode string
+	}{
+		{
+			name:       "objects of the sources are merged",
+			sourceObjs: []ast.FileObject{fake.RoleAtPath("base/role.yaml", core.Name("reader"), core.Namespace("shared"))},
+			wantIDs: []core.ID{
+				core.IDOf(fake.Namespace("acme/namespaces/shared")),
+				core.IDOf(fake.FileObject(fake.ConfigMapObject(core.Name("shared"), core.Namespace("shared")), "acme/cm.yaml")),
+				core.IDOf(fake.RoleAtPath("base/role.yaml", core.Name("reader"), core.Namespace("shared"))),
+			},
+		},
+		{
+			name:        "conflicting objects of the sources are reported as duplicate names",
+			sourceObjs:  []ast.FileObject{fake.FileObject(fake.ConfigMapObject(core.Name("shared"), core.Namespace("shared")), "base/cm.yaml")},
+			wantErrCode: nonhierarchical.NameCollisionErrorCode,
+		},
+	}
+
+	converter, err := openapitest.ValueConverterForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := &root{
+				sourceFormat: filesystem.SourceFormatUnstructured,
+				opts: opts{
+					parser: &sourcesParser{parse: map[string][]ast.FileObject{
+						"acme": primary(),
+						"base": tc.sourceObjs,
+					}},
+					syncName:           rootSyncName,
+					reconcilerName:     rootReconcilerName,
+					client:             syncertest.NewClient(t, core.Scheme, fake.RootSyncObjectV1Beta1(rootSyncName)),
+					discoveryInterface: syncertest.NewDiscoveryClient(kinds.Namespace(), kinds.Role(), kinds.ConfigMap()),
+					converter:          converter,
+					files: files{FileSource: FileSource{
+						SyncDir:    cmpath.RelativeSlash("acme"),
+						SourceRepo: "https://github.com/test/acme",
+					}},
+					updater: updater{
+						scope:     declared.RootReconciler,
+						resources: &declared.Resources{},
+					},
+				},
+			}
+			state := sourceState{commit: "def456", sources: sources}
+
+			objs, errs := parser.parseSource(context.Background(), state)
+			if tc.wantErrCode != "" {
+				if !status.HasBlockingErrors(errs) || errs.Errors()[0].Code() != tc.wantErrCode {
+					t.Fatalf("got parseSource() errors %v, want code %s", errs, tc.wantErrCode)
+				}
+				return
+			}
+			if errs != nil {
+				t.Fatalf("got parseSource() errors %v, want nil", errs)
+			}
+			var gotIDs []core.ID
+			for _, obj := range objs {
+				gotIDs = append(gotIDs, core.IDOf(obj))
+				wantRepo := "https://github.com/test/acme"
+				if obj.GetKind() == kinds.Role().Kind {
+					wantRepo = "https://github.com/test/base"
+				}
+				if gitContext := core.GetAnnotation(obj, metadata.GitContextKey); !strings.Contains(gitContext, wantRepo) {
+					t.Errorf("got %s annotation %q for %s, want repo %s", metadata.GitContextKey, gitContext, core.IDOf(obj), wantRepo)
+				}
+				if token := core.GetAnnotation(obj, metadata.SyncTokenAnnotationKey); token != "def456" {
+					t.Errorf("got sync token %q for %s, want def456", token, core.IDOf(obj))
+				}
+			}
+			if diff := cmp.Diff(tc.wantIDs, gotIDs, cmpopts.SortSlices(func(a, b core.ID) bool { return a.String() < b.String() })); diff != "" {
+				t.Errorf("parseSource() diff (- want, + got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRoot_ParseDynamicNamespaceSelectors(t *testing.T) {
+	nssObj := fake.NamespaceSelectorObject(core.Name("dev"))
+	nssObj.Spec.Selector.MatchLabels = map[string]string{"environment": "dev"}
+	nssObj.Spec.Mode = v1.NSSelectorDynamicMode
+	parsed := []ast.FileObject{
+		fake.FileObject(nssObj, "acme/nss.yaml"),
+		fake.RoleAtPath("acme/role.yaml", core.Name("reader"), core.Annotation(metadata.NamespaceSelectorAnnotationKey, "dev")),
+	}
+
+	converter, err := openapitest.ValueConverterForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := &root{
+		sourceFormat: filesystem.SourceFormatUnstructured,
+		opts: opts{
+			parser:         &fakeParser{parse: parsed},
+			syncName:       rootSyncName,
+			reconcilerName: rootReconcilerName,
+			client: syncertest.NewClient(t, core.Scheme, fake.RootSyncObjectV1Beta1(rootSyncName),
+				fake.NamespaceObject("tenant-a", core.Label("environment", "dev")),
+				fake.NamespaceObject("tenant-b", core.Label("environment", "prod"))),
+			discoveryInterface: syncertest.NewDiscoveryClient(kinds.Namespace(), kinds.Role()),
+			converter:          converter,
+			files: files{FileSource: FileSource{
+				SyncDir: cmpath.RelativeSlash("acme"),
+			}},
+			updater: updater{
+				scope:     declared.RootReconciler,
+				resources: &declared.Resources{},
+			},
+		},
+	}
+
+	objs, errs := parser.parseSource(context.Background(), sourceState{commit: "abc123"})
+	if errs != nil {
+		t.Fatalf("got parseSource() errors %v, want nil", errs)
+	}
+	if diff := cmp.Diff(map[string][]string{"dev": {"tenant-a"}}, parser.nsSelection.selected); diff != "" {
+		t.Errorf("nsSelection.selected diff (- want, + got): %s", diff)
+	}
+	var gotIDs []core.ID
+	for _, obj := range objs {
+		gotIDs = append(gotIDs, core.IDOf(obj))
+	}
+	wantIDs := []core.ID{
+		core.IDOf(fake.RoleObject(core.Name("reader"), core.Namespace("tenant-a"))),
+	}
+	if diff := cmp.Diff(wantIDs, gotIDs); diff != "" {
+		t.Errorf("parseSource() diff (- want, + got): %s", diff)
+	}
+}
+
+func TestRoot_ParseLiveClusterLabels(t *testing.T) {
+	clusterMetadata := func(data map[string]string) *corev1.ConfigMap {
+		cm := fake.ConfigMapObject(core.Name(configsync.ClusterMetadataName), core.Namespace(configsync.ControllerNamespace))
+		cm.Data = data
+		return cm
+	}
+	// parsed returns new objects for each test case since parseSource mutates
+	// them.
+	parsed := func() []ast.FileObject {
+		csObj := fake.ClusterSelectorObject(core.Name("prod"))
+		csObj.Spec.Selector.MatchLabels = map[string]string{"environment": "prod"}
+		return []ast.FileObject{
+			fake.FileObject(csObj, "acme/cs.yaml"),
+			fake.Cluster(core.Name("declared"), core.Label("environment", "prod")),
+			fake.RoleAtPath("acme/prod.yaml", core.Name("prod"), core.Namespace("foo"),
+				core.Annotation(metadata.LegacyClusterSelectorAnnotationKey, "prod")),
+			fake.RoleAtPath("acme/us.yaml", core.Name("us"), core.Namespace("foo"),
+				core.Annotation(metadata.ClusterNameSelectorAnnotationKey, "us-cluster")),
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		clusterName string
+		liveObjs    []client.Object
+		wantNames   []string
+		wantErrCode string
+	}{
+		{
+			name:        "declared Cluster without live metadata",
+			clusterName: "declared",
+			wantNames:   []string{"prod"},
+		},
+		{
+			name:        "labels from the cluster metadata ConfigMap",
+			clusterName: "us-cluster",
+			liveObjs: []client.Object{
+				clusterMetadata(map[string]string{configsync.ClusterMetadataLabelsKey: "environment=prod,region=us"}),
+			},
+			wantNames: []string{"prod", "us"},
+		},
+		{
+			name:        "cluster metadata ConfigMap takes precedence over declared Cluster",
+			clusterName: "declared",
+			liveObjs: []client.Object{
+				clusterMetadata(map[string]string{configsync.ClusterMetadataLabelsKey: "environment=dev"}),
+			},
+		},
+		{
+			name: "cluster name from the cluster metadata ConfigMap",
+			liveObjs: []client.Object{
+				clusterMetadata(map[string]string{configsync.ClusterMetadataClusterNameKey: "us-cluster"}),
+			},
+			wantNames: []string{"us"},
+		},
+		{
+			name:        "labels from the live Cluster object",
+			clusterName: "us-cluster",
+			liveObjs: []client.Object{
+				fake.ClusterObject(core.Name("us-cluster"), core.Label("environment", "prod")),
+			},
+			wantNames: []string{"prod", "us"},
+		},
+		{
+			name:        "invalid labels in the cluster metadata ConfigMap",
+			clusterName: "us-cluster",
+			liveObjs: []client.Object{
+				clusterMetadata(map[string]string{configsync.ClusterMetadataLabelsKey: "environment"}),
+			},
+			wantErrCode: selectors.InvalidSelectorErrorCode,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			converter, err := openapitest.ValueConverterForTest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			parser := &root{
+				sourceFormat: filesystem.SourceFormatUnstructured,
+				opts: opts{
+					clusterName:        tc.clusterName,
+					parser:             &fakeParser{parse: parsed()},
+					syncName:           rootSyncName,
+					reconcilerName:     rootReconcilerName,
+					client:             syncertest.NewClient(t, core.Scheme, append(tc.liveObjs, fake.RootSyncObjectV1Beta1(rootSyncName))...),
+					discoveryInterface: syncertest.NewDiscoveryClient(kinds.Namespace(), kinds.Role()),
+					converter:          converter,
+					files: files{FileSource: FileSource{
+						SyncDir: cmpath.RelativeSlash("acme"),
+					}},
+					updater: updater{
+						scope:     declared.RootReconciler,
+						resources: &declared.Resources{},
+					},
+				},
+			}
+
+			objs, errs := parser.parseSource(context.Background(), sourceState{commit: "abc123"})
+			if tc.wantErrCode != "" {
+				if !status.HasBlockingErrors(errs) || errs.Errors()[0].Code() != tc.wantErrCode {
+					t.Fatalf("got parseSource() errors %v, want code %s", errs, tc.wantErrCode)
+				}
+				return
+			}
+			if errs != nil {
+				t.Fatalf("got parseSource() errors %v, want nil", errs)
+			}
+			var gotNames []string
+			for _, obj := range objs {
+				if obj.GetObjectKind().GroupVersionKind() == kinds.Role() {
+					gotNames = append(gotNames, obj.GetName())
+				}
+			}
+			if diff := cmp.Diff(tc.wantNames, gotNames); diff != "" {
+				t.Errorf("parseSource() diff (- want, + got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRoot_ParseLivePolicies(t *testing.T) {
+	validationPolicies := func(data map[string]string) *corev1.ConfigMap {
+		cm := fake.ConfigMapObject(core.Name(configsync.ValidationPoliciesName), core.Namespace(configsync.ControllerNamespace))
+		cm.Data = data
+		return cm
+	}
+
+	testCases := []struct {
+		name        string
+		liveObjs    []client.Object
+		wantErrCode string
+	}{
+		{
+			name: "no validation policies ConfigMap",
+		},
+		{
+			name: "complying objects",
+			liveObjs: []client.Object{
+				validationPolicies(map[string]string{"foo-only": "expression: object.metadata.namespace == 'foo'"}),
+			},
+		},
+		{
+			name: "violating objects",
+			liveObjs: []client.Object{
+				validationPolicies(map[string]string{"team-label": "match:\n  kinds: [Role]\nexpression: has(object.metadata.labels.team)"}),
+			},
+			wantErrCode: policy.ViolationErrorCode,
+		},
+		{
+			name: "invalid policy",
+			liveObjs: []client.Object{
+				validationPolicies(map[string]string{"invalid": "expression: object.("}),
+			},
+			wantErrCode: policy.InvalidPolicyErrorCode,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			converter, err := openapitest.ValueConverterForTest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			parser := &root{
+				sourceFormat: filesystem.SourceFormatUnstructured,
+				opts: opts{
+					parser:             &fakeParser{parse: []ast.FileObject{fake.RoleAtPath("acme/role.yaml", core.Namespace("foo"))}},
+					syncName:           rootSyncName,
+					reconcilerName:     rootReconcilerName,
+					client:             syncertest.NewClient(t, core.Scheme, append(tc.liveObjs, fake.RootSyncObjectV1Beta1(rootSyncName))...),
+					discoveryInterface: syncertest.NewDiscoveryClient(kinds.Namespace(), kinds.Role()),
+					converter:          converter,
+					files: files{FileSource: FileSource{
+						SyncDir: cmpath.RelativeSlash("acme"),
+					}},
+					updater: updater{
+						scope:     declared.RootReconciler,
+						resources: &declared.Resources{},
+					},
+				},
+			}
+
+			_, errs := parser.parseSource(context.Background(), sourceState{commit: "abc123"})
+			if tc.wantErrCode != "" {
+				if !status.HasBlockingErrors(errs) || errs.Errors()[0].Code() != tc.wantErrCode {
+					t.Fatalf("got parseSource() errors %v, want code %s", errs, tc.wantErrCode)
+				}
+				return
+			}
+			if errs != nil {
+				t.Fatalf("got parseSource() errors %v, want nil", errs)
+			}
+		})
+	}
+}
+
+// sourcesParser returns the objects of the source whose policy directory
+// matches the key.
+type sourcesParser struct {
+	fakeParser
+	parse map[string][]ast.FileObject
+}
+
+func (p *sourcesParser) Parse(filePaths reader.FilePaths) ([]ast.FileObject, status.MultiError) {
+	return p.parse[filePaths.PolicyDir.SlashPath()], nil
+}
+
 func fakeCRD(opts ...core.MetaMutator) ast.FileObject {
 	crd := fake.CustomResourceDefinitionV1Object(opts...)
 	crd.Spec.Group = "acme.com"
@@ -683,6 +1054,7 @@ func TestRoot_Parse_Discovery(t *testing.T) {
 					syncName:           rootSyncName,
 					reconcilerName:     rootReconcilerName,
 					client:             syncertest.NewClient(t, runtime.NewScheme(), fake.RootSyncObjectV1Beta1(rootSyncName)),
+					recorder:           &record.FakeRecorder{},
 					discoveryInterface: tc.discoveryClient,
 					converter:          converter,
 					updater: updater{
@@ -743,6 +1115,7 @@ func TestRoot_ParseErrorsMetricValidation(t *testing.T) {
 					syncName:           rootSyncName,
 					reconcilerName:     rootReconcilerName,
 					client:             syncertest.NewClient(t, core.Scheme, fake.RootSyncObjectV1Beta1(rootSyncName)),
+					recorder:           &record.FakeRecorder{},
 					discoveryInterface: syncertest.NewDiscoveryClient(kinds.Namespace(), kinds.Role()),
 					updater: updater{
 						scope:     declared.RootReconciler,
@@ -801,6 +1174,7 @@ func TestRoot_SourceReconcilerErrorsMetricValidation(t *testing.T) {
 					syncName:           rootSyncName,
 					reconcilerName:     rootReconcilerName,
 					client:             syncertest.NewClient(t, core.Scheme, fake.RootSyncObjectV1Beta1(rootSyncName)),
+					recorder:           &record.FakeRecorder{},
 					discoveryInterface: syncertest.NewDiscoveryClient(kinds.Namespace(), kinds.Role()),
 					updater: updater{
 						scope:     declared.RootReconciler,
@@ -874,6 +1248,7 @@ func TestRoot_SourceAndSyncReconcilerErrorsMetricValidation(t *testing.T) {
 					syncName:           rootSyncName,
 					reconcilerName:     rootReconcilerName,
 					client:             syncertest.NewClient(t, core.Scheme, fake.RootSyncObjectV1Beta1(rootSyncName)),
+					recorder:           &record.FakeRecorder{},
 					discoveryInterface: syncertest.NewDiscoveryClient(kinds.Namespace(), kinds.Role()),
 					mux:                &sync.Mutex{},
 				},
@@ -948,6 +1323,14 @@ func (a *fakeApplier) Errors() status.MultiError {
 	return errs
 }
 
+func (a *fakeApplier) UnhealthyErrors() status.MultiError {
+	return nil
+}
+
+func (a *fakeApplier) DryRunResult() *v1beta1.DryRunResult {
+	return nil
+}
+
 func (a *fakeApplier) Syncing() bool {
 	return false
 }
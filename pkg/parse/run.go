@@ -16,17 +16,23 @@ package parse
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/dependency"
 	"kpt.dev/configsync/pkg/hydrate"
 	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
 	"kpt.dev/configsync/pkg/metrics"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/validate/raw/validate"
 	webhookconfiguration "kpt.dev/configsync/pkg/webhook/configuration"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -37,6 +43,7 @@ const (
 	triggerRetry              = "retry"
 	triggerManagementConflict = "managementConflict"
 	triggerWatchUpdate        = "watchUpdate"
+	triggerNamespaceUpdate    = "namespaceUpdate"
 )
 
 const (
@@ -98,6 +105,32 @@ func Run(ctx context.Context, p Parser) {
 			retryTimer.Reset(opts.retryPeriod)               // Schedule retry attempt
 			statusUpdateTimer.Reset(opts.statusUpdatePeriod) // Schedule status update attempt
 
+		// Re-import declared resources immediately when the webhook endpoint
+		// receives a source change notification, instead of waiting for runTimer.
+		case <-opts.webhookTriggers:
+			klog.Infof("Source change notification received from the webhook")
+			run(ctx, p, triggerReimport, state)
+
+			runTimer.Reset(opts.pollingPeriod)               // Schedule re-run attempt
+			retryTimer.Reset(opts.retryPeriod)               // Schedule retry attempt
+			statusUpdateTimer.Reset(opts.statusUpdatePeriod) // Schedule status update attempt
+
+		// Parse and apply the source again when a change of the live
+		// Namespaces changes the Namespaces selected by the dynamic
+		// NamespaceSelectors.
+		case <-opts.namespaceTriggers:
+			if !namespaceSelectionChanged(ctx, opts) {
+				continue
+			}
+			klog.Infof("The live Namespaces selected by the dynamic NamespaceSelectors changed")
+			// Reset the cache to make sure the source is parsed again.
+			// The cached sourceState will not be reset to avoid reading all the source files unnecessarily.
+			state.resetAllButSourceState()
+			run(ctx, p, triggerNamespaceUpdate, state)
+
+			retryTimer.Reset(opts.retryPeriod)               // Schedule retry attempt
+			statusUpdateTimer.Reset(opts.statusUpdatePeriod) // Schedule status update attempt
+
 		// Retry if there was an error, conflict, or any watches need to be updated.
 		case <-retryTimer.C:
 			var trigger string
@@ -128,7 +161,10 @@ func Run(ctx context.Context, p Parser) {
 			// Skip sync status update if the .status.sync.commit is out of date.
 			// This avoids overwriting a newer Syncing condition with the status
 			// from an older commit.
-			if state.syncStatus.commit == state.sourceStatus.commit &&
+			// Also skip it while waiting for a dependency, to keep the
+			// WaitingForDependency reason of the Syncing condition.
+			if state.dependency == nil &&
+				state.syncStatus.commit == state.sourceStatus.commit &&
 				state.syncStatus.commit == state.renderingStatus.commit {
 
 				klog.V(3).Info("Updating sync status (periodic while not syncing)")
@@ -143,9 +179,23 @@ func Run(ctx context.Context, p Parser) {
 }
 
 func run(ctx context.Context, p Parser, trigger string, state *reconcilerState) {
+	// Neither sync new commits nor correct drift while suspended.
+	if suspended(ctx, p, state) {
+		return
+	}
+
+	// Neither parse nor apply the source until the dependencies have synced.
+	if waitingForDependency(ctx, p, state) {
+		return
+	}
+
 	var syncDir cmpath.Absolute
 	gs := sourceStatus{}
 	gs.commit, syncDir, gs.errs = hydrate.SourceCommitAndDir(p.options().SourceType, p.options().SourceDir, p.options().SyncDir, p.options().reconcilerName)
+	var sources []additionalSourceState
+	if gs.errs == nil {
+		sources, gs.errs = p.options().readSources(p.options().reconcilerName)
+	}
 
 	// If failed to fetch the source commit and directory, set `.status.source` to fail early.
 	// Otherwise, set `.status.rendering` before `.status.source` because the parser needs to
@@ -161,6 +211,7 @@ func run(ctx context.Context, p Parser, trigger string, state *reconcilerState)
 				state.syncingConditionLastUpdate = gs.lastUpdate
 			}
 		}
+		notifyFailure(ctx, p, gs.commit, gs.errs)
 		state.invalidate(status.Append(gs.errs, setSourceStatusErr))
 		return
 	}
@@ -197,23 +248,25 @@ func run(ctx context.Context, p Parser, trigger string, state *reconcilerState)
 			state.renderingStatus = rs
 			state.syncingConditionLastUpdate = rs.lastUpdate
 		}
+		notifyFailure(ctx, p, rs.commit, rs.errs)
 		state.invalidate(status.Append(rs.errs, setRenderingStatusErr))
 		return
 	}
 
 	// rendering is done, starts to read the source or hydrated configs.
-	oldSyncDir := state.cache.source.syncDir
+	oldSyncDir := state.cache.source.syncDirs()
 	// `read` is called no matter what the trigger is.
 	ps := sourceState{
 		commit:  gs.commit,
 		syncDir: syncDir,
+		sources: sources,
 	}
 	if errs := read(ctx, p, trigger, state, ps); errs != nil {
 		state.invalidate(errs)
 		return
 	}
 
-	newSyncDir := state.cache.source.syncDir
+	newSyncDir := state.cache.source.syncDirs()
 	// The parse-apply-watch sequence will be skipped if the trigger type is `triggerReimport` and
 	// there is no new source changes. The reasons are:
 	//   * If a former parse-apply-watch sequence for syncDir succeeded, there is no need to run the sequence again;
@@ -234,6 +287,85 @@ func run(ctx context.Context, p Parser, trigger string, state *reconcilerState)
 	state.checkpoint()
 }
 
+// suspended returns true if the reconciler is suspended by spec.suspend or
+// spec.syncWindows. The remediator is paused while the reconciler is suspended,
+// and the Suspended condition is updated whenever the suspension changes.
+func suspended(ctx context.Context, p Parser, state *reconcilerState) bool {
+	opts := p.options()
+	suspension := opts.syncWindows.State(time.Now())
+	if suspension.Suspended && !state.suspended {
+		klog.Infof("Suspending the reconciler: %s", suspension.Message)
+		opts.remediator.Pause()
+		state.suspended = true
+	} else if !suspension.Suspended && state.suspended {
+		klog.Info("Resuming the reconciler")
+		opts.remediator.Resume()
+		state.suspended = false
+	}
+
+	if state.suspension == nil || *state.suspension != suspension {
+		if err := p.setSuspendedCondition(ctx, suspension); err != nil {
+			klog.Warningf("failed to update the Suspended condition: %v", err)
+		} else {
+			state.suspension = &suspension
+		}
+	}
+	return suspension.Suspended
+}
+
+// waitingForDependency returns true if a RootSync or RepoSync listed in
+// spec.dependsOn has not synced its latest commit without errors yet. The
+// Syncing condition is updated whenever the dependency to wait for changes.
+// A cycle of dependencies is reported as a sync error, and retried until the
+// spec.dependsOn of the syncs in the cycle is fixed.
+func waitingForDependency(ctx context.Context, p Parser, state *reconcilerState) bool {
+	opts := p.options()
+	if len(opts.dependsOn) == 0 {
+		return false
+	}
+	self := v1beta1.Dependency{Kind: configsync.RootSyncKind, Name: opts.syncName}
+	if opts.scope != declared.RootReconciler {
+		self = v1beta1.Dependency{Kind: configsync.RepoSyncKind, Name: opts.syncName, Namespace: string(opts.scope)}
+	}
+	waiting, err := dependency.Check(ctx, opts.k8sClient(), self, opts.dependsOn)
+	var cycleErr *dependency.CycleError
+	if errors.As(err, &cycleErr) {
+		errs := validate.DependencyCycle(cycleErr)
+		if err := setSyncStatus(ctx, p, state, false, errs); err != nil {
+			klog.Warningf("failed to update sync status: %v", err)
+		}
+		state.dependency = nil
+		state.invalidate(errs)
+		return true
+	}
+	if err != nil {
+		state.invalidate(status.APIServerError(err, "failed to check the dependencies"))
+		return true
+	}
+	if !waiting.Waiting {
+		if state.dependency != nil {
+			klog.Info("The dependencies have synced their latest commit")
+			state.dependency = nil
+		}
+		return false
+	}
+
+	if state.dependency == nil || *state.dependency != waiting {
+		klog.Infof("Waiting for a dependency: %s", waiting.Message)
+		if err := p.setWaitingForDependencyCondition(ctx, waiting.Message); err != nil {
+			klog.Warningf("failed to update the Syncing condition: %v", err)
+			return true
+		}
+		state.dependency = &waiting
+		// Parse and apply the source again once the dependencies have synced,
+		// even without new commits, to replace the Syncing condition.
+		state.resetCache()
+		state.lastApplied = ""
+		state.syncStatus = syncStatus{}
+	}
+	return true
+}
+
 // read reads config files from source if no rendering is needed, or from hydrated output if rendering is done.
 // It also updates the .status.rendering and .status.source fields.
 func read(ctx context.Context, p Parser, trigger string, state *reconcilerState, sourceState sourceState) status.MultiError {
@@ -255,6 +387,7 @@ func read(ctx context.Context, p Parser, trigger string, state *reconcilerState,
 		state.renderingStatus = hydrationStatus
 		state.syncingConditionLastUpdate = hydrationStatus.lastUpdate
 	}
+	notifyFailure(ctx, p, hydrationStatus.commit, hydrationStatus.errs)
 	renderingErrs := status.Append(hydrationStatus.errs, setRenderingStatusErr)
 	if renderingErrs != nil {
 		return renderingErrs
@@ -266,6 +399,7 @@ func read(ctx context.Context, p Parser, trigger string, state *reconcilerState,
 
 	// Only call `setSourceStatus` if `readFromSource` fails.
 	// If `readFromSource` succeeds, `parse` may still fail.
+	notifyFailure(ctx, p, sourceStatus.commit, sourceStatus.errs)
 	sourceStatus.lastUpdate = metav1.Now()
 	var setSourceStatusErr error
 	if state.needToSetSourceStatus(sourceStatus) {
@@ -305,10 +439,17 @@ func readFromSource(ctx context.Context, p Parser, trigger string, state *reconc
 
 	var hydrationErr hydrate.HydrationError
 	if _, err := os.Stat(absHydratedRoot.OSPath()); err == nil {
+		// Only the source is rendered, the additional sources are read as is.
+		sources := sourceState.sources
 		sourceState, hydrationErr = opts.readHydratedDir(absHydratedRoot, opts.HydratedLink, opts.reconcilerName)
+		sourceState.sources = sources
 		if hydrationErr != nil {
 			hydrationStatus.message = RenderingFailed
-			hydrationStatus.errs = status.HydrationError(hydrationErr.Code(), hydrationErr)
+			if kustomizeErr, ok := hydrationErr.(hydrate.KustomizeError); ok {
+				hydrationStatus.errs = status.KustomizeHydrationError(kustomizeErr, kustomizeErr.SourcePath)
+			} else {
+				hydrationStatus.errs = status.HydrationError(hydrationErr.Code(), hydrationErr)
+			}
 			return hydrationStatus, sourceStatus
 		}
 		hydrationStatus.message = RenderingSucceeded
@@ -320,11 +461,11 @@ func readFromSource(ctx context.Context, p Parser, trigger string, state *reconc
 		hydrationStatus.message = RenderingSkipped
 	}
 
-	if sourceState.syncDir == state.cache.source.syncDir {
+	if sourceState.syncDirs() == state.cache.source.syncDirs() {
 		return hydrationStatus, sourceStatus
 	}
 
-	klog.Infof("New source changes (%s) detected, reset the cache", sourceState.syncDir.OSPath())
+	klog.Infof("New source changes (%s) detected, reset the cache", sourceState.syncDirs())
 
 	// Reset the cache to make sure all the steps of a parse-apply-watch loop will run.
 	state.resetCache()
@@ -349,7 +490,8 @@ func parseSource(ctx context.Context, p Parser, trigger string, state *reconcile
 	metrics.RecordParserDuration(ctx, trigger, "parse", metrics.StatusTagKey(sourceErrs), start)
 	state.cache.setParserResult(objs, sourceErrs)
 
-	if !status.HasBlockingErrors(sourceErrs) {
+	// A dry-run must not mutate the cluster, including the admission webhook.
+	if !status.HasBlockingErrors(sourceErrs) && !p.options().dryRun {
 		err := webhookconfiguration.Update(ctx, p.options().k8sClient(), p.options().discoveryClient(), objs)
 		if err != nil {
 			// Don't block if updating the admission webhook fails.
@@ -388,13 +530,22 @@ func parseAndUpdate(ctx context.Context, p Parser, trigger string, state *reconc
 	}
 
 	if status.HasBlockingErrors(sourceErrs) {
+		notifyFailure(ctx, p, newSourceStatus.commit, sourceErrs)
 		return sourceErrs
 	}
 
+	if state.syncStatus.commit != state.cache.source.commit {
+		p.recordEvent(ctx, corev1.EventTypeNormal, EventReasonSyncStarted, "Started syncing commit %s", state.cache.source.commit)
+	}
+
 	// Create a new context with its cancellation function.
 	ctxForUpdateSyncStatus, cancel := context.WithCancel(context.Background())
 
-	go updateSyncStatusPeriodically(ctxForUpdateSyncStatus, p, state)
+	periodicUpdatesDone := make(chan struct{})
+	go func() {
+		defer close(periodicUpdatesDone)
+		updateSyncStatusPeriodically(ctxForUpdateSyncStatus, p, state)
+	}()
 
 	klog.V(3).Info("Updater starting...")
 	start := time.Now()
@@ -402,8 +553,10 @@ func parseAndUpdate(ctx context.Context, p Parser, trigger string, state *reconc
 	metrics.RecordParserDuration(ctx, trigger, "update", metrics.StatusTagKey(syncErrs), start)
 	klog.V(3).Info("Updater stopped")
 
-	// This is to terminate `updateSyncStatusPeriodically`.
+	// This is to terminate `updateSyncStatusPeriodically`. Wait for it to
+	// return, so an in-flight periodic update cannot overwrite the final status.
 	cancel()
+	<-periodicUpdatesDone
 
 	klog.V(3).Info("Updating sync status (after sync)")
 	if err := setSyncStatus(ctx, p, state, false, syncErrs); err != nil {
@@ -413,6 +566,32 @@ func parseAndUpdate(ctx context.Context, p Parser, trigger string, state *reconc
 	return status.Append(sourceErrs, syncErrs)
 }
 
+// namespaceSelectionChanged returns true if the live Namespaces selected by
+// the dynamic NamespaceSelectors of the last parsed source changed.
+func namespaceSelectionChanged(ctx context.Context, opts *opts) bool {
+	if !opts.nsSelection.dynamic() {
+		return false
+	}
+	live, err := liveNamespaceLabels(ctx, opts.k8sClient())
+	if err != nil {
+		// Parse the source again, which reports the error.
+		klog.Warningf("Failed to evaluate the dynamic NamespaceSelectors: %v", err)
+		return true
+	}
+	return opts.nsSelection.changed(live)
+}
+
+// notifyFailure notifies the endpoints that the commit failed to be fetched,
+// rendered or parsed, unless there are no errors, or only transient errors
+// that are not surfaced in the status either.
+func notifyFailure(ctx context.Context, p Parser, commit string, errs status.MultiError) {
+	// A dry-run does not sync the commit, so there is nothing to notify.
+	if errs == nil || status.HasTransientErrors(errs) || p.options().dryRun {
+		return
+	}
+	p.options().notifier.Notify(ctx, commit, status.ToCSE(errs))
+}
+
 // setSyncStatus updates `.status.sync` and the Syncing condition, if needed,
 // as well as `state.syncStatus` and `state.syncingConditionLastUpdate` if
 // the update is successful.
@@ -421,15 +600,26 @@ func setSyncStatus(ctx context.Context, p Parser, state *reconcilerState, syncin
 	newSyncStatus := syncStatus{
 		syncing:    syncing,
 		commit:     state.cache.source.commit,
+		sources:    state.cache.source.sourceCommits(),
 		errs:       syncErrs,
+		drift:      p.options().remediator.Drift(),
 		lastUpdate: metav1.Now(),
 	}
+	if !syncing {
+		newSyncStatus.rolledBackTo, _ = p.options().rolledBackTo(newSyncStatus.commit)
+		newSyncStatus.healthyCommit = p.options().resources.HealthyCommit()
+		newSyncStatus.dryRun = state.cache.dryRun
+	}
 	if state.needToSetSyncStatus(newSyncStatus) {
 		if err := p.SetSyncStatus(ctx, newSyncStatus); err != nil {
 			return err
 		}
 		state.syncStatus = newSyncStatus
 		state.syncingConditionLastUpdate = newSyncStatus.lastUpdate
+		// A dry-run does not sync the commit, so there is nothing to notify.
+		if !syncing && !p.options().dryRun {
+			p.options().notifier.Notify(ctx, newSyncStatus.commit, status.ToCSE(syncErrs))
+		}
 	}
 
 	// Extract conflict errors from sync errors.
@@ -448,6 +638,20 @@ func setSyncStatus(ctx context.Context, p Parser, state *reconcilerState, syncin
 	return nil
 }
 
+// syncCompletedReason returns the reason and message of the Syncing condition
+// once the sync of newStatus.commit is done.
+func syncCompletedReason(newStatus syncStatus) (string, string) {
+	if newStatus.rolledBackTo != "" {
+		return ReasonRolledBack, fmt.Sprintf("Rolled back to commit %s because commit %s failed to sync",
+			newStatus.rolledBackTo, newStatus.commit)
+	}
+	if newStatus.dryRun != nil {
+		return ReasonDryRun, fmt.Sprintf("Dry-run completed: %d to create, %d to update, %d to prune",
+			len(newStatus.dryRun.Creates), len(newStatus.dryRun.Updates), len(newStatus.dryRun.Prunes))
+	}
+	return "Sync", "Sync Completed"
+}
+
 // updateSyncStatusPeriodically update the sync status periodically until the
 // cancellation function of the context is called.
 func updateSyncStatusPeriodically(ctx context.Context, p Parser, state *reconcilerState) {
@@ -17,15 +17,22 @@ package parse
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/dependency"
 	"kpt.dev/configsync/pkg/hydrate"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
 	"kpt.dev/configsync/pkg/importer/filesystem"
@@ -33,6 +40,7 @@ import (
 	"kpt.dev/configsync/pkg/importer/reader"
 	"kpt.dev/configsync/pkg/kinds"
 	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/notification"
 	"kpt.dev/configsync/pkg/rootsync"
 	"kpt.dev/configsync/pkg/status"
 	syncerFake "kpt.dev/configsync/pkg/syncer/syncertest/fake"
@@ -58,6 +66,7 @@ func newParser(t *testing.T, fs FileSource) Parser {
 		syncName:           rootSyncName,
 		reconcilerName:     rootReconcilerName,
 		client:             syncerFake.NewClient(t, core.Scheme, fake.RootSyncObjectV1Beta1(rootSyncName)),
+		recorder:           &record.FakeRecorder{},
 		discoveryInterface: syncerFake.NewDiscoveryClient(kinds.Namespace(), kinds.Role()),
 		converter:          converter,
 		files:              files{FileSource: fs},
@@ -170,6 +179,8 @@ func TestRun(t *testing.T) {
 		expectedStateRenderingErrs status.MultiError
 		expectedRSSourceErrs       []v1beta1.ConfigSyncError
 		expectedRSRenderingErrs    []v1beta1.ConfigSyncError
+		// expectedNotification is the type of the CloudEvent posted, if any.
+		expectedNotification string
 	}{
 		{
 			id:             "0",
@@ -190,6 +201,7 @@ func TestRun(t *testing.T) {
 			expectedRSSourceErrs:    status.ToCSE(status.SourceError.Sprint("error in the git-sync container: git sync permission issue").Build()),
 			expectedMsg:             "Source",
 			expectedErrorSourceRefs: []v1beta1.ErrorSource{v1beta1.SourceError},
+			expectedNotification:    notification.EventTypeFailed,
 		},
 		{
 			id:                "2",
@@ -213,15 +225,38 @@ func TestRun(t *testing.T) {
 			// rendering error is exposed to the RootSync status
 			expectedRSRenderingErrs: status.ToCSE(status.HydrationError(status.ActionableHydrationErrorCode, fmt.Errorf("rendering error"))),
 			expectedErrorSourceRefs: []v1beta1.ErrorSource{v1beta1.RenderingError},
+			expectedNotification:    notification.EventTypeFailed,
 		},
 		{
-			id:                "4",
-			name:              "successful read",
-			sourceRootExist:   true,
-			hydratedRootExist: true,
-			hydrationDone:     true,
-			needRetry:         false,
-			expectedMsg:       "Sync Completed",
+			id:                         "5",
+			name:                       "kustomization error",
+			sourceRootExist:            true,
+			hydratedRootExist:          true,
+			hydrationDone:              true,
+			hydratedError:              `{"code": "1068", "error": "rendering error", "SourcePath": "base/kustomization.yaml"}`,
+			needRetry:                  true,
+			expectedMsg:                "Rendering failed",
+			expectedErrors:             "1 error(s)\n\n\n[1] KNV1068: rendering error\n\npath: base/kustomization.yaml\n\nFor more information, see https://g.co/cloud/acm-errors#knv1068\n",
+			expectedStateRenderingErrs: status.KustomizeHydrationError(fmt.Errorf("rendering error"), "base/kustomization.yaml"),
+			// the failing kustomization is exposed in the RootSync status
+			expectedRSRenderingErrs: []v1beta1.ConfigSyncError{{
+				Code:         status.ActionableHydrationErrorCode,
+				ErrorMessage: "KNV1068: rendering error\n\npath: base/kustomization.yaml\n\nFor more information, see https://g.co/cloud/acm-errors#knv1068",
+				Hint:         status.Hint("1068"),
+				Resources:    []v1beta1.ResourceRef{{SourcePath: "base/kustomization.yaml"}},
+			}},
+			expectedErrorSourceRefs: []v1beta1.ErrorSource{v1beta1.RenderingError},
+			expectedNotification:    notification.EventTypeFailed,
+		},
+		{
+			id:                   "4",
+			name:                 "successful read",
+			sourceRootExist:      true,
+			hydratedRootExist:    true,
+			hydrationDone:        true,
+			needRetry:            false,
+			expectedMsg:          "Sync Completed",
+			expectedNotification: notification.EventTypeSynced,
 		},
 	}
 
@@ -271,10 +306,36 @@ func TestRun(t *testing.T) {
 				SourceRepo:   "https://github.com/test/test.git",
 				SourceBranch: "main",
 			}
+			notifications := make(chan string, 10)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				notifications <- req.Header.Get("ce-type")
+			}))
+			defer srv.Close()
+
 			parser := newParser(t, fs)
+			parser.options().notifier, err = notification.New(configsync.RootSyncKind, configsync.ControllerNamespace, rootSyncName,
+				[]v1beta1.Notification{{URL: srv.URL, Template: "{{.Subject}}"}}, "")
+			if err != nil {
+				t.Fatal(err)
+			}
 			state := &reconcilerState{}
 			run(context.Background(), parser, triggerReimport, state)
 
+			if tc.expectedNotification != "" {
+				select {
+				case got := <-notifications:
+					testutil.AssertEqual(t, tc.expectedNotification, got, "[%s] unexpected notification", tc.name)
+				case <-time.After(10 * time.Second):
+					t.Errorf("[%s] timed out waiting for the %s notification", tc.name, tc.expectedNotification)
+				}
+			} else {
+				select {
+				case got := <-notifications:
+					t.Errorf("[%s] unexpected %s notification", tc.name, got)
+				case <-time.After(100 * time.Millisecond):
+				}
+			}
+
 			testutil.AssertEqual(t, tc.needRetry, state.cache.needToRetry, "[%s] unexpected state.cache.needToRetry return", tc.name)
 			if state.cache.errs != nil {
 				testutil.AssertEqual(t, tc.expectedErrors, state.cache.errs.Error(), "[%s] unexpected state.cache.errs return", tc.name)
@@ -298,3 +359,38 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+func TestWaitingForDependency(t *testing.T) {
+	parser := newParser(t, FileSource{})
+	parser.options().dependsOn = []v1beta1.Dependency{{Kind: configsync.RootSyncKind, Name: "crds"}}
+	state := &reconcilerState{}
+	ctx := context.Background()
+
+	// The dependency does not exist yet.
+	if !waitingForDependency(ctx, parser, state) {
+		t.Fatal("waitingForDependency() got false, want true")
+	}
+	rs := &v1beta1.RootSync{}
+	if err := parser.options().client.Get(ctx, rootsync.ObjectKey(parser.options().syncName), rs); err != nil {
+		t.Fatal(err)
+	}
+	syncing := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncSyncing)
+	if syncing == nil || syncing.Reason != dependency.ReasonWaitingForDependency {
+		t.Fatalf("got Syncing condition %v, want reason %s", syncing, dependency.ReasonWaitingForDependency)
+	}
+	testutil.AssertEqual(t, "Waiting for RootSync config-management-system/crds to sync its latest commit without errors", syncing.Message)
+	testutil.AssertEqual(t, true, state.dependency != nil && state.dependency.Waiting, "unexpected state.dependency")
+
+	// The dependency has synced its latest commit.
+	crds := fake.RootSyncObjectV1Beta1("crds")
+	crds.Status.Source.Commit = "abc123"
+	crds.Status.LastSyncedCommit = "abc123"
+	rootsync.SetSyncing(crds, false, "Sync", "Sync Completed", "abc123", nil, &v1beta1.ErrorSummary{}, metav1.Now())
+	if err := parser.options().client.Create(ctx, crds); err != nil {
+		t.Fatal(err)
+	}
+	if waitingForDependency(ctx, parser, state) {
+		t.Fatal("waitingForDependency() got true, want false")
+	}
+	testutil.AssertEqual(t, (*dependency.State)(nil), state.dependency, "unexpected state.dependency")
+}
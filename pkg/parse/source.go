@@ -18,7 +18,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
@@ -51,6 +53,9 @@ type FileSource struct {
 	SourceBranch string
 	// SourceRev is the revision of the source repo to sync.
 	SourceRev string
+	// Sources lists the additional sources of a RootSync, each fetched into
+	// its own directory under RepoRoot.
+	Sources []reconcilermanager.Source
 }
 
 // files lists files in a repository and ensures the source repository hasn't been
@@ -71,6 +76,68 @@ type sourceState struct {
 	syncDir cmpath.Absolute
 	// files is the list of all observed files in the sync directory (recursively).
 	files []cmpath.Absolute
+	// sources is the state read from the additional sources of a RootSync.
+	sources []additionalSourceState
+}
+
+// syncDirs identifies the sync directories of the source and of its
+// additional sources, which change whenever any of them has a new commit.
+func (s sourceState) syncDirs() string {
+	dirs := []string{s.syncDir.OSPath()}
+	for _, src := range s.sources {
+		dirs = append(dirs, src.syncDir.OSPath())
+	}
+	return strings.Join(dirs, string(filepath.ListSeparator))
+}
+
+// sourceCommits returns the commit of each additional source.
+func (s sourceState) sourceCommits() []v1beta1.SourceCommit {
+	if len(s.sources) == 0 {
+		return nil
+	}
+	result := make([]v1beta1.SourceCommit, len(s.sources))
+	for i, src := range s.sources {
+		result[i] = v1beta1.SourceCommit{Name: src.source.Name, Commit: src.commit}
+	}
+	return result
+}
+
+// additionalSourceState contains all state read from an additional source of
+// a RootSync.
+type additionalSourceState struct {
+	// source is the additional source.
+	source reconcilermanager.Source
+	// commit is the commit read from the additional source.
+	commit string
+	// sourceDir is the path to the symbolic link of the additional source.
+	sourceDir cmpath.Absolute
+	// syncDir is the absolute path to the sync directory of the additional source.
+	syncDir cmpath.Absolute
+	// files is the list of all observed files in the sync directory (recursively).
+	files []cmpath.Absolute
+}
+
+// readSources returns the commit and the sync directory of each additional
+// source, fetched by its own sidecar container under the sources directory.
+func (o *files) readSources(reconcilerName string) ([]additionalSourceState, status.MultiError) {
+	var result []additionalSourceState
+	var errs status.MultiError
+	link := filepath.Base(o.SourceDir.OSPath())
+	for _, src := range o.Sources {
+		sourceDir := o.RepoRoot.Join(cmpath.RelativeSlash(path.Join(reconcilermanager.SourcesDir, src.Name, link)))
+		commit, syncDir, err := hydrate.SourceCommitAndDir(v1beta1.SourceType(src.SourceType), sourceDir, cmpath.RelativeOS(src.SyncDir), reconcilerName)
+		if err != nil {
+			errs = status.Append(errs, err)
+			continue
+		}
+		result = append(result, additionalSourceState{
+			source:    src,
+			commit:    commit,
+			sourceDir: sourceDir,
+			syncDir:   syncDir,
+		})
+	}
+	return result, errs
 }
 
 // readConfigFiles reads all the files under state.syncDir and sets state.files.
@@ -103,6 +170,30 @@ func (o *files) readConfigFiles(state *sourceState, p Parser) status.Error {
 		return status.TransientError(fmt.Errorf("source commit changed while listing files, was %s, now %s. It will be retried in the next sync", state.commit, newCommit))
 	}
 
+	for i := range state.sources {
+		src := &state.sources[i]
+		srcFiles, err := listFiles(src.syncDir, map[string]bool{".git": true})
+		if err != nil {
+			return status.PathWrapError(errors.Wrapf(err, "listing files in the configs directory of source %q", src.source.Name), src.syncDir.OSPath())
+		}
+		// Only the primary source is rendered, so a Kustomization in an
+		// additional source would be parsed as a plain object.
+		for _, f := range srcFiles {
+			if hydrate.HasKustomization(filepath.Base(f.OSPath())) {
+				return status.HydrationError(status.ActionableHydrationErrorCode,
+					fmt.Errorf("source %q has the Kustomization config file %s, but additional sources are not rendered. "+
+						"To fix, commit the rendered configs to the source, or move the source to its own RootSync", src.source.Name, f.OSPath()))
+			}
+		}
+		newCommit, err := hydrate.ComputeCommit(src.sourceDir)
+		if err != nil {
+			return status.TransientError(err)
+		} else if newCommit != src.commit {
+			return status.TransientError(fmt.Errorf("commit of source %q changed while listing files, was %s, now %s. It will be retried in the next sync", src.source.Name, src.commit, newCommit))
+		}
+		src.files = srcFiles
+	}
+
 	state.files = fileList
 	return nil
 }
@@ -186,6 +277,9 @@ func hydratedError(errorFile, label string) hydrate.HydrationError {
 		return hydrate.NewInternalError(err)
 	}
 	if payload.Code == status.ActionableHydrationErrorCode {
+		if payload.SourcePath != "" {
+			return hydrate.NewKustomizeError(errors.New(payload.Error), payload.SourcePath)
+		}
 		return hydrate.NewActionableError(errors.New(payload.Error))
 	}
 	return hydrate.NewInternalError(errors.New(payload.Error))
@@ -23,12 +23,14 @@ import (
 	"sync"
 	"testing"
 
+	"k8s.io/client-go/tools/record"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/importer/filesystem"
 	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
 	ft "kpt.dev/configsync/pkg/importer/filesystem/filesystemtest"
 	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/status"
 	syncertest "kpt.dev/configsync/pkg/syncer/syncertest/fake"
 	"kpt.dev/configsync/pkg/testing/fake"
@@ -111,6 +113,7 @@ func TestReadConfigFiles(t *testing.T) {
 					syncName:           rootSyncName,
 					reconcilerName:     rootReconcilerName,
 					client:             syncertest.NewClient(t, core.Scheme, fake.RootSyncObjectV1Beta1(rootSyncName)),
+					recorder:           &record.FakeRecorder{},
 					discoveryInterface: syncertest.NewDiscoveryClient(kinds.Namespace(), kinds.Role()),
 					updater: updater{
 						scope:     declared.RootReconciler,
@@ -128,3 +131,42 @@ func TestReadConfigFiles(t *testing.T) {
 		})
 	}
 }
+
+func TestReadConfigFiles_SourceKustomization(t *testing.T) {
+	tempRoot := t.TempDir()
+	commitDir := filepath.Join(tempRoot, originCommit)
+	if err := os.Mkdir(commitDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	symDir := filepath.Join(tempRoot, "rev")
+	if err := os.Symlink(commitDir, symDir); err != nil {
+		t.Fatal(err)
+	}
+	sourceSyncDir := filepath.Join(tempRoot, "sources", "shared")
+	if err := os.MkdirAll(sourceSyncDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(sourceSyncDir, "kustomization.yaml", "resources: []\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	state := &sourceState{
+		commit:  originCommit,
+		syncDir: cmpath.Absolute(commitDir),
+		sources: []additionalSourceState{{
+			source:    reconcilermanager.Source{Name: "shared"},
+			commit:    originCommit,
+			sourceDir: cmpath.Absolute(symDir),
+			syncDir:   cmpath.Absolute(sourceSyncDir),
+		}},
+	}
+	parser := &root{opts: opts{files: files{FileSource: FileSource{SourceDir: cmpath.Absolute(symDir)}}}}
+
+	err := parser.readConfigFiles(state, parser)
+	if err == nil {
+		t.Fatal("readConfigFiles() got no error, want an error for the Kustomization of the additional source")
+	}
+	if err.Code() != status.ActionableHydrationErrorCode {
+		t.Errorf("readConfigFiles() got error code %s, want %s", err.Code(), status.ActionableHydrationErrorCode)
+	}
+}
@@ -18,9 +18,13 @@ import (
 	"math"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/dependency"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/syncwindow"
 )
 
 const (
@@ -50,14 +54,29 @@ func (rs renderingStatus) equal(other renderingStatus) bool {
 }
 
 type syncStatus struct {
-	syncing    bool
-	commit     string
-	errs       status.MultiError
+	syncing bool
+	commit  string
+	// sources are the commits of the additional sources of a RootSync.
+	sources []v1beta1.SourceCommit
+	errs    status.MultiError
+	drift   []v1beta1.ResourceDrift
+	// rolledBackTo is the last healthy commit that was re-applied, if commit
+	// failed to sync and was rolled back.
+	rolledBackTo string
+	// healthyCommit is the last commit that was synced and became healthy
+	// while autoRollback is enabled, if any.
+	healthyCommit string
+	// dryRun is the result of the server-side dry-run of commit, if the
+	// reconciler runs a dry-run.
+	dryRun     *v1beta1.DryRunResult
 	lastUpdate metav1.Time
 }
 
 func (gs syncStatus) equal(other syncStatus) bool {
-	return gs.syncing == other.syncing && gs.commit == other.commit && status.DeepEqual(gs.errs, other.errs)
+	return gs.syncing == other.syncing && gs.commit == other.commit && status.DeepEqual(gs.errs, other.errs) &&
+		equality.Semantic.DeepEqual(gs.drift, other.drift) && gs.rolledBackTo == other.rolledBackTo &&
+		gs.healthyCommit == other.healthyCommit &&
+		equality.Semantic.DeepEqual(gs.dryRun, other.dryRun) && equality.Semantic.DeepEqual(gs.sources, other.sources)
 }
 
 type reconcilerState struct {
@@ -78,10 +97,22 @@ type reconcilerState struct {
 
 	// cache tracks the progress made by the reconciler for a source commit.
 	cache cacheForCommit
+
+	// suspended is true if the remediator is paused because the reconciler is
+	// suspended by spec.suspend or spec.syncWindows.
+	suspended bool
+
+	// suspension tracks the Suspended condition of the RepoSync/RootSync, or
+	// nil if it has not been updated yet.
+	suspension *syncwindow.State
+
+	// dependency tracks the dependency the reconciler waits for, as reported
+	// in the Syncing condition, or nil if it does not wait for any.
+	dependency *dependency.State
 }
 
 func (s *reconcilerState) checkpoint() {
-	applied := s.cache.source.syncDir.OSPath()
+	applied := s.cache.source.syncDirs()
 	if applied == s.lastApplied {
 		return
 	}
@@ -33,21 +33,68 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// ReasonRolledBack is the reason of the Syncing condition when a commit that
+// failed to sync was rolled back to the last healthy commit.
+const ReasonRolledBack = "RolledBack"
+
+// ReasonDryRun is the reason of the Syncing condition when a commit was applied
+// with a server-side dry-run, without mutating the cluster.
+const ReasonDryRun = "DryRun"
+
+const (
+	// rollbackAttempts is the number of failed attempts in a row to sync a
+	// commit after which autoRollback rolls it back.
+	rollbackAttempts = 3
+	// rollbackTimeout is how long a commit can keep failing to sync before
+	// autoRollback rolls it back, whatever the number of attempts.
+	rollbackTimeout = 5 * time.Minute
+)
+
 // updater mutates the most-recently-seen versions of objects stored in memory.
 type updater struct {
 	scope      declared.Scope
 	resources  *declared.Resources
 	remediator remediator.Interface
 	applier    applier.Applier
+	// autoRollback re-applies the last healthy commit when a new commit fails
+	// to apply or its objects do not become healthy.
+	autoRollback bool
+	// dryRun applies the objects with a server-side dry-run, so the remediator
+	// is not started.
+	dryRun bool
 
 	errorMux       sync.RWMutex
 	validationErrs status.MultiError
 	watchErrs      status.MultiError
+	// rollback is the last roll back, or nil if the current commit was not
+	// rolled back.
+	rollback *rollback
+	// failure tracks the failed attempts to sync the current commit, or is
+	// nil if the last attempt succeeded.
+	failure *failedSync
 
 	updateMux sync.RWMutex
 	updating  bool
 }
 
+// failedSync tracks the failed attempts in a row to sync a commit.
+type failedSync struct {
+	commit   string
+	attempts int
+	// since is the time of the first failed attempt.
+	since time.Time
+}
+
+// rollback records a commit that failed to sync and was rolled back.
+type rollback struct {
+	// failedCommit is the commit that failed to sync.
+	failedCommit string
+	// commit is the last healthy commit that was re-applied.
+	commit string
+	// errs are the errors of the failed commit.
+	errs status.MultiError
+}
+
 func (u *updater) needToUpdateWatch() bool {
 	return u.remediator.NeedsUpdate()
 }
@@ -68,9 +115,30 @@ func (u *updater) Errors() status.MultiError {
 	errs = status.Append(errs, u.validationErrs)
 	errs = status.Append(errs, u.applier.Errors())
 	errs = status.Append(errs, u.watchErrs)
+	if u.rollback != nil {
+		errs = status.Append(errs, u.rollback.errs)
+	}
 	return errs
 }
 
+// rolledBackTo returns the commit that was re-applied, if the given commit
+// failed to sync and was rolled back.
+// This method is safe to call while Update is running.
+func (u *updater) rolledBackTo(commit string) (string, bool) {
+	u.errorMux.RLock()
+	defer u.errorMux.RUnlock()
+	if u.rollback == nil || u.rollback.failedCommit != commit {
+		return "", false
+	}
+	return u.rollback.commit, true
+}
+
+func (u *updater) setRollback(r *rollback) {
+	u.errorMux.Lock()
+	defer u.errorMux.Unlock()
+	u.rollback = r
+}
+
 // conflictErrors converts []ManagementConflictError into []MultiErrors.
 // This method is safe to call while Update is running.
 func (u *updater) conflictErrors() status.MultiError {
@@ -134,6 +202,14 @@ func (u *updater) declaredCRDs() ([]*v1beta1.CustomResourceDefinition, status.Mu
 // 5. Updates the remediator watches
 // 6. Restarts the remediator
 //
+// If dryRun is enabled, the objects are applied with a server-side dry-run,
+// and the remediator watches are not updated, nor is the remediator restarted.
+//
+// If autoRollback is enabled and the objects keep failing to apply or to become
+// healthy, the last healthy commit is re-applied instead. See shouldRollBack.
+// The failed commit is not retried until the cache is reset by a resync, or the
+// source moves to a new commit.
+//
 // Any errors returned will be prepended with any known conflict errors from the
 // remediator. This is required to preserve errors that have been reported by
 // another reconciler.
@@ -158,6 +234,12 @@ func (u *updater) Update(ctx context.Context, cache *cacheForCommit) status.Mult
 // update performs most of the work for `Update`, making it easier to
 // consistently prepend the conflict errors.
 func (u *updater) update(ctx context.Context, cache *cacheForCommit) status.MultiError {
+	if cache.rolledBack {
+		// The commit was already rolled back.
+		return u.rollback.errs
+	}
+	u.setRollback(nil)
+
 	// Stop remediator workers.
 	// This prevents objects been updated in the wrong order (dependencies).
 	// Continue watching previously declared objects and updating the queue.
@@ -184,9 +266,22 @@ func (u *updater) update(ctx context.Context, cache *cacheForCommit) status.Mult
 	if !cache.applied {
 		declaredObjs, _ := u.resources.DeclaredObjects()
 		_, err := u.apply(ctx, declaredObjs, cache.source.commit)
+		if u.dryRun {
+			cache.dryRun = u.applier.DryRunResult()
+		}
+		if u.autoRollback {
+			err = status.Append(err, u.applier.UnhealthyErrors())
+		}
 		if err != nil {
+			if u.autoRollback && u.shouldRollBack(cache.source.commit) {
+				return u.rollBack(ctx, cache, err)
+			}
 			return err
 		}
+		u.failure = nil
+		if u.autoRollback && cache.parserErrs == nil {
+			u.resources.MarkHealthy()
+		}
 		// Only mark the commit as applied if there were no (non-blocking) parse errors.
 		// This ensures the apply will be retried until parsing fully succeeds.
 		if cache.parserErrs == nil {
@@ -194,6 +289,12 @@ func (u *updater) update(ctx context.Context, cache *cacheForCommit) status.Mult
 		}
 	}
 
+	// Nothing was applied by a dry-run, so the remediator stays paused, and
+	// does not revert the objects on the cluster to the declared objects.
+	if u.dryRun {
+		return nil
+	}
+
 	// Update the resource watches (triggers for the Remediator).
 	if !cache.watchesUpdated {
 		declaredGVKs, _ := u.resources.DeclaredGVKs()
@@ -218,6 +319,51 @@ func (u *updater) update(ctx context.Context, cache *cacheForCommit) status.Mult
 	return nil
 }
 
+// shouldRollBack records a failed attempt to sync the commit, and returns true
+// once the commit failed rollbackAttempts times in a row, or has been failing
+// for rollbackTimeout. Until then, the commit is retried with the usual retry
+// backoff. A commit that was rolled back is rolled back again as soon as it
+// fails on a later retry.
+func (u *updater) shouldRollBack(commit string) bool {
+	if u.failure == nil || u.failure.commit != commit {
+		u.failure = &failedSync{commit: commit, since: time.Now()}
+	}
+	u.failure.attempts++
+	return u.failure.attempts >= rollbackAttempts || time.Since(u.failure.since) >= rollbackTimeout
+}
+
+// rollBack re-applies the last healthy commit after the commit of the cache
+// failed to sync, and restarts the remediator. It returns the errors of the
+// failed commit, along with any error re-applying the last healthy commit.
+func (u *updater) rollBack(ctx context.Context, cache *cacheForCommit, errs status.MultiError) status.MultiError {
+	failedCommit := cache.source.commit
+	healthyCommit, found := u.resources.RollBack()
+	if !found {
+		klog.Warningf("Unable to roll back commit %s: no commit was synced successfully yet", failedCommit)
+		return errs
+	}
+	klog.Infof("Rolling back to commit %s because commit %s failed to sync", healthyCommit, failedCommit)
+	declaredObjs, _ := u.resources.DeclaredObjects()
+	// The roll back replaces the declared resources, so declare them again if
+	// the commit is retried.
+	cache.declaredResourcesUpdated = false
+	if _, err := u.apply(ctx, declaredObjs, healthyCommit); err != nil {
+		return status.Append(errs, err)
+	}
+	declaredGVKs, _ := u.resources.DeclaredGVKs()
+	if err := u.watch(ctx, declaredGVKs); err != nil {
+		return status.Append(errs, err)
+	}
+	u.remediator.Resume()
+	cache.rolledBack = true
+	u.setRollback(&rollback{
+		failedCommit: failedCommit,
+		commit:       healthyCommit,
+		errs:         errs,
+	})
+	return errs
+}
+
 func (u *updater) declare(ctx context.Context, objs []client.Object, commit string) ([]client.Object, status.MultiError) {
 	klog.V(1).Info("Declared resources updating...")
 	objs, err := u.resources.Update(ctx, objs, commit)
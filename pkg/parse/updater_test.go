@@ -0,0 +1,296 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/applier"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/testing/fake"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// healthCheckApplier is a fakeApplier that reports the objects named
+// unhealthyName as not healthy.
+type healthCheckApplier struct {
+	fakeApplier
+	unhealthyName string
+	unhealthy     status.MultiError
+	applied       [][]string
+}
+
+func (a *healthCheckApplier) Apply(ctx context.Context, objs []client.Object) (map[schema.GroupVersionKind]struct{}, status.MultiError) {
+	a.unhealthy = nil
+	var names []string
+	for _, obj := range objs {
+		names = append(names, obj.GetName())
+		if obj.GetName() == a.unhealthyName {
+			a.unhealthy = status.Append(a.unhealthy, applier.UnhealthyErrorForResource(actuation.ReconcileTimeout, core.IDOf(obj)))
+		}
+	}
+	a.applied = append(a.applied, names)
+	return a.fakeApplier.Apply(ctx, objs)
+}
+
+func (a *healthCheckApplier) UnhealthyErrors() status.MultiError {
+	return a.unhealthy
+}
+
+// dryRunApplier is a fakeApplier that reports every applied object as an
+// object to create.
+type dryRunApplier struct {
+	fakeApplier
+	result *v1beta1.DryRunResult
+}
+
+func (a *dryRunApplier) Apply(ctx context.Context, objs []client.Object) (map[schema.GroupVersionKind]struct{}, status.MultiError) {
+	a.result = &v1beta1.DryRunResult{}
+	for _, obj := range objs {
+		a.result.Creates = append(a.result.Creates, v1beta1.ResourceRef{Name: obj.GetName()})
+	}
+	return a.fakeApplier.Apply(ctx, objs)
+}
+
+func (a *dryRunApplier) DryRunResult() *v1beta1.DryRunResult {
+	return a.result
+}
+
+// resumeRecorder is a noOpRemediator that records whether it was resumed.
+type resumeRecorder struct {
+	noOpRemediator
+	resumed bool
+}
+
+func (r *resumeRecorder) Resume() {
+	r.resumed = true
+}
+
+func cacheFor(commit string, objs ...ast.FileObject) *cacheForCommit {
+	return &cacheForCommit{
+		source:      sourceState{commit: commit},
+		objsToApply: objs,
+	}
+}
+
+func TestUpdater_AutoRollback(t *testing.T) {
+	healthy := fake.ClusterRoleObject(core.Name("healthy"))
+	broken := fake.ClusterRoleObject(core.Name("broken"))
+
+	var brokenAttempts [][]string
+	for i := 0; i < rollbackAttempts; i++ {
+		brokenAttempts = append(brokenAttempts, []string{"broken"})
+	}
+
+	testCases := []struct {
+		name             string
+		autoRollback     bool
+		wantApplied      [][]string
+		wantRolledBackTo string
+		wantCommit       string
+	}{
+		{
+			name:         "autoRollback disabled",
+			autoRollback: false,
+			// Unhealthy objects are not errors, so the commit is not retried.
+			wantApplied: [][]string{{"healthy"}, {"broken"}},
+			wantCommit:  "2",
+		},
+		{
+			name:             "autoRollback enabled",
+			autoRollback:     true,
+			wantApplied:      append(append([][]string{{"healthy"}}, brokenAttempts...), []string{"healthy"}),
+			wantRolledBackTo: "1",
+			wantCommit:       "1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := &healthCheckApplier{unhealthyName: "broken"}
+			u := &updater{
+				scope:        declared.RootReconciler,
+				resources:    &declared.Resources{},
+				remediator:   &noOpRemediator{},
+				applier:      app,
+				autoRollback: tc.autoRollback,
+			}
+			if errs := u.Update(context.Background(), cacheFor("1", fake.FileObject(healthy, "healthy.yaml"))); errs != nil {
+				t.Fatalf("unexpected errors syncing the first commit: %v", errs)
+			}
+
+			brokenCache := cacheFor("2", fake.FileObject(broken, "broken.yaml"))
+			for i := 1; i < rollbackAttempts; i++ {
+				if errs := u.Update(context.Background(), brokenCache); tc.autoRollback && errs == nil {
+					t.Fatal("got no errors syncing the broken commit, want unhealthy errors")
+				}
+				if _, rolledBack := u.rolledBackTo("2"); rolledBack {
+					t.Fatalf("broken commit rolled back after %d attempts, want %d", i, rollbackAttempts)
+				}
+			}
+			if errs := u.Update(context.Background(), brokenCache); tc.autoRollback && errs == nil {
+				t.Fatal("got no errors syncing the broken commit, want unhealthy errors")
+			}
+			// Retry the broken commit once more.
+			u.Update(context.Background(), brokenCache)
+
+			if diff := cmp.Diff(tc.wantApplied, app.applied); diff != "" {
+				t.Errorf("unexpected applied objects (-want +got):\n%s", diff)
+			}
+			rolledBackTo, _ := u.rolledBackTo("2")
+			if rolledBackTo != tc.wantRolledBackTo {
+				t.Errorf("got rolledBackTo %q, want %q", rolledBackTo, tc.wantRolledBackTo)
+			}
+			if _, commit := u.resources.DeclaredObjects(); commit != tc.wantCommit {
+				t.Errorf("got declared commit %q, want %q", commit, tc.wantCommit)
+			}
+			if tc.autoRollback && u.Errors() == nil {
+				t.Error("got no updater errors after roll back, want the errors of the broken commit")
+			}
+		})
+	}
+}
+
+func TestUpdater_AutoRollbackTimeout(t *testing.T) {
+	healthy := fake.ClusterRoleObject(core.Name("healthy"))
+	broken := fake.ClusterRoleObject(core.Name("broken"))
+
+	app := &healthCheckApplier{unhealthyName: "broken"}
+	u := &updater{
+		scope:        declared.RootReconciler,
+		resources:    &declared.Resources{},
+		remediator:   &noOpRemediator{},
+		applier:      app,
+		autoRollback: true,
+	}
+	if errs := u.Update(context.Background(), cacheFor("1", fake.FileObject(healthy, "healthy.yaml"))); errs != nil {
+		t.Fatalf("unexpected errors syncing the first commit: %v", errs)
+	}
+	// The broken commit has been failing for longer than the timeout.
+	u.failure = &failedSync{commit: "2", attempts: 1, since: time.Now().Add(-rollbackTimeout)}
+	u.Update(context.Background(), cacheFor("2", fake.FileObject(broken, "broken.yaml")))
+
+	if rolledBackTo, _ := u.rolledBackTo("2"); rolledBackTo != "1" {
+		t.Errorf("got rolledBackTo %q, want %q", rolledBackTo, "1")
+	}
+}
+
+func TestUpdater_AutoRollbackResync(t *testing.T) {
+	healthy := fake.ClusterRoleObject(core.Name("healthy"))
+	broken := fake.ClusterRoleObject(core.Name("broken"))
+
+	app := &healthCheckApplier{unhealthyName: "broken"}
+	u := &updater{
+		scope:        declared.RootReconciler,
+		resources:    &declared.Resources{},
+		remediator:   &noOpRemediator{},
+		applier:      app,
+		autoRollback: true,
+	}
+	if errs := u.Update(context.Background(), cacheFor("1", fake.FileObject(healthy, "healthy.yaml"))); errs != nil {
+		t.Fatalf("unexpected errors syncing the first commit: %v", errs)
+	}
+	for i := 0; i < rollbackAttempts; i++ {
+		u.Update(context.Background(), cacheFor("2", fake.FileObject(broken, "broken.yaml")))
+	}
+	if _, rolledBack := u.rolledBackTo("2"); !rolledBack {
+		t.Fatal("broken commit was not rolled back")
+	}
+
+	// A resync resets the cache, and retries the commit. It is rolled back
+	// again at once if it still fails.
+	app.applied = nil
+	u.Update(context.Background(), cacheFor("2", fake.FileObject(broken, "broken.yaml")))
+	if diff := cmp.Diff([][]string{{"broken"}, {"healthy"}}, app.applied); diff != "" {
+		t.Errorf("unexpected applied objects on resync (-want +got):\n%s", diff)
+	}
+	if _, rolledBack := u.rolledBackTo("2"); !rolledBack {
+		t.Error("broken commit was not rolled back again on resync")
+	}
+
+	// The commit syncs once it becomes healthy.
+	app.unhealthyName = ""
+	app.applied = nil
+	if errs := u.Update(context.Background(), cacheFor("2", fake.FileObject(broken, "broken.yaml"))); errs != nil {
+		t.Fatalf("unexpected errors syncing the fixed commit: %v", errs)
+	}
+	if diff := cmp.Diff([][]string{{"broken"}}, app.applied); diff != "" {
+		t.Errorf("unexpected applied objects (-want +got):\n%s", diff)
+	}
+	if _, rolledBack := u.rolledBackTo("2"); rolledBack {
+		t.Error("fixed commit is still reported as rolled back")
+	}
+	if got := u.resources.HealthyCommit(); got != "2" {
+		t.Errorf("got healthy commit %q, want %q", got, "2")
+	}
+}
+
+func TestUpdater_DryRun(t *testing.T) {
+	obj := fake.ClusterRoleObject(core.Name("admin"))
+
+	testCases := []struct {
+		name        string
+		dryRun      bool
+		wantResult  *v1beta1.DryRunResult
+		wantResumed bool
+	}{
+		{
+			name:        "dryRun disabled",
+			dryRun:      false,
+			wantResumed: true,
+		},
+		{
+			name:   "dryRun enabled",
+			dryRun: true,
+			wantResult: &v1beta1.DryRunResult{
+				Creates: []v1beta1.ResourceRef{{Name: "admin"}},
+			},
+			wantResumed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rem := &resumeRecorder{}
+			u := &updater{
+				scope:      declared.RootReconciler,
+				resources:  &declared.Resources{},
+				remediator: rem,
+				applier:    &dryRunApplier{},
+				dryRun:     tc.dryRun,
+			}
+			cache := cacheFor("1", fake.FileObject(obj, "admin.yaml"))
+			if errs := u.Update(context.Background(), cache); errs != nil {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+
+			if diff := cmp.Diff(tc.wantResult, cache.dryRun); diff != "" {
+				t.Errorf("unexpected dry-run result (-want +got):\n%s", diff)
+			}
+			if rem.resumed != tc.wantResumed {
+				t.Errorf("got remediator resumed %v, want %v", rem.resumed, tc.wantResumed)
+			}
+		})
+	}
+}
@@ -153,6 +153,7 @@ func TestRepoSyncFinalize(t *testing.T) {
 							{
 								Code:         "2002",
 								ErrorMessage: "KNV2002: example message: APIServer error: destroy error\n\nFor more information, see https://g.co/cloud/acm-errors#knv2002",
+								Hint:         status.Hint("2002"),
 							},
 						},
 					},
@@ -186,6 +187,7 @@ func TestRepoSyncFinalize(t *testing.T) {
 							{
 								Code:         "2002",
 								ErrorMessage: "KNV2002: example message: APIServer error: destroy error\n\nFor more information, see https://g.co/cloud/acm-errors#knv2002",
+								Hint:         status.Hint("2002"),
 							},
 						},
 					},
@@ -213,6 +215,7 @@ func TestRepoSyncFinalize(t *testing.T) {
 							{
 								Code:         "2002",
 								ErrorMessage: "KNV2002: example message: APIServer error: destroy error\n\nFor more information, see https://g.co/cloud/acm-errors#knv2002",
+								Hint:         status.Hint("2002"),
 							},
 						},
 					},
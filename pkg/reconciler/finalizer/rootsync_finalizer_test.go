@@ -162,6 +162,7 @@ func TestRootSyncFinalize(t *testing.T) {
 							{
 								Code:         "2002",
 								ErrorMessage: "KNV2002: example message: APIServer error: destroy error\n\nFor more information, see https://g.co/cloud/acm-errors#knv2002",
+								Hint:         status.Hint("2002"),
 							},
 						},
 					},
@@ -195,6 +196,7 @@ func TestRootSyncFinalize(t *testing.T) {
 							{
 								Code:         "2002",
 								ErrorMessage: "KNV2002: example message: APIServer error: destroy error\n\nFor more information, see https://g.co/cloud/acm-errors#knv2002",
+								Hint:         status.Hint("2002"),
 							},
 						},
 					},
@@ -222,6 +224,7 @@ func TestRootSyncFinalize(t *testing.T) {
 							{
 								Code:         "2002",
 								ErrorMessage: "KNV2002: example message: APIServer error: destroy error\n\nFor more information, see https://g.co/cloud/acm-errors#knv2002",
+								Hint:         status.Hint("2002"),
 							},
 						},
 					},
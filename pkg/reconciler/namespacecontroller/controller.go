@@ -0,0 +1,76 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespacecontroller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Controller that watches the live Namespaces, and notifies the Parser when a
+// Namespace is created or deleted, or its labels change, so that dynamic
+// NamespaceSelectors are evaluated against the current Namespace labels. The
+// Parser only parses the source again if the selected Namespaces changed.
+type Controller struct {
+	triggers chan struct{}
+}
+
+// New returns a new Namespace Controller.
+func New() *Controller {
+	return &Controller{
+		// Buffer a single notification, so that the notifications received
+		// while the Parser is busy are coalesced.
+		triggers: make(chan struct{}, 1),
+	}
+}
+
+// Triggers returns the channel that receives a value whenever the live
+// Namespaces change.
+func (c *Controller) Triggers() <-chan struct{} {
+	return c.triggers
+}
+
+// SetupWithManager registers the Namespace Controller with the reconciler.
+func (c *Controller) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("Namespace").
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: 1,
+		}).
+		For(&corev1.Namespace{}, builder.WithPredicates(
+			// Only send update events when the labels change. Create and
+			// delete events are always sent.
+			predicate.LabelChangedPredicate{},
+		)).
+		Complete(c)
+}
+
+// Reconcile notifies the Parser that a Namespace changed.
+func (c *Controller) Reconcile(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+	select {
+	case c.triggers <- struct{}{}:
+		klog.V(3).Infof("Namespace %s changed", req.Name)
+	default:
+		// A notification is already pending.
+	}
+	return reconcile.Result{}, nil
+}
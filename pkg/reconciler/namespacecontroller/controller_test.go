@@ -0,0 +1,45 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespacecontroller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcile(t *testing.T) {
+	c := New()
+	for _, name := range []string{"tenant-a", "tenant-b"} {
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+		if _, err := c.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("Reconcile() got error %v, want nil", err)
+		}
+	}
+
+	// The notifications are coalesced while the Parser is busy.
+	select {
+	case <-c.Triggers():
+	default:
+		t.Fatal("got no notification, want one")
+	}
+	select {
+	case <-c.Triggers():
+		t.Fatal("got a second notification, want one")
+	default:
+	}
+}
@@ -16,12 +16,18 @@ package reconciler
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
+	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/applier"
 	"kpt.dev/configsync/pkg/client/restconfig"
@@ -30,14 +36,19 @@ import (
 	"kpt.dev/configsync/pkg/importer/filesystem"
 	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
 	"kpt.dev/configsync/pkg/importer/reader"
+	"kpt.dev/configsync/pkg/notification"
 	"kpt.dev/configsync/pkg/parse"
 	"kpt.dev/configsync/pkg/reconciler/finalizer"
+	"kpt.dev/configsync/pkg/reconciler/namespacecontroller"
+	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/remediator"
 	"kpt.dev/configsync/pkg/remediator/watch"
 	syncerclient "kpt.dev/configsync/pkg/syncer/client"
 	"kpt.dev/configsync/pkg/syncer/metrics"
 	"kpt.dev/configsync/pkg/syncer/reconcile"
 	"kpt.dev/configsync/pkg/syncer/reconcile/fight"
+	"kpt.dev/configsync/pkg/syncwindow"
+	"kpt.dev/configsync/pkg/trigger"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
@@ -106,6 +117,34 @@ type Options struct {
 	ReconcileTimeout string
 	// APIServerTimeout is the client-side timeout used for talking to the API server
 	APIServerTimeout string
+	// WebhookToken authenticates the source change notifications accepted by
+	// the webhook endpoint. The webhook endpoint is disabled if it is empty.
+	WebhookToken string
+	// Suspend suspends syncing, regardless of the sync windows.
+	Suspend bool
+	// SyncWindows allow or deny syncing on a schedule.
+	SyncWindows []v1beta1.SyncWindow
+	// DriftPolicy controls whether the remediator reverts drift, or only
+	// reports it in the sync status. The applier preserves the reported drift.
+	DriftPolicy configsync.DriftPolicy
+	// BreakGlassMaxDuration is the longest duration for which the remediator
+	// honors the break-glass exemption of a managed object.
+	BreakGlassMaxDuration time.Duration
+	// AutoRollback re-applies the last healthy commit when a new commit fails
+	// to apply or its objects do not become healthy.
+	AutoRollback bool
+	// DryRun applies and prunes the objects with a server-side dry-run, and
+	// reports the changes in the sync status without mutating the cluster.
+	DryRun bool
+	// DependsOn lists the RootSyncs and RepoSyncs that must sync their latest
+	// commit without errors before the source is parsed and applied.
+	DependsOn []v1beta1.Dependency
+	// Notifications lists the HTTP endpoints notified when a commit is synced,
+	// or fails to sync.
+	Notifications []v1beta1.Notification
+	// NotificationSecretsDir is the directory where the Secret referenced by
+	// the i-th notification is mounted, in the <i> subdirectory.
+	NotificationSecretsDir string
 	// RootOptions is the set of options to fill in if this is configuring the
 	// Root reconciler.
 	// Unset for Namespace repositories.
@@ -116,6 +155,9 @@ type Options struct {
 type RootOptions struct {
 	// SourceFormat is how the Root repository is structured.
 	SourceFormat filesystem.SourceFormat
+	// Sources lists the additional sources whose objects are merged with the
+	// objects of the primary source.
+	Sources []reconcilermanager.Source
 }
 
 // Run configures and starts the various components of a reconciler process.
@@ -178,7 +220,7 @@ func Run(opts Options) {
 	if err != nil {
 		klog.Fatalf("Error creating clients: %v", err)
 	}
-	supervisor, err := applier.NewSupervisor(clientSet, opts.ReconcilerScope, opts.SyncName, reconcileTimeout)
+	supervisor, err := applier.NewSupervisor(clientSet, opts.ReconcilerScope, opts.SyncName, reconcileTimeout, opts.DryRun, opts.DriftPolicy)
 	if err != nil {
 		klog.Fatalf("Error creating applier: %v", err)
 	}
@@ -194,33 +236,85 @@ func Run(opts Options) {
 		klog.Fatalf("Error creating rest config for the remediator: %v", err)
 	}
 
-	rem, err := remediator.New(opts.ReconcilerScope, opts.SyncName, cfgForWatch, baseApplier, decls, opts.NumWorkers)
+	rem, err := remediator.New(opts.ReconcilerScope, opts.SyncName, cfgForWatch, baseApplier, decls, opts.NumWorkers, opts.DriftPolicy, opts.BreakGlassMaxDuration)
 	if err != nil {
 		klog.Fatalf("Instantiating Remediator: %v", err)
 	}
 
+	// Configure the webhook endpoint, which triggers the Parser and the
+	// hydration-controller when the source changes.
+	var webhookServer *trigger.Server
+	var webhookTriggers <-chan struct{}
+	if opts.WebhookToken != "" {
+		webhookServer = trigger.NewServer(opts.WebhookToken, trigger.LocalURL(reconcilermanager.HydrationWebhookPort))
+		webhookTriggers = webhookServer.Triggers()
+	}
+
+	syncWindows, err := syncwindow.New(opts.Suspend, opts.SyncWindows)
+	if err != nil {
+		klog.Fatalf("Invalid sync windows: %v", err)
+	}
+
+	notifier, err := newNotifier(opts.ReconcilerScope, opts.SyncName, opts.Notifications, opts.NotificationSecretsDir)
+	if err != nil {
+		klog.Fatalf("Invalid notifications: %v", err)
+	}
+
+	recorder, err := newEventRecorder(cfg, opts.ReconcilerName)
+	if err != nil {
+		klog.Fatalf("Error creating event recorder: %v", err)
+	}
+
+	// Nothing is applied by a dry-run, so there is nothing to roll back.
+	autoRollback := opts.AutoRollback && !opts.DryRun
+
 	// Configure the Parser.
 	var parser parse.Parser
-	fs := parse.FileSource{
-		SourceDir:    opts.SourceRoot,
-		RepoRoot:     opts.RepoRoot,
-		HydratedRoot: opts.HydratedRoot,
-		HydratedLink: opts.HydratedLink,
-		SyncDir:      opts.SyncDir,
-		SourceType:   opts.SourceType,
-		SourceRepo:   opts.SourceRepo,
-		SourceBranch: opts.SourceBranch,
-		SourceRev:    opts.SourceRev,
+	runnerOpts := parse.RunnerOptions{
+		ClusterName:        opts.ClusterName,
+		SyncName:           opts.SyncName,
+		ReconcilerName:     opts.ReconcilerName,
+		FileReader:         &reader.File{},
+		Client:             cl,
+		Recorder:           recorder,
+		PollingPeriod:      opts.PollingPeriod,
+		ResyncPeriod:       opts.ResyncPeriod,
+		RetryPeriod:        opts.RetryPeriod,
+		StatusUpdatePeriod: opts.StatusUpdatePeriod,
+		WebhookTriggers:    webhookTriggers,
+		SyncWindows:        syncWindows,
+		DependsOn:          opts.DependsOn,
+		Notifier:           notifier,
+		Files: parse.FileSource{
+			SourceDir:    opts.SourceRoot,
+			RepoRoot:     opts.RepoRoot,
+			HydratedRoot: opts.HydratedRoot,
+			HydratedLink: opts.HydratedLink,
+			SyncDir:      opts.SyncDir,
+			SourceType:   opts.SourceType,
+			SourceRepo:   opts.SourceRepo,
+			SourceBranch: opts.SourceBranch,
+			SourceRev:    opts.SourceRev,
+		},
+		DiscoveryClient: discoveryClient,
+		Resources:       decls,
+		Applier:         supervisor,
+		Remediator:      rem,
+		AutoRollback:    autoRollback,
+		DryRun:          opts.DryRun,
 	}
+	// Only the root reconciler watches the live Namespaces, for the dynamic
+	// NamespaceSelectors.
+	var namespaceController *namespacecontroller.Controller
 	if opts.ReconcilerScope == declared.RootReconciler {
-		parser, err = parse.NewRootRunner(opts.ClusterName, opts.SyncName, opts.ReconcilerName, opts.SourceFormat, &reader.File{}, cl,
-			opts.PollingPeriod, opts.ResyncPeriod, opts.RetryPeriod, opts.StatusUpdatePeriod, fs, discoveryClient, decls, supervisor, rem)
+		namespaceController = namespacecontroller.New()
+		runnerOpts.Files.Sources = opts.Sources
+		parser, err = parse.NewRootRunner(runnerOpts, opts.SourceFormat, namespaceController.Triggers())
 		if err != nil {
 			klog.Fatalf("Instantiating Root Repository Parser: %v", err)
 		}
 	} else {
-		parser, err = parse.NewNamespaceRunner(opts.ClusterName, opts.SyncName, opts.ReconcilerName, opts.ReconcilerScope, &reader.File{}, cl,
-			opts.PollingPeriod, opts.ResyncPeriod, opts.RetryPeriod, opts.StatusUpdatePeriod, fs, discoveryClient, decls, supervisor, rem)
+		parser, err = parse.NewNamespaceRunner(runnerOpts, opts.ReconcilerScope)
 		if err != nil {
 			klog.Fatalf("Instantiating Namespace Repository Parser: %v", err)
 		}
@@ -281,6 +375,12 @@ func Run(opts Options) {
 		klog.Fatalf("Instantiating Finalizer: %v", err)
 	}
 
+	if namespaceController != nil {
+		if err := namespaceController.SetupWithManager(mgr); err != nil {
+			klog.Fatalf("Instantiating Namespace Controller: %v", err)
+		}
+	}
+
 	klog.Info("Starting ControllerManager")
 	// TODO: Once everything is using the controller-manager, move mgr.Start to the top level.
 	doneChanForManager := make(chan struct{})
@@ -300,6 +400,17 @@ func Run(opts Options) {
 		}
 	}()
 
+	if webhookServer != nil {
+		klog.Info("Starting webhook server")
+		go func() {
+			addr := fmt.Sprintf(":%d", reconcilermanager.ReconcilerWebhookPort)
+			if err := webhookServer.Run(ctx, addr); err != nil {
+				// The Parser keeps polling for source changes, so don't exit.
+				klog.Errorf("Webhook server exited: %v", err)
+			}
+		}()
+	}
+
 	klog.Info("Starting Remediator")
 	// TODO: Convert the Remediator to use the controller-manager framework.
 	doneChanForRemediator := rem.Start(ctx) // non-blocking
@@ -324,3 +435,26 @@ func Run(opts Options) {
 	<-signalCtx.Done()
 	klog.Info("All controllers exited")
 }
+
+// newEventRecorder returns an EventRecorder which emits Events as component.
+// The default correlator of the broadcaster aggregates similar Events, and
+// rate-limits the Events of each object to a burst of 25 and then one every 5
+// minutes, so that retry loops don't flood the API server.
+func newEventRecorder(cfg *rest.Config, component string) (record.EventRecorder, error) {
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+	return broadcaster.NewRecorder(core.Scheme, corev1.EventSource{Component: component}), nil
+}
+
+// newNotifier returns the Notifier of the RootSync or RepoSync reconciled in
+// scope.
+func newNotifier(scope declared.Scope, syncName string, notifications []v1beta1.Notification, secretsDir string) (*notification.Notifier, error) {
+	if scope == declared.RootReconciler {
+		return notification.New(configsync.RootSyncKind, configsync.ControllerNamespace, syncName, notifications, secretsDir)
+	}
+	return notification.New(configsync.RepoSyncKind, string(scope), syncName, notifications, secretsDir)
+}
@@ -58,6 +58,10 @@ const (
 	// HelmSync is the name of the helm-sync container in reconciler pods.
 	HelmSync = "helm-sync"
 
+	// AskpassSidecar is the name of the askpass-sidecar container in reconciler
+	// pods, which serves the credentials minted for git-sync.
+	AskpassSidecar = "askpass-sidecar"
+
 	// HydrationController is the name of the hydration-controller container in reconciler pods.
 	HydrationController = "hydration-controller"
 
@@ -103,6 +107,24 @@ const (
 	HydrationPollingPeriod = "HYDRATION_POLLING_PERIOD"
 )
 
+const (
+	// KustomizeEnableHelm is the OS env variable key for whether
+	// `kustomize build` renders the helmCharts field of a kustomization.
+	KustomizeEnableHelm = "KUSTOMIZE_ENABLE_HELM"
+
+	// KustomizeEnableAlphaPlugins is the OS env variable key for whether
+	// `kustomize build` enables the alpha plugins.
+	KustomizeEnableAlphaPlugins = "KUSTOMIZE_ENABLE_ALPHA_PLUGINS"
+
+	// KustomizeLoadRestrictor is the OS env variable key for the load
+	// restrictor of `kustomize build`.
+	KustomizeLoadRestrictor = "KUSTOMIZE_LOAD_RESTRICTOR"
+
+	// KustomizeHelmCommand is the OS env variable key for the Helm binary used
+	// by `kustomize build`.
+	KustomizeHelmCommand = "KUSTOMIZE_HELM_COMMAND"
+)
+
 const (
 	// OciSyncImage is the OS env variable key for the OCI image URL.
 	OciSyncImage = "OCI_SYNC_IMAGE"
@@ -112,6 +134,10 @@ const (
 
 	// OciSyncWait is the OS env variable key for the OCI sync wait period in seconds.
 	OciSyncWait = "OCI_SYNC_WAIT"
+
+	// OciSyncVerificationProvider is the OS env variable key for the tool used
+	// to sign the OCI image.
+	OciSyncVerificationProvider = "OCI_SYNC_VERIFICATION_PROVIDER"
 )
 
 const (
@@ -133,8 +159,9 @@ const (
 	// HelmValues is the OS env variable key for the Helm chart values.
 	HelmValues = "HELM_VALUES"
 
-	//HelmValuesFiles is the OS env variable key for Helm values files.
-	HelmValuesFiles = "HELM_VALUES_FILES"
+	// HelmValuesSources is the OS env variable key for the JSON-encoded,
+	// ordered list of Helm chart values, which are merged before HelmValues.
+	HelmValuesSources = "HELM_VALUES_SOURCES"
 
 	//HelmIncludeCRDs is the OS env variable key for whether to include CRDs in helm rendering output.
 	HelmIncludeCRDs = "HELM_INCLUDE_CRDS"
@@ -144,4 +171,91 @@ const (
 
 	// HelmSyncWait is the OS env variable key for the Helm sync wait period in seconds.
 	HelmSyncWait = "HELM_SYNC_WAIT"
+
+	// HelmSyncVerificationProvider is the OS env variable key for the tool used
+	// to sign the Helm chart.
+	HelmSyncVerificationProvider = "HELM_SYNC_VERIFICATION_PROVIDER"
+)
+
+const (
+	// WebhookToken is the OS env variable key for the token that authenticates
+	// source change notifications sent to the reconciler webhook endpoint.
+	// The webhook endpoint is disabled if the token is empty.
+	WebhookToken = "WEBHOOK_TOKEN"
+
+	// ReconcilerWebhookPort is the port on which the reconciler container
+	// serves the webhook endpoint.
+	ReconcilerWebhookPort = 8676
+
+	// HydrationWebhookPort is the port on which the hydration-controller
+	// container serves the webhook endpoint. It only listens on the loopback
+	// interface, and receives the notifications forwarded by the reconciler.
+	HydrationWebhookPort = 8677
+
+	// SourceWebhookPort is the port on which the oci-sync and helm-sync
+	// containers serve the webhook endpoint. It only listens on the loopback
+	// interface, and receives the notifications forwarded by the
+	// hydration-controller.
+	SourceWebhookPort = 8678
+)
+
+const (
+	// Suspend is the OS env variable key for whether syncing is suspended.
+	Suspend = "SUSPEND"
+
+	// SyncWindows is the OS env variable key for the JSON encoded sync windows
+	// that allow or deny syncing.
+	SyncWindows = "SYNC_WINDOWS"
+
+	// DriftPolicy is the OS env variable key for whether the remediator reverts
+	// or only reports drift.
+	DriftPolicy = "DRIFT_POLICY"
+
+	// AutoRollback is the OS env variable key for whether the reconciler
+	// re-applies the last healthy commit when a new commit fails to sync.
+	AutoRollback = "AUTO_ROLLBACK"
+
+	// DryRun is the OS env variable key for whether the reconciler applies and
+	// prunes the objects with a server-side dry-run.
+	DryRun = "DRY_RUN"
+
+	// DependsOn is the OS env variable key for the JSON encoded RootSyncs and
+	// RepoSyncs that must sync their latest commit before the reconciler
+	// parses and applies the source.
+	DependsOn = "DEPENDS_ON"
+
+	// Notifications is the OS env variable key for the JSON encoded HTTP
+	// endpoints notified when a commit is synced, or fails to sync.
+	Notifications = "NOTIFICATIONS"
+
+	// NotificationSecretsDir is the OS env variable key for the directory
+	// where the Secrets referenced by the notifications are mounted.
+	NotificationSecretsDir = "NOTIFICATION_SECRETS_DIR"
+
+	// Sources is the OS env variable key for the JSON encoded additional
+	// sources of a RootSync, merged with the objects of the primary source.
+	Sources = "SOURCES"
+
+	// PrometheusPort is the OS env variable key for the port on which the
+	// reconciler serves its metrics in the Prometheus exposition format. The
+	// metrics are not served if it is unset or 0.
+	PrometheusPort = "PROMETHEUS_PORT"
+)
+
+const (
+	// AskpassAuth is the OS env variable key for the auth type of the
+	// askpass-sidecar container.
+	AskpassAuth = "ASKPASS_AUTH"
+
+	// TokenExchangeURL is the OS env variable key for the token endpoint that
+	// exchanges the Kubernetes Service Account token for credentials.
+	TokenExchangeURL = "TOKEN_EXCHANGE_URL"
+
+	// TokenExchangeUsername is the OS env variable key for the username that
+	// goes with the exchanged token.
+	TokenExchangeUsername = "TOKEN_EXCHANGE_USERNAME"
+
+	// AskpassSidecarPort is the port on which the askpass-sidecar container
+	// serves the credentials. It only listens on the loopback interface.
+	AskpassSidecarPort = 9103
 )
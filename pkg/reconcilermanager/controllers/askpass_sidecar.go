@@ -0,0 +1,114 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/credentials"
+	"kpt.dev/configsync/pkg/reconcilermanager"
+)
+
+const (
+	// k8sSATokenVolumeName is the name of the volume of the projected
+	// Kubernetes Service Account token, which is exchanged for credentials when
+	// the auth type is k8sserviceaccount.
+	k8sSATokenVolumeName = "k8s-sa-token"
+	// k8sSATokenExpirationSeconds is the requested duration of validity of the
+	// projected token. The kubelet rotates the token before it expires.
+	k8sSATokenExpirationSeconds = int64(3600)
+)
+
+var askpassSidecarURL = fmt.Sprintf("http://localhost:%v/git_askpass", reconcilermanager.AskpassSidecarPort)
+
+// usesAskpassSidecar returns true if git-sync gets its credentials from the
+// askpass-sidecar container.
+func usesAskpassSidecar(sourceType string, auth configsync.AuthType) bool {
+	return v1beta1.SourceType(sourceType) == v1beta1.GitSource &&
+		(auth == configsync.AuthGitHubApp || auth == configsync.AuthK8sServiceAccount)
+}
+
+// askpassSidecarEnvs returns the environment variables for the askpass-sidecar
+// container.
+func askpassSidecarEnvs(auth configsync.AuthType, tokenExchange *v1beta1.TokenExchange) []corev1.EnvVar {
+	result := []corev1.EnvVar{{
+		Name:  reconcilermanager.AskpassAuth,
+		Value: string(auth),
+	}}
+	if auth == configsync.AuthK8sServiceAccount {
+		result = append(result, tokenExchangeEnvs(tokenExchange)...)
+	}
+	return result
+}
+
+// tokenExchangeEnvs returns the environment variables that configure the
+// exchange of the Kubernetes Service Account token for credentials.
+func tokenExchangeEnvs(tokenExchange *v1beta1.TokenExchange) []corev1.EnvVar {
+	if tokenExchange == nil {
+		return nil
+	}
+	result := []corev1.EnvVar{{
+		Name:  reconcilermanager.TokenExchangeURL,
+		Value: tokenExchange.URL,
+	}}
+	if tokenExchange.Username != "" {
+		result = append(result, corev1.EnvVar{
+			Name:  reconcilermanager.TokenExchangeUsername,
+			Value: tokenExchange.Username,
+		})
+	}
+	return result
+}
+
+// k8sSATokenVolume returns the volume of the projected Kubernetes Service
+// Account token, with the audience the token endpoint expects.
+func k8sSATokenVolume(tokenExchange *v1beta1.TokenExchange) corev1.Volume {
+	var audience string
+	if tokenExchange != nil {
+		audience = tokenExchange.Audience
+		if audience == "" {
+			audience = tokenExchange.URL
+		}
+	}
+	expirationSeconds := k8sSATokenExpirationSeconds
+	return corev1.Volume{
+		Name: k8sSATokenVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+						Audience:          audience,
+						ExpirationSeconds: &expirationSeconds,
+						Path:              credentials.ServiceAccountTokenFile,
+					},
+				}},
+				DefaultMode: &defaultMode,
+			},
+		},
+	}
+}
+
+// k8sSATokenVolumeMount returns the mount of the projected Kubernetes Service
+// Account token.
+func k8sSATokenVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      k8sSATokenVolumeName,
+		ReadOnly:  true,
+		MountPath: credentials.ServiceAccountTokenDir,
+	}
+}
@@ -32,6 +32,21 @@ func RepoSyncPermissionsName() string {
 	return fmt.Sprintf("%s:%s", configsync.GroupName, core.NsReconcilerPrefix)
 }
 
+// RepoSyncConfigPermissionsName returns the name of the namespace reconciler
+// permissions in the config-management-system namespace.
+// e.g. configsync.gke.io:ns-reconciler-config
+func RepoSyncConfigPermissionsName() string {
+	return RepoSyncPermissionsName() + "-config"
+}
+
+// RepoSyncDependencyPermissionsName returns the name of the permissions of a
+// namespace reconciler on the syncs listed in the spec.dependsOn field of its
+// RepoSync.
+// e.g. configsync.gke.io:ns-reconciler-bookinfo-dependencies
+func RepoSyncDependencyPermissionsName(reconcilerName string) string {
+	return fmt.Sprintf("%s:%s-dependencies", configsync.GroupName, reconcilerName)
+}
+
 // RootSyncPermissionsName returns root reconciler permissions name.
 // e.g. configsync.gke.io:root-reconciler
 func RootSyncPermissionsName() string {
@@ -0,0 +1,127 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/reposync"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// userConfigMapNames returns the names of the ConfigMaps in the RepoSync
+// namespace that are copied to the config-management-system namespace: the
+// Helm values files and the keys trusted to verify the source signature.
+func userConfigMapNames(rs *v1beta1.RepoSync) []string {
+	var names []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, ref := range helmValuesFileRefs(rs) {
+		add(ref.Name)
+	}
+	verification := sourceVerification(rs.Spec.SourceType, rs.Spec.Oci, reposync.GetHelmBase(rs.Spec.Helm))
+	for _, name := range verificationKeyNames(verification, kinds.ConfigMap().Kind) {
+		add(name)
+	}
+	return names
+}
+
+// isUpsertedConfigMap returns true if the provided ConfigMap from the
+// config-management-system namespace was upserted by the Reconciler.
+func isUpsertedConfigMap(rs *v1beta1.RepoSync, configMapName string) bool {
+	reconcilerName := core.NsReconcilerName(rs.GetNamespace(), rs.GetName())
+	for _, name := range userConfigMapNames(rs) {
+		if configMapName == ReconcilerResourceName(reconcilerName, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// upsertConfigMaps creates or updates the ConfigMaps in the
+// config-management-system namespace using the referenced ConfigMaps in the
+// RepoSync namespace, and deletes the copies that are no longer referenced.
+func upsertConfigMaps(ctx context.Context, log logr.Logger, rs *v1beta1.RepoSync, c client.Client, reconcilerRef types.NamespacedName) (client.ObjectKey, error) {
+	rsRef := client.ObjectKeyFromObject(rs)
+	desired := map[string]bool{}
+	for _, name := range userConfigMapNames(rs) {
+		nsCMRef := client.ObjectKey{Namespace: rsRef.Namespace, Name: name}
+		cmsCMRef := client.ObjectKey{Namespace: reconcilerRef.Namespace, Name: ReconcilerResourceName(reconcilerRef.Name, name)}
+		desired[cmsCMRef.Name] = true
+
+		userCM := &corev1.ConfigMap{}
+		if err := c.Get(ctx, nsCMRef, userCM); err != nil {
+			return cmsCMRef, errors.Wrapf(err, "user ConfigMap %s get failed", nsCMRef)
+		}
+		cmsCM := &corev1.ConfigMap{}
+		cmsCM.Name = cmsCMRef.Name
+		cmsCM.Namespace = cmsCMRef.Namespace
+		op, err := controllerruntime.CreateOrUpdate(ctx, c, cmsCM, func() error {
+			core.SetLabel(cmsCM, metadata.SyncNamespaceLabel, rsRef.Namespace)
+			core.SetLabel(cmsCM, metadata.SyncNameLabel, rsRef.Name)
+			cmsCM.Data = userCM.Data
+			cmsCM.BinaryData = userCM.BinaryData
+			return nil
+		})
+		if err != nil {
+			return cmsCMRef, err
+		}
+		if op != controllerutil.OperationResultNone {
+			log.Info("Managed object upsert successful",
+				logFieldObject, cmsCMRef.String(),
+				logFieldKind, "ConfigMap",
+				logFieldOperation, op)
+		}
+	}
+
+	// Delete the copies of ConfigMaps that are no longer referenced.
+	cmList := &corev1.ConfigMapList{}
+	if err := c.List(ctx, cmList, client.InNamespace(reconcilerRef.Namespace), client.MatchingLabels{
+		metadata.SyncNamespaceLabel: rsRef.Namespace,
+		metadata.SyncNameLabel:      rsRef.Name,
+	}); err != nil {
+		return client.ObjectKey{}, errors.Wrap(err, "failed to list copied ConfigMaps")
+	}
+	for _, cm := range cmList.Items {
+		if desired[cm.Name] || !strings.HasPrefix(cm.Name, reconcilerRef.Name+"-") {
+			continue
+		}
+		cmRef := client.ObjectKeyFromObject(&cm)
+		if err := c.Delete(ctx, &cm); err != nil && !apierrors.IsNotFound(err) {
+			return cmRef, err
+		}
+		log.Info("Managed object delete successful",
+			logFieldObject, cmRef.String(),
+			logFieldKind, kinds.ConfigMap().Kind)
+	}
+	return client.ObjectKey{}, nil
+}
@@ -14,6 +14,8 @@
 
 package controllers
 
+import "kpt.dev/configsync/pkg/credentials"
+
 const (
 	// GCPSAAnnotationKey is used to annotate RepoSync/RootSync controller SA when
 	// spec.git.auth: gcpserviceaccount is used with Workload Identity enabled on a
@@ -32,6 +34,12 @@ const (
 	GitSecretConfigKeyToken = "token"
 	// GitSecretConfigKeyTokenUsername is the key at which a token's username is stored
 	GitSecretConfigKeyTokenUsername = "username"
+	// GitSecretConfigKeyGitHubAppID is the key at which the ID of a GitHub App is stored
+	GitSecretConfigKeyGitHubAppID = credentials.GitHubAppIDKey
+	// GitSecretConfigKeyGitHubAppInstallationID is the key at which the installation ID of a GitHub App is stored
+	GitSecretConfigKeyGitHubAppInstallationID = credentials.GitHubAppInstallationIDKey
+	// GitSecretConfigKeyGitHubAppPrivateKey is the key at which the private key of a GitHub App is stored
+	GitSecretConfigKeyGitHubAppPrivateKey = credentials.GitHubAppPrivateKeyKey
 )
 
 // Helm secret data key names
@@ -41,3 +49,8 @@ const (
 	// HelmSecretKeyUsername is the key at which a token's username is stored
 	HelmSecretKeyUsername = "username"
 )
+
+// EventReasonStalled is the reason of the Warning Event emitted on a RootSync or
+// RepoSync when the reconciler-manager sets its Stalled condition, e.g. because
+// its spec is invalid.
+const EventReasonStalled = "Stalled"
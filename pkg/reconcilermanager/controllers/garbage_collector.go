@@ -25,8 +25,10 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -57,6 +59,9 @@ func (r *RepoSyncReconciler) cleanupNSControllerResources(ctx context.Context, r
 	if err := r.deleteConfigMaps(ctx, reconcilerRef); err != nil {
 		return err
 	}
+	if err := r.deleteCopiedConfigMaps(ctx, reconcilerRef, rsKey); err != nil {
+		return err
+	}
 	// serviceaccount
 	if err := r.deleteServiceAccount(ctx, reconcilerRef); err != nil {
 		return err
@@ -65,6 +70,14 @@ func (r *RepoSyncReconciler) cleanupNSControllerResources(ctx context.Context, r
 	if err := r.deleteRoleBinding(ctx, reconcilerRef, rsKey); err != nil {
 		return err
 	}
+	// dependency roles and rolebindings
+	if _, err := r.deleteDependencyRoles(ctx, reconcilerRef, rsKey, nil); err != nil {
+		return err
+	}
+	// service
+	if err := r.deleteWebhookService(ctx, reconcilerRef); err != nil {
+		return err
+	}
 	// secret
 	if err := r.deleteSecrets(ctx, reconcilerRef); err != nil {
 		return err
@@ -125,12 +138,49 @@ func (r *RepoSyncReconciler) deleteConfigMaps(ctx context.Context, reconcilerRef
 	return nil
 }
 
+// deleteCopiedConfigMaps deletes the user ConfigMaps copied to the
+// config-management-system namespace for the RepoSync.
+func (r *RepoSyncReconciler) deleteCopiedConfigMaps(ctx context.Context, reconcilerRef, rsKey types.NamespacedName) error {
+	cmList := &corev1.ConfigMapList{}
+	if err := r.client.List(ctx, cmList, client.InNamespace(reconcilerRef.Namespace), client.MatchingLabels{
+		metadata.SyncNamespaceLabel: rsKey.Namespace,
+		metadata.SyncNameLabel:      rsKey.Name,
+	}); err != nil {
+		return err
+	}
+	for _, cm := range cmList.Items {
+		if strings.HasPrefix(cm.Name, reconcilerRef.Name+"-") {
+			if err := r.cleanup(ctx, client.ObjectKeyFromObject(&cm), kinds.ConfigMap()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (r *RepoSyncReconciler) deleteServiceAccount(ctx context.Context, reconcilerRef types.NamespacedName) error {
 	return r.cleanup(ctx, reconcilerRef, kinds.ServiceAccount())
 }
 
 func (r *RepoSyncReconciler) deleteRoleBinding(ctx context.Context, reconcilerRef, rsRef types.NamespacedName) error {
 	rbKey := client.ObjectKey{Namespace: rsRef.Namespace, Name: RepoSyncPermissionsName()}
+	if err := r.deleteSharedRoleBindingSubject(ctx, reconcilerRef, rbKey); err != nil {
+		return err
+	}
+	configRBKey := client.ObjectKey{Namespace: configsync.ControllerNamespace, Name: RepoSyncConfigPermissionsName()}
+	err := r.deleteSharedRoleBindingSubject(ctx, reconcilerRef, configRBKey)
+	if apierrors.IsNotFound(errors.Cause(err)) {
+		// The reconcilers created by an older version have no RoleBinding in
+		// the config-management-system namespace.
+		return nil
+	}
+	return err
+}
+
+// deleteSharedRoleBindingSubject removes the reconciler from the subjects of
+// the RoleBinding shared by the namespace reconcilers, and deletes the
+// RoleBinding if the reconciler was the last subject.
+func (r *RepoSyncReconciler) deleteSharedRoleBindingSubject(ctx context.Context, reconcilerRef types.NamespacedName, rbKey client.ObjectKey) error {
 	rb := &rbacv1.RoleBinding{}
 	if err := r.client.Get(ctx, rbKey, rb); err != nil {
 		return errors.Wrapf(err, "failed to get the RoleBinding object %s", rbKey)
@@ -143,6 +193,43 @@ func (r *RepoSyncReconciler) deleteRoleBinding(ctx context.Context, reconcilerRe
 	return r.client.Update(ctx, rb)
 }
 
+// deleteDependencyRoles deletes the Roles and RoleBindings that allow the
+// reconciler to get the syncs it depends on, except in the namespaces to keep.
+func (r *RepoSyncReconciler) deleteDependencyRoles(ctx context.Context, reconcilerRef, rsKey types.NamespacedName, keep map[string][]rbacv1.PolicyRule) (client.ObjectKey, error) {
+	name := RepoSyncDependencyPermissionsName(reconcilerRef.Name)
+	labels := client.MatchingLabels{
+		metadata.SyncNamespaceLabel: rsKey.Namespace,
+		metadata.SyncNameLabel:      rsKey.Name,
+	}
+	rbList := &rbacv1.RoleBindingList{}
+	if err := r.client.List(ctx, rbList, labels); err != nil {
+		return client.ObjectKey{}, errors.Wrap(err, "failed to list the dependency RoleBindings")
+	}
+	for _, rb := range rbList.Items {
+		rbKey := client.ObjectKeyFromObject(&rb)
+		if rb.Name != name || keep[rb.Namespace] != nil {
+			continue
+		}
+		if err := r.cleanup(ctx, rbKey, kinds.RoleBinding()); err != nil {
+			return rbKey, err
+		}
+	}
+	roleList := &rbacv1.RoleList{}
+	if err := r.client.List(ctx, roleList, labels); err != nil {
+		return client.ObjectKey{}, errors.Wrap(err, "failed to list the dependency Roles")
+	}
+	for _, role := range roleList.Items {
+		roleKey := client.ObjectKeyFromObject(&role)
+		if role.Name != name || keep[role.Namespace] != nil {
+			continue
+		}
+		if err := r.cleanup(ctx, roleKey, kinds.Role()); err != nil {
+			return roleKey, err
+		}
+	}
+	return client.ObjectKey{}, nil
+}
+
 func (r *RepoSyncReconciler) deleteDeployment(ctx context.Context, reconcilerRef types.NamespacedName) error {
 	return r.cleanup(ctx, reconcilerRef, kinds.Deployment())
 }
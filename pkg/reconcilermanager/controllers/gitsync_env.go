@@ -204,6 +204,11 @@ func gitSyncEnvs(_ context.Context, opts options) []corev1.EnvVar {
 			Name:  "GIT_ASKPASS_URL",
 			Value: gceNodeAskpassURL,
 		})
+	case configsync.AuthGitHubApp, configsync.AuthK8sServiceAccount:
+		result = append(result, corev1.EnvVar{
+			Name:  "GIT_ASKPASS_URL",
+			Value: askpassSidecarURL,
+		})
 	case configsync.AuthSSH:
 		result = append(result, corev1.EnvVar{
 			Name:  "GIT_SYNC_SSH",
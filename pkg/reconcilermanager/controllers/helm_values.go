@@ -0,0 +1,144 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/helm"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// helmValuesFileName returns the name of the values file of the i-th values
+// source in the helm-values volume.
+func helmValuesFileName(i int, ref v1beta1.ValuesFileRef) string {
+	return fmt.Sprintf("%d-%s", i, ref.GetDataKey())
+}
+
+// helmValuesSources returns the ordered list of values passed to the helm-sync
+// container, in the order of the values sources. The ConfigMaps are read from
+// the files projected into the helm-values volume. The values field is passed
+// separately, and merged last.
+func helmValuesSources(helmBase *v1beta1.HelmBase) []helm.ValuesSource {
+	var result []helm.ValuesSource
+	for i, src := range helmBase.ValuesSources {
+		switch {
+		case src.ChartFile != "":
+			result = append(result, helm.ValuesSource{File: src.ChartFile})
+		case src.ConfigMapRef != nil:
+			result = append(result, helm.ValuesSource{File: filepath.Join(HelmValuesPath, helmValuesFileName(i, *src.ConfigMapRef))})
+		case src.Inline != nil:
+			result = append(result, helm.ValuesSource{Inline: string(src.Inline.Raw)})
+		}
+	}
+	return result
+}
+
+// helmValuesSourcesEnv returns the JSON encoding of the values passed to the
+// helm-sync container.
+func helmValuesSourcesEnv(helmBase *v1beta1.HelmBase) string {
+	sources := helmValuesSources(helmBase)
+	if len(sources) == 0 {
+		return ""
+	}
+	// Marshaling a list of structs of strings can't fail.
+	out, _ := json.Marshal(sources)
+	return string(out)
+}
+
+// helmValuesVolume returns the volume that projects the values files of the
+// ConfigMaps referenced by the values sources. configMapName maps the name of
+// a referenced ConfigMap to the name of the ConfigMap in the
+// config-management-system namespace.
+func helmValuesVolume(valuesSources []v1beta1.ValuesSource, configMapName func(string) string) corev1.Volume {
+	var sources []corev1.VolumeProjection
+	for i, src := range valuesSources {
+		if src.ConfigMapRef == nil {
+			continue
+		}
+		ref := *src.ConfigMapRef
+		sources = append(sources, corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: configMapName(ref.Name),
+				},
+				Items: []corev1.KeyToPath{
+					{
+						Key:  ref.GetDataKey(),
+						Path: helmValuesFileName(i, ref),
+					},
+				},
+			},
+		})
+	}
+	return corev1.Volume{
+		Name: HelmValuesVolume,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources:     sources,
+				DefaultMode: &defaultMode,
+			},
+		},
+	}
+}
+
+// helmValuesVolumeMount returns the VolumeMount of the helm-values volume.
+func helmValuesVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      HelmValuesVolume,
+		MountPath: HelmValuesPath,
+		ReadOnly:  true,
+	}
+}
+
+// validateHelmValuesConfigMaps verifies that the referenced ConfigMaps exist
+// in the given namespace and contain the values files.
+func validateHelmValuesConfigMaps(ctx context.Context, refs []v1beta1.ValuesFileRef, namespace string, c client.Client) error {
+	for _, ref := range refs {
+		cmRef := client.ObjectKey{
+			Name:      ref.Name,
+			Namespace: namespace,
+		}
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, cmRef, cm); err != nil {
+			if apierrors.IsNotFound(err) {
+				return errors.Errorf(
+					"ConfigMap %s not found: create one to provide the helm values file", cmRef)
+			}
+			return errors.Wrapf(err,
+				"ConfigMap %s get failed", cmRef)
+		}
+		if _, ok := cm.Data[ref.GetDataKey()]; !ok {
+			return fmt.Errorf("helm values file key %s is not present in %v ConfigMap", ref.GetDataKey(), cm.Name)
+		}
+	}
+	return nil
+}
+
+// helmValuesFileRefs returns the ConfigMaps referenced by the RepoSync, if
+// the source type is helm.
+func helmValuesFileRefs(rs *v1beta1.RepoSync) []v1beta1.ValuesFileRef {
+	if v1beta1.SourceType(rs.Spec.SourceType) != v1beta1.HelmSource || rs.Spec.Helm == nil {
+		return nil
+	}
+	return rs.Spec.Helm.GetValuesFileRefs()
+}
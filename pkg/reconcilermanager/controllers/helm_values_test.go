@@ -0,0 +1,62 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/helm"
+)
+
+func TestHelmValuesSources(t *testing.T) {
+	helmBase := &v1beta1.HelmBase{
+		Values: &apiextensionsv1.JSON{Raw: []byte(`{"replicas":5}`)},
+		ValuesSources: []v1beta1.ValuesSource{
+			{Inline: &apiextensionsv1.JSON{Raw: []byte(`{"replicas":3}`)}},
+			{ConfigMapRef: &v1beta1.ValuesFileRef{Name: "platform-values"}},
+			{ChartFile: "values-prod.yaml"},
+			{ConfigMapRef: &v1beta1.ValuesFileRef{Name: "app-values", DataKey: "prod.yaml"}},
+		},
+	}
+	want := []helm.ValuesSource{
+		{Inline: `{"replicas":3}`},
+		{File: HelmValuesPath + "/1-values.yaml"},
+		{File: "values-prod.yaml"},
+		{File: HelmValuesPath + "/3-prod.yaml"},
+	}
+	if diff := cmp.Diff(want, helmValuesSources(helmBase)); diff != "" {
+		t.Errorf("helmValuesSources() diff (- want, + got):\n%s", diff)
+	}
+
+	// The projected files must match the files passed to helm-sync.
+	volume := helmValuesVolume(helmBase.ValuesSources, func(name string) string { return name })
+	var gotPaths []string
+	for _, src := range volume.Projected.Sources {
+		for _, item := range src.ConfigMap.Items {
+			gotPaths = append(gotPaths, HelmValuesPath+"/"+item.Path)
+		}
+	}
+	wantPaths := []string{want[1].File, want[3].File}
+	if diff := cmp.Diff(wantPaths, gotPaths); diff != "" {
+		t.Errorf("helmValuesVolume() diff (- want, + got):\n%s", diff)
+	}
+
+	if got := helmValuesSourcesEnv(&v1beta1.HelmBase{}); got != "" {
+		t.Errorf("helmValuesSourcesEnv() = %q, want empty without values sources", got)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// notificationSecretsVolumeName returns the name of the volume of the Secret
+// referenced by the i-th notification.
+func notificationSecretsVolumeName(i int) string {
+	return fmt.Sprintf("%s-%d", NotificationSecretsVolume, i)
+}
+
+// notificationSecretsVolumes returns a volume for each notification that
+// references a Secret. secretName maps the name of a referenced Secret to the
+// name of the Secret in the config-management-system namespace.
+func notificationSecretsVolumes(notifications []v1beta1.Notification, secretName func(string) string) []corev1.Volume {
+	var volumes []corev1.Volume
+	for i, n := range notifications {
+		if n.SecretRef == nil {
+			continue
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: notificationSecretsVolumeName(i),
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  secretName(n.SecretRef.Name),
+					DefaultMode: &defaultMode,
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+// notificationSecretsVolumeMounts returns the VolumeMounts of the Secrets
+// referenced by the notifications, in the NotificationSecretsPath/<i>
+// directory for the i-th notification.
+func notificationSecretsVolumeMounts(notifications []v1beta1.Notification) []corev1.VolumeMount {
+	var mounts []corev1.VolumeMount
+	for i, n := range notifications {
+		if n.SecretRef == nil {
+			continue
+		}
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      notificationSecretsVolumeName(i),
+			MountPath: filepath.Join(NotificationSecretsPath, strconv.Itoa(i)),
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+// notificationSecretNames returns the names of the Secrets referenced by the
+// notifications.
+func notificationSecretNames(notifications []v1beta1.Notification) []string {
+	var names []string
+	for _, n := range notifications {
+		if n.SecretRef != nil && n.SecretRef.Name != "" {
+			names = append(names, n.SecretRef.Name)
+		}
+	}
+	return names
+}
+
+// validateNotificationSecrets verifies that the Secrets referenced by the
+// notifications exist in the given namespace.
+func validateNotificationSecrets(ctx context.Context, notifications []v1beta1.Notification, namespace string, c client.Client) error {
+	for _, name := range notificationSecretNames(notifications) {
+		secretRef := client.ObjectKey{Name: name, Namespace: namespace}
+		if err := c.Get(ctx, secretRef, &corev1.Secret{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				return errors.Errorf("Secret %s not found: create one to provide the url or the headers of the notification", secretRef)
+			}
+			return errors.Wrapf(err, "Secret %s get failed", secretRef)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+func TestNotificationSecretsVolumes(t *testing.T) {
+	notifications := []v1beta1.Notification{
+		{URL: "https://example.com/hook"},
+		{SecretRef: &v1beta1.SecretReference{Name: "slack"}},
+	}
+	gotVolumes := notificationSecretsVolumes(notifications, func(name string) string {
+		return ReconcilerResourceName(nsReconcilerName, name)
+	})
+	wantVolumes := []corev1.Volume{{
+		Name: "notification-secrets-1",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  ReconcilerResourceName(nsReconcilerName, "slack"),
+				DefaultMode: &defaultMode,
+			},
+		},
+	}}
+	if diff := cmp.Diff(wantVolumes, gotVolumes); diff != "" {
+		t.Errorf("notificationSecretsVolumes() got diff (-want +got):\n%s", diff)
+	}
+	gotMounts := notificationSecretsVolumeMounts(notifications)
+	wantMounts := []corev1.VolumeMount{{
+		Name:      "notification-secrets-1",
+		MountPath: "/etc/notification-secrets/1",
+		ReadOnly:  true,
+	}}
+	if diff := cmp.Diff(wantMounts, gotMounts); diff != "" {
+		t.Errorf("notificationSecretsVolumeMounts() got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestUpsertNotificationSecrets(t *testing.T) {
+	rs := fake.RepoSyncObjectV1Beta1(reposyncNs, reposyncName)
+	rs.Spec.Notifications = []v1beta1.Notification{{SecretRef: &v1beta1.SecretReference{Name: "slack"}}}
+	userSecret := fake.SecretObject("slack", core.Namespace(reposyncNs))
+	userSecret.Data = map[string][]byte{"url": []byte("https://hooks.slack.com/services/T000/B000/XXXX")}
+	c := fakeClient(t, userSecret)
+
+	ctx := context.Background()
+	if _, err := upsertNotificationSecrets(ctx, logr.Discard(), rs, c, nsReconcilerKey); err != nil {
+		t.Fatalf("upsertNotificationSecrets() got error: %v", err)
+	}
+	got := &corev1.Secret{}
+	key := nsReconcilerKey
+	key.Name = ReconcilerResourceName(nsReconcilerName, "slack")
+	if err := c.Get(ctx, key, got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(userSecret.Data, got.Data); diff != "" {
+		t.Errorf("upserted Secret got diff (-want +got):\n%s", diff)
+	}
+	if !isUpsertedSecret(rs, key.Name) {
+		t.Errorf("isUpsertedSecret(%q) got false, want true", key.Name)
+	}
+
+	rs.Spec.Notifications[0].SecretRef.Name = "missing"
+	if _, err := upsertNotificationSecrets(ctx, logr.Discard(), rs, c, nsReconcilerKey); err == nil {
+		t.Error("upsertNotificationSecrets() got no error for a missing Secret")
+	}
+}
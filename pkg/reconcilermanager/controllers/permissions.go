@@ -18,6 +18,8 @@ import (
 	"sort"
 
 	rbacv1 "k8s.io/api/rbac/v1"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 )
 
 // rolereference returns an intialized Role with apigroup, kind and name.
@@ -97,3 +99,55 @@ func sortSubjects(subjects ...rbacv1.Subject) []rbacv1.Subject {
 	}
 	return subjects
 }
+
+// dependencyRules returns the rules, by namespace, that allow the reconciler of
+// a RepoSync in namespace to get the syncs it depends on. The reconciler can
+// already get the RepoSyncs in its own namespace, so they need no rule.
+func dependencyRules(namespace string, dependencies []v1beta1.Dependency) map[string][]rbacv1.PolicyRule {
+	names := map[string]map[string][]string{}
+	for _, dep := range defaultDependencies(namespace, dependencies) {
+		var depNamespace, resource string
+		switch dep.Kind {
+		case configsync.RootSyncKind:
+			depNamespace, resource = configsync.ControllerNamespace, "rootsyncs"
+		case configsync.RepoSyncKind:
+			if dep.Namespace == namespace {
+				continue
+			}
+			depNamespace, resource = dep.Namespace, "reposyncs"
+		default:
+			continue
+		}
+		if names[depNamespace] == nil {
+			names[depNamespace] = map[string][]string{}
+		}
+		names[depNamespace][resource] = append(names[depNamespace][resource], dep.Name)
+	}
+	rules := map[string][]rbacv1.PolicyRule{}
+	for depNamespace, resources := range names {
+		for _, resource := range []string{"reposyncs", "rootsyncs"} {
+			if len(resources[resource]) == 0 {
+				continue
+			}
+			rules[depNamespace] = append(rules[depNamespace], rbacv1.PolicyRule{
+				APIGroups:     []string{configsync.GroupName},
+				Resources:     []string{resource},
+				ResourceNames: uniqueSorted(resources[resource]),
+				Verbs:         []string{"get"},
+			})
+		}
+	}
+	return rules
+}
+
+// uniqueSorted returns the strings sorted, without duplicates.
+func uniqueSorted(list []string) []string {
+	sort.Strings(list)
+	var result []string
+	for i, s := range list {
+		if i == 0 || s != list[i-1] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
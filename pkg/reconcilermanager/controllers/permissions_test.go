@@ -19,6 +19,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"sigs.k8s.io/cli-utils/pkg/testutil"
 )
 
@@ -438,3 +440,81 @@ func TestRemoveSubject(t *testing.T) {
 		})
 	}
 }
+
+func TestDependencyRules(t *testing.T) {
+	rootSyncRule := func(names ...string) rbacv1.PolicyRule {
+		return rbacv1.PolicyRule{
+			APIGroups:     []string{configsync.GroupName},
+			Resources:     []string{"rootsyncs"},
+			ResourceNames: names,
+			Verbs:         []string{"get"},
+		}
+	}
+	repoSyncRule := func(names ...string) rbacv1.PolicyRule {
+		return rbacv1.PolicyRule{
+			APIGroups:     []string{configsync.GroupName},
+			Resources:     []string{"reposyncs"},
+			ResourceNames: names,
+			Verbs:         []string{"get"},
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		dependencies []v1beta1.Dependency
+		expected     map[string][]rbacv1.PolicyRule
+	}{
+		{
+			name:     "no dependencies",
+			expected: map[string][]rbacv1.PolicyRule{},
+		},
+		{
+			name: "RepoSyncs in the same namespace",
+			dependencies: []v1beta1.Dependency{
+				{Kind: configsync.RepoSyncKind, Name: "repo-sync"},
+				{Kind: configsync.RepoSyncKind, Name: "repo-sync-2", Namespace: "bookinfo"},
+			},
+			expected: map[string][]rbacv1.PolicyRule{},
+		},
+		{
+			name: "RootSyncs",
+			dependencies: []v1beta1.Dependency{
+				{Kind: configsync.RootSyncKind, Name: "root-sync-2"},
+				{Kind: configsync.RootSyncKind, Name: "root-sync"},
+				{Kind: configsync.RootSyncKind, Name: "root-sync-2"},
+			},
+			expected: map[string][]rbacv1.PolicyRule{
+				configsync.ControllerNamespace: {rootSyncRule("root-sync", "root-sync-2")},
+			},
+		},
+		{
+			name: "RepoSyncs in other namespaces",
+			dependencies: []v1beta1.Dependency{
+				{Kind: configsync.RepoSyncKind, Name: "repo-sync", Namespace: "videoinfo"},
+				{Kind: configsync.RepoSyncKind, Name: "repo-sync", Namespace: "shipping"},
+				{Kind: configsync.RepoSyncKind, Name: "repo-sync", Namespace: "bookinfo"},
+			},
+			expected: map[string][]rbacv1.PolicyRule{
+				"videoinfo": {repoSyncRule("repo-sync")},
+				"shipping":  {repoSyncRule("repo-sync")},
+			},
+		},
+		{
+			name: "RootSyncs and RepoSyncs in the config-management-system namespace",
+			dependencies: []v1beta1.Dependency{
+				{Kind: configsync.RootSyncKind, Name: "root-sync"},
+				{Kind: configsync.RepoSyncKind, Name: "repo-sync", Namespace: configsync.ControllerNamespace},
+			},
+			expected: map[string][]rbacv1.PolicyRule{
+				configsync.ControllerNamespace: {repoSyncRule("repo-sync"), rootSyncRule("root-sync")},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := dependencyRules("bookinfo", tc.dependencies)
+			testutil.AssertEqual(t, tc.expected, rules)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/metrics"
+	"kpt.dev/configsync/pkg/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// PrometheusServiceName is the name of the Service exposing the metrics of
+	// the reconcilers and the reconciler-manager in the Prometheus exposition
+	// format.
+	PrometheusServiceName = "config-sync-metrics"
+
+	prometheusServiceRetryPeriod = time.Minute
+)
+
+var _ manager.Runnable = &PrometheusServiceReconciler{}
+
+// PrometheusServiceReconciler creates the Service exposing the metrics in the
+// Prometheus exposition format when they are enabled, and deletes it
+// otherwise.
+type PrometheusServiceReconciler struct {
+	// reader reads the Service from the API server, so that the
+	// reconciler-manager does not cache all the Services of the cluster.
+	reader client.Reader
+	client client.Client
+	port   int
+	log    logr.Logger
+}
+
+// NewPrometheusServiceReconciler returns a new PrometheusServiceReconciler.
+// The Service is deleted if port is 0.
+func NewPrometheusServiceReconciler(reader client.Reader, client client.Client, port int, log logr.Logger) *PrometheusServiceReconciler {
+	return &PrometheusServiceReconciler{
+		reader: reader,
+		client: client,
+		port:   port,
+		log:    log,
+	}
+}
+
+// Start implements manager.Runnable. It retries until the Service is
+// reconciled or the context is cancelled.
+func (r *PrometheusServiceReconciler) Start(ctx context.Context) error {
+	return wait.PollImmediateUntilWithContext(ctx, prometheusServiceRetryPeriod, func(ctx context.Context) (bool, error) {
+		if err := r.reconcile(ctx); err != nil {
+			r.log.Error(err, "Failed to reconcile the Prometheus metrics Service")
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+func (r *PrometheusServiceReconciler) reconcile(ctx context.Context) error {
+	key := client.ObjectKey{Namespace: configsync.ControllerNamespace, Name: PrometheusServiceName}
+	svc := &corev1.Service{}
+	err := r.reader.Get(ctx, key, svc)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return status.APIServerErrorf(err, "failed to get Service %s", key)
+	}
+	found := err == nil
+
+	if r.port <= 0 {
+		if !found {
+			return nil
+		}
+		if err := r.client.Delete(ctx, svc); err != nil && !apierrors.IsNotFound(err) {
+			return status.APIServerErrorf(err, "failed to delete Service %s", key)
+		}
+		r.log.Info("Managed object delete successful",
+			logFieldObject, key.String(),
+			logFieldKind, "Service")
+		return nil
+	}
+
+	existing := svc.DeepCopy()
+	svc.Name = key.Name
+	svc.Namespace = key.Namespace
+	svc.Labels = map[string]string{
+		"monitored":          "true",
+		metadata.SystemLabel: "true",
+		metadata.ArchLabel:   "csmr",
+	}
+	svc.Spec.Selector = map[string]string{
+		metadata.PrometheusMetricsLabel: "true",
+	}
+	svc.Spec.Ports = []corev1.ServicePort{{
+		Name:       metrics.PrometheusPortName,
+		Protocol:   corev1.ProtocolTCP,
+		Port:       int32(r.port),
+		TargetPort: intstr.FromInt(r.port),
+	}}
+
+	if !found {
+		if err := r.client.Create(ctx, svc); err != nil {
+			return status.APIServerErrorf(err, "failed to create Service %s", key)
+		}
+		r.log.Info("Managed object create successful",
+			logFieldObject, key.String(),
+			logFieldKind, "Service")
+		return nil
+	}
+	if equality.Semantic.DeepEqual(existing, svc) {
+		return nil
+	}
+	if err := r.client.Update(ctx, svc); err != nil {
+		return status.APIServerErrorf(err, "failed to update Service %s", key)
+	}
+	r.log.Info("Managed object update successful",
+		logFieldObject, key.String(),
+		logFieldKind, "Service")
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/metrics"
+	syncerFake "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPrometheusServiceReconciler(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := syncerFake.NewClient(t, core.Scheme)
+	key := client.ObjectKey{Namespace: configsync.ControllerNamespace, Name: PrometheusServiceName}
+	log := controllerruntime.Log.WithName("controllers").WithName("PrometheusService")
+
+	// The Service is created when the metrics are enabled.
+	require.NoError(t, NewPrometheusServiceReconciler(fakeClient, fakeClient, 8675, log).reconcile(ctx))
+	svc := &corev1.Service{}
+	require.NoError(t, fakeClient.Get(ctx, key, svc))
+	require.Equal(t, map[string]string{metadata.PrometheusMetricsLabel: "true"}, svc.Spec.Selector)
+	require.Equal(t, []corev1.ServicePort{{
+		Name:       metrics.PrometheusPortName,
+		Protocol:   corev1.ProtocolTCP,
+		Port:       8675,
+		TargetPort: intstr.FromInt(8675),
+	}}, svc.Spec.Ports)
+
+	// The Service is updated when the port changes.
+	require.NoError(t, NewPrometheusServiceReconciler(fakeClient, fakeClient, 9090, log).reconcile(ctx))
+	require.NoError(t, fakeClient.Get(ctx, key, svc))
+	require.Equal(t, int32(9090), svc.Spec.Ports[0].Port)
+
+	// The Service is deleted when the metrics are disabled.
+	require.NoError(t, NewPrometheusServiceReconciler(fakeClient, fakeClient, 0, log).reconcile(ctx))
+	err := fakeClient.Get(ctx, key, svc)
+	require.True(t, apierrors.IsNotFound(err), "got error %v, want NotFound", err)
+
+	// Deleting a missing Service is a no-op.
+	require.NoError(t, NewPrometheusServiceReconciler(fakeClient, fakeClient, 0, log).reconcile(ctx))
+}
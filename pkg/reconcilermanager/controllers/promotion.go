@@ -0,0 +1,145 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/reposync"
+	"kpt.dev/configsync/pkg/rootsync"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// promotionPollingPeriod is the period of time between checking the sync to
+// follow for a new commit to promote.
+const promotionPollingPeriod = 30 * time.Second
+
+// promote updates the promotion status with the commit synced by the sync to
+// follow. The commit is pending until the sync to follow has synced it without
+// errors for the soak time, and is promoted afterwards. Errors of the sync to
+// follow reset the soak time.
+//
+// It returns how long to wait before checking the sync to follow again.
+func promote(ctx context.Context, c client.Client, promotion *v1beta1.Promotion, namespace string, current *v1beta1.PromotionStatus, now time.Time) (*v1beta1.PromotionStatus, time.Duration, error) {
+	commit, err := followedCommit(ctx, c, promotion.SourceRef, namespace)
+	if err != nil {
+		return current, 0, err
+	}
+	next := &v1beta1.PromotionStatus{}
+	if current != nil {
+		*next = *current
+	}
+	switch {
+	case commit == "" || commit == next.Commit:
+		// Nothing to promote.
+		next.PendingCommit = ""
+		next.PendingSince = metav1.Time{}
+		return next, promotionPollingPeriod, nil
+	case commit != next.PendingCommit:
+		next.PendingCommit = commit
+		next.PendingSince = metav1.NewTime(now)
+	}
+	if remaining := next.PendingSince.Add(promotion.GetSoakTime()).Sub(now); remaining > 0 {
+		if remaining < promotionPollingPeriod {
+			return next, remaining, nil
+		}
+		return next, promotionPollingPeriod, nil
+	}
+	next.Commit = commit
+	next.PendingCommit = ""
+	next.PendingSince = metav1.Time{}
+	return next, promotionPollingPeriod, nil
+}
+
+// followedCommit returns the commit synced without errors by the sync to
+// follow, or an empty string if it has not synced any commit yet, if it is
+// still syncing, or if it has errors.
+func followedCommit(ctx context.Context, c client.Client, ref v1beta1.PromotionSourceRef, namespace string) (string, error) {
+	var err error
+	switch ref.Kind {
+	case configsync.RootSyncKind:
+		rs := &v1beta1.RootSync{}
+		if err = c.Get(ctx, types.NamespacedName{Namespace: configsync.ControllerNamespace, Name: ref.Name}, rs); err == nil {
+			syncing := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncSyncing)
+			if syncing != nil && syncing.Status == metav1.ConditionFalse && syncing.Commit == rs.Status.LastSyncedCommit &&
+				rootsync.ConditionHasNoErrors(*syncing) {
+				return rs.Status.LastSyncedCommit, nil
+			}
+			return "", nil
+		}
+	case configsync.RepoSyncKind:
+		rs := &v1beta1.RepoSync{}
+		if err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, rs); err == nil {
+			syncing := reposync.GetCondition(rs.Status.Conditions, v1beta1.RepoSyncSyncing)
+			if syncing != nil && syncing.Status == metav1.ConditionFalse && syncing.Commit == rs.Status.LastSyncedCommit &&
+				reposync.ConditionHasNoErrors(*syncing) {
+				return rs.Status.LastSyncedCommit, nil
+			}
+			return "", nil
+		}
+	default:
+		cm := &corev1.ConfigMap{}
+		if err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, cm); err == nil {
+			return cm.Data[configsync.PromotionCommitKey], nil
+		}
+	}
+	if apierrors.IsNotFound(err) {
+		// The sync to follow may not be created yet.
+		return "", nil
+	}
+	return "", fmt.Errorf("failed to get the %s %s to follow: %w", ref.Kind, ref.Name, err)
+}
+
+// promotionMessage returns the message of the Reconciling condition while no
+// commit has been promoted yet.
+func promotionMessage(promotion *v1beta1.Promotion, promotionStatus *v1beta1.PromotionStatus) string {
+	ref := promotion.SourceRef
+	if promotionStatus.PendingCommit != "" {
+		return fmt.Sprintf("Waiting for commit %s of %s %s to soak until %s", promotionStatus.PendingCommit, ref.Kind, ref.Name,
+			promotionStatus.PendingSince.Add(promotion.GetSoakTime()).UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("Waiting for %s %s to sync a commit without errors", ref.Kind, ref.Name)
+}
+
+// pinSource pins the git revision or the OCI image digest of the source to the
+// promoted commit.
+func pinSource(sourceType string, git *v1beta1.Git, oci *v1beta1.Oci, commit string) {
+	switch v1beta1.SourceType(sourceType) {
+	case v1beta1.GitSource:
+		git.Revision = commit
+	case v1beta1.OciSource:
+		oci.Image = ociRepository(oci.Image) + "@sha256:" + commit
+	}
+}
+
+// ociRepository returns the image name without its tag or digest.
+func ociRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i]
+	}
+	return image
+}
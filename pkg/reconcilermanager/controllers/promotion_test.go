@@ -0,0 +1,195 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	syncerFake "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+	"kpt.dev/configsync/pkg/testing/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func canaryRootSync(lastSyncedCommit string, syncing v1beta1.RootSyncCondition) *v1beta1.RootSync {
+	rs := fake.RootSyncObjectV1Beta1("canary")
+	rs.Status.LastSyncedCommit = lastSyncedCommit
+	rs.Status.Conditions = []v1beta1.RootSyncCondition{syncing}
+	return rs
+}
+
+func canaryStatusConfigMap(commit string) *corev1.ConfigMap {
+	cm := fake.ConfigMapObject(core.Name("canary-status"), core.Namespace(configsync.ControllerNamespace))
+	cm.Data = map[string]string{configsync.PromotionCommitKey: commit}
+	return cm
+}
+
+func TestPromote(t *testing.T) {
+	now := time.Date(2022, 9, 16, 18, 0, 0, 0, time.UTC)
+	synced := v1beta1.RootSyncCondition{
+		Type:         v1beta1.RootSyncSyncing,
+		Status:       metav1.ConditionFalse,
+		Commit:       "def456",
+		ErrorSummary: &v1beta1.ErrorSummary{},
+	}
+	failed := v1beta1.RootSyncCondition{
+		Type:         v1beta1.RootSyncSyncing,
+		Status:       metav1.ConditionFalse,
+		Commit:       "def456",
+		ErrorSummary: &v1beta1.ErrorSummary{TotalCount: 1},
+	}
+	syncing := v1beta1.RootSyncCondition{
+		Type:         v1beta1.RootSyncSyncing,
+		Status:       metav1.ConditionTrue,
+		Commit:       "def456",
+		ErrorSummary: &v1beta1.ErrorSummary{},
+	}
+	promotion := &v1beta1.Promotion{
+		SourceRef: v1beta1.PromotionSourceRef{Kind: configsync.RootSyncKind, Name: "canary"},
+		SoakTime:  &metav1.Duration{Duration: time.Hour},
+	}
+
+	testCases := []struct {
+		name             string
+		promotion        *v1beta1.Promotion
+		objs             []client.Object
+		current          *v1beta1.PromotionStatus
+		want             *v1beta1.PromotionStatus
+		wantRequeueAfter time.Duration
+	}{
+		{
+			name:             "sync to follow not found",
+			promotion:        promotion,
+			current:          &v1beta1.PromotionStatus{Commit: "abc123"},
+			want:             &v1beta1.PromotionStatus{Commit: "abc123"},
+			wantRequeueAfter: promotionPollingPeriod,
+		},
+		{
+			name:             "new commit starts soaking",
+			promotion:        promotion,
+			objs:             []client.Object{canaryRootSync("def456", synced)},
+			current:          &v1beta1.PromotionStatus{Commit: "abc123"},
+			want:             &v1beta1.PromotionStatus{Commit: "abc123", PendingCommit: "def456", PendingSince: metav1.NewTime(now)},
+			wantRequeueAfter: promotionPollingPeriod,
+		},
+		{
+			name:             "commit is still soaking",
+			promotion:        promotion,
+			objs:             []client.Object{canaryRootSync("def456", synced)},
+			current:          &v1beta1.PromotionStatus{Commit: "abc123", PendingCommit: "def456", PendingSince: metav1.NewTime(now.Add(-time.Hour + 10*time.Second))},
+			want:             &v1beta1.PromotionStatus{Commit: "abc123", PendingCommit: "def456", PendingSince: metav1.NewTime(now.Add(-time.Hour + 10*time.Second))},
+			wantRequeueAfter: 10 * time.Second,
+		},
+		{
+			name:             "commit has soaked",
+			promotion:        promotion,
+			objs:             []client.Object{canaryRootSync("def456", synced)},
+			current:          &v1beta1.PromotionStatus{Commit: "abc123", PendingCommit: "def456", PendingSince: metav1.NewTime(now.Add(-time.Hour))},
+			want:             &v1beta1.PromotionStatus{Commit: "def456"},
+			wantRequeueAfter: promotionPollingPeriod,
+		},
+		{
+			name:             "errors reset the soak time",
+			promotion:        promotion,
+			objs:             []client.Object{canaryRootSync("def456", failed)},
+			current:          &v1beta1.PromotionStatus{Commit: "abc123", PendingCommit: "def456", PendingSince: metav1.NewTime(now.Add(-time.Hour))},
+			want:             &v1beta1.PromotionStatus{Commit: "abc123"},
+			wantRequeueAfter: promotionPollingPeriod,
+		},
+		{
+			name:             "commit still syncing is not promoted",
+			promotion:        promotion,
+			objs:             []client.Object{canaryRootSync("def456", syncing)},
+			current:          &v1beta1.PromotionStatus{Commit: "abc123"},
+			want:             &v1beta1.PromotionStatus{Commit: "abc123"},
+			wantRequeueAfter: promotionPollingPeriod,
+		},
+		{
+			name: "first commit without soak time",
+			promotion: &v1beta1.Promotion{
+				SourceRef: v1beta1.PromotionSourceRef{Kind: configsync.RootSyncKind, Name: "canary"},
+			},
+			objs:             []client.Object{canaryRootSync("def456", synced)},
+			want:             &v1beta1.PromotionStatus{Commit: "def456"},
+			wantRequeueAfter: promotionPollingPeriod,
+		},
+		{
+			name: "commit exported to a ConfigMap",
+			promotion: &v1beta1.Promotion{
+				SourceRef: v1beta1.PromotionSourceRef{Kind: "ConfigMap", Name: "canary-status"},
+			},
+			objs:             []client.Object{canaryStatusConfigMap("def456")},
+			want:             &v1beta1.PromotionStatus{Commit: "def456"},
+			wantRequeueAfter: promotionPollingPeriod,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := syncerFake.NewClient(t, core.Scheme, tc.objs...)
+			got, requeueAfter, err := promote(context.Background(), fakeClient, tc.promotion, configsync.ControllerNamespace, tc.current, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("promote() got diff (-want +got):\n%s", diff)
+			}
+			if requeueAfter != tc.wantRequeueAfter {
+				t.Errorf("promote() got requeueAfter %v, want %v", requeueAfter, tc.wantRequeueAfter)
+			}
+		})
+	}
+}
+
+func TestPinSource(t *testing.T) {
+	testCases := []struct {
+		name      string
+		image     string
+		wantImage string
+	}{
+		{
+			name:      "image with a tag",
+			image:     "us-docker.pkg.dev/my-project/my-repo/pkg:v1",
+			wantImage: "us-docker.pkg.dev/my-project/my-repo/pkg@sha256:def456",
+		},
+		{
+			name:      "image with a digest",
+			image:     "localhost:5000/pkg@sha256:abc123",
+			wantImage: "localhost:5000/pkg@sha256:def456",
+		},
+		{
+			name:      "registry with a port",
+			image:     "localhost:5000/pkg",
+			wantImage: "localhost:5000/pkg@sha256:def456",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			oci := &v1beta1.Oci{Image: tc.image}
+			pinSource(string(v1beta1.OciSource), nil, oci, "def456")
+			if oci.Image != tc.wantImage {
+				t.Errorf("pinSource() got image %q, want %q", oci.Image, tc.wantImage)
+			}
+		})
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
 	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	hubv1 "kpt.dev/configsync/pkg/api/hub/v1"
@@ -62,6 +63,27 @@ const (
 	// It will be used in both the indexing and watching.
 	helmSecretRefField = ".spec.helm.secretRef.name"
 
+	// webhookSecretRefField is the path of the field in the RootSync|RepoSync CRDs
+	// that we wish to use as the "object reference".
+	// It will be used in both the indexing and watching.
+	webhookSecretRefField = ".spec.webhook.secretRef.name"
+
+	// helmValuesFileRefField is the path of the field in the RootSync|RepoSync CRDs
+	// that we wish to use as the "object reference".
+	// It will be used in both the indexing and watching.
+	helmValuesFileRefField = ".spec.helm.valuesSources.configMapRef.name"
+
+	// verificationKeyRefField is the path of the `publicKeys` field of the
+	// `spec.oci.verification` or `spec.helm.verification` field in the
+	// RootSync|RepoSync CRDs that we wish to use as the "object reference".
+	// It will be used in both the indexing and watching.
+	verificationKeyRefField = ".spec.verification.publicKeys.name"
+
+	// notificationSecretRefField is the path of the field in the
+	// RootSync|RepoSync CRDs that we wish to use as the "object reference".
+	// It will be used in both the indexing and watching.
+	notificationSecretRefField = ".spec.notifications.secretRef.name"
+
 	// fleetMembershipName is the name of the fleet membership
 	fleetMembershipName = "membership"
 
@@ -101,6 +123,13 @@ type reconcilerBase struct {
 	hydrationPollingPeriod  time.Duration
 	membership              *hubv1.Membership
 
+	// recorder emits Events on the RootSyncs and RepoSyncs.
+	recorder record.EventRecorder
+
+	// prometheusPort is the port on which the reconcilers serve their metrics
+	// in the Prometheus exposition format, or 0 if they don't.
+	prometheusPort int
+
 	// syncKind is the kind of the sync object: RootSync or RepoSync.
 	syncKind string
 
@@ -28,11 +28,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	v1 "kpt.dev/configsync/pkg/api/configmanagement/v1"
 	"kpt.dev/configsync/pkg/api/configsync"
@@ -70,16 +72,18 @@ type RepoSyncReconciler struct {
 }
 
 // NewRepoSyncReconciler returns a new RepoSyncReconciler.
-func NewRepoSyncReconciler(clusterName string, reconcilerPollingPeriod, hydrationPollingPeriod time.Duration, client client.Client, dynamicClient dynamic.Interface, log logr.Logger, scheme *runtime.Scheme) *RepoSyncReconciler {
+func NewRepoSyncReconciler(clusterName string, reconcilerPollingPeriod, hydrationPollingPeriod time.Duration, prometheusPort int, client client.Client, dynamicClient dynamic.Interface, recorder record.EventRecorder, log logr.Logger, scheme *runtime.Scheme) *RepoSyncReconciler {
 	return &RepoSyncReconciler{
 		reconcilerBase: reconcilerBase{
 			clusterName:             clusterName,
 			client:                  client,
 			dynamicClient:           dynamicClient,
+			recorder:                recorder,
 			log:                     log,
 			scheme:                  scheme,
 			reconcilerPollingPeriod: reconcilerPollingPeriod,
 			hydrationPollingPeriod:  hydrationPollingPeriod,
+			prometheusPort:          prometheusPort,
 			syncKind:                configsync.RepoSyncKind,
 		},
 		repoSyncs: make(map[types.NamespacedName]struct{}),
@@ -184,6 +188,38 @@ func (r *RepoSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 		return controllerruntime.Result{}, updateErr
 	}
 
+	// Promote the commit synced by the sync to follow, if any.
+	var requeueAfter time.Duration
+	if rs.Spec.Promotion != nil {
+		rs.Status.Promotion, requeueAfter, err = promote(ctx, r.client, rs.Spec.Promotion, rs.Namespace, rs.Status.Promotion, time.Now())
+		if err != nil {
+			log.Error(err, "Promotion failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+			reposync.SetStalled(rs, "Promotion", err)
+			// Get errors should always trigger retry (return error),
+			// even if status update is successful.
+			_, updateErr := r.updateStatus(ctx, currentRS, rs)
+			if updateErr != nil {
+				log.Error(updateErr, "Object status update failed",
+					logFieldObject, rsRef.String(),
+					logFieldKind, r.syncKind)
+			}
+			// Use the get error for metric tagging.
+			metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+			return controllerruntime.Result{}, errors.Wrap(err, "Promotion reconcile failed")
+		}
+		if rs.Status.Promotion.Commit == "" {
+			// Don't sync anything until a commit is promoted.
+			reposync.SetReconciling(rs, "Promotion", promotionMessage(rs.Spec.Promotion, rs.Status.Promotion))
+			_, updateErr := r.updateStatus(ctx, currentRS, rs)
+			metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(updateErr), start)
+			return controllerruntime.Result{RequeueAfter: requeueAfter}, updateErr
+		}
+	} else {
+		rs.Status.Promotion = nil
+	}
+
 	// Create secret in config-management-system namespace using the
 	// existing secret in the reposync.namespace.
 	if sRef, err := upsertAuthSecret(ctx, log, rs, r.client, reconcilerRef); err != nil {
@@ -233,6 +269,89 @@ func (r *RepoSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 		return controllerruntime.Result{}, errors.Wrap(err, "Secret reconcile failed")
 	}
 
+	// Create secret in config-management-system namespace using the
+	// existing secret in the reposync.namespace.
+	if sRef, err := upsertWebhookSecret(ctx, log, rs, r.client, reconcilerRef); err != nil {
+		log.Error(err, "Managed object upsert failed",
+			logFieldObject, sRef.String(),
+			logFieldKind, "Secret",
+			"type", "webhook")
+		reposync.SetStalled(rs, "Secret", err)
+		// Upsert errors should always trigger retry (return error),
+		// even if status update is successful.
+		_, updateErr := r.updateStatus(ctx, currentRS, rs)
+		if updateErr != nil {
+			log.Error(updateErr, "Object status update failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+		}
+		// Use the upsert error for metric tagging.
+		metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+		return controllerruntime.Result{}, errors.Wrap(err, "Secret reconcile failed")
+	}
+
+	// Create secrets in config-management-system namespace using the
+	// existing secrets in the reposync.namespace.
+	if sRef, err := upsertVerificationSecrets(ctx, log, rs, r.client, reconcilerRef); err != nil {
+		log.Error(err, "Managed object upsert failed",
+			logFieldObject, sRef.String(),
+			logFieldKind, "Secret",
+			"type", "verification")
+		reposync.SetStalled(rs, "Secret", err)
+		// Upsert errors should always trigger retry (return error),
+		// even if status update is successful.
+		_, updateErr := r.updateStatus(ctx, currentRS, rs)
+		if updateErr != nil {
+			log.Error(updateErr, "Object status update failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+		}
+		// Use the upsert error for metric tagging.
+		metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+		return controllerruntime.Result{}, errors.Wrap(err, "Secret reconcile failed")
+	}
+
+	// Create secrets in config-management-system namespace using the
+	// existing secrets in the reposync.namespace.
+	if sRef, err := upsertNotificationSecrets(ctx, log, rs, r.client, reconcilerRef); err != nil {
+		log.Error(err, "Managed object upsert failed",
+			logFieldObject, sRef.String(),
+			logFieldKind, "Secret",
+			"type", "notification")
+		reposync.SetStalled(rs, "Secret", err)
+		// Upsert errors should always trigger retry (return error),
+		// even if status update is successful.
+		_, updateErr := r.updateStatus(ctx, currentRS, rs)
+		if updateErr != nil {
+			log.Error(updateErr, "Object status update failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+		}
+		// Use the upsert error for metric tagging.
+		metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+		return controllerruntime.Result{}, errors.Wrap(err, "Secret reconcile failed")
+	}
+
+	// Create ConfigMaps in config-management-system namespace using the
+	// existing ConfigMaps in the reposync.namespace.
+	if cmRef, err := upsertConfigMaps(ctx, log, rs, r.client, reconcilerRef); err != nil {
+		log.Error(err, "Managed object upsert failed",
+			logFieldObject, cmRef.String(),
+			logFieldKind, "ConfigMap")
+		reposync.SetStalled(rs, "ConfigMap", err)
+		// Upsert errors should always trigger retry (return error),
+		// even if status update is successful.
+		_, updateErr := r.updateStatus(ctx, currentRS, rs)
+		if updateErr != nil {
+			log.Error(updateErr, "Object status update failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+		}
+		// Use the upsert error for metric tagging.
+		metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+		return controllerruntime.Result{}, errors.Wrap(err, "ConfigMap reconcile failed")
+	}
+
 	labelMap := map[string]string{
 		metadata.SyncNamespaceLabel: rs.Namespace,
 		metadata.SyncNameLabel:      rs.Name,
@@ -292,7 +411,51 @@ func (r *RepoSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 		return controllerruntime.Result{}, errors.Wrap(err, "RoleBinding reconcile failed")
 	}
 
-	containerEnvs := r.populateContainerEnvs(ctx, rs, reconcilerRef.Name)
+	// Overwrite the reconciler permissions on the syncs it depends on.
+	if roleRef, err := r.upsertDependencyRoles(ctx, rs, reconcilerRef, labelMap); err != nil {
+		log.Error(err, "Managed object upsert failed",
+			logFieldObject, roleRef.String(),
+			logFieldKind, "Role")
+		reposync.SetStalled(rs, "Role", err)
+		// Upsert errors should always trigger retry (return error),
+		// even if status update is successful.
+		_, updateErr := r.updateStatus(ctx, currentRS, rs)
+		if updateErr != nil {
+			log.Error(updateErr, "Object status update failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+		}
+		// Use the upsert error for metric tagging.
+		metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+		return controllerruntime.Result{}, errors.Wrap(err, "Role reconcile failed")
+	}
+
+	// Overwrite reconciler webhook Service.
+	if svcRef, err := r.reconcileWebhookService(ctx, rs.Spec.Webhook, reconcilerRef, labelMap); err != nil {
+		log.Error(err, "Managed object upsert failed",
+			logFieldObject, svcRef.String(),
+			logFieldKind, "Service")
+		reposync.SetStalled(rs, "Service", err)
+		// Upsert errors should always trigger retry (return error),
+		// even if status update is successful.
+		_, updateErr := r.updateStatus(ctx, currentRS, rs)
+		if updateErr != nil {
+			log.Error(updateErr, "Object status update failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+		}
+		// Use the upsert error for metric tagging.
+		metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+		return controllerruntime.Result{}, errors.Wrap(err, "Service reconcile failed")
+	}
+
+	// Sync the promoted commit instead of the declared revision.
+	deployedRS := rs
+	if rs.Spec.Promotion != nil {
+		deployedRS = rs.DeepCopy()
+		pinSource(deployedRS.Spec.SourceType, deployedRS.Spec.Git, deployedRS.Spec.Oci, rs.Status.Promotion.Commit)
+	}
+	containerEnvs := r.populateContainerEnvs(ctx, deployedRS, reconcilerRef.Name)
 	mut := r.mutationsFor(ctx, rs, containerEnvs)
 
 	// Upsert Namespace reconciler deployment.
@@ -395,7 +558,7 @@ func (r *RepoSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 			logFieldObject, rsRef.String(),
 			logFieldKind, r.syncKind)
 	}
-	return controllerruntime.Result{}, nil
+	return controllerruntime.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // SetupWithManager registers RepoSync controller with reconciler-manager.
@@ -430,6 +593,52 @@ func (r *RepoSyncReconciler) SetupWithManager(mgr controllerruntime.Manager, wat
 	}); err != nil {
 		return err
 	}
+	// Index the `webhookSecretRefField` field, so that we will be able to lookup RepoSync be a referenced `webhookSecretRefField` name.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.RepoSync{}, webhookSecretRefField, func(rawObj client.Object) []string {
+		rs := rawObj.(*v1beta1.RepoSync)
+		if rs.Spec.Webhook == nil || v1beta1.GetSecretName(rs.Spec.Webhook.SecretRef) == "" {
+			return nil
+		}
+		return []string{rs.Spec.Webhook.SecretRef.Name}
+	}); err != nil {
+		return err
+	}
+	// Index the `helmValuesFileRefField` field, so that we will be able to lookup RepoSync be a referenced ConfigMap name.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.RepoSync{}, helmValuesFileRefField, func(rawObj client.Object) []string {
+		rs := rawObj.(*v1beta1.RepoSync)
+		if rs.Spec.Helm == nil {
+			return nil
+		}
+		var names []string
+		for _, ref := range rs.Spec.Helm.GetValuesFileRefs() {
+			names = append(names, ref.Name)
+		}
+		return names
+	}); err != nil {
+		return err
+	}
+	// Index the `verificationKeyRefField` field, so that we will be able to lookup RepoSync be a referenced Secret or ConfigMap name.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.RepoSync{}, verificationKeyRefField, func(rawObj client.Object) []string {
+		rs := rawObj.(*v1beta1.RepoSync)
+		verification := sourceVerification(rs.Spec.SourceType, rs.Spec.Oci, reposync.GetHelmBase(rs.Spec.Helm))
+		if verification == nil {
+			return nil
+		}
+		var names []string
+		for _, ref := range verification.PublicKeys {
+			names = append(names, ref.Name)
+		}
+		return names
+	}); err != nil {
+		return err
+	}
+	// Index the `notificationSecretRefField` field, so that we will be able to lookup RepoSync be a referenced Secret name.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.RepoSync{}, notificationSecretRefField, func(rawObj client.Object) []string {
+		rs := rawObj.(*v1beta1.RepoSync)
+		return notificationSecretNames(rs.Spec.Notifications)
+	}); err != nil {
+		return err
+	}
 
 	controllerBuilder := controllerruntime.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{
@@ -440,6 +649,9 @@ func (r *RepoSyncReconciler) SetupWithManager(mgr controllerruntime.Manager, wat
 		Watches(&source.Kind{Type: &corev1.Secret{}},
 			handler.EnqueueRequestsFromMapFunc(r.mapSecretToRepoSyncs),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapConfigMapToRepoSyncs),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Watches(&source.Kind{Type: &appsv1.Deployment{}},
 			handler.EnqueueRequestsFromMapFunc(r.mapObjectToRepoSync),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
@@ -556,7 +768,7 @@ func (r *RepoSyncReconciler) mapSecretToRepoSyncs(secret client.Object) []reconc
 	// The user-managed ns-reconciler Secret might be shared among multiple RepoSync objects in the same namespace,
 	// so requeue all the attached RepoSync objects.
 	attachedRepoSyncs := &v1beta1.RepoSyncList{}
-	secretFields := []string{gitSecretRefField, caCertSecretRefField, helmSecretRefField}
+	secretFields := []string{gitSecretRefField, caCertSecretRefField, helmSecretRefField, webhookSecretRefField, verificationKeyRefField, notificationSecretRefField}
 	for _, secretField := range secretFields {
 		listOps := &client.ListOptions{
 			FieldSelector: fields.OneTermEqualSelector(secretField, secret.GetName()),
@@ -586,6 +798,59 @@ func (r *RepoSyncReconciler) mapSecretToRepoSyncs(secret client.Object) []reconc
 	return requests
 }
 
+// mapConfigMapToRepoSyncs define a mapping from a ConfigMap object to the
+// RepoSync objects that reference it via the `spec.helm.valuesSources` field
+// or the `publicKeys` field of the source verification, or that it was copied
+// for.
+func (r *RepoSyncReconciler) mapConfigMapToRepoSyncs(cm client.Object) []reconcile.Request {
+	// map the copied ns-reconciler ConfigMap in the config-management-system to RepoSync request.
+	if cm.GetNamespace() == configsync.ControllerNamespace {
+		// Ignore ConfigMaps in the config-management-system namespace that don't start with ns-reconciler.
+		if !strings.HasPrefix(cm.GetName(), core.NsReconcilerPrefix) {
+			return nil
+		}
+		allRepoSyncs := &v1beta1.RepoSyncList{}
+		if err := r.client.List(context.Background(), allRepoSyncs); err != nil {
+			klog.Errorf("failed to list all RepoSyncs for object (name: %s, namespace: %s): %v", cm.GetName(), cm.GetNamespace(), err)
+			return nil
+		}
+		for _, rs := range allRepoSyncs.Items {
+			if isUpsertedConfigMap(&rs, cm.GetName()) {
+				return requeueRepoSyncRequest(cm, &rs)
+			}
+		}
+		return nil
+	}
+
+	// map the user-managed ConfigMap in the RepoSync's namespace to RepoSync request.
+	attachedRepoSyncs := &v1beta1.RepoSyncList{}
+	configMapFields := []string{helmValuesFileRefField, verificationKeyRefField}
+	for _, configMapField := range configMapFields {
+		listOps := &client.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector(configMapField, cm.GetName()),
+			Namespace:     cm.GetNamespace(),
+		}
+		fetchedRepoSyncs := &v1beta1.RepoSyncList{}
+		if err := r.client.List(context.Background(), fetchedRepoSyncs, listOps); err != nil {
+			klog.Errorf("failed to list attached RepoSyncs for ConfigMap (name: %s, namespace: %s): %v", cm.GetName(), cm.GetNamespace(), err)
+			return nil
+		}
+		attachedRepoSyncs.Items = append(attachedRepoSyncs.Items, fetchedRepoSyncs.Items...)
+	}
+	requests := make([]reconcile.Request, len(attachedRepoSyncs.Items))
+	attachedRSNames := make([]string, len(attachedRepoSyncs.Items))
+	for i, rs := range attachedRepoSyncs.Items {
+		attachedRSNames[i] = rs.GetName()
+		requests[i] = reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&rs),
+		}
+	}
+	if len(requests) > 0 {
+		klog.Infof("Changes to ConfigMap (name: %s, namespace: %s) triggers a reconciliation for the RepoSync object %q in the same namespace.", cm.GetName(), cm.GetNamespace(), strings.Join(attachedRSNames, ", "))
+	}
+	return requests
+}
+
 // mapObjectToRepoSync define a mapping from an object in 'config-management-system'
 // namespace to a RepoSync to be reconciled.
 func (r *RepoSyncReconciler) mapObjectToRepoSync(obj client.Object) []reconcile.Request {
@@ -598,7 +863,9 @@ func (r *RepoSyncReconciler) mapObjectToRepoSync(obj client.Object) []reconcile.
 	// Ignore changes from resources without the ns-reconciler prefix or configsync.gke.io:ns-reconciler
 	// because all the generated resources have the prefix.
 	nsRoleBindingName := RepoSyncPermissionsName()
-	if !strings.HasPrefix(obj.GetName(), core.NsReconcilerPrefix) && obj.GetName() != nsRoleBindingName {
+	nsConfigRoleBindingName := RepoSyncConfigPermissionsName()
+	if !strings.HasPrefix(obj.GetName(), core.NsReconcilerPrefix) && obj.GetName() != nsRoleBindingName &&
+		obj.GetName() != nsConfigRoleBindingName {
 		return nil
 	}
 
@@ -622,7 +889,7 @@ func (r *RepoSyncReconciler) mapObjectToRepoSync(obj client.Object) []reconcile.
 		reconcilerName := core.NsReconcilerName(rs.GetNamespace(), rs.GetName())
 		switch obj.(type) {
 		case *rbacv1.RoleBinding:
-			if obj.GetName() == nsRoleBindingName {
+			if obj.GetName() == nsRoleBindingName || obj.GetName() == nsConfigRoleBindingName {
 				requests = append(requests, reconcile.Request{
 					NamespacedName: client.ObjectKeyFromObject(&rs),
 				})
@@ -682,22 +949,83 @@ func (r *RepoSyncReconciler) populateContainerEnvs(ctx context.Context, rs *v1be
 			noSSLVerify:     rs.Spec.Git.NoSSLVerify,
 			caCertSecretRef: v1beta1.GetSecretName(rs.Spec.Git.CACertSecretRef),
 		})
+		if usesAskpassSidecar(rs.Spec.SourceType, rs.Spec.Git.Auth) {
+			result[reconcilermanager.AskpassSidecar] = askpassSidecarEnvs(rs.Spec.Git.Auth, rs.Spec.Git.TokenExchange)
+		}
 	case v1beta1.OciSource:
-		result[reconcilermanager.OciSync] = ociSyncEnvs(rs.Spec.Oci.Image, rs.Spec.Oci.Auth, v1beta1.GetPeriodSecs(rs.Spec.Oci.Period))
+		result[reconcilermanager.OciSync] = ociSyncEnvs(rs.Spec.Oci.Image, rs.Spec.Oci.Auth, v1beta1.GetPeriodSecs(rs.Spec.Oci.Period), rs.Spec.Oci.Verification)
+		if rs.Spec.Oci.Auth == configsync.AuthK8sServiceAccount {
+			result[reconcilermanager.OciSync] = append(result[reconcilermanager.OciSync], tokenExchangeEnvs(rs.Spec.Oci.TokenExchange)...)
+		}
 	case v1beta1.HelmSource:
 		result[reconcilermanager.HelmSync] = helmSyncEnvs(&rs.Spec.Helm.HelmBase, rs.Namespace)
+		if rs.Spec.Helm.Auth == configsync.AuthK8sServiceAccount {
+			result[reconcilermanager.HelmSync] = append(result[reconcilermanager.HelmSync], tokenExchangeEnvs(rs.Spec.Helm.TokenExchange)...)
+		}
+	}
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], syncWindowEnvs(rs.Spec.Suspend, rs.Spec.SyncWindows)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], driftPolicyEnvs(rs.Spec.SafeOverride().DriftPolicy)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], autoRollbackEnvs(rs.Spec.SafeOverride().AutoRollback)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], dryRunEnvs(rs.Spec.SafeOverride().DryRun)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], dependsOnEnvs(rs.Namespace, rs.Spec.DependsOn)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], notificationEnvs(rs.Spec.Notifications)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], prometheusEnvs(r.prometheusPort)...)
+	result[reconcilermanager.HydrationController] = append(result[reconcilermanager.HydrationController], renderingEnvs(rs.Spec.SafeOverride().Rendering)...)
+	if shouldUpsertWebhookSecret(rs) {
+		addWebhookEnvs(result, rs.Spec.SourceType, ReconcilerResourceName(reconcilerName, v1beta1.GetSecretName(rs.Spec.Webhook.SecretRef)))
 	}
 	return result
 }
 
 func (r *RepoSyncReconciler) validateSpec(ctx context.Context, rs *v1beta1.RepoSync, reconcilerName string) error {
+	if err := r.validateSourceSpec(ctx, rs, reconcilerName); err != nil {
+		return err
+	}
+	if err := r.validateVerificationSpec(ctx, rs, reconcilerName); err != nil {
+		return err
+	}
+	if err := r.validateWebhookSpec(ctx, rs, reconcilerName); err != nil {
+		return err
+	}
+	if err := validate.SyncWindowsSpec(rs.Spec.SyncWindows, rs); err != nil {
+		return err
+	}
+	if err := validate.PromotionSpec(rs.Spec.Promotion, rs.Spec.SourceType, rs); err != nil {
+		return err
+	}
+	if err := validate.DependsOnSpec(rs.Spec.DependsOn, rs); err != nil {
+		return err
+	}
+	self := v1beta1.Dependency{Kind: configsync.RepoSyncKind, Name: rs.Name, Namespace: rs.Namespace}
+	if err := validateDependencyCycle(ctx, r.client, self, rs.Spec.DependsOn); err != nil {
+		return err
+	}
+	return r.validateNotificationsSpec(ctx, rs, reconcilerName)
+}
+
+// validateNotificationsSpec verifies that the Secrets referenced by the
+// notifications are present before creating ConfigMaps and Deployments.
+func (r *RepoSyncReconciler) validateNotificationsSpec(ctx context.Context, rs *v1beta1.RepoSync, reconcilerName string) error {
+	if err := validate.NotificationsSpec(rs.Spec.Notifications, rs); err != nil {
+		return err
+	}
+	for _, name := range notificationSecretNames(rs.Spec.Notifications) {
+		secretName := ReconcilerResourceName(reconcilerName, name)
+		if errs := validation.IsDNS1123Subdomain(secretName); errs != nil {
+			return errors.Errorf("The managed secret name %q is invalid: %s. To fix it, update '.spec.notifications.secretRef.name'", secretName, strings.Join(errs, ", "))
+		}
+	}
+	return validateNotificationSecrets(ctx, rs.Spec.Notifications, rs.Namespace, r.client)
+}
+
+func (r *RepoSyncReconciler) validateSourceSpec(ctx context.Context, rs *v1beta1.RepoSync, reconcilerName string) error {
 	switch v1beta1.SourceType(rs.Spec.SourceType) {
 	case v1beta1.GitSource:
 		return r.validateGitSpec(ctx, rs, reconcilerName)
 	case v1beta1.OciSource:
 		return validate.OciSpec(rs.Spec.Oci, rs)
 	case v1beta1.HelmSource:
-		return validate.HelmSpec(reposync.GetHelmBase(rs.Spec.Helm), rs)
+		return r.validateHelmSpec(ctx, rs, reconcilerName)
 	default:
 		return validate.InvalidSourceType(rs)
 	}
@@ -710,6 +1038,19 @@ func (r *RepoSyncReconciler) validateGitSpec(ctx context.Context, rs *v1beta1.Re
 	return r.validateNamespaceSecret(ctx, rs, reconcilerName)
 }
 
+func (r *RepoSyncReconciler) validateHelmSpec(ctx context.Context, rs *v1beta1.RepoSync, reconcilerName string) error {
+	if err := validate.HelmSpec(reposync.GetHelmBase(rs.Spec.Helm), rs); err != nil {
+		return err
+	}
+	for _, ref := range rs.Spec.Helm.GetValuesFileRefs() {
+		cmName := ReconcilerResourceName(reconcilerName, ref.Name)
+		if errs := validation.IsDNS1123Subdomain(cmName); errs != nil {
+			return errors.Errorf("The managed ConfigMap name %q is invalid: %s. To fix it, update '.spec.helm.valuesSources.configMapRef.name'", cmName, strings.Join(errs, ", "))
+		}
+	}
+	return validateHelmValuesConfigMaps(ctx, rs.Spec.Helm.GetValuesFileRefs(), rs.Namespace, r.client)
+}
+
 // validateNamespaceSecret verify that any necessary Secret is present before creating ConfigMaps and Deployments.
 func (r *RepoSyncReconciler) validateNamespaceSecret(ctx context.Context, repoSync *v1beta1.RepoSync, reconcilerName string) error {
 	var authType configsync.AuthType
@@ -741,6 +1082,37 @@ func (r *RepoSyncReconciler) validateNamespaceSecret(ctx context.Context, repoSy
 	return validateSecretData(authType, secret)
 }
 
+// validateVerificationSpec verify that the Secrets and ConfigMaps holding the
+// trusted keys are present before creating ConfigMaps and Deployments.
+func (r *RepoSyncReconciler) validateVerificationSpec(ctx context.Context, rs *v1beta1.RepoSync, reconcilerName string) error {
+	verification := sourceVerification(rs.Spec.SourceType, rs.Spec.Oci, reposync.GetHelmBase(rs.Spec.Helm))
+	if verification == nil {
+		return nil
+	}
+	for _, ref := range verification.PublicKeys {
+		name := ReconcilerResourceName(reconcilerName, ref.Name)
+		if errs := validation.IsDNS1123Subdomain(name); errs != nil {
+			return errors.Errorf("The managed %s name %q is invalid: %s. To fix it, update '.spec.%s.verification.publicKeys.name'", ref.Kind, name, strings.Join(errs, ", "), rs.Spec.SourceType)
+		}
+	}
+	return validateVerificationKeys(ctx, verification, rs.Namespace, r.client)
+}
+
+// validateWebhookSpec verify that the webhook Secret is present before creating ConfigMaps and Deployments.
+func (r *RepoSyncReconciler) validateWebhookSpec(ctx context.Context, rs *v1beta1.RepoSync, reconcilerName string) error {
+	if rs.Spec.Webhook == nil {
+		return nil
+	}
+	if err := validate.WebhookSpec(rs.Spec.Webhook, rs); err != nil {
+		return err
+	}
+	secretName := ReconcilerResourceName(reconcilerName, v1beta1.GetSecretName(rs.Spec.Webhook.SecretRef))
+	if errs := validation.IsDNS1123Subdomain(secretName); errs != nil {
+		return errors.Errorf("The managed secret name %q is invalid: %s. To fix it, update '.spec.webhook.secretRef.name'", secretName, strings.Join(errs, ", "))
+	}
+	return validateWebhookSecret(ctx, rs.Spec.Webhook, rs.Namespace, r.client)
+}
+
 func (r *RepoSyncReconciler) validateNamespaceName(namespaceName string) error {
 	if namespaceName == configsync.ControllerNamespace {
 		return fmt.Errorf("RepoSync objects are not allowed in the %s namespace", configsync.ControllerNamespace)
@@ -753,17 +1125,33 @@ func (r *RepoSyncReconciler) upsertRoleBinding(ctx context.Context, reconcilerRe
 		Namespace: rsRef.Namespace,
 		Name:      RepoSyncPermissionsName(),
 	}
+	if err := r.upsertSharedRoleBinding(ctx, reconcilerRef, rbRef); err != nil {
+		return rbRef, err
+	}
+	// The namespace reconcilers read the cluster metadata and the validation
+	// policies in the config-management-system namespace.
+	configRBRef := client.ObjectKey{
+		Namespace: configsync.ControllerNamespace,
+		Name:      RepoSyncConfigPermissionsName(),
+	}
+	return configRBRef, r.upsertSharedRoleBinding(ctx, reconcilerRef, configRBRef)
+}
+
+// upsertSharedRoleBinding adds the reconciler to the subjects of the RoleBinding
+// shared by the namespace reconcilers, which binds the ClusterRole of the same
+// name.
+func (r *RepoSyncReconciler) upsertSharedRoleBinding(ctx context.Context, reconcilerRef types.NamespacedName, rbRef client.ObjectKey) error {
 	childRB := &rbacv1.RoleBinding{}
 	childRB.Name = rbRef.Name
 	childRB.Namespace = rbRef.Namespace
 
 	op, err := controllerruntime.CreateOrUpdate(ctx, r.client, childRB, func() error {
-		childRB.RoleRef = rolereference(RepoSyncPermissionsName(), "ClusterRole")
+		childRB.RoleRef = rolereference(rbRef.Name, "ClusterRole")
 		childRB.Subjects = addSubject(childRB.Subjects, r.serviceAccountSubject(reconcilerRef))
 		return nil
 	})
 	if err != nil {
-		return rbRef, err
+		return err
 	}
 	if op != controllerutil.OperationResultNone {
 		r.log.Info("Managed object upsert successful",
@@ -771,7 +1159,56 @@ func (r *RepoSyncReconciler) upsertRoleBinding(ctx context.Context, reconcilerRe
 			logFieldKind, "RoleBinding",
 			logFieldOperation, op)
 	}
-	return rbRef, nil
+	return nil
+}
+
+// upsertDependencyRoles allows the reconciler to get the syncs listed in the
+// spec.dependsOn field of the RepoSync, with a Role and a RoleBinding in the
+// namespace of the dependencies, and deletes the ones no longer needed.
+func (r *RepoSyncReconciler) upsertDependencyRoles(ctx context.Context, rs *v1beta1.RepoSync, reconcilerRef types.NamespacedName, labelMap map[string]string) (client.ObjectKey, error) {
+	name := RepoSyncDependencyPermissionsName(reconcilerRef.Name)
+	rules := dependencyRules(rs.Namespace, rs.Spec.DependsOn)
+	for namespace, nsRules := range rules {
+		roleRef := client.ObjectKey{Namespace: namespace, Name: name}
+		role := &rbacv1.Role{}
+		role.Name = roleRef.Name
+		role.Namespace = roleRef.Namespace
+		op, err := controllerruntime.CreateOrUpdate(ctx, r.client, role, func() error {
+			r.addLabels(role, labelMap)
+			role.Rules = nsRules
+			return nil
+		})
+		if err != nil {
+			return roleRef, err
+		}
+		if op != controllerutil.OperationResultNone {
+			r.log.Info("Managed object upsert successful",
+				logFieldObject, roleRef.String(),
+				logFieldKind, "Role",
+				logFieldOperation, op)
+		}
+
+		rb := &rbacv1.RoleBinding{}
+		rb.Name = roleRef.Name
+		rb.Namespace = roleRef.Namespace
+		op, err = controllerruntime.CreateOrUpdate(ctx, r.client, rb, func() error {
+			r.addLabels(rb, labelMap)
+			rb.RoleRef = rolereference(name, "Role")
+			rb.Subjects = []rbacv1.Subject{r.serviceAccountSubject(reconcilerRef)}
+			return nil
+		})
+		if err != nil {
+			return roleRef, err
+		}
+		if op != controllerutil.OperationResultNone {
+			r.log.Info("Managed object upsert successful",
+				logFieldObject, roleRef.String(),
+				logFieldKind, "RoleBinding",
+				logFieldOperation, op)
+		}
+	}
+	rsRef := types.NamespacedName{Namespace: rs.Namespace, Name: rs.Name}
+	return r.deleteDependencyRoles(ctx, reconcilerRef, rsRef, rules)
 }
 
 func (r *RepoSyncReconciler) updateStatus(ctx context.Context, currentRS, rs *v1beta1.RepoSync) (bool, error) {
@@ -797,6 +1234,12 @@ func (r *RepoSyncReconciler) updateStatus(ctx context.Context, currentRS, rs *v1
 		return false, err
 	}
 
+	if stalled := reposync.GetCondition(rs.Status.Conditions, v1beta1.RepoSyncStalled); stalled != nil && stalled.Status == metav1.ConditionTrue {
+		if old := reposync.GetCondition(currentRS.Status.Conditions, v1beta1.RepoSyncStalled); old == nil || old.Status != metav1.ConditionTrue || old.Message != stalled.Message {
+			r.recorder.Eventf(rs, corev1.EventTypeWarning, EventReasonStalled, "%s: %s", stalled.Reason, stalled.Message)
+		}
+	}
+
 	// Register the latest ResourceVersion as reconciled.
 	r.setLastReconciled(core.ObjectNamespacedName(rs), resourceVersion)
 	return true, nil
@@ -813,20 +1256,24 @@ func (r *RepoSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RepoS
 		// Only inject the FWI credentials when the auth type is gcpserviceaccount and the membership info is available.
 		var auth configsync.AuthType
 		var gcpSAEmail string
+		var tokenExchange *v1beta1.TokenExchange
 		var secretRefName string
 		var caCertSecretRefName string
 		switch v1beta1.SourceType(rs.Spec.SourceType) {
 		case v1beta1.GitSource:
 			auth = rs.Spec.Auth
 			gcpSAEmail = rs.Spec.GCPServiceAccountEmail
+			tokenExchange = rs.Spec.Git.TokenExchange
 			secretRefName = v1beta1.GetSecretName(rs.Spec.SecretRef)
 			caCertSecretRefName = v1beta1.GetSecretName(rs.Spec.Git.CACertSecretRef)
 		case v1beta1.OciSource:
 			auth = rs.Spec.Oci.Auth
 			gcpSAEmail = rs.Spec.Oci.GCPServiceAccountEmail
+			tokenExchange = rs.Spec.Oci.TokenExchange
 		case v1beta1.HelmSource:
 			auth = rs.Spec.Helm.Auth
 			gcpSAEmail = rs.Spec.Helm.GCPServiceAccountEmail
+			tokenExchange = rs.Spec.Helm.TokenExchange
 			secretRefName = v1beta1.GetSecretName(rs.Spec.Helm.SecretRef)
 		}
 		injectFWICreds := useFWIAuth(auth, r.membership)
@@ -845,6 +1292,9 @@ func (r *RepoSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RepoS
 		// The Deployment object fetched from the API server has the field defined.
 		// Update DeprecatedServiceAccount to avoid discrepancy in equality check.
 		templateSpec.DeprecatedServiceAccount = reconcilerName
+		// Let the hydration-controller signal git-sync when a source change
+		// notification is received.
+		templateSpec.ShareProcessNamespace = webhookShareProcessNamespace(rs.Spec.Webhook, rs.Spec.SourceType)
 		// Mutate secret.secretname to secret reference specified in RepoSync CR.
 		// Secret reference is the name of the secret used by git-sync or helm-sync container to
 		// authenticate with the git or helm repository using the authorization method specified
@@ -854,6 +1304,29 @@ func (r *RepoSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RepoS
 			caCertSecretRefName = ReconcilerResourceName(reconcilerName, caCertSecretRefName)
 		}
 		templateSpec.Volumes = filterVolumes(templateSpec.Volumes, auth, secretName, caCertSecretRefName, rs.Spec.SourceType, r.membership)
+		// Project the Kubernetes Service Account token that is exchanged for
+		// credentials.
+		if auth == configsync.AuthK8sServiceAccount {
+			templateSpec.Volumes = append(templateSpec.Volumes, k8sSATokenVolume(tokenExchange))
+		}
+		// Project the Helm values files from the ConfigMaps copied to the
+		// config-management-system namespace.
+		if refs := helmValuesFileRefs(rs); len(refs) > 0 {
+			templateSpec.Volumes = append(templateSpec.Volumes, helmValuesVolume(rs.Spec.Helm.ValuesSources, func(name string) string {
+				return ReconcilerResourceName(reconcilerName, name)
+			}))
+		}
+		// Mount the trusted keys from the Secrets and ConfigMaps copied to the
+		// config-management-system namespace.
+		verification := sourceVerification(rs.Spec.SourceType, rs.Spec.Oci, reposync.GetHelmBase(rs.Spec.Helm))
+		templateSpec.Volumes = append(templateSpec.Volumes, verificationKeysVolumes(verification, func(name string) string {
+			return ReconcilerResourceName(reconcilerName, name)
+		})...)
+		// Mount the notification Secrets copied to the config-management-system
+		// namespace.
+		templateSpec.Volumes = append(templateSpec.Volumes, notificationSecretsVolumes(rs.Spec.Notifications, func(name string) string {
+			return ReconcilerResourceName(reconcilerName, name)
+		})...)
 		var updatedContainers []corev1.Container
 		// Mutate spec.Containers to update name, configmap references and volumemounts.
 		for _, container := range templateSpec.Containers {
@@ -861,6 +1334,10 @@ func (r *RepoSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RepoS
 			switch container.Name {
 			case reconcilermanager.Reconciler:
 				container.Env = append(container.Env, containerEnvs[container.Name]...)
+				if rs.Spec.Webhook != nil {
+					container.Ports = webhookContainerPorts(container.Ports)
+				}
+				container.VolumeMounts = append(container.VolumeMounts, notificationSecretsVolumeMounts(rs.Spec.Notifications)...)
 				mutateContainerResource(&container, rs.Spec.Override)
 			case reconcilermanager.HydrationController:
 				container.Env = append(container.Env, containerEnvs[container.Name]...)
@@ -876,6 +1353,10 @@ func (r *RepoSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RepoS
 					addContainer = false
 				} else {
 					container.Env = append(container.Env, containerEnvs[container.Name]...)
+					container.VolumeMounts = append(container.VolumeMounts, verificationKeysVolumeMounts(verification)...)
+					if auth == configsync.AuthK8sServiceAccount {
+						container.VolumeMounts = append(container.VolumeMounts, k8sSATokenVolumeMount())
+					}
 					injectFWICredsToContainer(&container, injectFWICreds)
 					mutateContainerResource(&container, rs.Spec.Override)
 				}
@@ -885,7 +1366,14 @@ func (r *RepoSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RepoS
 					addContainer = false
 				} else {
 					container.Env = append(container.Env, containerEnvs[container.Name]...)
+					if len(rs.Spec.Helm.GetValuesFileRefs()) > 0 {
+						container.VolumeMounts = append(container.VolumeMounts, helmValuesVolumeMount())
+					}
+					container.VolumeMounts = append(container.VolumeMounts, verificationKeysVolumeMounts(verification)...)
 					container.VolumeMounts = volumeMounts(rs.Spec.Helm.Auth, "", rs.Spec.SourceType, container.VolumeMounts)
+					if auth == configsync.AuthK8sServiceAccount {
+						container.VolumeMounts = append(container.VolumeMounts, k8sSATokenVolumeMount())
+					}
 					if authTypeToken(rs.Spec.Helm.Auth) {
 						container.Env = append(container.Env, helmSyncTokenAuthEnv(secretName)...)
 					}
@@ -910,6 +1398,18 @@ func (r *RepoSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RepoS
 					container.Env = append(container.Env, gitSyncHTTPSProxyEnv(secretName, keys)...)
 					mutateContainerResource(&container, rs.Spec.Override)
 				}
+			case reconcilermanager.AskpassSidecar:
+				// Don't add the askpass-sidecar container unless git-sync gets
+				// its credentials from it.
+				if !usesAskpassSidecar(rs.Spec.SourceType, auth) {
+					addContainer = false
+				} else {
+					container.Env = append(container.Env, containerEnvs[container.Name]...)
+					container.VolumeMounts = volumeMounts(auth, "", rs.Spec.SourceType, container.VolumeMounts)
+					if auth == configsync.AuthK8sServiceAccount {
+						container.VolumeMounts = append(container.VolumeMounts, k8sSATokenVolumeMount())
+					}
+				}
 			case metrics.OtelAgentName:
 				// The no-op case to avoid unknown container error after
 				// first-ever reconcile.
@@ -35,6 +35,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	v1 "kpt.dev/configsync/pkg/api/configmanagement/v1"
 	"kpt.dev/configsync/pkg/api/configsync"
@@ -276,8 +277,10 @@ func setupNSReconciler(t *testing.T, objs ...client.Object) (*syncerFake.Client,
 		testCluster,
 		filesystemPollingPeriod,
 		hydrationPollingPeriod,
+		0,
 		fakeClient,
 		fakeDynamicClient,
+		&record.FakeRecorder{},
 		controllerruntime.Log.WithName("controllers").WithName(configsync.RepoSyncKind),
 		fakeClient.Scheme(),
 	)
@@ -1846,7 +1849,16 @@ func TestMultipleRepoSyncs(t *testing.T) {
 		core.UID("1"), core.ResourceVersion("1"), core.Generation(1),
 	)
 	roleBinding1.Subjects = addSubjectByName(roleBinding1.Subjects, nsReconcilerName)
-	wantRoleBindings := map[core.ID]*rbacv1.RoleBinding{core.IDOf(roleBinding1): roleBinding1}
+	configRoleBinding := rolebinding(
+		RepoSyncConfigPermissionsName(),
+		core.Namespace(configsync.ControllerNamespace),
+	)
+	configRoleBinding.RoleRef.Name = RepoSyncConfigPermissionsName()
+	configRoleBinding.Subjects = addSubjectByName(configRoleBinding.Subjects, nsReconcilerName)
+	wantRoleBindings := map[core.ID]*rbacv1.RoleBinding{
+		core.IDOf(roleBinding1):      roleBinding1,
+		core.IDOf(configRoleBinding): configRoleBinding,
+	}
 
 	repoContainerEnv1 := testReconciler.populateContainerEnvs(ctx, rs1, nsReconcilerName)
 	repoDeployment1 := repoSyncDeployment(
@@ -1926,6 +1938,7 @@ func TestMultipleRepoSyncs(t *testing.T) {
 		core.UID("1"), core.ResourceVersion("1"), core.Generation(1),
 	)
 	roleBinding2.Subjects = addSubjectByName(roleBinding2.Subjects, nsReconcilerName2)
+	configRoleBinding.Subjects = addSubjectByName(configRoleBinding.Subjects, nsReconcilerName2)
 	wantRoleBindings[core.IDOf(roleBinding2)] = roleBinding2
 	validateRoleBindings(t, wantRoleBindings, fakeClient)
 	if t.Failed() {
@@ -1985,6 +1998,7 @@ func TestMultipleRepoSyncs(t *testing.T) {
 
 	// Add to roleBinding2.Subjects because rs3 and rs2 are in the same namespace.
 	roleBinding2.Subjects = addSubjectByName(roleBinding2.Subjects, nsReconcilerName3)
+	configRoleBinding.Subjects = addSubjectByName(configRoleBinding.Subjects, nsReconcilerName3)
 	validateRoleBindings(t, wantRoleBindings, fakeClient)
 	if t.Failed() {
 		t.FailNow()
@@ -2047,6 +2061,7 @@ func TestMultipleRepoSyncs(t *testing.T) {
 
 	// Add to roleBinding1.Subjects because rs1 and rs4 are in the same namespace.
 	roleBinding1.Subjects = addSubjectByName(roleBinding1.Subjects, nsReconcilerName4)
+	configRoleBinding.Subjects = addSubjectByName(configRoleBinding.Subjects, nsReconcilerName4)
 	validateRoleBindings(t, wantRoleBindings, fakeClient)
 	if t.Failed() {
 		t.FailNow()
@@ -2110,6 +2125,7 @@ func TestMultipleRepoSyncs(t *testing.T) {
 
 	// Add to roleBinding1.Subjects because rs1 and rs5 are in the same namespace.
 	roleBinding1.Subjects = addSubjectByName(roleBinding1.Subjects, nsReconcilerName5)
+	configRoleBinding.Subjects = addSubjectByName(configRoleBinding.Subjects, nsReconcilerName5)
 	validateRoleBindings(t, wantRoleBindings, fakeClient)
 	if t.Failed() {
 		t.FailNow()
@@ -2238,6 +2254,7 @@ func TestMultipleRepoSyncs(t *testing.T) {
 
 	// Subject for rs1 is removed from RoleBinding.Subjects
 	roleBinding1.Subjects = deleteSubjectByName(roleBinding1.Subjects, nsReconcilerName)
+	configRoleBinding.Subjects = deleteSubjectByName(configRoleBinding.Subjects, nsReconcilerName)
 	validateRoleBindings(t, wantRoleBindings, fakeClient)
 	validateRepoGeneratedResourcesDeleted(t, fakeClient, nsReconcilerName, v1beta1.GetSecretName(rs1.Spec.Git.SecretRef))
 	if t.Failed() {
@@ -2258,6 +2275,7 @@ func TestMultipleRepoSyncs(t *testing.T) {
 
 	// Subject for rs2 is removed from RoleBinding.Subjects
 	roleBinding2.Subjects = deleteSubjectByName(roleBinding2.Subjects, nsReconcilerName2)
+	configRoleBinding.Subjects = deleteSubjectByName(configRoleBinding.Subjects, nsReconcilerName2)
 	validateRoleBindings(t, wantRoleBindings, fakeClient)
 
 	validateRepoGeneratedResourcesDeleted(t, fakeClient, nsReconcilerName2, v1beta1.GetSecretName(rs2.Spec.Git.SecretRef))
@@ -2282,6 +2300,8 @@ func TestMultipleRepoSyncs(t *testing.T) {
 		t.Error(err)
 	}
 	delete(wantRoleBindings, core.IDOf(roleBinding2))
+	configRoleBinding.Subjects = deleteSubjectByName(configRoleBinding.Subjects, nsReconcilerName3)
+	validateRoleBindings(t, wantRoleBindings, fakeClient)
 	validateRepoGeneratedResourcesDeleted(t, fakeClient, nsReconcilerName3, v1beta1.GetSecretName(rs3.Spec.Git.SecretRef))
 	if t.Failed() {
 		t.FailNow()
@@ -2301,6 +2321,7 @@ func TestMultipleRepoSyncs(t *testing.T) {
 
 	// Subject for rs4 is removed from RoleBinding.Subjects
 	roleBinding1.Subjects = deleteSubjectByName(roleBinding1.Subjects, nsReconcilerName4)
+	configRoleBinding.Subjects = deleteSubjectByName(configRoleBinding.Subjects, nsReconcilerName4)
 	validateRoleBindings(t, wantRoleBindings, fakeClient)
 	validateRepoGeneratedResourcesDeleted(t, fakeClient, nsReconcilerName4, v1beta1.GetSecretName(rs4.Spec.Git.SecretRef))
 	if t.Failed() {
@@ -2323,6 +2344,11 @@ func TestMultipleRepoSyncs(t *testing.T) {
 	if err := validateResourceDeleted(core.IDOf(roleBinding1), fakeClient); err != nil {
 		t.Error(err)
 	}
+	// Verify the RoleBinding in the config-management-system namespace is
+	// deleted after all RepoSyncs are deleted.
+	if err := validateResourceDeleted(core.IDOf(configRoleBinding), fakeClient); err != nil {
+		t.Error(err)
+	}
 	validateRepoGeneratedResourcesDeleted(t, fakeClient, nsReconcilerName5, v1beta1.GetSecretName(rs5.Spec.Git.SecretRef))
 }
 
@@ -2606,6 +2632,24 @@ func TestMapObjectToRepoSync(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   fmt.Sprintf("A rolebinding from the %s namespace, same as %s", nsReconcilerKey.Namespace, RepoSyncConfigPermissionsName()),
+			object: fake.RoleBindingObject(core.Name(RepoSyncConfigPermissionsName()), core.Namespace(nsReconcilerKey.Namespace)),
+			want: []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "rs1",
+						Namespace: "ns1",
+					},
+				},
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "rs2",
+						Namespace: "ns2",
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -3450,6 +3494,94 @@ func TestPopulateRepoContainerEnvs(t *testing.T) {
 	}
 }
 
+func TestRepoSyncDependencyRoles(t *testing.T) {
+	// Mock out parseDeployment for testing.
+	parseDeployment = parsedDeployment
+
+	rs := repoSync(reposyncNs, reposyncName, reposyncRef(gitRevision), reposyncBranch(branch), reposyncSecretType(configsync.AuthSSH), reposyncSecretRef(reposyncSSHKey))
+	rs.Spec.DependsOn = []v1beta1.Dependency{
+		{Kind: configsync.RootSyncKind, Name: "root-sync"},
+		{Kind: configsync.RepoSyncKind, Name: "repo-sync", Namespace: "videoinfo"},
+		{Kind: configsync.RepoSyncKind, Name: "repo-sync"},
+	}
+	reqNamespacedName := namespacedName(rs.Name, rs.Namespace)
+	fakeClient, _, testReconciler := setupNSReconciler(t, rs, secretObj(t, reposyncSSHKey, configsync.AuthSSH, v1beta1.GitSource, core.Namespace(rs.Namespace)))
+
+	ctx := context.Background()
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error, got error: %q, want error: nil", err)
+	}
+
+	name := RepoSyncDependencyPermissionsName(nsReconcilerName)
+	validateDependencyRole := func(namespace string, rule rbacv1.PolicyRule) {
+		t.Helper()
+		role := &rbacv1.Role{}
+		err := fakeClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, role)
+		require.NoError(t, err, "Role[%s/%s] not found", namespace, name)
+		testutil.AssertEqual(t, []rbacv1.PolicyRule{rule}, role.Rules, "Role[%s/%s] unexpected rules", namespace, name)
+
+		rb := rolebinding(name, core.Namespace(namespace))
+		rb.Subjects = addSubjectByName(nil, nsReconcilerName)
+		validateRoleBindings(t, map[core.ID]*rbacv1.RoleBinding{core.IDOf(rb): rb}, fakeClient)
+	}
+	validateDependencyRoleDeleted := func(namespace string) {
+		t.Helper()
+		role := fake.RoleObject(core.Namespace(namespace), core.Name(name))
+		if err := validateResourceDeleted(core.IDOf(role), fakeClient); err != nil {
+			t.Error(err)
+		}
+		rb := rolebinding(name, core.Namespace(namespace))
+		if err := validateResourceDeleted(core.IDOf(rb), fakeClient); err != nil {
+			t.Error(err)
+		}
+	}
+
+	validateDependencyRole(configsync.ControllerNamespace, rbacv1.PolicyRule{
+		APIGroups:     []string{configsync.GroupName},
+		Resources:     []string{"rootsyncs"},
+		ResourceNames: []string{"root-sync"},
+		Verbs:         []string{"get"},
+	})
+	validateDependencyRole("videoinfo", rbacv1.PolicyRule{
+		APIGroups:     []string{configsync.GroupName},
+		Resources:     []string{"reposyncs"},
+		ResourceNames: []string{"repo-sync"},
+		Verbs:         []string{"get"},
+	})
+	// The reconciler can already get the RepoSyncs in its own namespace.
+	validateDependencyRoleDeleted(reposyncNs)
+	if t.Failed() {
+		t.FailNow()
+	}
+	t.Log("Roles and RoleBindings successfully created")
+
+	// Drop the dependency on the RepoSync in the videoinfo namespace.
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(rs), rs); err != nil {
+		t.Fatalf("failed to get the repo sync: %v", err)
+	}
+	rs.Spec.DependsOn = rs.Spec.DependsOn[:1]
+	if err := fakeClient.Update(ctx, rs); err != nil {
+		t.Fatalf("failed to update the repo sync request, got error: %v", err)
+	}
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error upon request update, got error: %q, want error: nil", err)
+	}
+	validateDependencyRoleDeleted("videoinfo")
+	if t.Failed() {
+		t.FailNow()
+	}
+	t.Log("Role and RoleBinding successfully deleted")
+
+	rs.ResourceVersion = "" // Skip ResourceVersion validation
+	if err := fakeClient.Delete(ctx, rs); err != nil {
+		t.Fatalf("failed to delete the repo sync request, got error: %v, want error: nil", err)
+	}
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error upon request delete, got error: %q, want error: nil", err)
+	}
+	validateDependencyRoleDeleted(configsync.ControllerNamespace)
+}
+
 func validateRepoSyncStatus(t *testing.T, want *v1beta1.RepoSync, fakeClient *syncerFake.Client) {
 	t.Helper()
 
@@ -34,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/api/configmanagement"
 	"kpt.dev/configsync/pkg/api/configsync"
@@ -78,16 +79,18 @@ type RootSyncReconciler struct {
 }
 
 // NewRootSyncReconciler returns a new RootSyncReconciler.
-func NewRootSyncReconciler(clusterName string, reconcilerPollingPeriod, hydrationPollingPeriod time.Duration, client client.Client, dynamicClient dynamic.Interface, log logr.Logger, scheme *runtime.Scheme) *RootSyncReconciler {
+func NewRootSyncReconciler(clusterName string, reconcilerPollingPeriod, hydrationPollingPeriod time.Duration, prometheusPort int, client client.Client, dynamicClient dynamic.Interface, recorder record.EventRecorder, log logr.Logger, scheme *runtime.Scheme) *RootSyncReconciler {
 	return &RootSyncReconciler{
 		reconcilerBase: reconcilerBase{
 			clusterName:             clusterName,
 			client:                  client,
 			dynamicClient:           dynamicClient,
+			recorder:                recorder,
 			log:                     log,
 			scheme:                  scheme,
 			reconcilerPollingPeriod: reconcilerPollingPeriod,
 			hydrationPollingPeriod:  hydrationPollingPeriod,
+			prometheusPort:          prometheusPort,
 			syncKind:                configsync.RootSyncKind,
 		},
 	}
@@ -186,6 +189,38 @@ func (r *RootSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 		return controllerruntime.Result{}, updateErr
 	}
 
+	// Promote the commit synced by the sync to follow, if any.
+	var requeueAfter time.Duration
+	if rs.Spec.Promotion != nil {
+		rs.Status.Promotion, requeueAfter, err = promote(ctx, r.client, rs.Spec.Promotion, rs.Namespace, rs.Status.Promotion, time.Now())
+		if err != nil {
+			log.Error(err, "Promotion failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+			rootsync.SetStalled(rs, "Promotion", err)
+			// Get errors should always trigger retry (return error),
+			// even if status update is successful.
+			_, updateErr := r.updateStatus(ctx, currentRS, rs)
+			if updateErr != nil {
+				log.Error(updateErr, "Object status update failed",
+					logFieldObject, rsRef.String(),
+					logFieldKind, r.syncKind)
+			}
+			// Use the get error for metric tagging.
+			metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+			return controllerruntime.Result{}, errors.Wrap(err, "Promotion reconcile failed")
+		}
+		if rs.Status.Promotion.Commit == "" {
+			// Don't sync anything until a commit is promoted.
+			rootsync.SetReconciling(rs, "Promotion", promotionMessage(rs.Spec.Promotion, rs.Status.Promotion))
+			_, updateErr := r.updateStatus(ctx, currentRS, rs)
+			metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(updateErr), start)
+			return controllerruntime.Result{RequeueAfter: requeueAfter}, updateErr
+		}
+	} else {
+		rs.Status.Promotion = nil
+	}
+
 	labelMap := map[string]string{
 		metadata.SyncNamespaceLabel: rs.Namespace,
 		metadata.SyncNameLabel:      rs.Name,
@@ -245,7 +280,32 @@ func (r *RootSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 		return controllerruntime.Result{}, errors.Wrap(err, "ClusterRoleBinding reconcile failed")
 	}
 
-	containerEnvs := r.populateContainerEnvs(ctx, rs, reconcilerRef.Name)
+	// Overwrite reconciler webhook Service.
+	if svcRef, err := r.reconcileWebhookService(ctx, rs.Spec.Webhook, reconcilerRef, labelMap, owRefs); err != nil {
+		log.Error(err, "Managed object upsert failed",
+			logFieldObject, svcRef.String(),
+			logFieldKind, "Service")
+		rootsync.SetStalled(rs, "Service", err)
+		// Upsert errors should always trigger retry (return error),
+		// even if status update is successful.
+		_, updateErr := r.updateStatus(ctx, currentRS, rs)
+		if updateErr != nil {
+			log.Error(updateErr, "Object status update failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+		}
+		// Use the upsert error for metric tagging.
+		metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+		return controllerruntime.Result{}, errors.Wrap(err, "Service reconcile failed")
+	}
+
+	// Sync the promoted commit instead of the declared revision.
+	deployedRS := rs
+	if rs.Spec.Promotion != nil {
+		deployedRS = rs.DeepCopy()
+		pinSource(deployedRS.Spec.SourceType, deployedRS.Spec.Git, deployedRS.Spec.Oci, rs.Status.Promotion.Commit)
+	}
+	containerEnvs := r.populateContainerEnvs(ctx, deployedRS, reconcilerRef.Name)
 	mut := r.mutationsFor(ctx, rs, containerEnvs)
 
 	// Upsert Root reconciler deployment.
@@ -348,7 +408,7 @@ func (r *RootSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 			logFieldObject, rsRef.String(),
 			logFieldKind, r.syncKind)
 	}
-	return controllerruntime.Result{}, nil
+	return controllerruntime.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // SetupWithManager registers RootSync controller with reconciler-manager.
@@ -363,6 +423,42 @@ func (r *RootSyncReconciler) SetupWithManager(mgr controllerruntime.Manager, wat
 	}); err != nil {
 		return err
 	}
+	// Index the `helmValuesFileRefField` field, so that we will be able to lookup RootSync be a referenced ConfigMap name.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.RootSync{}, helmValuesFileRefField, func(rawObj client.Object) []string {
+		rs := rawObj.(*v1beta1.RootSync)
+		if rs.Spec.Helm == nil {
+			return nil
+		}
+		var names []string
+		for _, ref := range rs.Spec.Helm.GetValuesFileRefs() {
+			names = append(names, ref.Name)
+		}
+		return names
+	}); err != nil {
+		return err
+	}
+	// Index the `verificationKeyRefField` field, so that we will be able to lookup RootSync be a referenced Secret or ConfigMap name.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.RootSync{}, verificationKeyRefField, func(rawObj client.Object) []string {
+		rs := rawObj.(*v1beta1.RootSync)
+		verification := sourceVerification(rs.Spec.SourceType, rs.Spec.Oci, rootsync.GetHelmBase(rs.Spec.Helm))
+		if verification == nil {
+			return nil
+		}
+		var names []string
+		for _, ref := range verification.PublicKeys {
+			names = append(names, ref.Name)
+		}
+		return names
+	}); err != nil {
+		return err
+	}
+	// Index the `notificationSecretRefField` field, so that we will be able to lookup RootSync be a referenced Secret name.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.RootSync{}, notificationSecretRefField, func(rawObj client.Object) []string {
+		rs := rawObj.(*v1beta1.RootSync)
+		return notificationSecretNames(rs.Spec.Notifications)
+	}); err != nil {
+		return err
+	}
 
 	controllerBuilder := controllerruntime.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{
@@ -372,6 +468,9 @@ func (r *RootSyncReconciler) SetupWithManager(mgr controllerruntime.Manager, wat
 		Owns(&appsv1.Deployment{}).
 		Watches(&source.Kind{Type: &corev1.Secret{}},
 			handler.EnqueueRequestsFromMapFunc(r.mapSecretToRootSyncs),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapConfigMapToRootSyncs),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}))
 
 	if watchFleetMembership {
@@ -430,7 +529,8 @@ func (r *RootSyncReconciler) requeueAllRootSyncs() []reconcile.Request {
 }
 
 // mapSecretToRootSyncs define a mapping from the Secret object to its attached
-// RootSync objects via the `spec.git.secretRef.name` field .
+// RootSync objects via the `spec.git.secretRef.name` field or the `publicKeys`
+// field of the source verification.
 // The update to the Secret object will trigger a reconciliation of the RootSync objects.
 func (r *RootSyncReconciler) mapSecretToRootSyncs(secret client.Object) []reconcile.Request {
 	// Ignore secret in other namespaces because the RootSync's git secret MUST
@@ -445,13 +545,18 @@ func (r *RootSyncReconciler) mapSecretToRootSyncs(secret client.Object) []reconc
 	}
 
 	attachedRootSyncs := &v1beta1.RootSyncList{}
-	listOps := &client.ListOptions{
-		FieldSelector: fields.OneTermEqualSelector(gitSecretRefField, secret.GetName()),
-		Namespace:     secret.GetNamespace(),
-	}
-	if err := r.client.List(context.Background(), attachedRootSyncs, listOps); err != nil {
-		klog.Errorf("failed to list attached RootSyncs for secret (name: %s, namespace: %s): %v", secret.GetName(), secret.GetNamespace(), err)
-		return nil
+	secretFields := []string{gitSecretRefField, verificationKeyRefField, notificationSecretRefField}
+	for _, secretField := range secretFields {
+		listOps := &client.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector(secretField, secret.GetName()),
+			Namespace:     secret.GetNamespace(),
+		}
+		fetchedRootSyncs := &v1beta1.RootSyncList{}
+		if err := r.client.List(context.Background(), fetchedRootSyncs, listOps); err != nil {
+			klog.Errorf("failed to list attached RootSyncs for secret (name: %s, namespace: %s): %v", secret.GetName(), secret.GetNamespace(), err)
+			return nil
+		}
+		attachedRootSyncs.Items = append(attachedRootSyncs.Items, fetchedRootSyncs.Items...)
 	}
 
 	requests := make([]reconcile.Request, len(attachedRootSyncs.Items))
@@ -471,6 +576,46 @@ func (r *RootSyncReconciler) mapSecretToRootSyncs(secret client.Object) []reconc
 	return requests
 }
 
+// mapConfigMapToRootSyncs define a mapping from the ConfigMap object to its
+// attached RootSync objects via the `spec.helm.valuesSources` field or the
+// `publicKeys` field of the source verification.
+// The update to the ConfigMap object will trigger a reconciliation of the RootSync objects.
+func (r *RootSyncReconciler) mapConfigMapToRootSyncs(cm client.Object) []reconcile.Request {
+	// Ignore ConfigMaps in other namespaces because the RootSync's helm values
+	// ConfigMaps MUST exist in the config-management-system namespace.
+	if cm.GetNamespace() != configsync.ControllerNamespace {
+		return nil
+	}
+
+	attachedRootSyncs := &v1beta1.RootSyncList{}
+	configMapFields := []string{helmValuesFileRefField, verificationKeyRefField}
+	for _, configMapField := range configMapFields {
+		listOps := &client.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector(configMapField, cm.GetName()),
+			Namespace:     cm.GetNamespace(),
+		}
+		fetchedRootSyncs := &v1beta1.RootSyncList{}
+		if err := r.client.List(context.Background(), fetchedRootSyncs, listOps); err != nil {
+			klog.Errorf("failed to list attached RootSyncs for ConfigMap (name: %s, namespace: %s): %v", cm.GetName(), cm.GetNamespace(), err)
+			return nil
+		}
+		attachedRootSyncs.Items = append(attachedRootSyncs.Items, fetchedRootSyncs.Items...)
+	}
+
+	requests := make([]reconcile.Request, len(attachedRootSyncs.Items))
+	attachedRSNames := make([]string, len(attachedRootSyncs.Items))
+	for i, rs := range attachedRootSyncs.Items {
+		attachedRSNames[i] = rs.GetName()
+		requests[i] = reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&rs),
+		}
+	}
+	if len(requests) > 0 {
+		klog.Infof("Changes to ConfigMap (name: %s, namespace: %s) triggers a reconciliation for the RootSync objects: %s", cm.GetName(), cm.GetNamespace(), strings.Join(attachedRSNames, ", "))
+	}
+	return requests
+}
+
 func (r *RootSyncReconciler) populateContainerEnvs(ctx context.Context, rs *v1beta1.RootSync, reconcilerName string) map[string][]corev1.EnvVar {
 	result := map[string][]corev1.EnvVar{
 		reconcilermanager.HydrationController: hydrationEnvs(rs.Spec.SourceType, rs.Spec.Git, rs.Spec.Oci, declared.RootReconciler, reconcilerName, r.hydrationPollingPeriod.String()),
@@ -489,22 +634,101 @@ func (r *RootSyncReconciler) populateContainerEnvs(ctx context.Context, rs *v1be
 			noSSLVerify:     rs.Spec.Git.NoSSLVerify,
 			caCertSecretRef: v1beta1.GetSecretName(rs.Spec.Git.CACertSecretRef),
 		})
+		if usesAskpassSidecar(rs.Spec.SourceType, rs.Spec.Git.Auth) {
+			result[reconcilermanager.AskpassSidecar] = askpassSidecarEnvs(rs.Spec.Git.Auth, rs.Spec.Git.TokenExchange)
+		}
 	case v1beta1.OciSource:
-		result[reconcilermanager.OciSync] = ociSyncEnvs(rs.Spec.Oci.Image, rs.Spec.Oci.Auth, v1beta1.GetPeriodSecs(rs.Spec.Oci.Period))
+		result[reconcilermanager.OciSync] = ociSyncEnvs(rs.Spec.Oci.Image, rs.Spec.Oci.Auth, v1beta1.GetPeriodSecs(rs.Spec.Oci.Period), rs.Spec.Oci.Verification)
+		if rs.Spec.Oci.Auth == configsync.AuthK8sServiceAccount {
+			result[reconcilermanager.OciSync] = append(result[reconcilermanager.OciSync], tokenExchangeEnvs(rs.Spec.Oci.TokenExchange)...)
+		}
 	case v1beta1.HelmSource:
 		result[reconcilermanager.HelmSync] = helmSyncEnvs(&rs.Spec.Helm.HelmBase, rs.Spec.Helm.Namespace)
+		if rs.Spec.Helm.Auth == configsync.AuthK8sServiceAccount {
+			result[reconcilermanager.HelmSync] = append(result[reconcilermanager.HelmSync], tokenExchangeEnvs(rs.Spec.Helm.TokenExchange)...)
+		}
+	}
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], syncWindowEnvs(rs.Spec.Suspend, rs.Spec.SyncWindows)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], driftPolicyEnvs(rs.Spec.SafeOverride().DriftPolicy)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], autoRollbackEnvs(rs.Spec.SafeOverride().AutoRollback)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], dryRunEnvs(rs.Spec.SafeOverride().DryRun)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], dependsOnEnvs(rs.Namespace, rs.Spec.DependsOn)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], notificationEnvs(rs.Spec.Notifications)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], prometheusEnvs(r.prometheusPort)...)
+	result[reconcilermanager.HydrationController] = append(result[reconcilermanager.HydrationController], renderingEnvs(rs.Spec.SafeOverride().Rendering)...)
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], sourcesEnvs(rs.Spec.Sources)...)
+	for _, src := range rs.Spec.Sources {
+		result[sourceContainerName(src)] = sourceSyncEnvs(ctx, src)
+	}
+	if rs.Spec.Webhook != nil {
+		addWebhookEnvs(result, rs.Spec.SourceType, v1beta1.GetSecretName(rs.Spec.Webhook.SecretRef))
 	}
 	return result
 }
 
 func (r *RootSyncReconciler) validateSpec(ctx context.Context, rs *v1beta1.RootSync) error {
+	if err := r.validateSourceSpec(ctx, rs); err != nil {
+		return err
+	}
+	if err := r.validateVerificationSpec(ctx, rs); err != nil {
+		return err
+	}
+	if err := r.validateWebhookSpec(ctx, rs); err != nil {
+		return err
+	}
+	if err := validate.SyncWindowsSpec(rs.Spec.SyncWindows, rs); err != nil {
+		return err
+	}
+	if err := validate.PromotionSpec(rs.Spec.Promotion, rs.Spec.SourceType, rs); err != nil {
+		return err
+	}
+	if err := validate.DependsOnSpec(rs.Spec.DependsOn, rs); err != nil {
+		return err
+	}
+	self := v1beta1.Dependency{Kind: configsync.RootSyncKind, Name: rs.Name, Namespace: rs.Namespace}
+	if err := validateDependencyCycle(ctx, r.client, self, rs.Spec.DependsOn); err != nil {
+		return err
+	}
+	if err := validate.NotificationsSpec(rs.Spec.Notifications, rs); err != nil {
+		return err
+	}
+	if err := validateNotificationSecrets(ctx, rs.Spec.Notifications, rs.Namespace, r.client); err != nil {
+		return err
+	}
+	return r.validateSourcesSpec(ctx, rs)
+}
+
+// validateSourcesSpec verify that the Secrets of the additional sources are
+// present before creating ConfigMaps and Deployments.
+func (r *RootSyncReconciler) validateSourcesSpec(ctx context.Context, rs *v1beta1.RootSync) error {
+	if err := validate.SourcesSpec(rs.Spec.Sources, rs.Spec.SourceFormat, rs); err != nil {
+		return err
+	}
+	for _, src := range rs.Spec.Sources {
+		var secretRef *v1beta1.SecretReference
+		switch {
+		case src.Git != nil && authTypeToken(src.Git.Auth):
+			secretRef = src.Git.SecretRef
+		case src.Helm != nil && authTypeToken(src.Helm.Auth):
+			secretRef = src.Helm.SecretRef
+		default:
+			continue
+		}
+		if _, err := validateSecretExist(ctx, v1beta1.GetSecretName(secretRef), rs.Namespace, r.client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RootSyncReconciler) validateSourceSpec(ctx context.Context, rs *v1beta1.RootSync) error {
 	switch v1beta1.SourceType(rs.Spec.SourceType) {
 	case v1beta1.GitSource:
 		return r.validateGitSpec(ctx, rs)
 	case v1beta1.OciSource:
 		return validate.OciSpec(rs.Spec.Oci, rs)
 	case v1beta1.HelmSource:
-		return validate.HelmSpec(rootsync.GetHelmBase(rs.Spec.Helm), rs)
+		return r.validateHelmSpec(ctx, rs)
 	default:
 		return validate.InvalidSourceType(rs)
 	}
@@ -517,6 +741,31 @@ func (r *RootSyncReconciler) validateGitSpec(ctx context.Context, rs *v1beta1.Ro
 	return r.validateRootSecret(ctx, rs)
 }
 
+// validateVerificationSpec verify that the Secrets and ConfigMaps holding the
+// trusted keys are present before creating ConfigMaps and Deployments.
+func (r *RootSyncReconciler) validateVerificationSpec(ctx context.Context, rs *v1beta1.RootSync) error {
+	verification := sourceVerification(rs.Spec.SourceType, rs.Spec.Oci, rootsync.GetHelmBase(rs.Spec.Helm))
+	return validateVerificationKeys(ctx, verification, rs.Namespace, r.client)
+}
+
+// validateWebhookSpec verify that the webhook Secret is present before creating ConfigMaps and Deployments.
+func (r *RootSyncReconciler) validateWebhookSpec(ctx context.Context, rs *v1beta1.RootSync) error {
+	if rs.Spec.Webhook == nil {
+		return nil
+	}
+	if err := validate.WebhookSpec(rs.Spec.Webhook, rs); err != nil {
+		return err
+	}
+	return validateWebhookSecret(ctx, rs.Spec.Webhook, rs.Namespace, r.client)
+}
+
+func (r *RootSyncReconciler) validateHelmSpec(ctx context.Context, rs *v1beta1.RootSync) error {
+	if err := validate.HelmSpec(rootsync.GetHelmBase(rs.Spec.Helm), rs); err != nil {
+		return err
+	}
+	return validateHelmValuesConfigMaps(ctx, rs.Spec.Helm.GetValuesFileRefs(), rs.Namespace, r.client)
+}
+
 func (r *RootSyncReconciler) validateNamespaceName(namespaceName string) error {
 	if namespaceName != configsync.ControllerNamespace {
 		return fmt.Errorf("RootSync objects are only allowed in the %s namespace, not in %s", configsync.ControllerNamespace, namespaceName)
@@ -586,6 +835,12 @@ func (r *RootSyncReconciler) updateStatus(ctx context.Context, currentRS, rs *v1
 		return false, err
 	}
 
+	if stalled := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncStalled); stalled != nil && stalled.Status == metav1.ConditionTrue {
+		if old := rootsync.GetCondition(currentRS.Status.Conditions, v1beta1.RootSyncStalled); old == nil || old.Status != metav1.ConditionTrue || old.Message != stalled.Message {
+			r.recorder.Eventf(rs, corev1.EventTypeWarning, EventReasonStalled, "%s: %s", stalled.Reason, stalled.Message)
+		}
+	}
+
 	// Register the latest ResourceVersion as reconciled.
 	r.setLastReconciled(core.ObjectNamespacedName(rs), resourceVersion)
 	return true, nil
@@ -611,20 +866,24 @@ func (r *RootSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RootS
 		// Only inject the FWI credentials when the auth type is gcpserviceaccount and the membership info is available.
 		var auth configsync.AuthType
 		var gcpSAEmail string
+		var tokenExchange *v1beta1.TokenExchange
 		var secretRefName string
 		var caCertSecretRefName string
 		switch v1beta1.SourceType(rs.Spec.SourceType) {
 		case v1beta1.GitSource:
 			auth = rs.Spec.Auth
 			gcpSAEmail = rs.Spec.GCPServiceAccountEmail
+			tokenExchange = rs.Spec.Git.TokenExchange
 			secretRefName = v1beta1.GetSecretName(rs.Spec.SecretRef)
 			caCertSecretRefName = v1beta1.GetSecretName(rs.Spec.Git.CACertSecretRef)
 		case v1beta1.OciSource:
 			auth = rs.Spec.Oci.Auth
 			gcpSAEmail = rs.Spec.Oci.GCPServiceAccountEmail
+			tokenExchange = rs.Spec.Oci.TokenExchange
 		case v1beta1.HelmSource:
 			auth = rs.Spec.Helm.Auth
 			gcpSAEmail = rs.Spec.Helm.GCPServiceAccountEmail
+			tokenExchange = rs.Spec.Helm.TokenExchange
 			secretRefName = v1beta1.GetSecretName(rs.Spec.Helm.SecretRef)
 		}
 		injectFWICreds := useFWIAuth(auth, r.membership)
@@ -644,20 +903,51 @@ func (r *RootSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RootS
 		// The Deployment object fetched from the API server has the field defined.
 		// Update DeprecatedServiceAccount to avoid discrepancy in equality check.
 		templateSpec.DeprecatedServiceAccount = reconcilerName
+		// Let the hydration-controller signal git-sync when a source change
+		// notification is received.
+		templateSpec.ShareProcessNamespace = webhookShareProcessNamespace(rs.Spec.Webhook, rs.Spec.SourceType)
 
 		// Mutate secret.secretname to secret reference specified in RootSync CR.
 		// Secret reference is the name of the secret used by git-sync or helm-sync container to
 		// authenticate with the git or helm repository using the authorization method specified
 		// in the RootSync CR.
 		templateSpec.Volumes = filterVolumes(templateSpec.Volumes, auth, secretRefName, caCertSecretRefName, rs.Spec.SourceType, r.membership)
+		// Project the Kubernetes Service Account token that is exchanged for
+		// credentials.
+		if auth == configsync.AuthK8sServiceAccount {
+			templateSpec.Volumes = append(templateSpec.Volumes, k8sSATokenVolume(tokenExchange))
+		}
+		// Project the Helm values files from the referenced ConfigMaps, which
+		// exist in the same namespace as the reconciler.
+		if v1beta1.SourceType(rs.Spec.SourceType) == v1beta1.HelmSource && len(rs.Spec.Helm.GetValuesFileRefs()) > 0 {
+			templateSpec.Volumes = append(templateSpec.Volumes, helmValuesVolume(rs.Spec.Helm.ValuesSources, func(name string) string {
+				return name
+			}))
+		}
+		// Mount the trusted keys from the referenced Secrets and ConfigMaps.
+		verification := sourceVerification(rs.Spec.SourceType, rs.Spec.Oci, rootsync.GetHelmBase(rs.Spec.Helm))
+		templateSpec.Volumes = append(templateSpec.Volumes, verificationKeysVolumes(verification, func(name string) string {
+			return name
+		})...)
+		// Mount the referenced notification Secrets.
+		templateSpec.Volumes = append(templateSpec.Volumes, notificationSecretsVolumes(rs.Spec.Notifications, func(name string) string {
+			return name
+		})...)
 
 		var updatedContainers []corev1.Container
+		// The sidecar containers of the additional sources are copies of the
+		// sidecar containers in the template.
+		sourceTemplates := make(map[string]corev1.Container)
 
 		for _, container := range templateSpec.Containers {
 			addContainer := true
 			switch container.Name {
 			case reconcilermanager.Reconciler:
 				container.Env = append(container.Env, containerEnvs[container.Name]...)
+				if rs.Spec.Webhook != nil {
+					container.Ports = webhookContainerPorts(container.Ports)
+				}
+				container.VolumeMounts = append(container.VolumeMounts, notificationSecretsVolumeMounts(rs.Spec.Notifications)...)
 				mutateContainerResource(&container, rs.Spec.Override)
 			case reconcilermanager.HydrationController:
 				container.Env = append(container.Env, containerEnvs[container.Name]...)
@@ -668,21 +958,34 @@ func (r *RootSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RootS
 				}
 				mutateContainerResource(&container, rs.Spec.Override)
 			case reconcilermanager.OciSync:
+				sourceTemplates[container.Name] = *container.DeepCopy()
 				// Don't add the oci-sync container when sourceType is NOT oci.
 				if v1beta1.SourceType(rs.Spec.SourceType) != v1beta1.OciSource {
 					addContainer = false
 				} else {
 					container.Env = append(container.Env, containerEnvs[container.Name]...)
+					container.VolumeMounts = append(container.VolumeMounts, verificationKeysVolumeMounts(verification)...)
+					if auth == configsync.AuthK8sServiceAccount {
+						container.VolumeMounts = append(container.VolumeMounts, k8sSATokenVolumeMount())
+					}
 					injectFWICredsToContainer(&container, injectFWICreds)
 					mutateContainerResource(&container, rs.Spec.Override)
 				}
 			case reconcilermanager.HelmSync:
+				sourceTemplates[container.Name] = *container.DeepCopy()
 				// Don't add the helm-sync container when sourceType is NOT helm.
 				if v1beta1.SourceType(rs.Spec.SourceType) != v1beta1.HelmSource {
 					addContainer = false
 				} else {
 					container.Env = append(container.Env, containerEnvs[container.Name]...)
+					if len(rs.Spec.Helm.GetValuesFileRefs()) > 0 {
+						container.VolumeMounts = append(container.VolumeMounts, helmValuesVolumeMount())
+					}
+					container.VolumeMounts = append(container.VolumeMounts, verificationKeysVolumeMounts(verification)...)
 					container.VolumeMounts = volumeMounts(rs.Spec.Helm.Auth, "", rs.Spec.SourceType, container.VolumeMounts)
+					if auth == configsync.AuthK8sServiceAccount {
+						container.VolumeMounts = append(container.VolumeMounts, k8sSATokenVolumeMount())
+					}
 					if authTypeToken(rs.Spec.Helm.Auth) {
 						container.Env = append(container.Env, helmSyncTokenAuthEnv(secretRefName)...)
 					}
@@ -690,6 +993,7 @@ func (r *RootSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RootS
 					mutateContainerResource(&container, rs.Spec.Override)
 				}
 			case reconcilermanager.GitSync:
+				sourceTemplates[container.Name] = *container.DeepCopy()
 				// Don't add the git-sync container when sourceType is NOT git.
 				if v1beta1.SourceType(rs.Spec.SourceType) != v1beta1.GitSource {
 					addContainer = false
@@ -708,6 +1012,18 @@ func (r *RootSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RootS
 					container.Env = append(container.Env, gitSyncHTTPSProxyEnv(secretName, keys)...)
 					mutateContainerResource(&container, rs.Spec.Override)
 				}
+			case reconcilermanager.AskpassSidecar:
+				// Don't add the askpass-sidecar container unless git-sync gets
+				// its credentials from it.
+				if !usesAskpassSidecar(rs.Spec.SourceType, auth) {
+					addContainer = false
+				} else {
+					container.Env = append(container.Env, containerEnvs[container.Name]...)
+					container.VolumeMounts = volumeMounts(auth, "", rs.Spec.SourceType, container.VolumeMounts)
+					if auth == configsync.AuthK8sServiceAccount {
+						container.VolumeMounts = append(container.VolumeMounts, k8sSATokenVolumeMount())
+					}
+				}
 			case metrics.OtelAgentName:
 				// The no-op case to avoid unknown container error after
 				// first-ever reconcile.
@@ -728,6 +1044,15 @@ func (r *RootSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RootS
 			updatedContainers = append(updatedContainers, gceNodeAskPassSidecar(gcpSAEmail, injectFWICreds))
 		}
 
+		for _, src := range rs.Spec.Sources {
+			container, err := sourceContainer(sourceTemplates, src, containerEnvs)
+			if err != nil {
+				return err
+			}
+			mutateContainerResource(&container, rs.Spec.Override)
+			updatedContainers = append(updatedContainers, container)
+		}
+
 		templateSpec.Containers = updatedContainers
 		return nil
 	}
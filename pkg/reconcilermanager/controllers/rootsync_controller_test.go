@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	v1 "kpt.dev/configsync/pkg/api/configmanagement/v1"
 	"kpt.dev/configsync/pkg/api/configsync"
@@ -118,8 +119,10 @@ func setupRootReconciler(t *testing.T, objs ...client.Object) (*syncerFake.Clien
 		testCluster,
 		filesystemPollingPeriod,
 		hydrationPollingPeriod,
+		0,
 		fakeClient,
 		fakeDynamicClient,
+		&record.FakeRecorder{},
 		controllerruntime.Log.WithName("controllers").WithName("RootSync"),
 		fakeClient.Scheme(),
 	)
@@ -2853,6 +2856,74 @@ func TestRootSyncReconcileStaleClientCache(t *testing.T) {
 	require.Contains(t, reconcilingCondition.Message, "RootSyncs must specify spec.git when spec.sourceType is \"git\"", "unexpected Stalled condition message")
 }
 
+func TestRootSyncDependencyCycle(t *testing.T) {
+	// Mock out parseDeployment for testing.
+	parseDeployment = parsedDeployment
+
+	rs := rootSyncWithOCI(rootsyncName, rootsyncOCIAuthType(configsync.AuthNone))
+	rs.Spec.DependsOn = []v1beta1.Dependency{{Kind: configsync.RootSyncKind, Name: "other"}}
+	other := fake.RootSyncObjectV1Beta1("other")
+	other.Spec.DependsOn = []v1beta1.Dependency{{Kind: configsync.RootSyncKind, Name: rootsyncName}}
+	reqNamespacedName := namespacedName(rs.Name, rs.Namespace)
+	fakeClient, _, testReconciler := setupRootReconciler(t, rs, other)
+	ctx := context.Background()
+
+	_, err := testReconciler.Reconcile(ctx, reqNamespacedName)
+	require.NoError(t, err, "unexpected Reconcile error")
+
+	// Expect Stalled condition with True status, because the dependencies form a cycle
+	rs = fake.RootSyncObjectV1Beta1(rootsyncName)
+	err = fakeClient.Get(ctx, core.ObjectNamespacedName(rs), rs)
+	require.NoError(t, err, "unexpected Get error")
+	stalledCondition := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncStalled)
+	require.NotNilf(t, stalledCondition, "status: %+v", rs.Status)
+	require.Equal(t, stalledCondition.Status, metav1.ConditionTrue, "unexpected Stalled condition status")
+	require.Contains(t, stalledCondition.Message, "KNV1061: RootSyncs and RepoSyncs must not depend on each other in a cycle", "unexpected Stalled condition message")
+	require.Contains(t, stalledCondition.Message, "RootSync config-management-system/my-root-sync -> RootSync config-management-system/other -> RootSync config-management-system/my-root-sync", "unexpected Stalled condition message")
+}
+
+func TestRootSyncStalledEvents(t *testing.T) {
+	rs := fake.RootSyncObjectV1Beta1(rootsyncName)
+	reqNamespacedName := namespacedName(rs.Name, rs.Namespace)
+	fakeClient, _, testReconciler := setupRootReconciler(t, rs)
+	recorder := record.NewFakeRecorder(10)
+	testReconciler.recorder = recorder
+	ctx := context.Background()
+
+	// The first Stalled transition should emit a Warning event
+	_, err := testReconciler.Reconcile(ctx, reqNamespacedName)
+	require.NoError(t, err, "unexpected Reconcile error")
+	require.Len(t, recorder.Events, 1, "unexpected number of events")
+	event := <-recorder.Events
+	require.Contains(t, event, "Warning Stalled Validation: KNV1061: RootSyncs must specify spec.sourceType", "unexpected event")
+
+	// A spec update with a different validation error should emit a new event
+	rs = fake.RootSyncObjectV1Beta1(rootsyncName)
+	err = fakeClient.Get(ctx, core.ObjectNamespacedName(rs), rs)
+	require.NoError(t, err, "unexpected Get error")
+	rs.Spec.SourceType = string(v1beta1.GitSource)
+	err = fakeClient.Update(ctx, rs)
+	require.NoError(t, err, "unexpected Update error")
+
+	_, err = testReconciler.Reconcile(ctx, reqNamespacedName)
+	require.NoError(t, err, "unexpected Reconcile error")
+	require.Len(t, recorder.Events, 1, "unexpected number of events")
+	event = <-recorder.Events
+	require.Contains(t, event, "Warning Stalled Validation: KNV1061: RootSyncs must specify spec.git", "unexpected event")
+
+	// A spec update that leaves the error unchanged should not emit an event
+	rs = fake.RootSyncObjectV1Beta1(rootsyncName)
+	err = fakeClient.Get(ctx, core.ObjectNamespacedName(rs), rs)
+	require.NoError(t, err, "unexpected Get error")
+	rs.Labels = map[string]string{"foo": "bar"}
+	err = fakeClient.Update(ctx, rs)
+	require.NoError(t, err, "unexpected Update error")
+
+	_, err = testReconciler.Reconcile(ctx, reqNamespacedName)
+	require.NoError(t, err, "unexpected Reconcile error")
+	require.Empty(t, recorder.Events, "unexpected events")
+}
+
 func TestPopulateRootContainerEnvs(t *testing.T) {
 	defaults := map[string]map[string]string{
 		reconcilermanager.HydrationController: {
@@ -2931,6 +3002,74 @@ func TestPopulateRootContainerEnvs(t *testing.T) {
 			rootSync: rootSync(rootsyncName, rootsyncOverrideAPIServerTimeout(metav1.Duration{Duration: 40 * time.Second})),
 			expected: createEnv(map[string]map[string]string{reconcilermanager.Reconciler: {reconcilermanager.APIServerTimeout: "40s"}}),
 		},
+		{
+			name: "dependsOn defaults the namespace of RepoSyncs",
+			rootSync: rootSync(rootsyncName, func(rs *v1beta1.RootSync) {
+				rs.Spec.DependsOn = []v1beta1.Dependency{
+					{Kind: configsync.RootSyncKind, Name: "crds"},
+					{Kind: configsync.RepoSyncKind, Name: "bookinfo", Namespace: "bookinfo"},
+					{Kind: configsync.RepoSyncKind, Name: "namespaces"},
+				}
+			}),
+			expected: createEnv(map[string]map[string]string{reconcilermanager.Reconciler: {
+				reconcilermanager.DependsOn: `[{"kind":"RootSync","name":"crds"},{"kind":"RepoSync","name":"bookinfo","namespace":"bookinfo"},{"kind":"RepoSync","name":"namespaces","namespace":"config-management-system"}]`,
+			}}),
+		},
+		{
+			name: "notifications are passed to the reconciler",
+			rootSync: rootSync(rootsyncName, func(rs *v1beta1.RootSync) {
+				rs.Spec.Notifications = []v1beta1.Notification{
+					{URL: "https://example.com/hook", On: []configsync.NotificationEvent{configsync.NotificationFailed}},
+				}
+			}),
+			expected: createEnv(map[string]map[string]string{reconcilermanager.Reconciler: {
+				reconcilermanager.Notifications: `[{"url":"https://example.com/hook","on":["failed"]}]`,
+			}}),
+		},
+		{
+			name: "dry-run is passed to the reconciler",
+			rootSync: rootSync(rootsyncName, func(rs *v1beta1.RootSync) {
+				rs.Spec.Override = &v1beta1.OverrideSpec{DryRun: pointer.Bool(true)}
+			}),
+			expected: createEnv(map[string]map[string]string{reconcilermanager.Reconciler: {
+				reconcilermanager.DryRun: "true",
+			}}),
+		},
+		{
+			name: "rendering options are passed to the hydration-controller",
+			rootSync: rootSync(rootsyncName, func(rs *v1beta1.RootSync) {
+				rs.Spec.Override = &v1beta1.OverrideSpec{Rendering: &v1beta1.RenderingSpec{
+					EnableHelm:     pointer.Bool(false),
+					LoadRestrictor: "LoadRestrictionsNone",
+				}}
+			}),
+			expected: createEnv(map[string]map[string]string{reconcilermanager.HydrationController: {
+				reconcilermanager.KustomizeEnableHelm:     "false",
+				reconcilermanager.KustomizeLoadRestrictor: "LoadRestrictionsNone",
+			}}),
+		},
+		{
+			name: "sources are passed to the reconciler and their sidecars",
+			rootSync: rootSync(rootsyncName, func(rs *v1beta1.RootSync) {
+				rs.Spec.SourceFormat = string(filesystem.SourceFormatUnstructured)
+				rs.Spec.Sources = []v1beta1.RootSyncSource{{
+					Name:       "policies",
+					SourceType: string(v1beta1.OciSource),
+					Oci:        &v1beta1.Oci{Image: "us-docker.pkg.dev/test/policies", Dir: "base", Auth: configsync.AuthGCENode},
+				}}
+			}),
+			expected: createEnv(map[string]map[string]string{
+				reconcilermanager.Reconciler: {
+					filesystem.SourceFormatKey: string(filesystem.SourceFormatUnstructured),
+					reconcilermanager.Sources:  `[{"name":"policies","sourceType":"oci","repo":"us-docker.pkg.dev/test/policies","syncDir":"base"}]`,
+				},
+				reconcilermanager.OciSync + "-policies": {
+					reconcilermanager.OciSyncImage: "us-docker.pkg.dev/test/policies",
+					reconcilermanager.OciSyncAuth:  string(configsync.AuthGCENode),
+					reconcilermanager.OciSyncWait:  "15.000000",
+				},
+			}),
+		},
 	}
 
 	ctx := context.Background()
@@ -2950,6 +3089,64 @@ func TestPopulateRootContainerEnvs(t *testing.T) {
 	}
 }
 
+func TestSourceContainer(t *testing.T) {
+	templates := map[string]corev1.Container{
+		reconcilermanager.GitSync: {
+			Name: reconcilermanager.GitSync,
+			Args: []string{"--root=/repo/source", "--dest=rev"},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "repo", MountPath: "/repo"},
+				{Name: "git-creds", MountPath: "/etc/git-secret", ReadOnly: true},
+			},
+		},
+	}
+	src := v1beta1.RootSyncSource{
+		Name:       "base",
+		SourceType: string(v1beta1.GitSource),
+		Git:        &v1beta1.Git{Repo: "https://github.com/test/base", Auth: configsync.AuthNone},
+	}
+	containerEnvs := map[string][]corev1.EnvVar{
+		"git-sync-base": {{Name: "GIT_SYNC_REPO", Value: "https://github.com/test/base"}},
+	}
+
+	got, err := sourceContainer(templates, src, containerEnvs)
+	if err != nil {
+		t.Fatalf("sourceContainer() got error %v, want nil", err)
+	}
+	want := corev1.Container{
+		Name:         "git-sync-base",
+		Args:         []string{"--root=/repo/sources/base", "--dest=rev"},
+		Env:          containerEnvs["git-sync-base"],
+		VolumeMounts: []corev1.VolumeMount{{Name: "repo", MountPath: "/repo"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("sourceContainer() diff (- want, + got): %s", diff)
+	}
+	// The template container must not be modified.
+	if diff := cmp.Diff([]string{"--root=/repo/source", "--dest=rev"}, templates[reconcilermanager.GitSync].Args); diff != "" {
+		t.Errorf("template container modified, diff (- want, + got): %s", diff)
+	}
+
+	// The auth types that need a volume or a sidecar are rejected.
+	for _, auth := range []configsync.AuthType{configsync.AuthSSH, configsync.AuthCookieFile, configsync.AuthGCENode, configsync.AuthGCPServiceAccount} {
+		src.Git.Auth = auth
+		if _, err := sourceContainer(templates, src, containerEnvs); err == nil {
+			t.Errorf("sourceContainer() got nil error for auth %q", auth)
+		}
+	}
+	src.Git.Auth = configsync.AuthNone
+	src.Git.CACertSecretRef = &v1beta1.SecretReference{Name: "ca-cert"}
+	if _, err := sourceContainer(templates, src, containerEnvs); err == nil {
+		t.Error("sourceContainer() got nil error for a CA certificate")
+	}
+
+	src.SourceType = string(v1beta1.HelmSource)
+	src.Helm = &v1beta1.HelmRootSync{HelmBase: v1beta1.HelmBase{Repo: "https://charts.example.com", Chart: "base", Auth: configsync.AuthNone}}
+	if _, err := sourceContainer(templates, src, containerEnvs); err == nil {
+		t.Error("sourceContainer() got nil error for a missing template container")
+	}
+}
+
 func validateRootSyncStatus(t *testing.T, want *v1beta1.RootSync, fakeClient *syncerFake.Client) {
 	t.Helper()
 
@@ -28,6 +28,7 @@ import (
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/kinds"
 	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/reposync"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -45,6 +46,19 @@ func isUpsertedSecret(rs *v1beta1.RepoSync, secretName string) bool {
 	if shouldUpsertHelmSecret(rs) && secretName == ReconcilerResourceName(reconcilerName, v1beta1.GetSecretName(rs.Spec.Helm.SecretRef)) {
 		return true
 	}
+	if shouldUpsertWebhookSecret(rs) && secretName == ReconcilerResourceName(reconcilerName, v1beta1.GetSecretName(rs.Spec.Webhook.SecretRef)) {
+		return true
+	}
+	for _, name := range verificationSecretNames(rs) {
+		if secretName == ReconcilerResourceName(reconcilerName, name) {
+			return true
+		}
+	}
+	for _, name := range notificationSecretNames(rs.Spec.Notifications) {
+		if secretName == ReconcilerResourceName(reconcilerName, name) {
+			return true
+		}
+	}
 	return false
 }
 
@@ -60,6 +74,10 @@ func shouldUpsertHelmSecret(rs *v1beta1.RepoSync) bool {
 	return v1beta1.SourceType(rs.Spec.SourceType) == v1beta1.HelmSource && rs.Spec.Helm != nil && rs.Spec.Helm.SecretRef != nil && !SkipForAuth(rs.Spec.Helm.Auth)
 }
 
+func shouldUpsertWebhookSecret(rs *v1beta1.RepoSync) bool {
+	return rs.Spec.Webhook != nil && rs.Spec.Webhook.SecretRef != nil
+}
+
 // upsertAuthSecret creates or updates the auth secret in the
 // config-management-system namespace using an existing secret in the RepoSync
 // namespace.
@@ -133,6 +151,65 @@ func upsertCACertSecret(ctx context.Context, log logr.Logger, rs *v1beta1.RepoSy
 	return client.ObjectKey{}, nil
 }
 
+// upsertWebhookSecret creates or updates the webhook secret in the
+// config-management-system namespace using an existing secret in the RepoSync
+// namespace.
+func upsertWebhookSecret(ctx context.Context, log logr.Logger, rs *v1beta1.RepoSync, c client.Client, reconcilerRef types.NamespacedName) (client.ObjectKey, error) {
+	rsRef := client.ObjectKeyFromObject(rs)
+	if shouldUpsertWebhookSecret(rs) {
+		nsSecretRef, cmsSecretRef := getSecretRefs(rsRef, reconcilerRef, v1beta1.GetSecretName(rs.Spec.Webhook.SecretRef))
+		userSecret, err := getUserSecret(ctx, c, nsSecretRef)
+		if err != nil {
+			return cmsSecretRef, errors.Wrap(err, "user secret required for webhook authentication")
+		}
+		op, err := upsertSecret(ctx, c, cmsSecretRef, rsRef, userSecret)
+		if err != nil {
+			return cmsSecretRef, err
+		}
+		if op != controllerutil.OperationResultNone {
+			log.Info("Managed object upsert successful",
+				logFieldObject, cmsSecretRef.String(),
+				logFieldKind, "Secret",
+				logFieldOperation, op)
+		}
+		return cmsSecretRef, nil
+	}
+	// No secret required
+	return client.ObjectKey{}, nil
+}
+
+// verificationSecretNames returns the names of the Secrets in the RepoSync
+// namespace that hold the keys trusted to verify the source signature.
+func verificationSecretNames(rs *v1beta1.RepoSync) []string {
+	verification := sourceVerification(rs.Spec.SourceType, rs.Spec.Oci, reposync.GetHelmBase(rs.Spec.Helm))
+	return verificationKeyNames(verification, kinds.Secret().Kind)
+}
+
+// upsertVerificationSecrets creates or updates the secrets that hold the
+// trusted keys in the config-management-system namespace using the existing
+// secrets in the RepoSync namespace.
+func upsertVerificationSecrets(ctx context.Context, log logr.Logger, rs *v1beta1.RepoSync, c client.Client, reconcilerRef types.NamespacedName) (client.ObjectKey, error) {
+	rsRef := client.ObjectKeyFromObject(rs)
+	for _, name := range verificationSecretNames(rs) {
+		nsSecretRef, cmsSecretRef := getSecretRefs(rsRef, reconcilerRef, name)
+		userSecret, err := getUserSecret(ctx, c, nsSecretRef)
+		if err != nil {
+			return cmsSecretRef, errors.Wrap(err, "user secret required for signature verification")
+		}
+		op, err := upsertSecret(ctx, c, cmsSecretRef, rsRef, userSecret)
+		if err != nil {
+			return cmsSecretRef, err
+		}
+		if op != controllerutil.OperationResultNone {
+			log.Info("Managed object upsert successful",
+				logFieldObject, cmsSecretRef.String(),
+				logFieldKind, "Secret",
+				logFieldOperation, op)
+		}
+	}
+	return client.ObjectKey{}, nil
+}
+
 func getSecretRefs(rsRef, reconcilerRef client.ObjectKey, secretName string) (nsSecretRef, cmsSecretRef client.ObjectKey) {
 	// User managed secret
 	nsSecretRef = client.ObjectKey{
@@ -240,12 +317,37 @@ func updateSecret(ctx context.Context, c client.Client, existingsecret *corev1.S
 }
 
 // SkipForAuth returns true if the passed auth is either 'none' or 'gcenode' or
-// 'gcpserviceaccount'.
+// 'gcpserviceaccount' or 'k8sserviceaccount'.
 func SkipForAuth(auth configsync.AuthType) bool {
 	switch auth {
-	case configsync.AuthNone, configsync.AuthGCENode, configsync.AuthGCPServiceAccount:
+	case configsync.AuthNone, configsync.AuthGCENode, configsync.AuthGCPServiceAccount, configsync.AuthK8sServiceAccount:
 		return true
 	default:
 		return false
 	}
 }
+
+// upsertNotificationSecrets creates or updates the secrets referenced by the
+// notifications in the config-management-system namespace using the existing
+// secrets in the RepoSync namespace.
+func upsertNotificationSecrets(ctx context.Context, log logr.Logger, rs *v1beta1.RepoSync, c client.Client, reconcilerRef types.NamespacedName) (client.ObjectKey, error) {
+	rsRef := client.ObjectKeyFromObject(rs)
+	for _, name := range notificationSecretNames(rs.Spec.Notifications) {
+		nsSecretRef, cmsSecretRef := getSecretRefs(rsRef, reconcilerRef, name)
+		userSecret, err := getUserSecret(ctx, c, nsSecretRef)
+		if err != nil {
+			return cmsSecretRef, errors.Wrap(err, "user secret required for notifications")
+		}
+		op, err := upsertSecret(ctx, c, cmsSecretRef, rsRef, userSecret)
+		if err != nil {
+			return cmsSecretRef, err
+		}
+		if op != controllerutil.OperationResultNone {
+			log.Info("Managed object upsert successful",
+				logFieldObject, cmsSecretRef.String(),
+				logFieldKind, "Secret",
+				logFieldOperation, op)
+		}
+	}
+	return client.ObjectKey{}, nil
+}
@@ -15,8 +15,13 @@
 package controllers
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -27,8 +32,11 @@ import (
 	hubv1 "kpt.dev/configsync/pkg/api/hub/v1"
 	"kpt.dev/configsync/pkg/applier"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/dependency"
 	"kpt.dev/configsync/pkg/importer/filesystem"
 	"kpt.dev/configsync/pkg/reconcilermanager"
+	"kpt.dev/configsync/pkg/validate/raw/validate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -189,7 +197,7 @@ func sourceFormatEnv(format string) corev1.EnvVar {
 }
 
 // ociSyncEnvs returns the environment variables for the oci-sync container.
-func ociSyncEnvs(image string, auth configsync.AuthType, period float64) []corev1.EnvVar {
+func ociSyncEnvs(image string, auth configsync.AuthType, period float64, verification *v1beta1.Verification) []corev1.EnvVar {
 	var result []corev1.EnvVar
 	result = append(result, corev1.EnvVar{
 		Name:  reconcilermanager.OciSyncImage,
@@ -201,6 +209,12 @@ func ociSyncEnvs(image string, auth configsync.AuthType, period float64) []corev
 		Name:  reconcilermanager.OciSyncWait,
 		Value: fmt.Sprintf("%f", period),
 	})
+	if verification != nil {
+		result = append(result, corev1.EnvVar{
+			Name:  reconcilermanager.OciSyncVerificationProvider,
+			Value: string(verification.Provider),
+		})
+	}
 	return result
 }
 
@@ -235,6 +249,9 @@ func helmSyncEnvs(helmBase *v1beta1.HelmBase, releaseNamespace string) []corev1.
 	}, corev1.EnvVar{
 		Name:  reconcilermanager.HelmValues,
 		Value: helmValues,
+	}, corev1.EnvVar{
+		Name:  reconcilermanager.HelmValuesSources,
+		Value: helmValuesSourcesEnv(helmBase),
 	}, corev1.EnvVar{
 		Name:  reconcilermanager.HelmIncludeCRDs,
 		Value: fmt.Sprint(helmBase.IncludeCRDs),
@@ -245,6 +262,12 @@ func helmSyncEnvs(helmBase *v1beta1.HelmBase, releaseNamespace string) []corev1.
 		Name:  reconcilermanager.HelmSyncWait,
 		Value: fmt.Sprintf("%f", v1beta1.GetPeriodSecs(helmBase.Period)),
 	})
+	if helmBase.Verification != nil {
+		result = append(result, corev1.EnvVar{
+			Name:  reconcilermanager.HelmSyncVerificationProvider,
+			Value: string(helmBase.Verification.Provider),
+		})
+	}
 	return result
 }
 
@@ -280,6 +303,288 @@ func helmSyncTokenAuthEnv(secretRef string) []corev1.EnvVar {
 	}
 }
 
+// syncWindowEnvs returns the environment variables that suspend the reconciler
+// container, either unconditionally or according to the sync windows.
+func syncWindowEnvs(suspend bool, windows []v1beta1.SyncWindow) []corev1.EnvVar {
+	var result []corev1.EnvVar
+	if suspend {
+		result = append(result, corev1.EnvVar{
+			Name:  reconcilermanager.Suspend,
+			Value: "true",
+		})
+	}
+	if len(windows) > 0 {
+		// A SyncWindow only holds strings and a duration, so it always marshals.
+		data, _ := json.Marshal(windows)
+		result = append(result, corev1.EnvVar{
+			Name:  reconcilermanager.SyncWindows,
+			Value: string(data),
+		})
+	}
+	return result
+}
+
+// driftPolicyEnvs returns the environment variables that configure whether the
+// remediator reverts or only reports drift.
+func driftPolicyEnvs(driftPolicy configsync.DriftPolicy) []corev1.EnvVar {
+	if driftPolicy == "" {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name:  reconcilermanager.DriftPolicy,
+		Value: string(driftPolicy),
+	}}
+}
+
+// autoRollbackEnvs returns the environment variables that configure whether
+// the reconciler rolls back commits that fail to sync.
+func autoRollbackEnvs(autoRollback *bool) []corev1.EnvVar {
+	if autoRollback == nil || !*autoRollback {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name:  reconcilermanager.AutoRollback,
+		Value: "true",
+	}}
+}
+
+// dryRunEnvs returns the environment variables that configure whether the
+// reconciler only previews the changes with a server-side dry-run.
+func dryRunEnvs(dryRun *bool) []corev1.EnvVar {
+	if dryRun == nil || !*dryRun {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name:  reconcilermanager.DryRun,
+		Value: "true",
+	}}
+}
+
+// renderingEnvs returns the environment variables that configure the options
+// of `kustomize build` in the hydration controller. Options left at their
+// default are omitted.
+func renderingEnvs(rendering *v1beta1.RenderingSpec) []corev1.EnvVar {
+	if rendering == nil {
+		return nil
+	}
+	var result []corev1.EnvVar
+	if rendering.EnableHelm != nil && !*rendering.EnableHelm {
+		result = append(result, corev1.EnvVar{
+			Name:  reconcilermanager.KustomizeEnableHelm,
+			Value: "false",
+		})
+	}
+	if rendering.EnableAlphaPlugins != nil && !*rendering.EnableAlphaPlugins {
+		result = append(result, corev1.EnvVar{
+			Name:  reconcilermanager.KustomizeEnableAlphaPlugins,
+			Value: "false",
+		})
+	}
+	if rendering.LoadRestrictor != "" {
+		result = append(result, corev1.EnvVar{
+			Name:  reconcilermanager.KustomizeLoadRestrictor,
+			Value: rendering.LoadRestrictor,
+		})
+	}
+	if rendering.HelmCommand != "" {
+		result = append(result, corev1.EnvVar{
+			Name:  reconcilermanager.KustomizeHelmCommand,
+			Value: rendering.HelmCommand,
+		})
+	}
+	return result
+}
+
+// prometheusEnvs returns the environment variables that make the reconciler
+// serve its metrics in the Prometheus exposition format on port.
+func prometheusEnvs(port int) []corev1.EnvVar {
+	if port <= 0 {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name:  reconcilermanager.PrometheusPort,
+		Value: strconv.Itoa(port),
+	}}
+}
+
+// dependsOnEnvs returns the environment variables that make the reconciler
+// wait for the dependencies of a sync in namespace. The namespace of a RepoSync
+// dependency defaults to the namespace of the sync.
+func dependsOnEnvs(namespace string, dependencies []v1beta1.Dependency) []corev1.EnvVar {
+	if len(dependencies) == 0 {
+		return nil
+	}
+	// A Dependency only holds strings, so it always marshals.
+	data, _ := json.Marshal(defaultDependencies(namespace, dependencies))
+	return []corev1.EnvVar{{
+		Name:  reconcilermanager.DependsOn,
+		Value: string(data),
+	}}
+}
+
+// defaultDependencies returns a copy of the dependencies, with the namespace of
+// RepoSync dependencies defaulted to the namespace of the dependent sync.
+func defaultDependencies(namespace string, dependencies []v1beta1.Dependency) []v1beta1.Dependency {
+	deps := make([]v1beta1.Dependency, len(dependencies))
+	copy(deps, dependencies)
+	for i := range deps {
+		if deps[i].Kind == configsync.RepoSyncKind && deps[i].Namespace == "" {
+			deps[i].Namespace = namespace
+		}
+	}
+	return deps
+}
+
+// validateDependencyCycle verifies that the dependencies of the sync self do
+// not lead back to a sync through the spec.dependsOn of the existing syncs.
+func validateDependencyCycle(ctx context.Context, c client.Reader, self v1beta1.Dependency, dependencies []v1beta1.Dependency) error {
+	if len(dependencies) == 0 {
+		return nil
+	}
+	err := dependency.CheckCycle(ctx, c, self, defaultDependencies(self.Namespace, dependencies))
+	var cycleErr *dependency.CycleError
+	if errors.As(err, &cycleErr) {
+		return validate.DependencyCycle(cycleErr)
+	}
+	return err
+}
+
+// notificationEnvs returns the environment variables that make the reconciler
+// notify the HTTP endpoints of the outcome of each sync.
+func notificationEnvs(notifications []v1beta1.Notification) []corev1.EnvVar {
+	if len(notifications) == 0 {
+		return nil
+	}
+	// A Notification only holds strings, so it always marshals.
+	data, _ := json.Marshal(notifications)
+	return []corev1.EnvVar{{
+		Name:  reconcilermanager.Notifications,
+		Value: string(data),
+	}}
+}
+
+// sourcesEnvs returns the environment variables that make the reconciler merge
+// the objects of the additional sources of a RootSync.
+func sourcesEnvs(sources []v1beta1.RootSyncSource) []corev1.EnvVar {
+	if len(sources) == 0 {
+		return nil
+	}
+	result := make([]reconcilermanager.Source, len(sources))
+	for i, src := range sources {
+		result[i] = reconcilerSource(src)
+	}
+	// A Source only holds strings, so it always marshals.
+	data, _ := json.Marshal(result)
+	return []corev1.EnvVar{{
+		Name:  reconcilermanager.Sources,
+		Value: string(data),
+	}}
+}
+
+// reconcilerSource returns the additional source as passed to the reconciler,
+// with the same defaults as the primary source. The configs of a Helm chart
+// are rendered into a directory named after the chart.
+func reconcilerSource(src v1beta1.RootSyncSource) reconcilermanager.Source {
+	result := reconcilermanager.Source{
+		Name:       src.Name,
+		SourceType: src.SourceType,
+	}
+	switch v1beta1.SourceType(src.SourceType) {
+	case v1beta1.GitSource:
+		result.Repo = src.Git.Repo
+		result.SyncDir = src.Git.Dir
+		result.Branch = src.Git.Branch
+		if result.Branch == "" {
+			result.Branch = "master"
+		}
+		result.Rev = src.Git.Revision
+		if result.Rev == "" {
+			result.Rev = "HEAD"
+		}
+	case v1beta1.OciSource:
+		result.Repo = src.Oci.Image
+		result.SyncDir = src.Oci.Dir
+	case v1beta1.HelmSource:
+		result.Repo = src.Helm.Repo
+		result.SyncDir = src.Helm.Chart
+		result.Rev = src.Helm.Version
+		if result.Rev == "" {
+			result.Rev = "latest"
+		}
+	}
+	return result
+}
+
+// sourceContainerName returns the name of the sidecar container that fetches
+// an additional source.
+func sourceContainerName(src v1beta1.RootSyncSource) string {
+	switch v1beta1.SourceType(src.SourceType) {
+	case v1beta1.GitSource:
+		return reconcilermanager.GitSync + "-" + src.Name
+	case v1beta1.OciSource:
+		return reconcilermanager.OciSync + "-" + src.Name
+	default:
+		return reconcilermanager.HelmSync + "-" + src.Name
+	}
+}
+
+// sourceSyncEnvs returns the environment variables for the sidecar container
+// that fetches an additional source.
+func sourceSyncEnvs(ctx context.Context, src v1beta1.RootSyncSource) []corev1.EnvVar {
+	switch v1beta1.SourceType(src.SourceType) {
+	case v1beta1.GitSource:
+		result := gitSyncEnvs(ctx, options{
+			ref:         src.Git.Revision,
+			branch:      src.Git.Branch,
+			repo:        src.Git.Repo,
+			secretType:  src.Git.Auth,
+			period:      v1beta1.GetPeriodSecs(src.Git.Period),
+			proxy:       src.Git.Proxy,
+			noSSLVerify: src.Git.NoSSLVerify,
+		})
+		if authTypeToken(src.Git.Auth) {
+			result = append(result, gitSyncTokenAuthEnv(v1beta1.GetSecretName(src.Git.SecretRef))...)
+		}
+		return result
+	case v1beta1.OciSource:
+		return ociSyncEnvs(src.Oci.Image, src.Oci.Auth, v1beta1.GetPeriodSecs(src.Oci.Period), nil)
+	case v1beta1.HelmSource:
+		result := helmSyncEnvs(&src.Helm.HelmBase, src.Helm.Namespace)
+		if authTypeToken(src.Helm.Auth) {
+			result = append(result, helmSyncTokenAuthEnv(v1beta1.GetSecretName(src.Helm.SecretRef))...)
+		}
+		return result
+	}
+	return nil
+}
+
+// sourceContainer returns the sidecar container that fetches an additional
+// source, copied from the template container of the same source type. The
+// source is fetched into its own directory under the sources directory, and
+// its credentials are only ever passed as environment variables.
+func sourceContainer(templates map[string]corev1.Container, src v1beta1.RootSyncSource, containerEnvs map[string][]corev1.EnvVar) (corev1.Container, error) {
+	if err := validate.SourceAuth(src); err != nil {
+		return corev1.Container{}, errors.Wrapf(err, "additional source %q", src.Name)
+	}
+	templateName := strings.TrimSuffix(sourceContainerName(src), "-"+src.Name)
+	template, found := templates[templateName]
+	if !found {
+		return corev1.Container{}, errors.Errorf("missing container in reconciler deployment template: %q", templateName)
+	}
+	container := *template.DeepCopy()
+	container.Name = sourceContainerName(src)
+	for i, arg := range container.Args {
+		if strings.HasPrefix(arg, "--root=") {
+			container.Args[i] = "--root=" + path.Join(strings.TrimPrefix(arg, "--root="), "..", reconcilermanager.SourcesDir, src.Name)
+		}
+	}
+	container.Env = append(container.Env, containerEnvs[container.Name]...)
+	// The credential and CA certificate volumes of the template belong to the
+	// primary source, so they are never mounted.
+	container.VolumeMounts = volumeMounts(configsync.AuthNone, "", src.SourceType, container.VolumeMounts)
+	return container, nil
+}
+
 func ownerReference(kind, name string, uid types.UID) metav1.OwnerReference {
 	return metav1.OwnerReference{
 		APIVersion:         v1beta1.SchemeGroupVersion.String(),
@@ -62,6 +62,12 @@ func validateSecretData(auth configsync.AuthType, secret *corev1.Secret) error {
 		if _, ok := secret.Data[GitSecretConfigKeyTokenUsername]; !ok {
 			return fmt.Errorf("git secretType was set as %q but username key is not present in %v secret", auth, secret.Name)
 		}
+	case configsync.AuthGitHubApp:
+		for _, key := range []string{GitSecretConfigKeyGitHubAppID, GitSecretConfigKeyGitHubAppInstallationID, GitSecretConfigKeyGitHubAppPrivateKey} {
+			if _, ok := secret.Data[key]; !ok {
+				return fmt.Errorf("git secretType was set as %q but %s key is not present in %v secret", auth, key, secret.Name)
+			}
+		}
 	case configsync.AuthNone:
 	case configsync.AuthGCENode:
 	default:
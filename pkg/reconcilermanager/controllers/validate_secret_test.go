@@ -89,6 +89,27 @@ func TestValidateSecretData(t *testing.T) {
 			auth:   configsync.AuthCookieFile,
 			secret: secretObj(t, "ssh-key", "cookie_file", v1beta1.GitSource, core.Namespace("bookinfo")),
 		},
+		{
+			name: "GitHub App auth data present",
+			auth: configsync.AuthGitHubApp,
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					GitSecretConfigKeyGitHubAppID:             []byte("1"),
+					GitSecretConfigKeyGitHubAppInstallationID: []byte("2"),
+					GitSecretConfigKeyGitHubAppPrivateKey:     []byte("key"),
+				},
+			},
+		},
+		{
+			name: "GitHub App auth data missing",
+			auth: configsync.AuthGitHubApp,
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					GitSecretConfigKeyGitHubAppID: []byte("1"),
+				},
+			},
+			wantError: true,
+		},
 		{
 			name: "None auth",
 			auth: configsync.AuthNone,
@@ -0,0 +1,139 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/kinds"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// sourceVerification returns the signature verification of the source, if
+// the source type is oci or helm.
+func sourceVerification(sourceType string, oci *v1beta1.Oci, helmBase *v1beta1.HelmBase) *v1beta1.Verification {
+	switch v1beta1.SourceType(sourceType) {
+	case v1beta1.OciSource:
+		if oci != nil {
+			return oci.Verification
+		}
+	case v1beta1.HelmSource:
+		if helmBase != nil {
+			return helmBase.Verification
+		}
+	}
+	return nil
+}
+
+// verificationKeysVolumeName returns the name of the volume of the i-th
+// public key reference.
+func verificationKeysVolumeName(i int) string {
+	return fmt.Sprintf("%s-%d", VerificationKeysVolume, i)
+}
+
+// verificationKeysVolumes returns a volume for each Secret or ConfigMap that
+// holds trusted keys, so that keys with the same name in different objects do
+// not collide. objectName maps the name of a referenced object to the name of
+// the object in the config-management-system namespace.
+func verificationKeysVolumes(verification *v1beta1.Verification, objectName func(string) string) []corev1.Volume {
+	if verification == nil {
+		return nil
+	}
+	var volumes []corev1.Volume
+	for i, ref := range verification.PublicKeys {
+		volume := corev1.Volume{Name: verificationKeysVolumeName(i)}
+		if ref.Kind == kinds.ConfigMap().Kind {
+			volume.ConfigMap = &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: objectName(ref.Name),
+				},
+				DefaultMode: &defaultMode,
+			}
+		} else {
+			volume.Secret = &corev1.SecretVolumeSource{
+				SecretName:  objectName(ref.Name),
+				DefaultMode: &defaultMode,
+			}
+		}
+		volumes = append(volumes, volume)
+	}
+	return volumes
+}
+
+// verificationKeysVolumeMounts returns the VolumeMounts of the trusted keys,
+// under the VerificationKeysPath directory.
+func verificationKeysVolumeMounts(verification *v1beta1.Verification) []corev1.VolumeMount {
+	if verification == nil {
+		return nil
+	}
+	var mounts []corev1.VolumeMount
+	for i := range verification.PublicKeys {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      verificationKeysVolumeName(i),
+			MountPath: filepath.Join(VerificationKeysPath, strconv.Itoa(i)),
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+// verificationKeyNames returns the names of the Secrets or ConfigMaps of the
+// given kind that hold trusted keys.
+func verificationKeyNames(verification *v1beta1.Verification, kind string) []string {
+	if verification == nil {
+		return nil
+	}
+	var names []string
+	for _, ref := range verification.PublicKeys {
+		if ref.Kind == kind {
+			names = append(names, ref.Name)
+		}
+	}
+	return names
+}
+
+// validateVerificationKeys verifies that the Secrets and ConfigMaps that hold
+// the trusted keys exist in the given namespace.
+func validateVerificationKeys(ctx context.Context, verification *v1beta1.Verification, namespace string, c client.Client) error {
+	if verification == nil {
+		return nil
+	}
+	for _, ref := range verification.PublicKeys {
+		objRef := client.ObjectKey{
+			Name:      ref.Name,
+			Namespace: namespace,
+		}
+		var obj client.Object = &corev1.Secret{}
+		if ref.Kind == kinds.ConfigMap().Kind {
+			obj = &corev1.ConfigMap{}
+		}
+		if err := c.Get(ctx, objRef, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return errors.Errorf(
+					"%s %s not found: create one to provide the trusted keys for signature verification", ref.Kind, objRef)
+			}
+			return errors.Wrapf(err,
+				"%s %s get failed", ref.Kind, objRef)
+		}
+	}
+	return nil
+}
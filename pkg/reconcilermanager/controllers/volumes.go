@@ -32,6 +32,27 @@ const GitCredentialVolume = "git-creds"
 // HelmCredentialVolume is the volume name of the git credentials.
 const HelmCredentialVolume = "helm-creds"
 
+// HelmValuesVolume is the volume name of the Helm values files projected from ConfigMaps.
+const HelmValuesVolume = "helm-values"
+
+// HelmValuesPath is the path where the Helm values files are mounted.
+const HelmValuesPath = "/etc/helm-values"
+
+// VerificationKeysVolume is the prefix of the volume names of the keys trusted
+// to verify the signature of OCI images and Helm charts.
+const VerificationKeysVolume = "verification-keys"
+
+// VerificationKeysPath is the path where the trusted keys are mounted.
+const VerificationKeysPath = "/etc/verification-keys"
+
+// NotificationSecretsVolume is the prefix of the volume names of the Secrets
+// referenced by the notifications.
+const NotificationSecretsVolume = "notification-secrets"
+
+// NotificationSecretsPath is the path where the notification Secrets are
+// mounted.
+const NotificationSecretsPath = "/etc/notification-secrets"
+
 // CACertVolume is the volume name of the CA certificate.
 const CACertVolume = "ca-cert"
 
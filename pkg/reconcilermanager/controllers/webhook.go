@@ -0,0 +1,193 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/reconcilermanager"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// webhookSecretConfigKeyToken is the Secret key that holds the token used
+	// to authenticate webhook requests.
+	webhookSecretConfigKeyToken = "token"
+
+	// webhookPortName is the name of the reconciler container port that
+	// serves the webhook endpoint.
+	webhookPortName = "webhook"
+
+	// gitSyncSyncOnSignal is the git-sync environment variable that makes
+	// git-sync sync immediately when it receives the given signal.
+	gitSyncSyncOnSignal = "GIT_SYNC_SYNC_ON_SIGNAL"
+)
+
+// webhookTokenEnv returns the environment variable that passes the webhook
+// token from the given Secret to a container.
+func webhookTokenEnv(secretRef string) []corev1.EnvVar {
+	return []corev1.EnvVar{{
+		Name: reconcilermanager.WebhookToken,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secretRef,
+				},
+				Key: webhookSecretConfigKeyToken,
+			},
+		},
+	}}
+}
+
+// addWebhookEnvs passes the webhook token to the reconciler and the
+// hydration-controller. The hydration-controller forwards the notifications to
+// the source sidecar: oci-sync and helm-sync serve the webhook endpoint with
+// the same token, and git-sync syncs when it receives a SIGHUP.
+func addWebhookEnvs(result map[string][]corev1.EnvVar, sourceType string, secretRef string) {
+	result[reconcilermanager.Reconciler] = append(result[reconcilermanager.Reconciler], webhookTokenEnv(secretRef)...)
+	result[reconcilermanager.HydrationController] = append(result[reconcilermanager.HydrationController], webhookTokenEnv(secretRef)...)
+	switch v1beta1.SourceType(sourceType) {
+	case v1beta1.GitSource:
+		result[reconcilermanager.GitSync] = append(result[reconcilermanager.GitSync], corev1.EnvVar{
+			Name:  gitSyncSyncOnSignal,
+			Value: "SIGHUP",
+		})
+	case v1beta1.OciSource:
+		result[reconcilermanager.OciSync] = append(result[reconcilermanager.OciSync], webhookTokenEnv(secretRef)...)
+	case v1beta1.HelmSource:
+		result[reconcilermanager.HelmSync] = append(result[reconcilermanager.HelmSync], webhookTokenEnv(secretRef)...)
+	}
+}
+
+// webhookShareProcessNamespace returns whether the containers of the
+// reconciler Pod share a process namespace, which lets the
+// hydration-controller signal git-sync when a notification is received.
+func webhookShareProcessNamespace(webhook *v1beta1.Webhook, sourceType string) *bool {
+	if webhook == nil || v1beta1.SourceType(sourceType) != v1beta1.GitSource {
+		return nil
+	}
+	share := true
+	return &share
+}
+
+// webhookContainerPorts adds the webhook port to the reconciler container
+// ports, unless it is already declared.
+func webhookContainerPorts(ports []corev1.ContainerPort) []corev1.ContainerPort {
+	for _, p := range ports {
+		if p.Name == webhookPortName {
+			return ports
+		}
+	}
+	return append(ports, corev1.ContainerPort{
+		Name:          webhookPortName,
+		ContainerPort: reconcilermanager.ReconcilerWebhookPort,
+		Protocol:      corev1.ProtocolTCP,
+	})
+}
+
+// validateWebhookSecret verifies that the webhook Secret exists and holds a token.
+func validateWebhookSecret(ctx context.Context, webhook *v1beta1.Webhook, namespace string, c client.Client) error {
+	if webhook == nil {
+		return nil
+	}
+	secret, err := validateSecretExist(ctx, v1beta1.GetSecretName(webhook.SecretRef), namespace, c)
+	if err != nil {
+		return err
+	}
+	if len(secret.Data[webhookSecretConfigKeyToken]) == 0 {
+		return fmt.Errorf("webhook was enabled but %s key is not present in %v secret", webhookSecretConfigKeyToken, secret.Name)
+	}
+	return nil
+}
+
+// upsertWebhookService creates or updates the Service that exposes the
+// webhook endpoint of the reconciler Pod.
+func (r *reconcilerBase) upsertWebhookService(
+	ctx context.Context,
+	reconcilerRef types.NamespacedName,
+	labelMap map[string]string,
+	refs ...metav1.OwnerReference,
+) (client.ObjectKey, error) {
+	childSvc := &corev1.Service{}
+	childSvc.Name = reconcilerRef.Name
+	childSvc.Namespace = reconcilerRef.Namespace
+	r.addLabels(childSvc, labelMap)
+
+	op, err := controllerruntime.CreateOrUpdate(ctx, r.client, childSvc, func() error {
+		// Only set ownerRefs for the RootSync Service. The Reconciler Manager
+		// performs garbage collection for RepoSync controller resources.
+		if len(refs) > 0 {
+			childSvc.OwnerReferences = refs
+		}
+		childSvc.Spec.Selector = map[string]string{
+			metadata.ReconcilerLabel: reconcilerRef.Name,
+		}
+		childSvc.Spec.Ports = []corev1.ServicePort{{
+			Name:       webhookPortName,
+			Port:       reconcilermanager.ReconcilerWebhookPort,
+			TargetPort: intstr.FromString(webhookPortName),
+			Protocol:   corev1.ProtocolTCP,
+		}}
+		return nil
+	})
+	if err != nil {
+		return reconcilerRef, err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.log.Info("Managed object upsert successful",
+			logFieldObject, reconcilerRef.String(),
+			logFieldKind, "Service",
+			logFieldOperation, op)
+	}
+	return reconcilerRef, nil
+}
+
+// deleteWebhookService deletes the webhook Service of the reconciler, if any.
+func (r *reconcilerBase) deleteWebhookService(ctx context.Context, reconcilerRef types.NamespacedName) error {
+	svc := &corev1.Service{}
+	if err := r.client.Get(ctx, reconcilerRef, svc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return r.cleanup(ctx, reconcilerRef, kinds.Service())
+}
+
+// reconcileWebhookService upserts the webhook Service when the webhook is
+// enabled, and deletes it otherwise.
+func (r *reconcilerBase) reconcileWebhookService(
+	ctx context.Context,
+	webhook *v1beta1.Webhook,
+	reconcilerRef types.NamespacedName,
+	labelMap map[string]string,
+	refs ...metav1.OwnerReference,
+) (client.ObjectKey, error) {
+	if webhook == nil {
+		return reconcilerRef, r.deleteWebhookService(ctx, reconcilerRef)
+	}
+	return r.upsertWebhookService(ctx, reconcilerRef, labelMap, refs...)
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/reconcilermanager"
+)
+
+func TestAddWebhookEnvs(t *testing.T) {
+	const secretName = "webhook-token"
+	tokenEnv := webhookTokenEnv(secretName)
+	testCases := []struct {
+		name       string
+		sourceType v1beta1.SourceType
+		want       map[string][]corev1.EnvVar
+	}{
+		{
+			name:       "git-sync syncs on SIGHUP",
+			sourceType: v1beta1.GitSource,
+			want: map[string][]corev1.EnvVar{
+				reconcilermanager.Reconciler:          tokenEnv,
+				reconcilermanager.HydrationController: tokenEnv,
+				reconcilermanager.GitSync:             {{Name: gitSyncSyncOnSignal, Value: "SIGHUP"}},
+			},
+		},
+		{
+			name:       "oci-sync serves the webhook endpoint",
+			sourceType: v1beta1.OciSource,
+			want: map[string][]corev1.EnvVar{
+				reconcilermanager.Reconciler:          tokenEnv,
+				reconcilermanager.HydrationController: tokenEnv,
+				reconcilermanager.OciSync:             tokenEnv,
+			},
+		},
+		{
+			name:       "helm-sync serves the webhook endpoint",
+			sourceType: v1beta1.HelmSource,
+			want: map[string][]corev1.EnvVar{
+				reconcilermanager.Reconciler:          tokenEnv,
+				reconcilermanager.HydrationController: tokenEnv,
+				reconcilermanager.HelmSync:            tokenEnv,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := map[string][]corev1.EnvVar{}
+			addWebhookEnvs(got, string(tc.sourceType), secretName)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestWebhookShareProcessNamespace(t *testing.T) {
+	webhook := &v1beta1.Webhook{}
+	if got := webhookShareProcessNamespace(webhook, string(v1beta1.GitSource)); got == nil || !*got {
+		t.Errorf("got %v, want the process namespace to be shared with git-sync", got)
+	}
+	if got := webhookShareProcessNamespace(webhook, string(v1beta1.OciSource)); got != nil {
+		t.Errorf("got %v, want nil for oci-sync, which serves the webhook endpoint", *got)
+	}
+	if got := webhookShareProcessNamespace(nil, string(v1beta1.GitSource)); got != nil {
+		t.Errorf("got %v, want nil without a webhook", *got)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcilermanager
+
+// SourcesDir is the directory, relative to the repo root, under which the
+// sidecar containers of the additional sources of a RootSync fetch them. Each
+// source is fetched into its own subdirectory, named after the source.
+const SourcesDir = "sources"
+
+// Source is an additional source of a RootSync, as passed to the reconciler.
+type Source struct {
+	// Name is the name of the source, and of its directory under SourcesDir.
+	Name string `json:"name"`
+	// SourceType is the type of the source, one of git, oci or helm.
+	SourceType string `json:"sourceType"`
+	// Repo is the repo, image or chart repo of the source.
+	Repo string `json:"repo"`
+	// Branch is the git branch of the source.
+	Branch string `json:"branch,omitempty"`
+	// Rev is the git revision, or the version of the Helm chart.
+	Rev string `json:"rev,omitempty"`
+	// SyncDir is the path to the directory of the configs within the source.
+	SyncDir string `json:"syncDir,omitempty"`
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import (
+	"sync"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	orderedmap "github.com/wk8/go-ordered-map"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+)
+
+// Handler is the generic interface of the drift handler.
+type Handler interface {
+	AddDrift(core.ID, v1beta1.ResourceDrift)
+	RemoveDrift(core.ID)
+
+	// Drift returns the drift the remediator reported instead of reverting it.
+	Drift() []v1beta1.ResourceDrift
+}
+
+// handler implements Handler.
+type handler struct {
+	// mux guards the drift
+	mux sync.Mutex
+	// drift tracks the objects that diverged from their declaration and were
+	// not remediated because of the report drift policy, and report to
+	// RootSync|RepoSync status.
+	drift *orderedmap.OrderedMap
+}
+
+var _ Handler = &handler{}
+
+// NewHandler instantiates a drift handler
+func NewHandler() Handler {
+	return &handler{
+		drift: orderedmap.New(),
+	}
+}
+
+// AddDrift records the drift of an object. The timestamp of a drift that was
+// already recorded is kept, to avoid updating the status on every watch event.
+func (h *handler) AddDrift(id core.ID, d v1beta1.ResourceDrift) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if old, found := h.drift.Get(id); found &&
+		cmp.Equal(old, d, cmpopts.IgnoreFields(v1beta1.ResourceDrift{}, "LastUpdate")) {
+		return
+	}
+	klog.Infof("Drift reported for %s", id)
+	h.drift.Set(id, d)
+}
+
+func (h *handler) RemoveDrift(id core.ID) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	_, deleted := h.drift.Delete(id)
+	if deleted {
+		klog.Infof("Drift resolved for %s", id)
+	}
+}
+
+func (h *handler) Drift() []v1beta1.ResourceDrift {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	// Return a copy
+	var drift []v1beta1.ResourceDrift
+	for pair := h.drift.Oldest(); pair != nil; pair = pair.Next() {
+		d := pair.Value.(v1beta1.ResourceDrift)
+		drift = append(drift, *d.DeepCopy())
+	}
+	return drift
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/metadata"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BreakGlass tracks the break-glass exemptions of the objects skipped by the
+// Workers of a remediator.
+//
+// The remediator does not trust the annotation blindly, since it may have been
+// set while the admission webhook was disabled: an exemption is honored for at
+// most the max duration after the remediator first saw it.
+type BreakGlass struct {
+	maxDuration time.Duration
+	now         func() time.Time
+
+	mux        sync.Mutex
+	exemptions map[core.ID]*exemption
+}
+
+// exemption is the break-glass exemption of an object.
+type exemption struct {
+	// deadline is the time after which the exemption is no longer honored,
+	// whatever the annotation says.
+	deadline time.Time
+	// refreshAt is the time at which timer refreshes the object.
+	refreshAt time.Time
+	// timer refreshes the object once its exemption expires, so that it is
+	// remediated.
+	timer *time.Timer
+}
+
+// NewBreakGlass returns a BreakGlass which honors the exemptions for at most
+// maxDuration.
+func NewBreakGlass(maxDuration time.Duration) *BreakGlass {
+	return &BreakGlass{
+		maxDuration: maxDuration,
+		now:         time.Now,
+		exemptions:  make(map[core.ID]*exemption),
+	}
+}
+
+// exempted returns the time until which the remediation of the object is
+// paused, and true if it is exempted. It schedules a single call to refresh
+// when the exemption expires, which replaces the call scheduled for a previous
+// version of the object.
+func (b *BreakGlass) exempted(id core.ID, obj client.Object, refresh func()) (time.Time, bool) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	now := b.now()
+	until, found := metadata.BreakGlassUntil(obj)
+	if !found || !now.Before(until) {
+		b.forget(id)
+		return time.Time{}, false
+	}
+
+	e, found := b.exemptions[id]
+	if !found {
+		e = &exemption{deadline: now.Add(b.maxDuration)}
+		b.exemptions[id] = e
+	}
+	if until.After(e.deadline) {
+		klog.Warningf("The %s annotation of %q is more than %s away, so the exemption only lasts until %s",
+			metadata.BreakGlassUntilAnnotationKey, id, b.maxDuration, e.deadline.Format(time.RFC3339))
+		until = e.deadline
+	}
+	if !now.Before(until) {
+		return time.Time{}, false
+	}
+
+	if e.timer != nil {
+		if e.refreshAt.Equal(until) {
+			return until, true
+		}
+		e.timer.Stop()
+	}
+	e.refreshAt = until
+	e.timer = time.AfterFunc(until.Sub(now), refresh)
+	return until, true
+}
+
+// forget stops tracking the exemption of the object, whose annotation was
+// removed or has expired.
+func (b *BreakGlass) forget(id core.ID) {
+	e, found := b.exemptions[id]
+	if !found {
+		return
+	}
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+	delete(b.exemptions, id)
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"testing"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+func TestBreakGlass_Exempted(t *testing.T) {
+	start := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name      string
+		until     time.Time
+		elapsed   time.Duration
+		want      bool
+		wantUntil time.Time
+	}{
+		{
+			name:      "Exemption within the max duration is honored",
+			until:     start.Add(30 * time.Minute),
+			elapsed:   10 * time.Minute,
+			want:      true,
+			wantUntil: start.Add(30 * time.Minute),
+		},
+		{
+			name:    "Expired exemption is not honored",
+			until:   start.Add(30 * time.Minute),
+			elapsed: 40 * time.Minute,
+		},
+		{
+			name:      "Exemption longer than the max duration is capped",
+			until:     start.Add(24 * time.Hour),
+			elapsed:   10 * time.Minute,
+			want:      true,
+			wantUntil: start.Add(time.Hour),
+		},
+		{
+			name:    "Exemption is not honored past the max duration",
+			until:   start.Add(24 * time.Hour),
+			elapsed: 61 * time.Minute,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := start
+			b := NewBreakGlass(time.Hour)
+			b.now = func() time.Time { return now }
+			defer stopTimers(b)
+			obj := fake.RoleObject(core.Name("admin"), core.Namespace("shipping"),
+				core.Annotation(metadata.BreakGlassUntilAnnotationKey, tc.until.Format(time.RFC3339)))
+			id := core.IDOf(obj)
+
+			// The max duration starts when the remediator first sees the
+			// exemption.
+			if _, exempted := b.exempted(id, obj, func() {}); !exempted {
+				t.Fatalf("got exempted() false at the first event, want true")
+			}
+			now = start.Add(tc.elapsed)
+			until, exempted := b.exempted(id, obj, func() {})
+			if exempted != tc.want {
+				t.Errorf("got exempted() %t, want %t", exempted, tc.want)
+			}
+			if !until.Equal(tc.wantUntil) {
+				t.Errorf("got exempted() until %v, want %v", until, tc.wantUntil)
+			}
+		})
+	}
+}
+
+func TestBreakGlass_OneRefreshPerObject(t *testing.T) {
+	b := NewBreakGlass(time.Hour)
+	defer stopTimers(b)
+	until := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	obj := fake.RoleObject(core.Name("admin"), core.Namespace("shipping"),
+		core.Annotation(metadata.BreakGlassUntilAnnotationKey, until.Format(time.RFC3339)))
+	id := core.IDOf(obj)
+
+	b.exempted(id, obj, func() {})
+	timer := b.exemptions[id].timer
+	for i := 0; i < 3; i++ {
+		b.exempted(id, obj, func() {})
+	}
+	if got := b.exemptions[id].timer; got != timer {
+		t.Errorf("got a new refresh scheduled for each event, want a single refresh")
+	}
+
+	// Extending the exemption replaces the scheduled refresh.
+	extended := obj.DeepCopyObject().(*rbacv1.Role)
+	core.SetAnnotation(extended, metadata.BreakGlassUntilAnnotationKey, until.Add(10*time.Minute).Format(time.RFC3339))
+	b.exempted(id, extended, func() {})
+	if e := b.exemptions[id]; e.timer == timer || !e.refreshAt.Equal(until.Add(10*time.Minute)) {
+		t.Errorf("got refresh at %v, want the refresh replaced at %v", e.refreshAt, until.Add(10*time.Minute))
+	}
+
+	// Removing the annotation forgets the exemption.
+	core.RemoveAnnotations(extended, metadata.BreakGlassUntilAnnotationKey)
+	if _, exempted := b.exempted(id, extended, func() {}); exempted {
+		t.Errorf("got exempted() true after the annotation was removed, want false")
+	}
+	if len(b.exemptions) != 0 {
+		t.Errorf("got %d exemptions tracked after the annotation was removed, want 0", len(b.exemptions))
+	}
+}
+
+func stopTimers(b *BreakGlass) {
+	for id := range b.exemptions {
+		b.forget(id)
+	}
+}
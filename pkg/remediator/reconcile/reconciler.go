@@ -16,15 +16,20 @@ package reconcile
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/diff"
 	"kpt.dev/configsync/pkg/importer/analyzer/validation/nonhierarchical"
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/metrics"
+	"kpt.dev/configsync/pkg/remediator/drift"
 	"kpt.dev/configsync/pkg/status"
 	syncerclient "kpt.dev/configsync/pkg/syncer/client"
 	syncerreconcile "kpt.dev/configsync/pkg/syncer/reconcile"
@@ -48,6 +53,12 @@ type reconciler struct {
 	declared *declared.Resources
 
 	fightHandler fight.Handler
+
+	// driftPolicy is the drift policy of the RootSync/RepoSync, which the drift
+	// policy annotation of a declared object overrides.
+	driftPolicy configsync.DriftPolicy
+	// driftHandler records the drift that is reported instead of reverted.
+	driftHandler drift.Handler
 }
 
 // newReconciler instantiates a new reconciler.
@@ -57,6 +68,8 @@ func newReconciler(
 	applier syncerreconcile.Applier,
 	declared *declared.Resources,
 	fightHandler fight.Handler,
+	driftPolicy configsync.DriftPolicy,
+	driftHandler drift.Handler,
 ) *reconciler {
 	return &reconciler{
 		scope:        scope,
@@ -64,6 +77,8 @@ func newReconciler(
 		applier:      applier,
 		declared:     declared,
 		fightHandler: fightHandler,
+		driftPolicy:  driftPolicy,
+		driftHandler: driftHandler,
 	}
 }
 
@@ -110,7 +125,16 @@ func (r *reconciler) Remediate(ctx context.Context, id core.ID, obj client.Objec
 // Remediate takes diff (declared & actual) and ensures the server matches the
 // declared state.
 func (r *reconciler) remediate(ctx context.Context, id core.ID, objDiff diff.Diff) status.Error {
-	switch t := objDiff.Operation(r.scope, r.syncName); t {
+	t := objDiff.Operation(r.scope, r.syncName)
+	switch t {
+	case diff.Create, diff.Update, diff.Delete:
+		if r.objectDriftPolicy(objDiff) == configsync.DriftPolicyReport {
+			return r.reportDrift(id, t, objDiff)
+		}
+	}
+	r.driftHandler.RemoveDrift(id)
+
+	switch t {
 	case diff.NoOp:
 		return nil
 	case diff.Create:
@@ -159,6 +183,62 @@ func (r *reconciler) remediate(ctx context.Context, id core.ID, objDiff diff.Dif
 	}
 }
 
+// objectDriftPolicy returns the value of the drift policy annotation of the
+// declared object, if any, or the drift policy of the RootSync/RepoSync.
+func (r *reconciler) objectDriftPolicy(objDiff diff.Diff) configsync.DriftPolicy {
+	if objDiff.Declared != nil {
+		if policy, found := objDiff.Declared.GetAnnotations()[metadata.DriftPolicyAnnotationKey]; found {
+			return configsync.DriftPolicy(policy)
+		}
+	}
+	return r.driftPolicy
+}
+
+// reportDrift records the drift of an object instead of reverting it. The
+// drift is removed once the object matches its declaration again.
+func (r *reconciler) reportDrift(id core.ID, operation diff.Operation, objDiff diff.Diff) status.Error {
+	obj := objDiff.Declared
+	var fields []v1beta1.FieldDrift
+	if operation == diff.Update {
+		fieldDiffs, err := objDiff.FieldDiffs()
+		if err != nil {
+			return err
+		}
+		if len(fieldDiffs) == 0 {
+			// Updates are triggered by every watch event, even if the object
+			// still matches its declaration.
+			r.driftHandler.RemoveDrift(id)
+			return nil
+		}
+		for _, fd := range fieldDiffs {
+			fields = append(fields, fieldDrift(fd))
+		}
+	} else if operation == diff.Delete {
+		obj = objDiff.Actual
+	}
+	klog.V(3).Infof("Remediator reporting drift of object %v instead of running %s", id, operation)
+	r.driftHandler.AddDrift(id, v1beta1.ResourceDrift{
+		Resource:   status.ToResourceRef(obj),
+		Operation:  string(operation),
+		Fields:     fields,
+		LastUpdate: metav1.Now(),
+	})
+	return nil
+}
+
+// fieldDrift converts a field diff to a FieldDrift, with JSON encoded values.
+func fieldDrift(fd diff.FieldDiff) v1beta1.FieldDrift {
+	result := v1beta1.FieldDrift{Path: fd.Path}
+	// The values come from unstructured objects, so they always marshal.
+	declared, _ := json.Marshal(fd.Declared)
+	result.Declared = string(declared)
+	if fd.Actual != nil {
+		actual, _ := json.Marshal(fd.Actual)
+		result.Actual = string(actual)
+	}
+	return result
+}
+
 // GetClient returns the reconciler's underlying client.Client.
 func (r *reconciler) GetClient() client.Client {
 	return r.applier.GetClient()
@@ -18,6 +18,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
@@ -25,12 +27,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/importer/analyzer/validation/nonhierarchical"
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/metrics"
 	"kpt.dev/configsync/pkg/policycontroller"
+	"kpt.dev/configsync/pkg/remediator/drift"
 	"kpt.dev/configsync/pkg/status"
 	syncerclient "kpt.dev/configsync/pkg/syncer/client"
 	"kpt.dev/configsync/pkg/syncer/syncertest"
@@ -237,7 +241,7 @@ func TestRemediator_Reconcile(t *testing.T) {
 			// Simulate the Parser having already parsed the resource and recorded it.
 			d := makeDeclared(t, "unused", tc.declared)
 
-			r := newReconciler(declared.RootReconciler, configsync.RootSyncName, c.Applier(), d, testingfake.NewFightHandler())
+			r := newReconciler(declared.RootReconciler, configsync.RootSyncName, c.Applier(), d, testingfake.NewFightHandler(), configsync.DriftPolicyRemediate, drift.NewHandler())
 
 			// Get the triggering object for the reconcile event.
 			var obj client.Object
@@ -265,6 +269,123 @@ func TestRemediator_Reconcile(t *testing.T) {
 	}
 }
 
+func TestRemediator_ReportDrift(t *testing.T) {
+	testCases := []struct {
+		name        string
+		driftPolicy configsync.DriftPolicy
+		// declared is the state of the object as returned by the Parser.
+		declared client.Object
+		// actual is the current state of the object on the cluster.
+		actual client.Object
+		// want is the expected final state of the object on the cluster after
+		// reconciliation.
+		want client.Object
+		// wantDrift is the expected drift reported instead of remediated.
+		wantDrift []v1beta1.ResourceDrift
+	}{
+		{
+			name:        "report policy reports updates",
+			driftPolicy: configsync.DriftPolicyReport,
+			declared: fake.RoleObject(core.Namespace("shop"), core.Name("admin"),
+				syncertest.ManagementEnabled, core.Label("team", "payments")),
+			actual: fake.RoleObject(core.Namespace("shop"), core.Name("admin"),
+				syncertest.ManagementEnabled, core.Label("team", "sre")),
+			want: fake.RoleObject(core.Namespace("shop"), core.Name("admin"),
+				core.UID("1"), core.ResourceVersion("1"), core.Generation(1),
+				syncertest.ManagementEnabled, core.Label("team", "sre")),
+			wantDrift: []v1beta1.ResourceDrift{{
+				Resource: v1beta1.ResourceRef{
+					Name:      "admin",
+					Namespace: "shop",
+					GVK:       metav1.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+				},
+				Operation: "update",
+				Fields: []v1beta1.FieldDrift{{
+					Path:     ".metadata.labels.team",
+					Declared: `"payments"`,
+					Actual:   `"sre"`,
+				}},
+			}},
+		},
+		{
+			name:        "report policy reports creates",
+			driftPolicy: configsync.DriftPolicyReport,
+			declared: fake.RoleObject(core.Namespace("shop"), core.Name("admin"),
+				syncertest.ManagementEnabled),
+			wantDrift: []v1beta1.ResourceDrift{{
+				Resource: v1beta1.ResourceRef{
+					Name:      "admin",
+					Namespace: "shop",
+					GVK:       metav1.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+				},
+				Operation: "create",
+			}},
+		},
+		{
+			name:        "report policy without drift",
+			driftPolicy: configsync.DriftPolicyReport,
+			declared: fake.RoleObject(core.Namespace("shop"), core.Name("admin"),
+				syncertest.ManagementEnabled, core.Label("team", "payments")),
+			actual: fake.RoleObject(core.Namespace("shop"), core.Name("admin"),
+				syncertest.ManagementEnabled, core.Label("team", "payments"), core.Label("extra", "label")),
+			want: fake.RoleObject(core.Namespace("shop"), core.Name("admin"),
+				core.UID("1"), core.ResourceVersion("1"), core.Generation(1),
+				syncertest.ManagementEnabled, core.Label("team", "payments"), core.Label("extra", "label")),
+		},
+		{
+			name:        "annotation overrides the remediate policy",
+			driftPolicy: configsync.DriftPolicyRemediate,
+			declared: fake.RoleObject(core.Namespace("shop"), core.Name("admin"),
+				syncertest.ManagementEnabled, core.Annotation(metadata.DriftPolicyAnnotationKey, "report")),
+			wantDrift: []v1beta1.ResourceDrift{{
+				Resource: v1beta1.ResourceRef{
+					Name:      "admin",
+					Namespace: "shop",
+					GVK:       metav1.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+				},
+				Operation: "create",
+			}},
+		},
+		{
+			name:        "annotation overrides the report policy",
+			driftPolicy: configsync.DriftPolicyReport,
+			declared: fake.RoleObject(core.Namespace("shop"), core.Name("admin"),
+				syncertest.ManagementEnabled, core.Annotation(metadata.DriftPolicyAnnotationKey, "remediate")),
+			want: fake.RoleObject(core.Namespace("shop"), core.Name("admin"),
+				core.UID("1"), core.ResourceVersion("1"), core.Generation(1),
+				syncertest.ManagementEnabled, core.Annotation(metadata.DriftPolicyAnnotationKey, "remediate")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var existingObjs []client.Object
+			if tc.actual != nil {
+				existingObjs = append(existingObjs, tc.actual)
+			}
+			c := testingfake.NewClient(t, core.Scheme, existingObjs...)
+			d := makeDeclared(t, "unused", tc.declared)
+			driftHandler := drift.NewHandler()
+
+			r := newReconciler(declared.RootReconciler, configsync.RootSyncName, c.Applier(), d, testingfake.NewFightHandler(), tc.driftPolicy, driftHandler)
+
+			if err := r.Remediate(context.Background(), core.IDOf(tc.declared), tc.actual); err != nil {
+				t.Fatalf("got Reconcile() = %v, want nil", err)
+			}
+
+			if tc.want == nil {
+				c.Check(t)
+			} else {
+				c.Check(t, tc.want)
+			}
+			if diff := cmp.Diff(tc.wantDrift, driftHandler.Drift(),
+				cmpopts.IgnoreFields(v1beta1.ResourceDrift{}, "LastUpdate")); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
 func TestRemediator_Reconcile_Metrics(t *testing.T) {
 	testCases := []struct {
 		name string
@@ -362,7 +483,7 @@ func TestRemediator_Reconcile_Metrics(t *testing.T) {
 			fakeApplier.UpdateError = tc.updateError
 			fakeApplier.DeleteError = tc.deleteError
 
-			reconciler := newReconciler(declared.RootReconciler, configsync.RootSyncName, fakeApplier, d, testingfake.NewFightHandler())
+			reconciler := newReconciler(declared.RootReconciler, configsync.RootSyncName, fakeApplier, d, testingfake.NewFightHandler(), configsync.DriftPolicyRemediate, drift.NewHandler())
 
 			// Get the triggering object for the reconcile event.
 			var obj client.Object
@@ -23,8 +23,11 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/remediator/drift"
 	"kpt.dev/configsync/pkg/remediator/queue"
 	"kpt.dev/configsync/pkg/status"
 	syncerclient "kpt.dev/configsync/pkg/syncer/client"
@@ -38,14 +41,17 @@ import (
 type Worker struct {
 	objectQueue queue.Interface
 	reconciler  reconcilerInterface
+	breakGlass  *BreakGlass
 }
 
 // NewWorker returns a new Worker for the given queue and declared resources.
 func NewWorker(scope declared.Scope, syncName string, a syncerreconcile.Applier,
-	q *queue.ObjectQueue, d *declared.Resources, fh fight.Handler) *Worker {
+	q *queue.ObjectQueue, d *declared.Resources, fh fight.Handler,
+	driftPolicy configsync.DriftPolicy, dh drift.Handler, bg *BreakGlass) *Worker {
 	return &Worker{
 		objectQueue: q,
-		reconciler:  newReconciler(scope, syncName, a, d, fh),
+		reconciler:  newReconciler(scope, syncName, a, d, fh, driftPolicy, dh),
+		breakGlass:  bg,
 	}
 }
 
@@ -97,6 +103,19 @@ func (w *Worker) processNextObject(ctx context.Context) error {
 
 func (w *Worker) process(ctx context.Context, obj client.Object) error {
 	id := core.IDOf(obj)
+	refresh := func() {
+		if err := w.refresh(ctx, obj); err != nil {
+			klog.Errorf("Worker unable to refresh %q after its exemption expired: %v", id, err)
+		}
+	}
+	if until, exempted := w.breakGlass.exempted(id, obj, refresh); exempted {
+		// Remediation is paused until the exemption expires, at which point the
+		// live version of the object is remediated.
+		klog.Infof("Worker skipping %q exempted by the %s annotation until %s",
+			id, metadata.BreakGlassUntilAnnotationKey, until.Format(time.RFC3339))
+		w.objectQueue.Forget(obj)
+		return nil
+	}
 	var toRemediate client.Object
 	if queue.WasDeleted(ctx, obj) {
 		// Passing a nil Object to the reconciler signals that the accompanying ID
@@ -31,6 +31,7 @@ import (
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/kinds"
 	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/remediator/drift"
 	"kpt.dev/configsync/pkg/remediator/queue"
 	"kpt.dev/configsync/pkg/status"
 	"kpt.dev/configsync/pkg/syncer/syncertest"
@@ -81,7 +82,7 @@ func TestWorker_Run_Remediates(t *testing.T) {
 	}
 
 	d := makeDeclared(t, randomCommitHash(), declaredObjs...)
-	w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d, syncertestfake.NewFightHandler())
+	w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d, syncertestfake.NewFightHandler(), configsync.DriftPolicyRemediate, drift.NewHandler(), NewBreakGlass(time.Hour))
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -188,7 +189,7 @@ func TestWorker_Run_RemediatesExisting(t *testing.T) {
 	}
 
 	d := makeDeclared(t, randomCommitHash(), declaredObjs...)
-	w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d, syncertestfake.NewFightHandler())
+	w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d, syncertestfake.NewFightHandler(), configsync.DriftPolicyRemediate, drift.NewHandler(), NewBreakGlass(time.Hour))
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -296,7 +297,7 @@ func TestWorker_ProcessNextObject(t *testing.T) {
 			}
 
 			d := makeDeclared(t, randomCommitHash(), tc.declared...)
-			w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d, syncertestfake.NewFightHandler())
+			w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d, syncertestfake.NewFightHandler(), configsync.DriftPolicyRemediate, drift.NewHandler(), NewBreakGlass(time.Hour))
 
 			for _, obj := range tc.toProcess {
 				if err := w.processNextObject(context.Background()); err != nil {
@@ -316,7 +317,7 @@ func TestWorker_Run_CancelledWhenEmpty(t *testing.T) {
 	defer q.ShutDown()
 	c := testingfake.NewClient(t, core.Scheme)
 	d := makeDeclared(t, randomCommitHash()) // no resources declared
-	w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d, syncertestfake.NewFightHandler())
+	w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d, syncertestfake.NewFightHandler(), configsync.DriftPolicyRemediate, drift.NewHandler(), NewBreakGlass(time.Hour))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -389,7 +390,7 @@ func TestWorker_Run_CancelledWhenNotEmpty(t *testing.T) {
 
 	d := makeDeclared(t, randomCommitHash(), declaredObjs...)
 	a := &testingfake.Applier{Client: c}
-	w := NewWorker(declared.RootReconciler, configsync.RootSyncName, a, q, d, syncertestfake.NewFightHandler())
+	w := NewWorker(declared.RootReconciler, configsync.RootSyncName, a, q, d, syncertestfake.NewFightHandler(), configsync.DriftPolicyRemediate, drift.NewHandler(), NewBreakGlass(time.Hour))
 
 	// Run worker in the background
 	doneCh := make(chan struct{})
@@ -535,6 +536,48 @@ func randomCommitHash() string {
 	return uuid.NewString()
 }
 
+func TestWorker_ProcessBreakGlass(t *testing.T) {
+	testCases := []struct {
+		name    string
+		until   time.Time
+		wantErr bool
+	}{
+		{
+			name:  "Exempted object is not remediated",
+			until: time.Now().Add(time.Hour),
+		},
+		{
+			name:    "Object whose exemption expired is remediated",
+			until:   time.Now().Add(-time.Hour),
+			wantErr: true,
+		},
+		{
+			name:  "Exemption longer than the max duration is capped",
+			until: time.Now().Add(24 * time.Hour),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &Worker{
+				objectQueue: &fakeQueue{},
+				reconciler: fakeReconciler{
+					client:       syncertestfake.NewClient(t, core.Scheme),
+					remediateErr: status.APIServerError(errors.New("remediated"), "updating"),
+				},
+				breakGlass: NewBreakGlass(time.Hour),
+			}
+			obj := fake.RoleObject(core.Name("admin"), core.Namespace("shipping"),
+				core.Annotation(metadata.BreakGlassUntilAnnotationKey, tc.until.Format(time.RFC3339)))
+
+			err := w.process(context.Background(), obj)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("got process() error %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
 type fakeReconciler struct {
 	client       client.Client
 	remediateErr status.Error
@@ -17,13 +17,17 @@ package remediator
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/remediator/conflict"
+	"kpt.dev/configsync/pkg/remediator/drift"
 	"kpt.dev/configsync/pkg/remediator/queue"
 	"kpt.dev/configsync/pkg/remediator/reconcile"
 	"kpt.dev/configsync/pkg/remediator/watch"
@@ -60,6 +64,7 @@ type Remediator struct {
 
 	conflictHandler conflict.Handler
 	fightHandler    fight.Handler
+	driftHandler    drift.Handler
 }
 
 // Interface is a fake-able subset of the interface Remediator implements that
@@ -83,6 +88,9 @@ type Interface interface {
 	ConflictErrors() []status.ManagementConflictError
 	// FightErrors returns the fight errors (KNV2005) the remediator encounters.
 	FightErrors() []status.Error
+	// Drift returns the drift the remediator reported instead of reverting it,
+	// because of the report drift policy.
+	Drift() []v1beta1.ResourceDrift
 }
 
 var _ Interface = &Remediator{}
@@ -92,13 +100,22 @@ var _ Interface = &Remediator{}
 //
 // It is safe for decls to be modified after they have been passed into the
 // Remediator.
-func New(scope declared.Scope, syncName string, cfg *rest.Config, applier syncerreconcile.Applier, decls *declared.Resources, numWorkers int) (*Remediator, error) {
+//
+// If driftPolicy is report, the drift of the declared resources is reported
+// instead of reverted, unless the drift policy annotation of a resource says
+// otherwise.
+//
+// The break-glass exemptions of the managed objects are honored for at most
+// breakGlassMaxDuration.
+func New(scope declared.Scope, syncName string, cfg *rest.Config, applier syncerreconcile.Applier, decls *declared.Resources, numWorkers int, driftPolicy configsync.DriftPolicy, breakGlassMaxDuration time.Duration) (*Remediator, error) {
 	q := queue.New(string(scope))
 	workers := make([]*reconcile.Worker, numWorkers)
 	fightHandler := fight.NewHandler()
 	conflictHandler := conflict.NewHandler()
+	driftHandler := drift.NewHandler()
+	breakGlass := reconcile.NewBreakGlass(breakGlassMaxDuration)
 	for i := 0; i < numWorkers; i++ {
-		workers[i] = reconcile.NewWorker(scope, syncName, applier, q, decls, fightHandler)
+		workers[i] = reconcile.NewWorker(scope, syncName, applier, q, decls, fightHandler, driftPolicy, driftHandler, breakGlass)
 	}
 
 	remediator := &Remediator{
@@ -106,6 +123,7 @@ func New(scope declared.Scope, syncName string, cfg *rest.Config, applier syncer
 		objectQueue:     q,
 		fightHandler:    fightHandler,
 		conflictHandler: conflictHandler,
+		driftHandler:    driftHandler,
 	}
 
 	watchMgr, err := watch.NewManager(scope, syncName, cfg, q, decls, nil, conflictHandler)
@@ -214,3 +232,8 @@ func (r *Remediator) ConflictErrors() []status.ManagementConflictError {
 func (r *Remediator) FightErrors() []status.Error {
 	return r.fightHandler.FightErrors()
 }
+
+// Drift implements Interface.
+func (r *Remediator) Drift() []v1beta1.ResourceDrift {
+	return r.driftHandler.Drift()
+}
@@ -129,6 +129,13 @@ func SetReconcilerFinalizing(rs *v1beta1.RepoSync, reason, message string) (upda
 	return updated
 }
 
+// SetSuspended sets the Suspended condition to True.
+// Use RemoveCondition to remove this condition. It should never be set to False.
+func SetSuspended(rs *v1beta1.RepoSync, reason, message string) (updated bool) {
+	updated, _ = setCondition(rs, v1beta1.RepoSyncSuspended, metav1.ConditionTrue, reason, message, "", nil, nil, nil, now())
+	return updated
+}
+
 // SetReconcilerFinalizerFailure sets the ReconcilerFinalizerFailure condition.
 // If there are errors, the status is True, otherwise False.
 // Use RemoveCondition to remove this condition when the finalizer is done.
@@ -609,6 +609,78 @@ func TestSetReconcilerFinalizing(t *testing.T) {
 	}
 }
 
+func TestSetSuspended(t *testing.T) {
+	now = func() metav1.Time {
+		return initialNow
+	}
+	testCases := []struct {
+		name        string
+		rs          *v1beta1.RepoSync
+		reason      string
+		message     string
+		want        []v1beta1.RepoSyncCondition
+		wantUpdated bool
+	}{
+		{
+			name:    "Set new suspended condition",
+			rs:      fake.RepoSyncObjectV1Beta1(testNs, configsync.RepoSyncName),
+			reason:  "Suspend",
+			message: "Syncing is suspended by spec.suspend",
+			want: []v1beta1.RepoSyncCondition{
+				// Update and transition
+				{
+					Type:               v1beta1.RepoSyncSuspended,
+					Status:             metav1.ConditionTrue,
+					Reason:             "Suspend",
+					Message:            "Syncing is suspended by spec.suspend",
+					LastUpdateTime:     updatedNow,
+					LastTransitionTime: updatedNow,
+				},
+			},
+			wantUpdated: true,
+		},
+		{
+			name: "Update to change the reason and message",
+			rs: fake.RepoSyncObjectV1Beta1(testNs, configsync.RepoSyncName,
+				withConditions(
+					v1beta1.RepoSyncCondition{
+						Type:               v1beta1.RepoSyncSuspended,
+						Status:             metav1.ConditionTrue,
+						Reason:             "Suspend",
+						Message:            "Syncing is suspended by spec.suspend",
+						LastUpdateTime:     initialNow,
+						LastTransitionTime: initialNow,
+					})),
+			reason:  "OutsideAllowSyncWindows",
+			message: "Syncing is suspended outside of the allow sync windows",
+			want: []v1beta1.RepoSyncCondition{
+				// Update but no transition
+				{
+					Type:               v1beta1.RepoSyncSuspended,
+					Status:             metav1.ConditionTrue,
+					Reason:             "OutsideAllowSyncWindows",
+					Message:            "Syncing is suspended outside of the allow sync windows",
+					LastUpdateTime:     updatedNow,
+					LastTransitionTime: initialNow,
+				},
+			},
+			wantUpdated: true,
+		},
+	}
+	now = func() metav1.Time {
+		return updatedNow
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			updated := SetSuspended(tc.rs, tc.reason, tc.message)
+			if diff := cmp.Diff(tc.want, tc.rs.Status.Conditions); diff != "" {
+				t.Error(diff)
+			}
+			assert.Equal(t, tc.wantUpdated, updated, "updated")
+		})
+	}
+}
+
 func TestSetReconcilerFinalizerFailure(t *testing.T) {
 	deployment1 := fake.DeploymentObject()
 	deployment1ID := core.IDOf(deployment1)
@@ -655,6 +727,7 @@ func TestSetReconcilerFinalizerFailure(t *testing.T) {
 						{
 							Code:         "2009",
 							ErrorMessage: "KNV2009: failed to delete Deployment.apps, /default-name: fake error\n\nFor more information, see https://g.co/cloud/acm-errors#knv2009",
+							Hint:         status.Hint("2009"),
 						},
 					},
 					LastUpdateTime:     updatedNow,
@@ -723,6 +796,7 @@ func TestSetReconcilerFinalizerFailure(t *testing.T) {
 						{
 							Code:         "2009",
 							ErrorMessage: "KNV2009: failed to delete Deployment.apps, /default-name: fake error\n\nFor more information, see https://g.co/cloud/acm-errors#knv2009",
+							Hint:         status.Hint("2009"),
 						},
 					},
 					LastUpdateTime:     updatedNow,
@@ -0,0 +1,528 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import "sort"
+
+// CatalogVersion is the version of the format of the error catalog. It
+// changes when fields are removed or change meaning, not when codes are
+// added.
+const CatalogVersion = "v1"
+
+// ErrorCategory is the stage of syncing which reports an error.
+type ErrorCategory string
+
+const (
+	// SourceCategory is for errors fetching or reading the source of truth.
+	SourceCategory ErrorCategory = "source"
+	// RenderingCategory is for errors rendering the source of truth.
+	RenderingCategory ErrorCategory = "rendering"
+	// ValidationCategory is for errors in the configs of the source of truth.
+	ValidationCategory ErrorCategory = "validation"
+	// SyncCategory is for errors applying the configs to the cluster.
+	SyncCategory ErrorCategory = "sync"
+)
+
+// ErrorSeverity is whether an error blocks syncing.
+type ErrorSeverity string
+
+const (
+	// ErrorSeverityError is for errors which block syncing until they are
+	// fixed.
+	ErrorSeverityError ErrorSeverity = "error"
+	// ErrorSeverityWarning is for errors which do not block syncing, or which
+	// are resolved automatically.
+	ErrorSeverityWarning ErrorSeverity = "warning"
+)
+
+// CatalogEntry documents an error code.
+type CatalogEntry struct {
+	// Code is the error code, like "1012".
+	Code string `json:"code"`
+	// Description describes the problem reported by the error code.
+	Description string `json:"description"`
+	// Severity is whether the errors block syncing.
+	Severity ErrorSeverity `json:"severity"`
+	// Category is the stage of syncing which reports the errors.
+	Category ErrorCategory `json:"category"`
+	// Hint describes how to fix the errors.
+	Hint string `json:"hint"`
+}
+
+// ErrorCatalog is the exportable catalog of the error codes.
+type ErrorCatalog struct {
+	// Version is the CatalogVersion of the catalog.
+	Version string `json:"version"`
+	// Codes are the entries of the catalog, sorted by code.
+	Codes []CatalogEntry `json:"codes"`
+}
+
+// Catalog returns the catalog of the documented error codes.
+func Catalog() ErrorCatalog {
+	result := ErrorCatalog{Version: CatalogVersion}
+	for code, entry := range catalog {
+		entry.Code = code
+		result.Codes = append(result.Codes, entry)
+	}
+	sort.Slice(result.Codes, func(i, j int) bool {
+		return result.Codes[i].Code < result.Codes[j].Code
+	})
+	return result
+}
+
+// CatalogEntryFor returns the catalog entry of the error code, and false if
+// the code is not documented.
+func CatalogEntryFor(code string) (CatalogEntry, bool) {
+	entry, found := catalog[code]
+	entry.Code = code
+	return entry, found
+}
+
+// Hint returns how to fix the errors with the code, or an empty string if the
+// code is not documented.
+func Hint(code string) string {
+	return catalog[code].Hint
+}
+
+// catalog documents every error code which is in use, keyed by code. The
+// nomoserrors tests verify that every registered code has an entry.
+var catalog = map[string]CatalogEntry{
+	"1003": {
+		Description: "A Namespace directory has subdirectories.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Move the subdirectories out of the Namespace directory, or turn it into an abstract namespace directory by removing its Namespace config.",
+	},
+	"1004": {
+		Description: "A cluster-scoped config declares a NamespaceSelector annotation.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the configmanagement.gke.io/namespace-selector annotation from the cluster-scoped config.",
+	},
+	"1005": {
+		Description: "A config has an invalid configmanagement.gke.io/managed annotation.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Set the configmanagement.gke.io/managed annotation to \"disabled\", or remove it.",
+	},
+	"1006": {
+		Description: "A config cannot be parsed as its declared kind.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Fix the fields of the config so that they match the schema of its apiVersion and kind.",
+	},
+	"1007": {
+		Description: "A namespace-scoped config is declared in an abstract namespace directory.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Move the config to a Namespace directory, or select Namespaces with a NamespaceSelector.",
+	},
+	"1009": {
+		Description: "The metadata.namespace of a config does not match its Namespace directory.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Set metadata.namespace to the name of the directory containing the config, or remove it.",
+	},
+	"1010": {
+		Description: "A config declares an unsupported Config Sync annotation.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the configmanagement.gke.io/ and configsync.gke.io/ annotations which are not documented as user annotations.",
+	},
+	"1011": {
+		Description: "A config declares a Config Sync label.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the configmanagement.gke.io/ and configsync.gke.io/ labels from the config.",
+	},
+	"1013": {
+		Description: "A config refers to a ClusterSelector or NamespaceSelector which is not declared.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Declare the selector, fix the name in the selector annotation, or move the selector to a directory which applies to the config.",
+	},
+	"1014": {
+		Description: "A ClusterSelector or NamespaceSelector is invalid.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Fix spec.selector and spec.mode of the selector so that they form a valid, non-empty label selector.",
+	},
+	"1017": {
+		Description: "A hierarchical repository does not declare a Repo in system/.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Add a Repo config to the system/ directory, or use the unstructured source format.",
+	},
+	"1019": {
+		Description: "A Namespace is declared outside of namespaces/.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Move the Namespace config to a subdirectory of namespaces/ named after the Namespace.",
+	},
+	"1020": {
+		Description: "The name of a Namespace does not match its directory.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Rename the Namespace or its directory so that they match.",
+	},
+	"1021": {
+		Description: "The kind of a config is not known by the cluster.",
+		Severity:    ErrorSeverityWarning,
+		Category:    ValidationCategory,
+		Hint:        "Declare the CustomResourceDefinition of the kind in the source or install it on the cluster, or fix the apiVersion and kind of the config.",
+	},
+	"1027": {
+		Description: "The Repo declares an unsupported spec.version.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Set spec.version of the Repo to \"1.0.0\".",
+	},
+	"1028": {
+		Description: "A directory is named after a reserved Namespace.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Rename or remove the directory of the config-management-system Namespace.",
+	},
+	"1029": {
+		Description: "Several configs declare the same object.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Rename or remove the duplicate configs so that each object is declared once across all the sources.",
+	},
+	"1030": {
+		Description: "A directory declares several Namespaces.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the duplicate Namespace configs so that each directory declares at most one.",
+	},
+	"1031": {
+		Description: "A config does not declare metadata.name.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Set metadata.name of the config.",
+	},
+	"1032": {
+		Description: "An unstructured repository declares a kind which is only supported in hierarchical repositories.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the config, or convert the repository to the hierarchy source format.",
+	},
+	"1033": {
+		Description: "A system config is declared outside of system/.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Move the config to the system/ directory.",
+	},
+	"1034": {
+		Description: "The source declares a Namespace which Config Sync manages itself.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the config-management-system Namespace from the source.",
+	},
+	"1036": {
+		Description: "A config has an invalid metadata.name.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Rename the config with at most 253 lower case alphanumeric characters, '-' or '.', starting and ending with an alphanumeric character.",
+	},
+	"1038": {
+		Description: "A kind which is not allowed in namespaces/ is declared there.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Move the config to the cluster/, clusterregistry/ or system/ directory matching its kind.",
+	},
+	"1039": {
+		Description: "A config is declared in the wrong top-level directory for its scope.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Move cluster-scoped configs to cluster/, Clusters and ClusterSelectors to clusterregistry/, and namespace-scoped configs to namespaces/.",
+	},
+	"1041": {
+		Description: "A HierarchyConfig references a kind which does not support hierarchical inheritance.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the kind from the HierarchyConfig.",
+	},
+	"1042": {
+		Description: "A HierarchyConfig declares an invalid hierarchyMode.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Set hierarchyMode to \"none\" or \"inherit\".",
+	},
+	"1043": {
+		Description: "A CustomResourceDefinition is declared in a reserved API group.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Use an API group other than configmanagement.gke.io for the CustomResourceDefinition.",
+	},
+	"1044": {
+		Description: "An abstract namespace directory has no Namespace below it.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Add a Namespace directory below the abstract namespace directory, or remove its configs.",
+	},
+	"1045": {
+		Description: "A config declares a field which Config Sync does not allow, such as status.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the disallowed field from the config.",
+	},
+	"1046": {
+		Description: "A HierarchyConfig references a cluster-scoped kind.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the cluster-scoped kind from the HierarchyConfig.",
+	},
+	"1047": {
+		Description: "A CustomResourceDefinition is removed while its custom resources are still declared.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the custom resources in the same commit as their CustomResourceDefinition, or declare the CustomResourceDefinition again.",
+	},
+	"1048": {
+		Description: "The name of a CustomResourceDefinition does not match its plural name and group.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Set metadata.name of the CustomResourceDefinition to <spec.names.plural>.<spec.group>.",
+	},
+	"1050": {
+		Description: "A config uses a deprecated group and kind.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Update the apiVersion of the config to the supported group.",
+	},
+	"1052": {
+		Description: "A cluster-scoped config declares metadata.namespace.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove metadata.namespace from the cluster-scoped config.",
+	},
+	"1053": {
+		Description: "A namespace-scoped config declares neither a Namespace nor a NamespaceSelector.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Set metadata.namespace or the configmanagement.gke.io/namespace-selector annotation of the config.",
+	},
+	"1054": {
+		Description: "A config declares an annotation whose value is not a string.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Quote the values of the annotations.",
+	},
+	"1055": {
+		Description: "A config declares an invalid metadata.namespace.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Set metadata.namespace to a valid Namespace name: lower case alphanumeric characters or '-', starting and ending with an alphanumeric character.",
+	},
+	"1056": {
+		Description: "A managed config is declared in an unmanaged Namespace.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the configmanagement.gke.io/managed: disabled annotation from the Namespace, or add it to the configs of the Namespace.",
+	},
+	"1057": {
+		Description: "A config declares a reserved Hierarchy Controller label.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the labels ending with .tree.hnc.x-k8s.io/depth from the config.",
+	},
+	"1058": {
+		Description: "A config of a Namespace repository declares another Namespace.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove metadata.namespace from the config, or set it to the Namespace of the RepoSync.",
+	},
+	"1060": {
+		Description: "An object is declared in the sources of several RootSyncs or RepoSyncs.",
+		Severity:    ErrorSeverityError,
+		Category:    SyncCategory,
+		Hint:        "Remove the declaration of the object from all but one of the sources.",
+	},
+	"1061": {
+		Description: "The source of a RootSync or RepoSync is misconfigured.",
+		Severity:    ErrorSeverityError,
+		Category:    SourceCategory,
+		Hint:        "Fix the spec of the RootSync or RepoSync as described in the error message.",
+	},
+	"1064": {
+		Description: "The api-resources file passed to nomos vet cannot be parsed.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Generate the file again with `kubectl api-resources > api-resources.txt`.",
+	},
+	"1065": {
+		Description: "A CustomResourceDefinition is malformed.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Fix the fields of the CustomResourceDefinition so that they match its schema.",
+	},
+	"1066": {
+		Description: "A config declares both the legacy and the inline cluster selector annotations.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove either the configmanagement.gke.io/cluster-selector or the configsync.gke.io/cluster-name-selector annotation.",
+	},
+	"1067": {
+		Description: "The declared fields of a config cannot be encoded for the admission webhook.",
+		Severity:    ErrorSeverityWarning,
+		Category:    ValidationCategory,
+		Hint:        "Remove the fields which are not in the schema of the kind, or update the CustomResourceDefinition.",
+	},
+	"1068": {
+		Description: "The source cannot be rendered because of an error in it.",
+		Severity:    ErrorSeverityError,
+		Category:    RenderingCategory,
+		Hint:        "Run `kustomize build` on the reported path to reproduce the error, and fix the kustomization or the rendering options.",
+	},
+	"1069": {
+		Description: "A RootSync or RepoSync declares itself in its source.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Remove the RootSync or RepoSync from its own source, and declare it in another source.",
+	},
+	"1070": {
+		Description: "A config has an invalid configsync.gke.io/drift-policy annotation.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Set the configsync.gke.io/drift-policy annotation to \"remediate\" or \"report\", or remove it.",
+	},
+	"1071": {
+		Description: "A config has an invalid configsync.gke.io/apply-wave annotation.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Set the configsync.gke.io/apply-wave annotation to an integer, or remove it.",
+	},
+	"1072": {
+		Description: "A config violates a validation policy.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Fix the config so that it complies with the policy, or guard the policy expression with has() if it fails on optional fields.",
+	},
+	"1073": {
+		Description: "A ConfigMap declares an invalid validation policy.",
+		Severity:    ErrorSeverityError,
+		Category:    ValidationCategory,
+		Hint:        "Fix the policy in the data key of the ConfigMap: set a valid expression and a supported engine.",
+	},
+	"2001": {
+		Description: "A file or directory of the source cannot be read or written.",
+		Severity:    ErrorSeverityError,
+		Category:    SourceCategory,
+		Hint:        "Check that the path exists and that the file permissions allow the reconciler to read it.",
+	},
+	"2002": {
+		Description: "A request to the Kubernetes API server failed.",
+		Severity:    ErrorSeverityError,
+		Category:    SyncCategory,
+		Hint:        "Check the health of the API server and of the reconciler's connection to it. The request is retried.",
+	},
+	"2003": {
+		Description: "An operating system call failed.",
+		Severity:    ErrorSeverityError,
+		Category:    SourceCategory,
+		Hint:        "Check the disk space and the volumes of the reconciler Pod.",
+	},
+	"2004": {
+		Description: "The source of truth cannot be fetched.",
+		Severity:    ErrorSeverityError,
+		Category:    SourceCategory,
+		Hint:        "Check the repository URL, the revision, the credentials in spec.*.secretRef and the network access of the reconciler Pod.",
+	},
+	"2005": {
+		Description: "Config Sync and another controller or user keep changing the same object.",
+		Severity:    ErrorSeverityError,
+		Category:    SyncCategory,
+		Hint:        "Find the other writer of the object and stop it from changing the declared fields, or remove the fields from the source.",
+	},
+	"2006": {
+		Description: "A new commit would delete every object of a kind, such as every Namespace.",
+		Severity:    ErrorSeverityError,
+		Category:    SourceCategory,
+		Hint:        "Check that the source is not accidentally empty. To delete everything on purpose, remove the objects in several commits.",
+	},
+	"2008": {
+		Description: "The cached state of an object does not match the cluster.",
+		Severity:    ErrorSeverityWarning,
+		Category:    SyncCategory,
+		Hint:        "No action is needed unless the error persists. The object is refreshed and the request is retried.",
+	},
+	"2009": {
+		Description: "Objects cannot be applied, pruned or deleted.",
+		Severity:    ErrorSeverityError,
+		Category:    SyncCategory,
+		Hint:        "Read the wrapped error for the failing object, and fix its declaration or the permissions of the reconciler.",
+	},
+	"2010": {
+		Description: "An operation on an object failed.",
+		Severity:    ErrorSeverityError,
+		Category:    SyncCategory,
+		Hint:        "Read the error message for the failing object and fix its declaration.",
+	},
+	"2011": {
+		Description: "Objects expected on the cluster were not found.",
+		Severity:    ErrorSeverityError,
+		Category:    SyncCategory,
+		Hint:        "Check whether another controller or user deletes the objects. The objects are created again on the next sync.",
+	},
+	"2012": {
+		Description: "The source declares several objects of a kind which must be unique.",
+		Severity:    ErrorSeverityError,
+		Category:    SyncCategory,
+		Hint:        "Remove the duplicate objects so that at most one remains.",
+	},
+	"2013": {
+		Description: "The reconciler does not have permission to manage an object.",
+		Severity:    ErrorSeverityError,
+		Category:    SyncCategory,
+		Hint:        "Grant the reconciler's service account the RBAC permissions on the kind, for example with a RoleBinding for a RepoSync.",
+	},
+	"2014": {
+		Description: "An invalid admission webhook configuration was removed.",
+		Severity:    ErrorSeverityWarning,
+		Category:    SyncCategory,
+		Hint:        "No action is needed. Do not edit the Config Sync ValidatingWebhookConfiguration.",
+	},
+	"2015": {
+		Description: "The source cannot be rendered because of an internal error.",
+		Severity:    ErrorSeverityError,
+		Category:    RenderingCategory,
+		Hint:        "Check the logs of the hydration-controller container. The rendering is retried.",
+	},
+	"2016": {
+		Description: "A transient error occurred.",
+		Severity:    ErrorSeverityWarning,
+		Category:    SyncCategory,
+		Hint:        "No action is needed unless the error persists. The operation is retried.",
+	},
+	"2017": {
+		Description: "No signature of the source is valid for the trusted keys.",
+		Severity:    ErrorSeverityError,
+		Category:    SourceCategory,
+		Hint:        "Sign the artifact with a trusted key, or add its public key to the verification configuration of the RootSync or RepoSync.",
+	},
+	"9998": {
+		Description: "Config Sync reached a state it should never reach.",
+		Severity:    ErrorSeverityError,
+		Category:    SyncCategory,
+		Hint:        "Report a bug with the error message and the logs of the reconciler.",
+	},
+	"9999": {
+		Description: "An error is not documented yet.",
+		Severity:    ErrorSeverityError,
+		Category:    SyncCategory,
+		Hint:        "Read the error message. Report a bug if the cause is not clear.",
+	},
+}
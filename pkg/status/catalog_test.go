@@ -0,0 +1,80 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	validSeverities := map[ErrorSeverity]bool{ErrorSeverityError: true, ErrorSeverityWarning: true}
+	validCategories := map[ErrorCategory]bool{
+		SourceCategory:     true,
+		RenderingCategory:  true,
+		ValidationCategory: true,
+		SyncCategory:       true,
+	}
+
+	catalog := Catalog()
+	if catalog.Version != CatalogVersion {
+		t.Errorf("got Version %q, want %q", catalog.Version, CatalogVersion)
+	}
+	if !sort.SliceIsSorted(catalog.Codes, func(i, j int) bool {
+		return catalog.Codes[i].Code < catalog.Codes[j].Code
+	}) {
+		t.Error("got unsorted Codes")
+	}
+	for _, entry := range catalog.Codes {
+		if entry.Description == "" {
+			t.Errorf("KNV%s: missing Description", entry.Code)
+		}
+		if entry.Hint == "" {
+			t.Errorf("KNV%s: missing Hint", entry.Code)
+		}
+		if !validSeverities[entry.Severity] {
+			t.Errorf("KNV%s: invalid Severity %q", entry.Code, entry.Severity)
+		}
+		if !validCategories[entry.Category] {
+			t.Errorf("KNV%s: invalid Category %q", entry.Code, entry.Category)
+		}
+	}
+}
+
+func TestCatalog_NonBlockingErrorsAreWarnings(t *testing.T) {
+	for code := range nonBlockingErrorCodes {
+		entry, found := CatalogEntryFor(code)
+		if !found {
+			t.Errorf("KNV%s: missing catalog entry", code)
+			continue
+		}
+		if entry.Severity != ErrorSeverityWarning {
+			t.Errorf("KNV%s: got Severity %q, want %q", code, entry.Severity, ErrorSeverityWarning)
+		}
+	}
+}
+
+func TestHint(t *testing.T) {
+	if got := Hint(UnknownKindErrorCode); got == "" {
+		t.Errorf("Hint(%q) = %q, want non-empty", UnknownKindErrorCode, got)
+	}
+	if got := Hint("0000"); got != "" {
+		t.Errorf("Hint(%q) = %q, want empty", "0000", got)
+	}
+	cse := UndocumentedError("foo").ToCSE()
+	if cse.Hint != Hint(UndocumentedErrorCode) {
+		t.Errorf("got ToCSE().Hint %q, want %q", cse.Hint, Hint(UndocumentedErrorCode))
+	}
+}
@@ -209,7 +209,9 @@ func fromResourceError(err ResourceError) v1.ConfigManagementError {
 	return cme
 }
 
-func toResourceRef(r client.Object) v1beta1.ResourceRef {
+// ToResourceRef returns the ResourceRef that identifies the object in the
+// RootSync/RepoSync status.
+func ToResourceRef(r client.Object) v1beta1.ResourceRef {
 	gvk := r.GetObjectKind().GroupVersionKind()
 	return v1beta1.ResourceRef{
 		SourcePath: GetSourceAnnotation(r),
@@ -227,6 +229,7 @@ func cseFromError(err Error) v1beta1.ConfigSyncError {
 	return v1beta1.ConfigSyncError{
 		Code:         err.Code(),
 		ErrorMessage: err.Error(),
+		Hint:         Hint(err.Code()),
 	}
 }
 
@@ -243,7 +246,7 @@ func cseFromPathError(err PathError) v1beta1.ConfigSyncError {
 func cseFromResourceError(err ResourceError) v1beta1.ConfigSyncError {
 	cse := cseFromError(err)
 	for _, r := range err.Resources() {
-		cse.Resources = append(cse.Resources, toResourceRef(r))
+		cse.Resources = append(cse.Resources, ToResourceRef(r))
 	}
 	return cse
 }
@@ -42,3 +42,9 @@ func HydrationError(code string, err error) Error {
 		return internalHydrationErrorBuilder.Wrap(err).Build()
 	}
 }
+
+// KustomizeHydrationError returns a user actionable hydration error for the
+// failing kustomization at slashPath within the repository.
+func KustomizeHydrationError(err error, slashPath string) Error {
+	return actionableHydrationErrorBuilder.Wrap(err).BuildWithPaths(path{slashPath: slashPath})
+}
@@ -0,0 +1,207 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncwindow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// macros are the predefined schedules supported in place of a cron expression.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// field describes the allowed values of a cron field.
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: monthNames}
+	// Both 0 and 7 are Sunday.
+	dowField = field{name: "day of week", min: 0, max: 7, names: dayNames}
+)
+
+// schedule is a parsed cron expression. Each field is a bit set of the values
+// that match.
+type schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar are true if the day of month or the day of week is
+	// unrestricted. If both are restricted, a day matches if either matches.
+	domStar, dowStar bool
+}
+
+// parseSchedule parses a cron expression in the standard five-field format:
+// minute, hour, day of month, month, and day of week. Each field is a comma
+// separated list of `*`, values, or ranges, with an optional `/step`.
+func parseSchedule(expr string) (*schedule, error) {
+	if macro, found := macros[strings.ToLower(strings.TrimSpace(expr))]; found {
+		expr = macro
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, found %d", expr, len(parts))
+	}
+	s := &schedule{
+		domStar: parts[2] == "*" || parts[2] == "?",
+		dowStar: parts[4] == "*" || parts[4] == "?",
+	}
+	var err error
+	if s.minute, err = parseField(parts[0], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(parts[1], hourField); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(parts[2], domField); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(parts[3], monthField); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(parts[4], dowField); err != nil {
+		return nil, err
+	}
+	// Sunday can be written as 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseField returns the bit set of the values matched by a cron field.
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangeExpr = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in the %s field", part[i+1:], f.name)
+			}
+		}
+		low, high := f.min, f.max
+		if rangeExpr != "*" && rangeExpr != "?" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if low, err = parseValue(bounds[0], f); err != nil {
+				return 0, err
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = parseValue(bounds[1], f); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// `N/step` means every step from N.
+				high = f.max
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in the %s field", rangeExpr, f.name)
+			}
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseValue parses a number or a name of a cron field.
+func parseValue(expr string, f field) (int, error) {
+	if v, found := f.names[strings.ToLower(expr)]; found {
+		return v, nil
+	}
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q in the %s field: must be between %d and %d", expr, f.name, f.min, f.max)
+	}
+	return v, nil
+}
+
+// matches returns true if the schedule starts at the minute of t.
+func (s *schedule) matches(t time.Time) bool {
+	return s.minute&(1<<uint(t.Minute())) != 0 &&
+		s.hour&(1<<uint(t.Hour())) != 0 &&
+		s.month&(1<<uint(t.Month())) != 0 &&
+		s.dayMatches(t)
+}
+
+// dayMatches returns true if the schedule matches the day of t.
+func (s *schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// prev returns the latest start of the schedule at or before t, and false if
+// the schedule does not start after the given limit.
+//
+// Rather than testing every minute, prev skips back over a whole month, day or
+// hour whenever that field does not match.
+func (s *schedule) prev(t, limit time.Time) (time.Time, bool) {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+	for t.After(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			// Skip to the last minute of the previous month.
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).Add(-time.Minute)
+		case !s.dayMatches(t):
+			// Skip to the last minute of the previous day.
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-time.Minute)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			// Skip to the last minute of the previous hour.
+			t = t.Add(-time.Duration(t.Minute()+1) * time.Minute)
+		default:
+			for m := t.Minute(); m >= 0; m-- {
+				if s.minute&(1<<uint(m)) != 0 {
+					start := t.Add(-time.Duration(t.Minute()-m) * time.Minute)
+					return start, start.After(limit)
+				}
+			}
+			t = t.Add(-time.Duration(t.Minute()+1) * time.Minute)
+		}
+	}
+	return time.Time{}, false
+}
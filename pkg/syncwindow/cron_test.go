@@ -0,0 +1,253 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncwindow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	testCases := []struct {
+		expr    string
+		time    string
+		want    bool
+		wantErr bool
+	}{
+		{expr: "0 18 * * 5", time: "2022-09-16T18:00:00Z", want: true},
+		{expr: "0 18 * * 5", time: "2022-09-16T18:01:00Z", want: false},
+		{expr: "0 18 * * 5", time: "2022-09-15T18:00:00Z", want: false},
+		{expr: "0 18 * * FRI", time: "2022-09-16T18:00:00Z", want: true},
+		{expr: "0 18 * * 0", time: "2022-09-18T18:00:00Z", want: true},
+		{expr: "0 0 * * 7", time: "2022-09-18T00:00:00Z", want: true},
+		{expr: "0 0 1 dec *", time: "2022-12-01T00:00:00Z", want: true},
+		{expr: "0 0 1 Dec *", time: "2022-11-01T00:00:00Z", want: false},
+		// Ranges.
+		{expr: "0 9-17 * * *", time: "2022-09-16T09:00:00Z", want: true},
+		{expr: "0 9-17 * * *", time: "2022-09-16T17:00:00Z", want: true},
+		{expr: "0 9-17 * * *", time: "2022-09-16T18:00:00Z", want: false},
+		{expr: "0 0 * * mon-fri", time: "2022-09-16T00:00:00Z", want: true},
+		{expr: "0 0 * * mon-fri", time: "2022-09-17T00:00:00Z", want: false},
+		{expr: "0 0 * jun-aug *", time: "2022-07-04T00:00:00Z", want: true},
+		{expr: "0 0 * * 5-7", time: "2022-09-18T00:00:00Z", want: true},
+		// Lists.
+		{expr: "0,30 * * * *", time: "2022-09-16T10:30:00Z", want: true},
+		{expr: "0,30 * * * *", time: "2022-09-16T10:15:00Z", want: false},
+		{expr: "0 0 1,15 * *", time: "2022-09-15T00:00:00Z", want: true},
+		// Steps.
+		{expr: "*/15 * * * *", time: "2022-09-16T10:45:00Z", want: true},
+		{expr: "*/15 * * * *", time: "2022-09-16T10:46:00Z", want: false},
+		{expr: "30 9-17/2 * * mon-fri", time: "2022-09-14T13:30:00Z", want: true},
+		{expr: "30 9-17/2 * * mon-fri", time: "2022-09-14T14:30:00Z", want: false},
+		{expr: "5/20 * * * *", time: "2022-09-16T10:45:00Z", want: true},
+		{expr: "5/20 * * * *", time: "2022-09-16T10:40:00Z", want: false},
+		{expr: "0 0 */2 * *", time: "2022-09-03T00:00:00Z", want: true},
+		{expr: "0 0 */2 * *", time: "2022-09-02T00:00:00Z", want: false},
+		// If both the day of month and the day of week are restricted, either matches.
+		{expr: "0 0 1 * 1", time: "2022-09-12T00:00:00Z", want: true},
+		{expr: "0 0 1 * 1", time: "2022-09-01T00:00:00Z", want: true},
+		{expr: "0 0 1 * 1", time: "2022-09-02T00:00:00Z", want: false},
+		// If either is unrestricted, only the other one applies.
+		{expr: "0 0 1 * *", time: "2022-09-12T00:00:00Z", want: false},
+		{expr: "0 0 * * 1", time: "2022-09-01T00:00:00Z", want: false},
+		{expr: "0 0 ? * 1", time: "2022-09-12T00:00:00Z", want: true},
+		{expr: "0 0 1-7 * */7", time: "2022-09-11T00:00:00Z", want: true},
+		// Macros.
+		{expr: "@daily", time: "2022-09-02T00:00:00Z", want: true},
+		{expr: "@hourly", time: "2022-09-02T13:00:00Z", want: true},
+		{expr: "@weekly", time: "2022-09-18T00:00:00Z", want: true},
+		{expr: "@weekly", time: "2022-09-19T00:00:00Z", want: false},
+		{expr: "@monthly", time: "2022-10-01T00:00:00Z", want: true},
+		{expr: "@Yearly", time: "2023-01-01T00:00:00Z", want: true},
+		// Invalid expressions.
+		{expr: "", wantErr: true},
+		{expr: "@fortnightly", wantErr: true},
+		{expr: "0 18 * *", wantErr: true},
+		{expr: "0 18 * * * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "-1 * * * *", wantErr: true},
+		{expr: "0 24 * * *", wantErr: true},
+		{expr: "0 0 0 * *", wantErr: true},
+		{expr: "0 0 32 * *", wantErr: true},
+		{expr: "0 0 * 13 *", wantErr: true},
+		{expr: "0 0 * * 8", wantErr: true},
+		{expr: "0 20-18 * * *", wantErr: true},
+		{expr: "0 1-2-3 * * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "*/-5 * * * *", wantErr: true},
+		{expr: "*/x * * * *", wantErr: true},
+		{expr: "0,,30 * * * *", wantErr: true},
+		{expr: "0 0 * * sunday", wantErr: true},
+		{expr: "0 0 * * L", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr+" "+tc.time, func(t *testing.T) {
+			s, err := parseSchedule(tc.expr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseSchedule() got error %v, want error %t", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			at, err := time.Parse(time.RFC3339, tc.time)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.matches(at); got != tc.want {
+				t.Errorf("matches(%s) = %t, want %t", tc.time, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSchedulePrev(t *testing.T) {
+	testCases := []struct {
+		name      string
+		expr      string
+		time      string
+		limit     string
+		want      string
+		wantFound bool
+	}{
+		{
+			name:      "start at the current minute",
+			expr:      "0 18 * * 5",
+			time:      "2022-09-16T18:00:30Z",
+			limit:     "2022-09-16T00:00:00Z",
+			want:      "2022-09-16T18:00:00Z",
+			wantFound: true,
+		},
+		{
+			name:      "earlier minute in the same hour",
+			expr:      "0,20,40 * * * *",
+			time:      "2022-09-16T18:39:00Z",
+			limit:     "2022-09-16T00:00:00Z",
+			want:      "2022-09-16T18:20:00Z",
+			wantFound: true,
+		},
+		{
+			name:      "previous hour",
+			expr:      "45 */6 * * *",
+			time:      "2022-09-16T18:30:00Z",
+			limit:     "2022-09-16T00:00:00Z",
+			want:      "2022-09-16T12:45:00Z",
+			wantFound: true,
+		},
+		{
+			name:      "previous week",
+			expr:      "0 18 * * FRI",
+			time:      "2022-09-16T17:59:00Z",
+			limit:     "2022-09-01T00:00:00Z",
+			want:      "2022-09-09T18:00:00Z",
+			wantFound: true,
+		},
+		{
+			name:      "previous year",
+			expr:      "@yearly",
+			time:      "2022-09-16T18:00:00Z",
+			limit:     "2021-09-16T18:00:00Z",
+			want:      "2022-01-01T00:00:00Z",
+			wantFound: true,
+		},
+		{
+			name:      "last day of a short month",
+			expr:      "0 0 31 * *",
+			time:      "2022-09-16T18:00:00Z",
+			limit:     "2022-01-01T00:00:00Z",
+			want:      "2022-08-31T00:00:00Z",
+			wantFound: true,
+		},
+		{
+			name:      "leap day",
+			expr:      "0 12 29 feb *",
+			time:      "2022-09-16T18:00:00Z",
+			limit:     "2019-01-01T00:00:00Z",
+			want:      "2020-02-29T12:00:00Z",
+			wantFound: true,
+		},
+		{
+			name:      "day of month or day of week",
+			expr:      "0 0 13 * 5",
+			time:      "2022-09-15T00:00:00Z",
+			limit:     "2022-09-01T00:00:00Z",
+			want:      "2022-09-13T00:00:00Z",
+			wantFound: true,
+		},
+		{
+			name:  "start at the limit",
+			expr:  "0 18 * * 5",
+			time:  "2022-09-16T19:00:00Z",
+			limit: "2022-09-16T18:00:00Z",
+		},
+		{
+			name:  "no start after the limit",
+			expr:  "0 0 29 feb *",
+			time:  "2022-09-16T18:00:00Z",
+			limit: "2020-03-01T00:00:00Z",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := parseSchedule(tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			at := mustParseTime(t, tc.time)
+			got, found := s.prev(at, mustParseTime(t, tc.limit))
+			if found != tc.wantFound {
+				t.Fatalf("prev() found = %t, want %t", found, tc.wantFound)
+			}
+			if !found {
+				return
+			}
+			if want := mustParseTime(t, tc.want); !got.Equal(want) {
+				t.Errorf("prev() = %s, want %s", got.Format(time.RFC3339), tc.want)
+			}
+		})
+	}
+}
+
+func TestSchedulePrevTimeZone(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	s, err := parseSchedule("30 1 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2022-11-06 01:30 happens twice in New York; prev returns the later one
+	// when the search starts after both.
+	now := time.Date(2022, time.November, 6, 3, 0, 0, 0, loc)
+	got, found := s.prev(now, now.Add(-24*time.Hour))
+	if !found {
+		t.Fatal("prev() found no start")
+	}
+	// 01:30 EST is 06:30 UTC.
+	if want := time.Date(2022, time.November, 6, 6, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("prev() = %s, want %s", got, want.In(loc))
+	}
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	result, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
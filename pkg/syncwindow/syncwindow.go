@@ -0,0 +1,167 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package syncwindow decides whether a reconciler is suspended, from the
+// spec.suspend and spec.syncWindows fields of a RootSync or RepoSync.
+package syncwindow
+
+import (
+	"fmt"
+	"time"
+
+	// Embed the time zone database, which the reconciler image does not ship.
+	_ "time/tzdata"
+
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+)
+
+const (
+	// ReasonSuspend is the reason of the Suspended condition when spec.suspend
+	// is true.
+	ReasonSuspend = "Suspend"
+	// ReasonDenyWindow is the reason of the Suspended condition during a deny
+	// window.
+	ReasonDenyWindow = "DenySyncWindow"
+	// ReasonOutsideAllowWindows is the reason of the Suspended condition
+	// outside of the allow windows.
+	ReasonOutsideAllowWindows = "OutsideAllowSyncWindows"
+)
+
+// Windows holds the parsed spec.suspend and spec.syncWindows fields.
+type Windows struct {
+	suspend bool
+	windows []window
+}
+
+type window struct {
+	kind     configsync.SyncWindowKind
+	expr     string
+	schedule *schedule
+	duration time.Duration
+	location *time.Location
+}
+
+// State describes whether the reconciler is suspended, and why.
+type State struct {
+	// Suspended is true if the reconciler must not sync new commits nor
+	// correct drift.
+	Suspended bool
+	// Reason is the reason of the Suspended condition.
+	Reason string
+	// Message is the message of the Suspended condition.
+	Message string
+}
+
+// New parses the sync windows.
+func New(suspend bool, syncWindows []v1beta1.SyncWindow) (*Windows, error) {
+	w := &Windows{suspend: suspend}
+	for _, sw := range syncWindows {
+		parsed, err := parseWindow(sw)
+		if err != nil {
+			return nil, err
+		}
+		w.windows = append(w.windows, parsed)
+	}
+	return w, nil
+}
+
+// Validate returns an error if the sync window is invalid.
+func Validate(sw v1beta1.SyncWindow) error {
+	_, err := parseWindow(sw)
+	return err
+}
+
+func parseWindow(sw v1beta1.SyncWindow) (window, error) {
+	switch sw.Kind {
+	case configsync.SyncWindowAllow, configsync.SyncWindowDeny:
+	default:
+		return window{}, fmt.Errorf("invalid sync window kind %q: must be one of %s or %s", sw.Kind, configsync.SyncWindowAllow, configsync.SyncWindowDeny)
+	}
+	s, err := parseSchedule(sw.Schedule)
+	if err != nil {
+		return window{}, err
+	}
+	if sw.Duration.Duration <= 0 {
+		return window{}, fmt.Errorf("invalid duration %q of the sync window %q: must be positive", sw.Duration.Duration, sw.Schedule)
+	}
+	location := time.UTC
+	if sw.TimeZone != "" {
+		location, err = time.LoadLocation(sw.TimeZone)
+		if err != nil {
+			return window{}, fmt.Errorf("invalid time zone of the sync window %q: %v", sw.Schedule, err)
+		}
+	}
+	return window{
+		kind:     sw.Kind,
+		expr:     sw.Schedule,
+		schedule: s,
+		duration: sw.Duration.Duration,
+		location: location,
+	}, nil
+}
+
+// State returns whether the reconciler is suspended at the given time.
+// Syncing is suspended if spec.suspend is true, during any deny window, and,
+// if there is any allow window, outside of all the allow windows.
+func (w *Windows) State(now time.Time) State {
+	if w == nil {
+		return State{}
+	}
+	if w.suspend {
+		return State{
+			Suspended: true,
+			Reason:    ReasonSuspend,
+			Message:   "Syncing is suspended by spec.suspend",
+		}
+	}
+	hasAllowWindow := false
+	inAllowWindow := false
+	for _, sw := range w.windows {
+		end, active := sw.activeUntil(now)
+		switch sw.kind {
+		case configsync.SyncWindowDeny:
+			if active {
+				return State{
+					Suspended: true,
+					Reason:    ReasonDenyWindow,
+					Message: fmt.Sprintf("Syncing is suspended by the deny sync window %q until %s",
+						sw.expr, end.Format(time.RFC3339)),
+				}
+			}
+		case configsync.SyncWindowAllow:
+			hasAllowWindow = true
+			inAllowWindow = inAllowWindow || active
+		}
+	}
+	if hasAllowWindow && !inAllowWindow {
+		return State{
+			Suspended: true,
+			Reason:    ReasonOutsideAllowWindows,
+			Message:   "Syncing is suspended outside of the allow sync windows",
+		}
+	}
+	return State{}
+}
+
+// activeUntil returns the end of the occurrence of the window that includes
+// now, if any.
+func (sw window) activeUntil(now time.Time) (time.Time, bool) {
+	now = now.In(sw.location)
+	start, found := sw.schedule.prev(now, now.Add(-sw.duration))
+	if !found {
+		return time.Time{}, false
+	}
+	return start.Add(sw.duration), true
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncwindow
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+)
+
+func syncWindow(kind configsync.SyncWindowKind, schedule string, duration time.Duration, timeZone string) v1beta1.SyncWindow {
+	return v1beta1.SyncWindow{
+		Kind:     kind,
+		Schedule: schedule,
+		Duration: metav1.Duration{Duration: duration},
+		TimeZone: timeZone,
+	}
+}
+
+func TestWindowsState(t *testing.T) {
+	// Friday 18:00 to Monday 06:00, Paris time.
+	weekendFreeze := syncWindow(configsync.SyncWindowDeny, "0 18 * * 5", 60*time.Hour, "Europe/Paris")
+	// Weekdays 09:00 to 17:00 UTC.
+	businessHours := syncWindow(configsync.SyncWindowAllow, "0 9 * * 1-5", 8*time.Hour, "")
+
+	testCases := []struct {
+		name        string
+		suspend     bool
+		windows     []v1beta1.SyncWindow
+		time        string
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name: "no window",
+			time: "2022-09-16T18:00:00Z",
+		},
+		{
+			name:        "suspend",
+			suspend:     true,
+			windows:     []v1beta1.SyncWindow{businessHours},
+			time:        "2022-09-14T10:00:00Z",
+			wantReason:  ReasonSuspend,
+			wantMessage: "Syncing is suspended by spec.suspend",
+		},
+		{
+			name:    "before a deny window",
+			windows: []v1beta1.SyncWindow{weekendFreeze},
+			time:    "2022-09-16T15:59:00Z",
+		},
+		{
+			name:        "during a deny window",
+			windows:     []v1beta1.SyncWindow{weekendFreeze},
+			time:        "2022-09-18T12:00:00Z",
+			wantReason:  ReasonDenyWindow,
+			wantMessage: `Syncing is suspended by the deny sync window "0 18 * * 5" until 2022-09-19T06:00:00+02:00`,
+		},
+		{
+			name:    "after a deny window",
+			windows: []v1beta1.SyncWindow{weekendFreeze},
+			time:    "2022-09-19T04:00:00Z",
+		},
+		{
+			name:    "during an allow window",
+			windows: []v1beta1.SyncWindow{businessHours},
+			time:    "2022-09-14T16:59:00Z",
+		},
+		{
+			name:        "outside of the allow windows",
+			windows:     []v1beta1.SyncWindow{businessHours},
+			time:        "2022-09-14T17:00:00Z",
+			wantReason:  ReasonOutsideAllowWindows,
+			wantMessage: "Syncing is suspended outside of the allow sync windows",
+		},
+		{
+			name:        "deny window overrides allow window",
+			windows:     []v1beta1.SyncWindow{businessHours, syncWindow(configsync.SyncWindowDeny, "0 12 * * *", time.Hour, "")},
+			time:        "2022-09-14T12:30:00Z",
+			wantReason:  ReasonDenyWindow,
+			wantMessage: `Syncing is suspended by the deny sync window "0 12 * * *" until 2022-09-14T13:00:00Z`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := New(tc.suspend, tc.windows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			now, err := time.Parse(time.RFC3339, tc.time)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := State{
+				Suspended: tc.wantReason != "",
+				Reason:    tc.wantReason,
+				Message:   tc.wantMessage,
+			}
+			if got := w.State(now); got != want {
+				t.Errorf("State() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		window  v1beta1.SyncWindow
+		wantErr bool
+	}{
+		{
+			name:   "valid window",
+			window: syncWindow(configsync.SyncWindowDeny, "0 18 * * 5", 60*time.Hour, "America/New_York"),
+		},
+		{
+			name:    "invalid kind",
+			window:  syncWindow("block", "0 18 * * 5", time.Hour, ""),
+			wantErr: true,
+		},
+		{
+			name:    "invalid schedule",
+			window:  syncWindow(configsync.SyncWindowAllow, "every friday", time.Hour, ""),
+			wantErr: true,
+		},
+		{
+			name:    "missing duration",
+			window:  syncWindow(configsync.SyncWindowAllow, "0 18 * * 5", 0, ""),
+			wantErr: true,
+		},
+		{
+			name:    "invalid time zone",
+			window:  syncWindow(configsync.SyncWindowAllow, "0 18 * * 5", time.Hour, "Mars/Olympus_Mons"),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := Validate(tc.window); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() got error %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
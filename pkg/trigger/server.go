@@ -0,0 +1,254 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trigger implements the webhook endpoint that lets Git providers and
+// OCI registries notify a reconciler about source changes, so that a sync can
+// start immediately instead of waiting for the next polling period.
+package trigger
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// Path is the HTTP path of the webhook endpoint.
+	Path = "/trigger"
+
+	// authorizationHeader carries a bearer token.
+	authorizationHeader = "Authorization"
+	// gitlabTokenHeader carries the secret token configured for a GitLab webhook.
+	gitlabTokenHeader = "X-Gitlab-Token"
+	// githubSignatureHeader carries the HMAC-SHA256 signature of a GitHub webhook payload.
+	githubSignatureHeader = "X-Hub-Signature-256"
+
+	bearerPrefix          = "Bearer "
+	githubSignaturePrefix = "sha256="
+
+	// maxPayloadBytes limits the size of the request body that is read to
+	// verify a payload signature. Push events are usually much smaller.
+	maxPayloadBytes = 5 << 20
+
+	// forwardTimeout is the timeout for forwarding a notification to another
+	// container in the reconciler Pod.
+	forwardTimeout = 5 * time.Second
+
+	// shutdownTimeout is how long the server waits for in-flight requests
+	// when the context is cancelled.
+	shutdownTimeout = 5 * time.Second
+)
+
+// Server accepts authenticated source change notifications and turns them into
+// sync triggers.
+//
+// Notifications received while a previous trigger is still pending are
+// coalesced into that trigger.
+type Server struct {
+	// token authenticates incoming requests.
+	token []byte
+	// forwardURLs are the endpoints that each accepted notification is
+	// forwarded to, e.g. the hydration-controller in the same Pod.
+	forwardURLs []string
+	// signalCommands are the names of the processes that receive a SIGHUP for
+	// each accepted notification, e.g. git-sync in the same Pod.
+	signalCommands []string
+	// procRoot is the proc filesystem that is searched for the processes to
+	// signal.
+	procRoot string
+	// triggers is the channel that receives a value for each accepted
+	// notification.
+	triggers chan struct{}
+	// httpClient is used to forward notifications.
+	httpClient *http.Client
+}
+
+// NewServer returns a Server that authenticates requests with the given token
+// and forwards each accepted notification to forwardURLs.
+func NewServer(token string, forwardURLs ...string) *Server {
+	return &Server{
+		token:       []byte(token),
+		forwardURLs: forwardURLs,
+		procRoot:    defaultProcRoot,
+		triggers:    make(chan struct{}, 1),
+		httpClient:  &http.Client{Timeout: forwardTimeout},
+	}
+}
+
+// SignalProcesses configures the Server to send a SIGHUP to the processes
+// running one of the given commands for each accepted notification. The
+// processes must share the process namespace of the Server and run as the
+// same user.
+func (s *Server) SignalProcesses(commands ...string) {
+	s.signalCommands = append(s.signalCommands, commands...)
+}
+
+// Triggers returns the channel that receives a value whenever a new sync
+// should be started.
+func (s *Server) Triggers() <-chan struct{} {
+	return s.triggers
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != Path {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPayloadBytes))
+	if err != nil {
+		http.Error(w, "unable to read request body", http.StatusBadRequest)
+		return
+	}
+	if !s.authenticate(r.Header, body) {
+		klog.Warningf("Rejected unauthenticated webhook request from %s", r.RemoteAddr)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	klog.Infof("Received source change notification from %s", r.RemoteAddr)
+	s.trigger()
+	s.forward(r.Context())
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// trigger queues a sync trigger, unless one is already pending.
+func (s *Server) trigger() {
+	select {
+	case s.triggers <- struct{}{}:
+	default:
+		klog.V(3).Info("A sync trigger is already pending")
+	}
+}
+
+// forward notifies the other endpoints and processes. Failures are logged but
+// do not fail the request, because the notified containers still poll for
+// changes.
+func (s *Server) forward(ctx context.Context) {
+	for _, command := range s.signalCommands {
+		n, err := signalProcesses(s.procRoot, command, syscall.SIGHUP, syscall.Kill)
+		switch {
+		case err != nil:
+			klog.Warningf("Failed to forward source change notification to %s: %v", command, err)
+		case n == 0:
+			klog.Warningf("Failed to forward source change notification to %s: process not found", command)
+		}
+	}
+	for _, u := range s.forwardURLs {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(nil))
+		if err != nil {
+			klog.Warningf("Failed to forward source change notification to %s: %v", u, err)
+			continue
+		}
+		req.Header.Set(authorizationHeader, bearerPrefix+string(s.token))
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			klog.Warningf("Failed to forward source change notification to %s: %v", u, err)
+			continue
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			klog.Warningf("Failed to forward source change notification to %s: %s", u, resp.Status)
+		}
+	}
+}
+
+// authenticate returns true if the request carries the expected token, either
+// as a bearer token, a GitLab secret token, or a GitHub payload signature.
+func (s *Server) authenticate(header http.Header, body []byte) bool {
+	if len(s.token) == 0 {
+		return false
+	}
+	if auth := header.Get(authorizationHeader); strings.HasPrefix(auth, bearerPrefix) {
+		return equal([]byte(strings.TrimPrefix(auth, bearerPrefix)), s.token)
+	}
+	if token := header.Get(gitlabTokenHeader); token != "" {
+		return equal([]byte(token), s.token)
+	}
+	if sig := header.Get(githubSignatureHeader); strings.HasPrefix(sig, githubSignaturePrefix) {
+		got, err := hex.DecodeString(strings.TrimPrefix(sig, githubSignaturePrefix))
+		if err != nil {
+			return false
+		}
+		mac := hmac.New(sha256.New, s.token)
+		mac.Write(body)
+		return hmac.Equal(got, mac.Sum(nil))
+	}
+	return false
+}
+
+func equal(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Run serves the webhook endpoint on addr until the context is cancelled.
+func (s *Server) Run(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path, s)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		klog.Infof("Serving the webhook endpoint on %s%s", addr, Path)
+		errCh <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("webhook server failed: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// LocalURL returns the URL of a webhook endpoint served by another container
+// in the same Pod.
+func LocalURL(port int) string {
+	return fmt.Sprintf("http://127.0.0.1:%d%s", port, Path)
+}
+
+// Wait waits for the given period, or until a trigger is received, whichever
+// comes first. It returns true if it was interrupted by a trigger.
+// A nil triggers channel never interrupts the wait.
+func Wait(period time.Duration, triggers <-chan struct{}) bool {
+	timer := time.NewTimer(period)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return false
+	case <-triggers:
+		klog.Info("Source change notification received, fetching now")
+		return true
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+const testToken = "s3cr3t"
+
+func githubSignature(token, payload string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(payload))
+	return githubSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestServeHTTP(t *testing.T) {
+	const payload = `{"ref":"refs/heads/main"}`
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		header      map[string]string
+		wantCode    int
+		wantTrigger bool
+	}{
+		{
+			name:        "bearer token",
+			method:      http.MethodPost,
+			path:        Path,
+			header:      map[string]string{authorizationHeader: bearerPrefix + testToken},
+			wantCode:    http.StatusAccepted,
+			wantTrigger: true,
+		},
+		{
+			name:        "gitlab token",
+			method:      http.MethodPost,
+			path:        Path,
+			header:      map[string]string{gitlabTokenHeader: testToken},
+			wantCode:    http.StatusAccepted,
+			wantTrigger: true,
+		},
+		{
+			name:        "github signature",
+			method:      http.MethodPost,
+			path:        Path,
+			header:      map[string]string{githubSignatureHeader: githubSignature(testToken, payload)},
+			wantCode:    http.StatusAccepted,
+			wantTrigger: true,
+		},
+		{
+			name:     "github signature with the wrong key",
+			method:   http.MethodPost,
+			path:     Path,
+			header:   map[string]string{githubSignatureHeader: githubSignature("wrong", payload)},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "wrong bearer token",
+			method:   http.MethodPost,
+			path:     Path,
+			header:   map[string]string{authorizationHeader: bearerPrefix + "wrong"},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "no credentials",
+			method:   http.MethodPost,
+			path:     Path,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "wrong method",
+			method:   http.MethodGet,
+			path:     Path,
+			header:   map[string]string{authorizationHeader: bearerPrefix + testToken},
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:     "wrong path",
+			method:   http.MethodPost,
+			path:     "/other",
+			header:   map[string]string{authorizationHeader: bearerPrefix + testToken},
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewServer(testToken)
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(payload))
+			for k, v := range tc.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			if rec.Code != tc.wantCode {
+				t.Errorf("got status code %d, want %d", rec.Code, tc.wantCode)
+			}
+			gotTrigger := false
+			select {
+			case <-s.Triggers():
+				gotTrigger = true
+			default:
+			}
+			if gotTrigger != tc.wantTrigger {
+				t.Errorf("got trigger %t, want %t", gotTrigger, tc.wantTrigger)
+			}
+		})
+	}
+}
+
+func TestServeHTTPEmptyToken(t *testing.T) {
+	s := NewServer("")
+	req := httptest.NewRequest(http.MethodPost, Path, nil)
+	req.Header.Set(authorizationHeader, bearerPrefix)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("got status code %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestServeHTTPCoalescesAndForwards(t *testing.T) {
+	forwarded := 0
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(authorizationHeader) != bearerPrefix+testToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		forwarded++
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer target.Close()
+
+	s := NewServer(testToken, target.URL+Path)
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, Path, nil)
+		req.Header.Set(authorizationHeader, bearerPrefix+testToken)
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("got status code %d, want %d", rec.Code, http.StatusAccepted)
+		}
+	}
+
+	if forwarded != 3 {
+		t.Errorf("got %d forwarded notifications, want 3", forwarded)
+	}
+	<-s.Triggers()
+	select {
+	case <-s.Triggers():
+		t.Error("got a second pending trigger, want notifications to be coalesced")
+	default:
+	}
+}
+
+func TestNotificationRefetchesSource(t *testing.T) {
+	// The source sidecar fetches once on startup, and then waits for the
+	// polling period, which is too long to elapse during the test.
+	sidecar := NewServer(testToken)
+	fetches := make(chan struct{}, 2)
+	go func() {
+		for i := 0; i < 2; i++ {
+			fetches <- struct{}{}
+			Wait(time.Hour, sidecar.Triggers())
+		}
+	}()
+	<-fetches
+
+	target := httptest.NewServer(sidecar)
+	defer target.Close()
+	s := NewServer(testToken, target.URL+Path)
+	req := httptest.NewRequest(http.MethodPost, Path, nil)
+	req.Header.Set(gitlabTokenHeader, testToken)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("got status code %d, want %d", rec.Code, http.StatusAccepted)
+	}
+
+	select {
+	case <-fetches:
+	case <-time.After(10 * time.Second):
+		t.Fatal("got no fetch after the notification, want the source to be fetched again")
+	}
+}
+
+func TestWait(t *testing.T) {
+	if Wait(time.Millisecond, nil) {
+		t.Error("got interrupted wait without triggers, want the period to elapse")
+	}
+	triggers := make(chan struct{}, 1)
+	triggers <- struct{}{}
+	if !Wait(time.Hour, triggers) {
+		t.Error("got uninterrupted wait, want the pending trigger to interrupt it")
+	}
+}
+
+func TestSignalProcesses(t *testing.T) {
+	procRoot := t.TempDir()
+	processes := map[string]string{
+		"10":   "/git-sync\x00--root=/repo/source\x00",
+		"11":   "git-sync",
+		"12":   "/reconciler\x00--v=0\x00",
+		"13":   "/usr/bin/git-sync-helper\x00",
+		"self": "/git-sync\x00",
+	}
+	for pid, cmdline := range processes {
+		if err := os.MkdirAll(filepath.Join(procRoot, pid), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(procRoot, pid, "cmdline"), []byte(cmdline), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A process without a readable command line.
+	if err := os.MkdirAll(filepath.Join(procRoot, "14"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var signalled []int
+	kill := func(pid int, sig syscall.Signal) error {
+		if sig != syscall.SIGHUP {
+			t.Errorf("got signal %v, want %v", sig, syscall.SIGHUP)
+		}
+		signalled = append(signalled, pid)
+		return nil
+	}
+	n, err := signalProcesses(procRoot, "git-sync", syscall.SIGHUP, kill)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || len(signalled) != 2 || signalled[0] != 10 || signalled[1] != 11 {
+		t.Errorf("got %d signalled processes %v, want [10 11]", n, signalled)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+)
+
+// defaultProcRoot is the mount point of the proc filesystem.
+const defaultProcRoot = "/proc"
+
+// signalProcesses sends sig to every process under procRoot whose command
+// name is command, and returns the number of processes signalled.
+//
+// The command name is the base name of the first argument of the process
+// command line, so that "/git-sync" matches "git-sync".
+func signalProcesses(procRoot, command string, sig syscall.Signal, kill func(pid int, sig syscall.Signal) error) (int, error) {
+	entries, err := ioutil.ReadDir(procRoot)
+	if err != nil {
+		return 0, err
+	}
+	self := os.Getpid()
+	count := 0
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == self {
+			continue
+		}
+		cmdline, err := ioutil.ReadFile(filepath.Join(procRoot, entry.Name(), "cmdline"))
+		if err != nil {
+			// The process exited, or it is not visible to this user.
+			continue
+		}
+		argv0 := cmdline
+		if i := bytes.IndexByte(cmdline, 0); i >= 0 {
+			argv0 = cmdline[:i]
+		}
+		if filepath.Base(string(argv0)) != command {
+			continue
+		}
+		if err := kill(pid, sig); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
@@ -16,6 +16,7 @@ package log
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -45,12 +46,19 @@ func (l *Logger) Error(err error, msg string, kvList ...interface{}) {
 	payload := struct {
 		Msg  string
 		Err  string
+		Code string `json:",omitempty"`
 		Args map[string]interface{}
 	}{
 		Msg:  msg,
 		Err:  err.Error(),
 		Args: map[string]interface{}{},
 	}
+	// Export the error code, if any, so that the reconciler can report the
+	// error with the same code.
+	var coded interface{ Code() string }
+	if errors.As(err, &coded) {
+		payload.Code = coded.Code()
+	}
 	if len(kvList)%2 != 0 {
 		kvList = append(kvList, "<no-value>")
 	}
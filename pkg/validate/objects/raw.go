@@ -35,6 +35,7 @@ type ObjectVisitor func(obj ast.FileObject) status.Error
 // Git repo for a cluster.
 type Raw struct {
 	ClusterName       string
+	ClusterLabels     map[string]string
 	ReconcilerName    string
 	PolicyDir         cmpath.Relative
 	Objects           []ast.FileObject
@@ -30,6 +30,9 @@ type Scoped struct {
 	Unknown               []ast.FileObject
 	DefaultNamespace      string
 	IsNamespaceReconciler bool
+	// LiveNamespaceLabels are the labels of the live Namespaces on the
+	// cluster, keyed by name. Only dynamic NamespaceSelectors select them.
+	LiveNamespaceLabels map[string]map[string]string
 }
 
 // Objects returns all FileObjects in the Scoped collection.
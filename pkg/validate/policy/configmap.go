@@ -0,0 +1,90 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FromConfigMap returns the policies declared in the data of the ConfigMap,
+// sorted by name.
+func FromConfigMap(cm *corev1.ConfigMap) ([]*Policy, status.MultiError) {
+	var policies []*Policy
+	var errs status.MultiError
+	for _, key := range sortedKeys(cm.Data) {
+		p, err := New(cm, key, cm.Data[key])
+		if err != nil {
+			errs = status.Append(errs, InvalidPolicyError(cm, key, err))
+			continue
+		}
+		policies = append(policies, p)
+	}
+	return policies, errs
+}
+
+// FromObjects returns the policies declared in the data of the ConfigMaps with
+// the validation policy annotation. A policy copied into several Namespaces of
+// a hierarchical repo is only returned once.
+func FromObjects(objs []ast.FileObject) ([]*Policy, status.MultiError) {
+	var policies []*Policy
+	var errs status.MultiError
+	seen := make(map[string]bool)
+	for _, obj := range objs {
+		if !declaresPolicies(obj) {
+			continue
+		}
+		data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+		if err != nil {
+			errs = status.Append(errs, InvalidPolicyError(obj, "data", err))
+			continue
+		}
+		for _, key := range sortedKeys(data) {
+			id := obj.GetName() + "/" + key + "\x00" + data[key]
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			p, err := New(obj, key, data[key])
+			if err != nil {
+				errs = status.Append(errs, InvalidPolicyError(obj, key, err))
+				continue
+			}
+			policies = append(policies, p)
+		}
+	}
+	return policies, errs
+}
+
+func declaresPolicies(obj client.Object) bool {
+	return obj.GetObjectKind().GroupVersionKind() == kinds.ConfigMap() &&
+		obj.GetAnnotations()[metadata.ValidationPolicyAnnotationKey] == metadata.ValidationPolicyAnnotationValue
+}
+
+func sortedKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"kpt.dev/configsync/pkg/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ViolationErrorCode is the error code for ViolationError.
+const ViolationErrorCode = "1072"
+
+var violationError = status.NewErrorBuilder(ViolationErrorCode)
+
+// ViolationError reports that an object does not comply with a validation
+// policy.
+func ViolationError(p *Policy, obj client.Object) status.Error {
+	message := p.Message
+	if message == "" {
+		message = "the expression `" + p.Expression + "` is false"
+	}
+	return violationError.Sprintf("Config violates the validation policy %q: %s", p.Name, message).
+		BuildWithResources(obj)
+}
+
+// EvaluationError reports that a validation policy cannot be evaluated
+// against an object. The object is rejected, as the policy may forbid it.
+func EvaluationError(p *Policy, obj client.Object, cause error) status.Error {
+	return violationError.Sprintf("Config cannot be evaluated against the validation policy %q. Guard the expression with has() if the fields are optional",
+		p.Name).Wrap(cause).BuildWithResources(obj)
+}
+
+// InvalidPolicyErrorCode is the error code for InvalidPolicyError.
+const InvalidPolicyErrorCode = "1073"
+
+var invalidPolicyError = status.NewErrorBuilder(InvalidPolicyErrorCode)
+
+// InvalidPolicyError reports that the data key of a ConfigMap does not declare
+// a valid validation policy.
+func InvalidPolicyError(cm client.Object, key string, cause error) status.Error {
+	return invalidPolicyError.Sprintf("The %q key of the ConfigMap does not declare a valid validation policy", key).
+		Wrap(cause).BuildWithResources(cm)
+}
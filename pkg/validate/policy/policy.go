@@ -0,0 +1,169 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policy evaluates the validation policies declared in ConfigMaps, in
+// the source or on the cluster, against the objects of the source before they
+// are applied.
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"kpt.dev/configsync/pkg/validate/policy/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// PredicateEngine is the name of the built-in engine for policies written in
+// the expression language documented in the predicate package.
+const PredicateEngine = "predicate"
+
+// Engine compiles the expressions of the policies written in a language.
+type Engine interface {
+	// Compile returns the Program of the expression.
+	Compile(expression string) (Program, error)
+}
+
+// Program is a compiled policy expression.
+type Program interface {
+	// Eval returns whether the object, in its unstructured form, complies with
+	// the policy.
+	Eval(object map[string]interface{}) (bool, error)
+}
+
+// engines are the available engines, keyed by the name used in the engine
+// field of the policies.
+var engines = map[string]Engine{
+	PredicateEngine: predicateEngine{},
+}
+
+// RegisterEngine makes an engine available to the policies under the passed
+// name, for example to support policies written in Rego. It must be called
+// during initialization.
+func RegisterEngine(name string, engine Engine) {
+	engines[name] = engine
+}
+
+// Match selects the objects a policy applies to. An empty field matches every
+// object.
+type Match struct {
+	// APIGroups are the API groups of the objects. The core group is "".
+	APIGroups []string `json:"apiGroups,omitempty"`
+	// Kinds are the kinds of the objects.
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+// Spec is the definition of a policy in a data key of a ConfigMap.
+type Spec struct {
+	// Engine is the language of the expression. Defaults to "predicate".
+	Engine string `json:"engine,omitempty"`
+	// Match selects the objects the policy applies to.
+	Match Match `json:"match,omitempty"`
+	// Expression must evaluate to true for the objects which comply with the
+	// policy. The object is bound to the `object` variable.
+	Expression string `json:"expression"`
+	// Message describes the violation of the policy.
+	Message string `json:"message,omitempty"`
+}
+
+// Policy is a compiled validation policy.
+type Policy struct {
+	// Name identifies the policy as <ConfigMap name>/<data key>.
+	Name string
+	Spec
+
+	// source is the ConfigMap declaring the policy.
+	source  client.Object
+	program Program
+}
+
+// New parses and compiles the policy declared in the data key of the ConfigMap.
+func New(cm client.Object, key, data string) (*Policy, error) {
+	p := &Policy{
+		Name:   cm.GetName() + "/" + key,
+		source: cm,
+	}
+	if err := yaml.UnmarshalStrict([]byte(data), &p.Spec); err != nil {
+		return nil, err
+	}
+	if p.Engine == "" {
+		p.Engine = PredicateEngine
+	}
+	if strings.TrimSpace(p.Expression) == "" {
+		return nil, fmt.Errorf("the expression is required")
+	}
+	engine, found := engines[p.Engine]
+	if !found {
+		return nil, fmt.Errorf("unsupported engine %q, must be one of %s", p.Engine, engineNames())
+	}
+	var err error
+	if p.program, err = engine.Compile(p.Expression); err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	return p, nil
+}
+
+// Matches returns whether the policy applies to the object.
+func (p *Policy) Matches(obj client.Object) bool {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	return matchesAny(p.Match.APIGroups, gvk.Group) && matchesAny(p.Match.Kinds, gvk.Kind)
+}
+
+// Eval returns whether the object complies with the policy.
+func (p *Policy) Eval(object map[string]interface{}) (bool, error) {
+	return p.program.Eval(object)
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func engineNames() string {
+	var names []string
+	for name := range engines {
+		names = append(names, fmt.Sprintf("%q", name))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// predicateEngine is the Engine of the policies written in the language of the
+// predicate package.
+type predicateEngine struct{}
+
+func (predicateEngine) Compile(expression string) (Program, error) {
+	p, err := predicate.Compile(expression, "object")
+	if err != nil {
+		return nil, err
+	}
+	return predicateProgram{p}, nil
+}
+
+type predicateProgram struct {
+	*predicate.Program
+}
+
+func (p predicateProgram) Eval(object map[string]interface{}) (bool, error) {
+	return p.Program.Eval(map[string]interface{}{"object": object})
+}
@@ -0,0 +1,205 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+const noHostNetwork = `match:
+  apiGroups: [apps]
+  kinds: [Deployment]
+expression: "!has(object.spec.template.spec.hostNetwork) || !object.spec.template.spec.hostNetwork"
+message: hostNetwork is not allowed`
+
+func policies(data map[string]string) []*Policy {
+	cm := fake.ConfigMapObject(core.Namespace(configsync.ControllerNamespace), core.Name(configsync.ValidationPoliciesName))
+	cm.Data = data
+	result, err := FromConfigMap(cm)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func deployment(hostNetwork interface{}) ast.FileObject {
+	obj := fake.Deployment("namespaces/foo")
+	if hostNetwork != nil {
+		obj.Object["spec"] = map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{"hostNetwork": hostNetwork},
+			},
+		}
+	}
+	return obj
+}
+
+func TestFromConfigMap(t *testing.T) {
+	testCases := []struct {
+		name      string
+		data      map[string]string
+		wantNames []string
+		wantErrs  status.MultiError
+	}{
+		{
+			name: "no policies",
+		},
+		{
+			name: "sorted policies",
+			data: map[string]string{
+				"no-host-network": noHostNetwork,
+				"any":             "expression: 'true'",
+			},
+			wantNames: []string{"validation-policies/any", "validation-policies/no-host-network"},
+		},
+		{
+			name: "missing expression",
+			data: map[string]string{
+				"empty": "message: nothing",
+			},
+			wantErrs: fake.Errors(InvalidPolicyErrorCode),
+		},
+		{
+			name: "unknown field",
+			data: map[string]string{
+				"typo": "expresion: 'true'",
+			},
+			wantErrs: fake.Errors(InvalidPolicyErrorCode),
+		},
+		{
+			name: "explicit engine",
+			data: map[string]string{
+				"any": "engine: predicate\nexpression: 'true'",
+			},
+			wantNames: []string{"validation-policies/any"},
+		},
+		{
+			name: "full CEL is not built in",
+			data: map[string]string{
+				"any": "engine: cel\nexpression: 'true'",
+			},
+			wantErrs: fake.Errors(InvalidPolicyErrorCode),
+		},
+		{
+			name: "unsupported engine",
+			data: map[string]string{
+				"rego": "engine: rego\nexpression: deny[msg]",
+			},
+			wantErrs: fake.Errors(InvalidPolicyErrorCode),
+		},
+		{
+			name: "invalid expression",
+			data: map[string]string{
+				"invalid": "expression: object.metadata.(",
+				"valid":   "expression: 'true'",
+			},
+			wantNames: []string{"validation-policies/valid"},
+			wantErrs:  fake.Errors(InvalidPolicyErrorCode),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cm := fake.ConfigMapObject(core.Namespace(configsync.ControllerNamespace), core.Name(configsync.ValidationPoliciesName))
+			cm.Data = tc.data
+			got, errs := FromConfigMap(cm)
+			if !errors.Is(errs, tc.wantErrs) {
+				t.Errorf("got FromConfigMap() error %v, want %v", errs, tc.wantErrs)
+			}
+			var gotNames []string
+			for _, p := range got {
+				gotNames = append(gotNames, p.Name)
+			}
+			if diff := cmp.Diff(tc.wantNames, gotNames); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	declared := fake.ConfigMapObject(core.Name("policies"),
+		core.Annotation(metadata.ValidationPolicyAnnotationKey, metadata.ValidationPolicyAnnotationValue))
+	declared.Data = map[string]string{"no-host-network": noHostNetwork}
+	disabled := fake.ConfigMapObject(core.Name("disabled"))
+	disabled.Data = map[string]string{"deny-all": "expression: 'false'"}
+
+	testCases := []struct {
+		name     string
+		policies []*Policy
+		objs     []ast.FileObject
+		wantErrs status.MultiError
+	}{
+		{
+			name: "no policies",
+			objs: []ast.FileObject{deployment(true)},
+		},
+		{
+			name:     "complying object",
+			policies: policies(map[string]string{"no-host-network": noHostNetwork}),
+			objs:     []ast.FileObject{deployment(false), deployment(nil)},
+		},
+		{
+			name:     "violating object",
+			policies: policies(map[string]string{"no-host-network": noHostNetwork}),
+			objs:     []ast.FileObject{deployment(true)},
+			wantErrs: fake.Errors(ViolationErrorCode),
+		},
+		{
+			name:     "unmatched kind",
+			policies: policies(map[string]string{"no-host-network": noHostNetwork}),
+			objs: []ast.FileObject{fake.Unstructured(kinds.Deployment().GroupVersion().WithKind("StatefulSet"),
+				core.Namespace("foo"))},
+		},
+		{
+			name:     "evaluation error",
+			policies: policies(map[string]string{"host-network": "expression: object.spec.template.spec.hostNetwork == false"}),
+			objs:     []ast.FileObject{deployment(nil)},
+			wantErrs: fake.Errors(ViolationErrorCode),
+		},
+		{
+			name:     "non-bool expression",
+			policies: policies(map[string]string{"name": "expression: object.metadata.name"}),
+			objs:     []ast.FileObject{deployment(nil)},
+			wantErrs: fake.Errors(ViolationErrorCode),
+		},
+		{
+			name:     "policy declared in the source",
+			objs:     []ast.FileObject{fake.FileObject(declared, "namespaces/foo/policies.yaml"), deployment(true)},
+			wantErrs: fake.Errors(ViolationErrorCode),
+		},
+		{
+			name: "ConfigMap without the annotation",
+			objs: []ast.FileObject{fake.FileObject(disabled, "namespaces/foo/disabled.yaml"), deployment(true)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := Validate(tc.objs, tc.policies)
+			if !errors.Is(errs, tc.wantErrs) {
+				t.Errorf("got Validate() error %v, want %v", errs, tc.wantErrs)
+			}
+		})
+	}
+}
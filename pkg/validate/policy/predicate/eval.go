@@ -0,0 +1,366 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// node is a node of the tree of an expression.
+type node interface {
+	// eval returns the value of the node with the values of the variables.
+	eval(vars map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+type variableNode struct {
+	name string
+}
+
+func (n *variableNode) eval(vars map[string]interface{}) (interface{}, error) {
+	return vars[n.name], nil
+}
+
+// fieldNode selects a field of a map, with .field or ['field'].
+type fieldNode struct {
+	operand node
+	field   string
+}
+
+func (n *fieldNode) eval(vars map[string]interface{}) (interface{}, error) {
+	m, err := evalMap(n.operand, vars, n.field)
+	if err != nil {
+		return nil, err
+	}
+	v, found := m[n.field]
+	if !found {
+		return nil, fmt.Errorf("no such field %q", n.field)
+	}
+	return v, nil
+}
+
+// indexNode selects an element of a list.
+type indexNode struct {
+	operand node
+	index   int
+}
+
+func (n *indexNode) eval(vars map[string]interface{}) (interface{}, error) {
+	v, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot index %s with %d", typeName(v), n.index)
+	}
+	if n.index >= len(list) {
+		return nil, fmt.Errorf("index %d out of range of a list of size %d", n.index, len(list))
+	}
+	return list[n.index], nil
+}
+
+// hasNode tests whether the field exists.
+type hasNode struct {
+	field *fieldNode
+}
+
+func (n *hasNode) eval(vars map[string]interface{}) (interface{}, error) {
+	m, err := evalMap(n.field.operand, vars, n.field.field)
+	if err != nil {
+		return nil, err
+	}
+	_, found := m[n.field.field]
+	return found, nil
+}
+
+type sizeNode struct {
+	operand node
+}
+
+func (n *sizeNode) eval(vars map[string]interface{}) (interface{}, error) {
+	v, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case string:
+		return int64(utf8.RuneCountInString(v)), nil
+	case []interface{}:
+		return int64(len(v)), nil
+	case map[string]interface{}:
+		return int64(len(v)), nil
+	}
+	return nil, fmt.Errorf("size() requires a string, a list or a map, got %s", typeName(v))
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(vars map[string]interface{}) (interface{}, error) {
+	b, err := evalBool(n.operand, vars, "!")
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+// logicNode is a && or || operation.
+type logicNode struct {
+	and         bool
+	left, right node
+}
+
+func (n *logicNode) eval(vars map[string]interface{}) (interface{}, error) {
+	op := "||"
+	if n.and {
+		op = "&&"
+	}
+	left, err := evalBool(n.left, vars, op)
+	if err != nil {
+		return nil, err
+	}
+	// false && x is false, and true || x is true.
+	if left != n.and {
+		return left, nil
+	}
+	return evalBool(n.right, vars, op)
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	}
+	c, ok := compareNumbers(left, right)
+	if !ok {
+		ls, lok := left.(string)
+		rs, rok := right.(string)
+		if !lok || !rok {
+			return nil, fmt.Errorf("cannot compare %s and %s with %s", typeName(left), typeName(right), n.op)
+		}
+		c = strings.Compare(ls, rs)
+	}
+	switch n.op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+// methodNode is a string method.
+type methodNode struct {
+	name        string
+	target, arg node
+}
+
+func (n *methodNode) eval(vars map[string]interface{}) (interface{}, error) {
+	s, err := evalString(n.target, vars, n.name)
+	if err != nil {
+		return nil, err
+	}
+	arg, err := evalString(n.arg, vars, n.name)
+	if err != nil {
+		return nil, err
+	}
+	switch n.name {
+	case "startsWith":
+		return strings.HasPrefix(s, arg), nil
+	case "endsWith":
+		return strings.HasSuffix(s, arg), nil
+	case "contains":
+		return strings.Contains(s, arg), nil
+	default:
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", arg, err)
+		}
+		return re.MatchString(s), nil
+	}
+}
+
+// quantifierNode is the all() or exists() method of a list.
+type quantifierNode struct {
+	all      bool
+	target   node
+	variable string
+	body     node
+}
+
+func (n *quantifierNode) eval(vars map[string]interface{}) (interface{}, error) {
+	name := "exists"
+	if n.all {
+		name = "all"
+	}
+	v, err := n.target.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s() requires a list, got %s", name, typeName(v))
+	}
+	inner := make(map[string]interface{}, len(vars)+1)
+	for k, v := range vars {
+		inner[k] = v
+	}
+	for _, elem := range list {
+		inner[n.variable] = elem
+		b, err := evalBool(n.body, inner, name+"()")
+		if err != nil {
+			return nil, err
+		}
+		// all() stops on the first false element, exists() on the first true
+		// one.
+		if b != n.all {
+			return b, nil
+		}
+	}
+	return n.all, nil
+}
+
+func evalBool(n node, vars map[string]interface{}, op string) (bool, error) {
+	v, err := n.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s requires a bool, got %s", op, typeName(v))
+	}
+	return b, nil
+}
+
+func evalString(n node, vars map[string]interface{}, method string) (string, error) {
+	v, err := n.eval(vars)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s() requires strings, got %s", method, typeName(v))
+	}
+	return s, nil
+}
+
+func evalMap(n node, vars map[string]interface{}, field string) (map[string]interface{}, error) {
+	v, err := n.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot select field %q of %s", field, typeName(v))
+	}
+	return m, nil
+}
+
+// equal returns whether the values are equal. Ints and floats are compared by
+// value, the other values must have the same type.
+func equal(a, b interface{}) bool {
+	if c, ok := compareNumbers(a, b); ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compareNumbers returns -1, 0 or 1 if the number a is less than, equal to or
+// greater than the number b, and false if a or b is not a number.
+func compareNumbers(a, b interface{}) (int, bool) {
+	ai, aInt := a.(int64)
+	bi, bInt := b.(int64)
+	if aInt && bInt {
+		return sign(ai < bi, ai > bi), true
+	}
+	af, aOK := toFloat(a)
+	bf, bOK := toFloat(b)
+	if !aOK || !bOK {
+		return 0, false
+	}
+	return sign(af < bf, af > bf), true
+}
+
+func sign(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// typeName returns the name of the type of the value in the errors.
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case float64:
+		return "float"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,401 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokName
+	tokInt
+	tokString
+	tokOperator
+)
+
+type token struct {
+	kind tokenKind
+	// text is the source of the token, or the unquoted string.
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// operators are the operators and punctuation, two-character ones first.
+var operators = []string{"||", "&&", "==", "!=", "<=", ">=", "!", "<", ">", "(", ")", "[", "]", ".", ","}
+
+// lexer splits an expression into tokens.
+type lexer struct {
+	input string
+	pos   int
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && strings.ContainsRune(" \t\r\n", rune(l.input[l.pos])) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos == len(l.input) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+	c := l.input[l.pos]
+	switch {
+	case isLetter(c):
+		for l.pos < len(l.input) && (isLetter(l.input[l.pos]) || isDigit(l.input[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokName, text: l.input[start:l.pos], pos: start}, nil
+	case isDigit(c):
+		for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokInt, text: l.input[start:l.pos], pos: start}, nil
+	case c == '\'' || c == '"':
+		return l.scanString(c)
+	}
+	for _, op := range operators {
+		if strings.HasPrefix(l.input[l.pos:], op) {
+			l.pos += len(op)
+			return token{kind: tokOperator, text: op, pos: start}, nil
+		}
+	}
+	return token{}, fmt.Errorf("unexpected character %q at position %d", c, start)
+}
+
+// scanString scans a string quoted with quote, and unescapes it.
+func (l *lexer) scanString(quote byte) (token, error) {
+	start := l.pos
+	var sb strings.Builder
+	for l.pos++; l.pos < len(l.input); l.pos++ {
+		c := l.input[l.pos]
+		switch {
+		case c == quote:
+			l.pos++
+			return token{kind: tokString, text: sb.String(), pos: start}, nil
+		case c == '\\' && l.pos+1 < len(l.input):
+			l.pos++
+			switch e := l.input[l.pos]; e {
+			case '\\', '\'', '"':
+				sb.WriteByte(e)
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				return token{}, fmt.Errorf("unsupported escape sequence \\%c at position %d", e, l.pos-1)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return token{}, fmt.Errorf("unterminated string at position %d", start)
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// parser builds the tree of an expression, following the grammar documented
+// in the package comment.
+type parser struct {
+	lexer lexer
+	tok   token
+	// scope holds the variables the expression may refer to.
+	scope []string
+}
+
+func (p *parser) parse() (node, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return n, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), p.tok.pos)
+}
+
+// is returns whether the current token is the operator op.
+func (p *parser) is(op string) bool {
+	return p.tok.kind == tokOperator && p.tok.text == op
+}
+
+// expect consumes the operator op.
+func (p *parser) expect(op string) error {
+	if !p.is(op) {
+		return p.errorf("expected %q, got %s", op, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseLogic("||", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseLogic("&&", p.parseUnary)
+}
+
+func (p *parser) parseLogic(op string, parseOperand func() (node, error)) (node, error) {
+	left, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for p.is(op) {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicNode{and: op == "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if !p.is("!") {
+		return p.parseComparison()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &notNode{operand: operand}, nil
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<", "<=", ">", ">="} {
+		if !p.is(op) {
+			continue
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseValue() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.is("."):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokName {
+				return nil, p.errorf("expected a field or method name, got %s", p.tok)
+			}
+			name := p.tok.text
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.is("(") {
+				n, err = p.parseMethod(n, name)
+			} else {
+				n = &fieldNode{operand: n, field: name}
+			}
+		case p.is("["):
+			n, err = p.parseIndex(n)
+		default:
+			return n, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseIndex parses the index of the operand, after the "[".
+func (p *parser) parseIndex(operand node) (node, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	var n node
+	switch p.tok.kind {
+	case tokString:
+		n = &fieldNode{operand: operand, field: p.tok.text}
+	case tokInt:
+		index, err := strconv.Atoi(p.tok.text)
+		if err != nil {
+			return nil, p.errorf("index %s out of range", p.tok.text)
+		}
+		n = &indexNode{operand: operand, index: index}
+	default:
+		return nil, p.errorf("expected a string or an int index, got %s", p.tok)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return n, p.expect("]")
+}
+
+// parseMethod parses the arguments of the method of the target, from the "(".
+func (p *parser) parseMethod(target node, name string) (node, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	var n node
+	switch name {
+	case "startsWith", "endsWith", "contains", "matches":
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		n = &methodNode{name: name, target: target, arg: arg}
+	case "all", "exists":
+		if p.tok.kind != tokName || isReserved(p.tok.text) {
+			return nil, p.errorf("expected a variable name, got %s", p.tok)
+		}
+		variable := p.tok.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		p.scope = append(p.scope, variable)
+		body, err := p.parseOr()
+		p.scope = p.scope[:len(p.scope)-1]
+		if err != nil {
+			return nil, err
+		}
+		n = &quantifierNode{all: name == "all", target: target, variable: variable, body: body}
+	default:
+		return nil, p.errorf("unknown method %q", name)
+	}
+	return n, p.expect(")")
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokInt:
+		v, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return nil, p.errorf("int %s out of range", tok.text)
+		}
+		return &literalNode{value: v}, p.advance()
+	case tok.kind == tokString:
+		return &literalNode{value: tok.text}, p.advance()
+	case p.is("("):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return n, p.expect(")")
+	case tok.kind != tokName:
+		return nil, p.errorf("unexpected %s", tok)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	switch tok.text {
+	case "true", "false":
+		return &literalNode{value: tok.text == "true"}, nil
+	case "null":
+		return &literalNode{}, nil
+	case "has", "size":
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var n node
+		if tok.text == "has" {
+			arg, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			field, ok := arg.(*fieldNode)
+			if !ok {
+				return nil, p.errorf("has() requires a field selection")
+			}
+			n = &hasNode{field: field}
+		} else {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			n = &sizeNode{operand: arg}
+		}
+		return n, p.expect(")")
+	}
+	for _, v := range p.scope {
+		if v == tok.text {
+			return &variableNode{name: tok.text}, nil
+		}
+	}
+	return nil, fmt.Errorf("undeclared variable %q at position %d", tok.text, tok.pos)
+}
+
+// isReserved returns whether the name is a literal or a function, which cannot
+// name a variable.
+func isReserved(name string) bool {
+	switch name {
+	case "true", "false", "null", "has", "size":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package predicate implements the expressions of the validation policies: a
+// small language to test the fields of an unstructured object. The grammar,
+// with the operators from the lowest to the highest precedence, is:
+//
+//	expr       = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | comparison
+//	comparison = value [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" ) value ]
+//	value      = primary { "." name | "[" ( string | int ) "]" | "." method }
+//	primary    = "true" | "false" | "null" | int | string | variable |
+//	             "has(" value ")" | "size(" expr ")" | "(" expr ")"
+//	method     = ( "startsWith" | "endsWith" | "contains" | "matches" ) "(" expr ")" |
+//	             ( "all" | "exists" ) "(" variable "," expr ")"
+//
+// The ints are decimal. The strings are quoted with ' or ", and support the
+// \\, \', \", \n and \t escapes.
+//
+// The values are the ones of unstructured objects: null, bool, int, float,
+// string, list and map. The expression must evaluate to a bool, and fails to
+// evaluate on:
+//   - a field that does not exist, or an index out of range: guard the
+//     optional fields with has(), which is true if the map it selects from has
+//     the field;
+//   - an operand of the wrong type, for example a string operand of && or a
+//     field selected from a list.
+//
+// The && and || operators evaluate their operands from left to right, and
+// only evaluate the right operand if the left one does not decide the result.
+// == and != compare any values: ints and floats are compared by value, and
+// the other values of different types are never equal. <, <=, > and >= compare
+// two numbers or two strings. size() returns the number of characters of a
+// string, or of elements of a list or a map. startsWith(), endsWith(),
+// contains() and matches() test a string, matches() with an RE2 regular
+// expression. all() and exists() test whether the expression is true for all
+// or any element of a list, bound to the variable.
+package predicate
+
+import (
+	"fmt"
+)
+
+// Program is a compiled expression.
+type Program struct {
+	expression string
+	root       node
+}
+
+// Compile parses the expression, which may only refer to the passed variables.
+func Compile(expression string, variables ...string) (*Program, error) {
+	p := &parser{lexer: lexer{input: expression}, scope: variables}
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Program{expression: expression, root: root}, nil
+}
+
+// Eval evaluates the expression with the values of its variables.
+func (p *Program) Eval(variables map[string]interface{}) (bool, error) {
+	v, err := p.root.eval(variables)
+	if err != nil {
+		return false, err
+	}
+	result, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("the expression must evaluate to a bool, got %s", typeName(v))
+	}
+	return result, nil
+}
+
+// String returns the source expression of the program.
+func (p *Program) String() string {
+	return p.expression
+}
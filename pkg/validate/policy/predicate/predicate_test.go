@@ -0,0 +1,266 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"strings"
+	"testing"
+)
+
+var object = map[string]interface{}{
+	"kind": "Deployment",
+	"metadata": map[string]interface{}{
+		"name": "frontend",
+		"labels": map[string]interface{}{
+			"app.kubernetes.io/name": "shop",
+			"team":                   "web",
+		},
+	},
+	"spec": map[string]interface{}{
+		"replicas": int64(3),
+		"ratio":    0.5,
+		"paused":   false,
+		"selector": nil,
+		"template": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"hostNetwork": true,
+				"containers": []interface{}{
+					map[string]interface{}{"name": "app", "image": "gcr.io/shop/app:v1"},
+					map[string]interface{}{"name": "proxy", "image": "docker.io/envoy:v1"},
+				},
+				"volumes": []interface{}{},
+			},
+		},
+	},
+}
+
+func TestProgram_Eval(t *testing.T) {
+	testCases := []struct {
+		name       string
+		expression string
+		want       bool
+	}{
+		// Literals.
+		{name: "true", expression: "true", want: true},
+		{name: "false", expression: "false", want: false},
+		{name: "null", expression: "null == null", want: true},
+		{name: "int", expression: "42 == 42", want: true},
+		{name: "single-quoted string", expression: "'a' == \"a\"", want: true},
+		{name: "escapes", expression: `'\\\'\"\n\t' == "\\'\"\n\t"`, want: true},
+		{name: "whitespace", expression: " \t\r\ntrue\n", want: true},
+		// Selection.
+		{name: "field", expression: "object.kind == 'Deployment'", want: true},
+		{name: "nested field", expression: "object.metadata.name == 'frontend'", want: true},
+		{name: "string index", expression: "object.metadata.labels['app.kubernetes.io/name'] == 'shop'", want: true},
+		{name: "list index", expression: "object.spec.template.spec.containers[1].name == 'proxy'", want: true},
+		{name: "null field", expression: "object.spec.selector == null", want: true},
+		{name: "bool field", expression: "object.spec.template.spec.hostNetwork", want: true},
+		// Logical operators.
+		{name: "not", expression: "!object.spec.paused", want: true},
+		{name: "double not", expression: "!!true", want: true},
+		{name: "and", expression: "true && false", want: false},
+		{name: "or", expression: "false || true", want: true},
+		{name: "and before or", expression: "true || true && false", want: true},
+		{name: "parentheses", expression: "(true || true) && false", want: false},
+		{name: "and skips the right operand", expression: "false && object.missing", want: false},
+		{name: "or skips the right operand", expression: "true || object.missing", want: true},
+		{name: "guarded field", expression: "!has(object.spec.template.spec.hostNetwork) || !object.spec.template.spec.hostNetwork", want: false},
+		// Comparisons.
+		{name: "not equal", expression: "object.kind != 'Pod'", want: true},
+		{name: "int and float equal", expression: "object.spec.replicas == 3", want: true},
+		{name: "float", expression: "object.spec.ratio < 1", want: true},
+		{name: "int less", expression: "object.spec.replicas < 4", want: true},
+		{name: "int less or equal", expression: "object.spec.replicas <= 3", want: true},
+		{name: "int greater", expression: "object.spec.replicas > 3", want: false},
+		{name: "int greater or equal", expression: "object.spec.replicas >= 3", want: true},
+		{name: "float and int greater", expression: "object.spec.ratio > 0", want: true},
+		{name: "string less", expression: "'a' < 'b'", want: true},
+		{name: "string greater or equal", expression: "'a' >= 'b'", want: false},
+		{name: "different types are not equal", expression: "object.spec.replicas == '3'", want: false},
+		{name: "null is not false", expression: "object.spec.selector != false", want: true},
+		{name: "list equal", expression: "object.spec.template.spec.volumes == object.spec.template.spec.volumes", want: true},
+		{name: "map not equal", expression: "object.metadata != object.spec", want: true},
+		{name: "comparison of a method", expression: "object.metadata.name.startsWith('front') == true", want: true},
+		// Functions.
+		{name: "has", expression: "has(object.metadata.labels.team)", want: true},
+		{name: "has index", expression: "has(object.metadata.labels['app.kubernetes.io/name'])", want: true},
+		{name: "has missing", expression: "has(object.metadata.annotations)", want: false},
+		{name: "size of a string", expression: "size('héllo') == 5", want: true},
+		{name: "size of a list", expression: "size(object.spec.template.spec.containers) == 2", want: true},
+		{name: "size of a map", expression: "size(object.metadata.labels) == 2", want: true},
+		{name: "field named like a method", expression: "has(object.spec.all) || true", want: true},
+		// Methods.
+		{name: "startsWith", expression: "object.metadata.name.startsWith('front')", want: true},
+		{name: "endsWith", expression: "object.metadata.name.endsWith('front')", want: false},
+		{name: "contains", expression: "object.metadata.name.contains('ont')", want: true},
+		{name: "matches", expression: "object.metadata.name.matches('^[a-z]+$')", want: true},
+		{name: "all", expression: "object.spec.template.spec.containers.all(c, c.image.startsWith('gcr.io/'))", want: false},
+		{name: "exists", expression: "object.spec.template.spec.containers.exists(c, c.name == 'proxy')", want: true},
+		{name: "all of an empty list", expression: "object.spec.template.spec.volumes.all(v, false)", want: true},
+		{name: "exists in an empty list", expression: "object.spec.template.spec.volumes.exists(v, true)", want: false},
+		{name: "all stops on the first false element", expression: "object.spec.template.spec.containers.all(c, c.name == 'proxy' && c.missing)", want: false},
+		{name: "nested quantifiers", expression: "object.spec.template.spec.containers.all(c, object.spec.template.spec.containers.exists(d, d.name == c.name))", want: true},
+		{name: "shadowed variable", expression: "object.spec.template.spec.containers.exists(object, object.name == 'app')", want: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := Compile(tc.expression, "object")
+			if err != nil {
+				t.Fatalf("Compile(%q) = %v", tc.expression, err)
+			}
+			got, err := p.Eval(map[string]interface{}{"object": object})
+			if err != nil {
+				t.Fatalf("Eval() = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("got Eval() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestProgram_EvalErrors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		expression string
+		wantErr    string
+	}{
+		{name: "not a bool", expression: "object.metadata.name", wantErr: "the expression must evaluate to a bool, got string"},
+		{name: "missing field", expression: "object.metadata.annotations == null", wantErr: `no such field "annotations"`},
+		{name: "field of a list", expression: "object.spec.template.spec.containers.name == 'app'", wantErr: `cannot select field "name" of list`},
+		{name: "field of null", expression: "object.spec.selector.matchLabels == null", wantErr: `cannot select field "matchLabels" of null`},
+		{name: "index of a map", expression: "object.metadata[0] == null", wantErr: "cannot index map with 0"},
+		{name: "index out of range", expression: "object.spec.template.spec.containers[2] == null", wantErr: "index 2 out of range of a list of size 2"},
+		{name: "index of a missing field", expression: "object.missing[0] == null", wantErr: `no such field "missing"`},
+		{name: "and a float", expression: "object.spec.ratio && true", wantErr: "&& requires a bool, got float"},
+		{name: "not a list", expression: "!object.spec.template.spec.containers", wantErr: "! requires a bool, got list"},
+		{name: "has of a missing map", expression: "has(object.metadata.annotations.team)", wantErr: `no such field "annotations"`},
+		{name: "has of a string", expression: "has(object.kind.name)", wantErr: `cannot select field "name" of string`},
+		{name: "size of an int", expression: "size(1) == 1", wantErr: "size() requires a string, a list or a map, got int"},
+		{name: "size of a missing field", expression: "size(object.missing) == 1", wantErr: `no such field "missing"`},
+		{name: "not a string", expression: "!'a'", wantErr: "! requires a bool, got string"},
+		{name: "and an int", expression: "1 && true", wantErr: "&& requires a bool, got int"},
+		{name: "or a null", expression: "false || null", wantErr: "|| requires a bool, got null"},
+		{name: "and evaluates the right operand", expression: "true && object.missing", wantErr: `no such field "missing"`},
+		{name: "order of different types", expression: "1 < 'a'", wantErr: "cannot compare int and string with <"},
+		{name: "order of bools", expression: "true >= false", wantErr: "cannot compare bool and bool with >="},
+		{name: "left operand error", expression: "object.missing == 1", wantErr: `no such field "missing"`},
+		{name: "right operand error", expression: "1 == object.missing", wantErr: `no such field "missing"`},
+		{name: "method of an int", expression: "object.spec.replicas.startsWith('3')", wantErr: "startsWith() requires strings, got int"},
+		{name: "method argument", expression: "object.kind.endsWith(1)", wantErr: "endsWith() requires strings, got int"},
+		{name: "method target error", expression: "object.missing.contains('a')", wantErr: `no such field "missing"`},
+		{name: "method argument error", expression: "object.kind.contains(object.missing)", wantErr: `no such field "missing"`},
+		{name: "invalid regular expression", expression: "object.kind.matches('(')", wantErr: `invalid regular expression "("`},
+		{name: "quantifier of a map", expression: "object.metadata.all(x, true)", wantErr: "all() requires a list, got map"},
+		{name: "quantifier of a missing field", expression: "object.missing.exists(x, true)", wantErr: `no such field "missing"`},
+		{name: "quantifier body not a bool", expression: "object.spec.template.spec.containers.exists(c, c.name)", wantErr: "exists() requires a bool, got string"},
+		{name: "quantifier body error", expression: "object.spec.template.spec.containers.all(c, c.missing)", wantErr: `no such field "missing"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := Compile(tc.expression, "object")
+			if err != nil {
+				t.Fatalf("Compile(%q) = %v", tc.expression, err)
+			}
+			got, err := p.Eval(map[string]interface{}{"object": object})
+			if err == nil {
+				t.Fatalf("got Eval() = %t, want error %q", got, tc.wantErr)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("got Eval() error %q, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		expression string
+		wantErr    string
+	}{
+		{name: "empty", expression: "", wantErr: "unexpected end of expression at position 0"},
+		{name: "unexpected character", expression: "true # false", wantErr: "unexpected character '#' at position 5"},
+		{name: "unterminated string", expression: "'abc", wantErr: "unterminated string at position 0"},
+		{name: "trailing backslash", expression: `'abc\`, wantErr: "unterminated string at position 0"},
+		{name: "unsupported escape", expression: `'\x41'`, wantErr: `unsupported escape sequence \x at position 1`},
+		{name: "int out of range", expression: "9223372036854775808 == 1", wantErr: "int 9223372036854775808 out of range"},
+		{name: "index out of range", expression: "object[9223372036854775808]", wantErr: "index 9223372036854775808 out of range"},
+		{name: "trailing tokens", expression: "true false", wantErr: `unexpected "false" at position 5`},
+		{name: "chained comparison", expression: "1 == 1 == true", wantErr: `unexpected "==" at position 7`},
+		{name: "unbalanced parentheses", expression: "(true || false", wantErr: `expected ")", got end of expression`},
+		{name: "missing operand", expression: "true &&", wantErr: "unexpected end of expression"},
+		{name: "missing operand of !", expression: "!", wantErr: "unexpected end of expression at position 1"},
+		{name: "operator as operand", expression: "== 1", wantErr: `unexpected "==" at position 0`},
+		{name: "undeclared variable", expression: "request.name == 'x'", wantErr: `undeclared variable "request" at position 0`},
+		{name: "unknown function", expression: "lower(object.kind)", wantErr: `undeclared variable "lower"`},
+		{name: "function without arguments", expression: "has == true", wantErr: `expected "(", got "=="`},
+		{name: "has of a variable", expression: "has(object)", wantErr: "has() requires a field selection"},
+		{name: "has of an index", expression: "has(object.spec.containers[0])", wantErr: "has() requires a field selection"},
+		{name: "has with two arguments", expression: "has(object.kind, 1)", wantErr: `expected ")", got ","`},
+		{name: "size with two arguments", expression: "size(object, 1)", wantErr: `expected ")", got ","`},
+		{name: "missing field name", expression: "object.", wantErr: "expected a field or method name, got end of expression"},
+		{name: "int field name", expression: "object.1", wantErr: `expected a field or method name, got "1"`},
+		{name: "unknown method", expression: "object.kind.lower()", wantErr: `unknown method "lower"`},
+		{name: "method without argument", expression: "object.kind.startsWith()", wantErr: `unexpected ")"`},
+		{name: "method with two arguments", expression: "object.kind.contains('a', 'b')", wantErr: `expected ")", got ","`},
+		{name: "bool index", expression: "object[true]", wantErr: `expected a string or an int index, got "true"`},
+		{name: "negative index", expression: "object.spec[-1]", wantErr: "unexpected character '-'"},
+		{name: "unterminated index", expression: "object['kind'", wantErr: `expected "]", got end of expression`},
+		{name: "quantifier without variable", expression: "object.all('c', true)", wantErr: `expected a variable name, got "c"`},
+		{name: "quantifier with a reserved variable", expression: "object.all(null, true)", wantErr: `expected a variable name, got "null"`},
+		{name: "quantifier without body", expression: "object.all(c)", wantErr: `expected ",", got ")"`},
+		{name: "quantifier variable out of scope", expression: "object.all(c, true) && c", wantErr: `undeclared variable "c"`},
+		// Lexer errors are reported from every production.
+		{name: "invalid operand of !", expression: "!#", wantErr: "unexpected character '#' at position 1"},
+		{name: "invalid operand of &&", expression: "true && #", wantErr: "unexpected character '#' at position 8"},
+		{name: "invalid operand of ==", expression: "1 == #", wantErr: "unexpected character '#' at position 5"},
+		{name: "invalid token after a comparison", expression: "1 == 1 #", wantErr: "unexpected character '#' at position 7"},
+		{name: "invalid parenthesized expression", expression: "(#", wantErr: "unexpected character '#' at position 1"},
+		{name: "invalid field name", expression: "object.#", wantErr: "unexpected character '#' at position 7"},
+		{name: "invalid token after a field", expression: "object.kind #", wantErr: "unexpected character '#' at position 12"},
+		{name: "invalid index", expression: "object[#", wantErr: "unexpected character '#' at position 7"},
+		{name: "invalid token after an index", expression: "object['kind' #", wantErr: "unexpected character '#' at position 14"},
+		{name: "invalid method argument", expression: "object.kind.contains(#", wantErr: "unexpected character '#' at position 21"},
+		{name: "invalid quantifier variable", expression: "object.all(#", wantErr: "unexpected character '#' at position 11"},
+		{name: "invalid token after a quantifier variable", expression: "object.all(c #", wantErr: "unexpected character '#' at position 13"},
+		{name: "invalid quantifier body", expression: "object.all(c, #", wantErr: "unexpected character '#' at position 14"},
+		{name: "invalid argument of has", expression: "has(#", wantErr: "unexpected character '#' at position 4"},
+		{name: "invalid argument of size", expression: "size(#", wantErr: "unexpected character '#' at position 5"},
+		{name: "invalid token after a name", expression: "true #", wantErr: "unexpected character '#' at position 5"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Compile(tc.expression, "object")
+			if err == nil {
+				t.Fatalf("got Compile(%q) = nil error, want %q", tc.expression, tc.wantErr)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("got Compile(%q) error %q, want %q", tc.expression, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestProgram_String(t *testing.T) {
+	expression := "has(object.kind)"
+	p, err := Compile(expression, "object")
+	if err != nil {
+		t.Fatalf("Compile(%q) = %v", expression, err)
+	}
+	if got := p.String(); got != expression {
+		t.Errorf("got String() = %q, want %q", got, expression)
+	}
+}
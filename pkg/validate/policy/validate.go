@@ -0,0 +1,49 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/status"
+)
+
+// Validate evaluates the policies declared in the ConfigMaps of the objects
+// and the passed policies, such as the ones declared on the cluster, against
+// every object. It returns an error for each object violating a policy.
+func Validate(objs []ast.FileObject, policies []*Policy) status.MultiError {
+	declared, errs := FromObjects(objs)
+	if errs != nil {
+		return errs
+	}
+	policies = append(append([]*Policy{}, policies...), declared...)
+	if len(policies) == 0 {
+		return nil
+	}
+	for _, obj := range objs {
+		for _, p := range policies {
+			if !p.Matches(obj) {
+				continue
+			}
+			ok, err := p.Eval(obj.Object)
+			switch {
+			case err != nil:
+				errs = status.Append(errs, EvaluationError(p, obj, err))
+			case !ok:
+				errs = status.Append(errs, ViolationError(p, obj))
+			}
+		}
+	}
+	return errs
+}
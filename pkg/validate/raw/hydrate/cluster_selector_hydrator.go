@@ -97,7 +97,7 @@ func ClusterSelectors(objs *objects.Raw) status.MultiError {
 // buildHydratorSet splits the given Raw objects into important types (Cluster,
 // ClusterSelector, Namespace) and populates a hydratorSet with them.
 func buildHydratorSet(objs *objects.Raw) (*hydratorSet, status.MultiError) {
-	set := &hydratorSet{}
+	set := &hydratorSet{liveClusterLabels: objs.ClusterLabels}
 	var errs status.MultiError
 	for _, object := range objs.Objects {
 		switch object.GetObjectKind().GroupVersionKind() {
@@ -159,10 +159,13 @@ func stateFromInlineClusterSelector(clusterName, selector string) state {
 }
 
 type hydratorSet struct {
-	cluster    *clusterregistry.Cluster
-	selectors  []*v1.ClusterSelector
-	namespaces []ast.FileObject
-	resources  []ast.FileObject
+	cluster *clusterregistry.Cluster
+	// liveClusterLabels are the labels of the cluster read from outside the
+	// repo. If non-nil, they take precedence over the labels of cluster.
+	liveClusterLabels map[string]string
+	selectors         []*v1.ClusterSelector
+	namespaces        []ast.FileObject
+	resources         []ast.FileObject
 }
 
 func (h *hydratorSet) clusterObject(object ast.FileObject) status.Error {
@@ -187,7 +190,10 @@ func (h *hydratorSet) clusterSelectorObject(object ast.FileObject) status.Error
 func (h *hydratorSet) activeSelectors() (map[string]bool, status.MultiError) {
 	activeSels := make(map[string]bool)
 	clusterLabels := labels.Set{}
-	if h.cluster != nil {
+	switch {
+	case h.liveClusterLabels != nil:
+		clusterLabels = h.liveClusterLabels
+	case h.cluster != nil:
 		clusterLabels = h.cluster.Labels
 	}
 
@@ -226,6 +226,61 @@ func TestClusterSelectors(t *testing.T) {
 				ClusterName: unknownClusterName,
 			},
 		},
+		{
+			name: "Keep object with legacy cluster selector matching live cluster labels",
+			objs: &objects.Raw{
+				ClusterName:   unknownClusterName,
+				ClusterLabels: map[string]string{"environment": "prod"},
+				Objects: []ast.FileObject{
+					fake.Role(core.Namespace("foo"), withProdLegacyClusterSelector),
+					prodSelector,
+				},
+			},
+			want: &objects.Raw{
+				ClusterName:   unknownClusterName,
+				ClusterLabels: map[string]string{"environment": "prod"},
+				Objects: []ast.FileObject{
+					fake.Role(core.Namespace("foo"), withProdLegacyClusterSelector),
+				},
+			},
+		},
+		{
+			name: "Live cluster labels take precedence over declared Cluster",
+			objs: &objects.Raw{
+				ClusterName:   prodClusterName,
+				ClusterLabels: map[string]string{"environment": "dev"},
+				Objects: []ast.FileObject{
+					fake.Role(core.Name("prod-role"), core.Namespace("foo"), withProdLegacyClusterSelector),
+					fake.Role(core.Name("dev-role"), core.Namespace("foo"), withDevLegacyClusterSelector),
+					prodCluster,
+					prodSelector,
+					devSelector,
+				},
+			},
+			want: &objects.Raw{
+				ClusterName:   prodClusterName,
+				ClusterLabels: map[string]string{"environment": "dev"},
+				Objects: []ast.FileObject{
+					fake.Role(core.Name("dev-role"), core.Namespace("foo"), withDevLegacyClusterSelector),
+				},
+			},
+		},
+		{
+			name: "Empty live cluster labels match no ClusterSelector",
+			objs: &objects.Raw{
+				ClusterName:   prodClusterName,
+				ClusterLabels: map[string]string{},
+				Objects: []ast.FileObject{
+					fake.Role(core.Namespace("foo"), withProdLegacyClusterSelector),
+					prodCluster,
+					prodSelector,
+				},
+			},
+			want: &objects.Raw{
+				ClusterName:   prodClusterName,
+				ClusterLabels: map[string]string{},
+			},
+		},
 		{
 			name: "Keep object with inline cluster selector listing multiple clusters",
 			objs: &objects.Raw{
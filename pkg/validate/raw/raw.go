@@ -44,6 +44,8 @@ func Hierarchical(objs *objects.Raw) status.MultiError {
 		objects.VisitAllRaw(validate.Directory),
 		objects.VisitAllRaw(validate.HNCLabels),
 		objects.VisitAllRaw(validate.ManagementAnnotation),
+		objects.VisitAllRaw(validate.DriftPolicyAnnotation),
+		objects.VisitAllRaw(validate.ApplyWaveAnnotation),
 		objects.VisitAllRaw(validate.IllegalCRD),
 		objects.VisitAllRaw(validate.CRDName),
 		objects.VisitAllRaw(validate.RootSync),
@@ -98,6 +100,8 @@ func Unstructured(objs *objects.Raw) status.MultiError {
 		objects.VisitAllRaw(validate.Name),
 		objects.VisitAllRaw(validate.Namespace),
 		objects.VisitAllRaw(validate.ManagementAnnotation),
+		objects.VisitAllRaw(validate.DriftPolicyAnnotation),
+		objects.VisitAllRaw(validate.ApplyWaveAnnotation),
 		objects.VisitAllRaw(validate.IllegalCRD),
 		objects.VisitAllRaw(validate.CRDName),
 		objects.VisitAllRaw(validate.RootSync),
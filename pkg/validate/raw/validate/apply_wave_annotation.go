@@ -0,0 +1,52 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"strconv"
+
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ApplyWaveAnnotation returns an Error if the user-specified apply wave
+// annotation is not an integer.
+func ApplyWaveAnnotation(obj ast.FileObject) status.Error {
+	value, found := obj.GetAnnotations()[metadata.ApplyWaveAnnotationKey]
+	if !found {
+		return nil
+	}
+	if _, err := strconv.Atoi(value); err != nil {
+		return IllegalApplyWaveAnnotationError(obj, value)
+	}
+	return nil
+}
+
+// IllegalApplyWaveAnnotationErrorCode is the error code for
+// IllegalApplyWaveAnnotationError.
+const IllegalApplyWaveAnnotationErrorCode = "1071"
+
+var illegalApplyWaveAnnotationError = status.NewErrorBuilder(IllegalApplyWaveAnnotationErrorCode)
+
+// IllegalApplyWaveAnnotationError represents an illegal apply wave annotation
+// value.
+func IllegalApplyWaveAnnotationError(resource client.Object, value string) status.Error {
+	return illegalApplyWaveAnnotationError.
+		Sprintf("Config has invalid apply wave annotation %s=%s. If set, the value must be an integer.",
+			metadata.ApplyWaveAnnotationKey, value).
+		BuildWithResources(resource)
+}
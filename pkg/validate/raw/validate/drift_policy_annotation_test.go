@@ -0,0 +1,61 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+func TestDriftPolicyAnnotation(t *testing.T) {
+	testCases := []struct {
+		name string
+		obj  ast.FileObject
+		want status.Error
+	}{
+		{
+			name: "no drift policy annotation",
+			obj:  fake.Role(),
+		},
+		{
+			name: "remediate passes",
+			obj:  fake.Role(core.Annotation(metadata.DriftPolicyAnnotationKey, "remediate")),
+		},
+		{
+			name: "report passes",
+			obj:  fake.Role(core.Annotation(metadata.DriftPolicyAnnotationKey, "report")),
+		},
+		{
+			name: "invalid drift policy fails",
+			obj:  fake.Role(core.Annotation(metadata.DriftPolicyAnnotationKey, "Report")),
+			want: fake.Error(IllegalDriftPolicyAnnotationErrorCode),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := DriftPolicyAnnotation(tc.obj)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("got DriftPolicyAnnotation() error %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
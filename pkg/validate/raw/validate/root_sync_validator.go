@@ -46,7 +46,19 @@ func RootSync(obj ast.FileObject) status.Error {
 	if rs.Spec.SourceType == "" {
 		rs.Spec.SourceType = string(v1beta1.GitSource)
 	}
-	return SourceSpec(rs.Spec.SourceType, rs.Spec.Git, rs.Spec.Oci, rootsync.GetHelmBase(rs.Spec.Helm), rs)
+	if err := SourceSpec(rs.Spec.SourceType, rs.Spec.Git, rs.Spec.Oci, rootsync.GetHelmBase(rs.Spec.Helm), rs); err != nil {
+		return err
+	}
+	if err := WebhookSpec(rs.Spec.Webhook, rs); err != nil {
+		return err
+	}
+	if err := SyncWindowsSpec(rs.Spec.SyncWindows, rs); err != nil {
+		return err
+	}
+	if err := PromotionSpec(rs.Spec.Promotion, rs.Spec.SourceType, rs); err != nil {
+		return err
+	}
+	return DependsOnSpec(rs.Spec.DependsOn, rs)
 }
 
 func toRootSyncV1Beta1(rs *v1alpha1.RootSync) (*v1beta1.RootSync, status.Error) {
@@ -15,11 +15,19 @@
 package validate
 
 import (
+	"errors"
+	"fmt"
+	"path"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/util/validation"
 	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/importer/filesystem"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/notification"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/syncwindow"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -56,7 +64,11 @@ func GitSpec(git *v1beta1.Git, rs client.Object) status.Error {
 	// Note that Auth is a case-sensitive field, so ones with arbitrary capitalization
 	// will fail to apply.
 	switch git.Auth {
-	case configsync.AuthSSH, configsync.AuthCookieFile, configsync.AuthGCENode, configsync.AuthToken, configsync.AuthNone:
+	case configsync.AuthSSH, configsync.AuthCookieFile, configsync.AuthGCENode, configsync.AuthToken, configsync.AuthGitHubApp, configsync.AuthNone:
+	case configsync.AuthK8sServiceAccount:
+		if git.TokenExchange == nil || git.TokenExchange.URL == "" {
+			return MissingTokenExchangeURL(rs, v1beta1.GitSource)
+		}
 	case configsync.AuthGCPServiceAccount:
 		if git.GCPServiceAccountEmail == "" {
 			return MissingGCPSAEmail(rs)
@@ -75,7 +87,7 @@ func GitSpec(git *v1beta1.Git, rs client.Object) status.Error {
 
 	// Check the secret ref is specified if and only if it is required.
 	switch git.Auth {
-	case configsync.AuthNone, configsync.AuthGCENode, configsync.AuthGCPServiceAccount:
+	case configsync.AuthNone, configsync.AuthGCENode, configsync.AuthGCPServiceAccount, configsync.AuthK8sServiceAccount:
 		if git.SecretRef != nil && git.SecretRef.Name != "" {
 			return IllegalSecretRef(rs)
 		}
@@ -104,6 +116,10 @@ func OciSpec(oci *v1beta1.Oci, rs client.Object) status.Error {
 	// will fail to apply.
 	switch oci.Auth {
 	case configsync.AuthGCENode, configsync.AuthNone:
+	case configsync.AuthK8sServiceAccount:
+		if oci.TokenExchange == nil || oci.TokenExchange.URL == "" {
+			return MissingTokenExchangeURL(rs, v1beta1.OciSource)
+		}
 	case configsync.AuthGCPServiceAccount:
 		if oci.GCPServiceAccountEmail == "" {
 			return MissingGCPSAEmail(rs)
@@ -114,7 +130,7 @@ func OciSpec(oci *v1beta1.Oci, rs client.Object) status.Error {
 	default:
 		return InvalidOciAuthType(rs)
 	}
-	return nil
+	return verificationSpec(oci.Verification, v1beta1.OciSource, rs)
 }
 
 // HelmSpec validates the Helm specification for any obvious problems.
@@ -133,6 +149,21 @@ func HelmSpec(helm *v1beta1.HelmBase, rs client.Object) status.Error {
 		return MissingHelmChart(rs)
 	}
 
+	for _, src := range helm.ValuesSources {
+		// Each values source must set exactly one source of values.
+		if !validHelmValuesSource(src) {
+			return InvalidHelmValuesSource(rs)
+		}
+		// The values files must be located in the chart.
+		if src.ChartFile != "" && !validHelmValuesFile(src.ChartFile) {
+			return InvalidHelmValuesFile(rs, src.ChartFile)
+		}
+		// We can't locate the ConfigMap if we don't have its name.
+		if src.ConfigMapRef != nil && src.ConfigMapRef.Name == "" {
+			return MissingHelmValuesFileRefName(rs)
+		}
+	}
+
 	// Ensure auth is a valid value.
 	// Note that Auth is a case-sensitive field, so ones with arbitrary capitalization
 	// will fail to apply.
@@ -145,6 +176,13 @@ func HelmSpec(helm *v1beta1.HelmBase, rs client.Object) status.Error {
 		if helm.SecretRef == nil || helm.SecretRef.Name == "" {
 			return MissingSecretRef(rs)
 		}
+	case configsync.AuthK8sServiceAccount:
+		if helm.SecretRef != nil && helm.SecretRef.Name != "" {
+			return IllegalSecretRef(rs)
+		}
+		if helm.TokenExchange == nil || helm.TokenExchange.URL == "" {
+			return MissingTokenExchangeURL(rs, v1beta1.HelmSource)
+		}
 	case configsync.AuthGCPServiceAccount:
 		if helm.SecretRef != nil && helm.SecretRef.Name != "" {
 			return IllegalSecretRef(rs)
@@ -158,9 +196,268 @@ func HelmSpec(helm *v1beta1.HelmBase, rs client.Object) status.Error {
 	default:
 		return InvalidHelmAuthType(rs)
 	}
+
+	// Signatures can only be verified for charts stored in an OCI registry.
+	if helm.Verification != nil && !strings.HasPrefix(helm.Repo, "oci://") {
+		return UnsupportedHelmVerification(rs)
+	}
+	return verificationSpec(helm.Verification, v1beta1.HelmSource, rs)
+}
+
+// WebhookSpec validates the webhook specification for any obvious problems.
+func WebhookSpec(webhook *v1beta1.Webhook, rs client.Object) status.Error {
+	if webhook == nil {
+		return nil
+	}
+	// Webhook requests can't be authenticated without a token.
+	if webhook.SecretRef == nil || webhook.SecretRef.Name == "" {
+		return MissingWebhookSecretRef(rs)
+	}
+	return nil
+}
+
+// SyncWindowsSpec validates the sync windows for any obvious problems.
+func SyncWindowsSpec(windows []v1beta1.SyncWindow, rs client.Object) status.Error {
+	for _, w := range windows {
+		if err := syncwindow.Validate(w); err != nil {
+			return InvalidSyncWindow(rs, err)
+		}
+	}
+	return nil
+}
+
+// PromotionSpec validates the promotion specification for any obvious problems.
+func PromotionSpec(promotion *v1beta1.Promotion, sourceType string, rs client.Object) status.Error {
+	if promotion == nil {
+		return nil
+	}
+	// Only git commits and OCI image digests can be pinned.
+	switch v1beta1.SourceType(sourceType) {
+	case v1beta1.GitSource, v1beta1.OciSource:
+	default:
+		return UnsupportedPromotionSource(rs)
+	}
+	ref := promotion.SourceRef
+	switch ref.Kind {
+	case configsync.RootSyncKind, configsync.RepoSyncKind, kinds.ConfigMap().Kind:
+	default:
+		return InvalidPromotionSourceRef(rs)
+	}
+	// A sync can't follow itself.
+	if ref.Name == "" || (ref.Kind == rs.GetObjectKind().GroupVersionKind().Kind && ref.Name == rs.GetName()) {
+		return InvalidPromotionSourceRef(rs)
+	}
+	if promotion.SoakTime != nil && promotion.SoakTime.Duration < 0 {
+		return InvalidPromotionSoakTime(rs)
+	}
+	return nil
+}
+
+// DependsOnSpec validates the dependencies for any obvious problems.
+func DependsOnSpec(dependencies []v1beta1.Dependency, rs client.Object) status.Error {
+	for _, dep := range dependencies {
+		switch dep.Kind {
+		case configsync.RootSyncKind, configsync.RepoSyncKind:
+		default:
+			return InvalidDependency(rs)
+		}
+		if dep.Name == "" {
+			return InvalidDependency(rs)
+		}
+		// A sync can't depend on itself.
+		namespace := dep.Namespace
+		if dep.Kind == configsync.RootSyncKind {
+			namespace = configsync.ControllerNamespace
+		} else if namespace == "" {
+			namespace = rs.GetNamespace()
+		}
+		if dep.Kind == rs.GetObjectKind().GroupVersionKind().Kind && dep.Name == rs.GetName() && namespace == rs.GetNamespace() {
+			return InvalidDependency(rs)
+		}
+	}
+	return nil
+}
+
+// NotificationsSpec validates the notifications for any obvious problems.
+func NotificationsSpec(notifications []v1beta1.Notification, rs client.Object) status.Error {
+	for _, n := range notifications {
+		if err := notification.Validate(n); err != nil {
+			return InvalidNotification(rs, err)
+		}
+	}
+	return nil
+}
+
+// maxSourceNameLength is the maximum length of the name of an additional
+// source, so that the name of its sidecar container is a valid DNS label.
+const maxSourceNameLength = 50
+
+// SourcesSpec validates the additional sources of a RootSync for any obvious
+// problems.
+func SourcesSpec(sources []v1beta1.RootSyncSource, sourceFormat string, rs client.Object) status.Error {
+	if len(sources) == 0 {
+		return nil
+	}
+	// Sources can't be merged into a hierarchy.
+	if sourceFormat != string(filesystem.SourceFormatUnstructured) {
+		return UnstructuredSourcesRequired(rs)
+	}
+	names := make(map[string]bool, len(sources))
+	for _, src := range sources {
+		if len(src.Name) > maxSourceNameLength || len(validation.IsDNS1123Label(src.Name)) > 0 {
+			return InvalidSourceName(rs, src.Name)
+		}
+		if names[src.Name] {
+			return InvalidSource(rs, src.Name, errors.New("the name is not unique"))
+		}
+		names[src.Name] = true
+		if err := sourceSpec(src); err != nil {
+			return InvalidSource(rs, src.Name, err)
+		}
+	}
+	return nil
+}
+
+// sourceSpec validates a single additional source. Additional sources support
+// a subset of the auth types and options of the primary source.
+func sourceSpec(src v1beta1.RootSyncSource) error {
+	switch v1beta1.SourceType(src.SourceType) {
+	case v1beta1.GitSource:
+		if src.Git == nil || src.Git.Repo == "" {
+			return errors.New("git.repo must be set")
+		}
+		return SourceAuth(src)
+	case v1beta1.OciSource:
+		if src.Oci == nil || src.Oci.Image == "" {
+			return errors.New("oci.image must be set")
+		}
+		if src.Oci.Verification != nil {
+			return errors.New("oci.verification is not supported")
+		}
+		return SourceAuth(src)
+	case v1beta1.HelmSource:
+		if src.Helm == nil || src.Helm.Repo == "" || src.Helm.Chart == "" {
+			return errors.New("helm.repo and helm.chart must be set")
+		}
+		for _, vs := range src.Helm.ValuesSources {
+			if !validHelmValuesSource(vs) {
+				return errors.New("helm.valuesSources entries must set exactly one of chartFile, configMapRef and inline")
+			}
+			if vs.ChartFile != "" && !validHelmValuesFile(vs.ChartFile) {
+				return fmt.Errorf("helm.valuesSources chartFile %q must be a relative path within the chart", vs.ChartFile)
+			}
+			if vs.ConfigMapRef != nil {
+				return errors.New("helm.valuesSources configMapRef is not supported")
+			}
+		}
+		if src.Helm.Verification != nil {
+			return errors.New("helm.verification is not supported")
+		}
+		return SourceAuth(src)
+	default:
+		return fmt.Errorf("sourceType must be one of %s, %s, %s", v1beta1.GitSource, v1beta1.OciSource, v1beta1.HelmSource)
+	}
+}
+
+// SourceAuth validates the auth of an additional source. Its sidecar only
+// receives credentials as environment variables, so the auth types that need
+// a credential volume, a CA certificate or another sidecar are not supported.
+func SourceAuth(src v1beta1.RootSyncSource) error {
+	switch v1beta1.SourceType(src.SourceType) {
+	case v1beta1.GitSource:
+		if src.Git == nil {
+			return errors.New("git must be set")
+		}
+		if src.Git.CACertSecretRef != nil {
+			return errors.New("git.caCertSecretRef is not supported")
+		}
+		return sourceAuth(src.Git.Auth, src.Git.SecretRef, configsync.AuthNone, configsync.AuthToken)
+	case v1beta1.OciSource:
+		if src.Oci == nil {
+			return errors.New("oci must be set")
+		}
+		return sourceAuth(src.Oci.Auth, nil, configsync.AuthNone, configsync.AuthGCENode)
+	case v1beta1.HelmSource:
+		if src.Helm == nil {
+			return errors.New("helm must be set")
+		}
+		return sourceAuth(src.Helm.Auth, src.Helm.SecretRef, configsync.AuthNone, configsync.AuthToken, configsync.AuthGCENode)
+	default:
+		return fmt.Errorf("sourceType must be one of %s, %s, %s", v1beta1.GitSource, v1beta1.OciSource, v1beta1.HelmSource)
+	}
+}
+
+// sourceAuth validates the auth type of an additional source against the
+// allowed auth types. Only the token auth type may reference a Secret.
+func sourceAuth(auth configsync.AuthType, secretRef *v1beta1.SecretReference, allowed ...configsync.AuthType) error {
+	valid := false
+	for _, a := range allowed {
+		if auth == a {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("auth %q is not supported", auth)
+	}
+	hasSecretRef := secretRef != nil && secretRef.Name != ""
+	if auth == configsync.AuthToken && !hasSecretRef {
+		return errors.New("secretRef.name must be set when auth is token")
+	}
+	if auth != configsync.AuthToken && hasSecretRef {
+		return fmt.Errorf("secretRef must not be set when auth is %s", auth)
+	}
+	return nil
+}
+
+// verificationSpec validates the signature verification of the source for any
+// obvious problems.
+func verificationSpec(verification *v1beta1.Verification, sourceType v1beta1.SourceType, rs client.Object) status.Error {
+	if verification == nil {
+		return nil
+	}
+	switch verification.Provider {
+	case configsync.VerificationProviderCosign, configsync.VerificationProviderNotation:
+	default:
+		return InvalidVerificationProvider(rs, sourceType)
+	}
+	// Signatures can't be verified without trusted keys.
+	if len(verification.PublicKeys) == 0 {
+		return MissingVerificationPublicKeys(rs, sourceType)
+	}
+	for _, ref := range verification.PublicKeys {
+		if ref.Name == "" || (ref.Kind != kinds.Secret().Kind && ref.Kind != kinds.ConfigMap().Kind) {
+			return InvalidVerificationPublicKey(rs, sourceType)
+		}
+	}
 	return nil
 }
 
+// validHelmValuesSource returns true if the values source sets exactly one
+// source of values.
+func validHelmValuesSource(src v1beta1.ValuesSource) bool {
+	count := 0
+	if src.ChartFile != "" {
+		count++
+	}
+	if src.ConfigMapRef != nil {
+		count++
+	}
+	if src.Inline != nil {
+		count++
+	}
+	return count == 1
+}
+
+// validHelmValuesFile returns true if the values file is a path relative to
+// the root of the chart that does not escape it.
+func validHelmValuesFile(f string) bool {
+	if f == "" || path.IsAbs(f) {
+		return false
+	}
+	clean := path.Clean(f)
+	return clean != ".." && !strings.HasPrefix(clean, "../")
+}
+
 // InvalidSyncCode is the code for an invalid declared RootSync/RepoSync.
 var InvalidSyncCode = "1061"
 
@@ -187,7 +484,7 @@ func MissingGitRepo(o client.Object) status.Error {
 // InvalidAuthType reports that a RootSync/RepoSync doesn't use one of the known auth
 // methods.
 func InvalidAuthType(o client.Object) status.Error {
-	types := []string{string(configsync.AuthSSH), string(configsync.AuthCookieFile), string(configsync.AuthGCENode), string(configsync.AuthToken), string(configsync.AuthNone), string(configsync.AuthGCPServiceAccount)}
+	types := []string{string(configsync.AuthSSH), string(configsync.AuthCookieFile), string(configsync.AuthGCENode), string(configsync.AuthToken), string(configsync.AuthNone), string(configsync.AuthGCPServiceAccount), string(configsync.AuthGitHubApp), string(configsync.AuthK8sServiceAccount)}
 	kind := o.GetObjectKind().GroupVersionKind().Kind
 	return invalidSyncBuilder.
 		Sprintf("%ss must specify spec.git.auth to be one of %s", kind,
@@ -210,8 +507,8 @@ func NoOpProxy(o client.Object) status.Error {
 func IllegalSecretRef(o client.Object) status.Error {
 	kind := o.GetObjectKind().GroupVersionKind().Kind
 	return invalidSyncBuilder.
-		Sprintf("%ss which specify spec.git.auth as one of %q, %q, or %q must not specify spec.git.secretRef",
-			kind, configsync.AuthNone, configsync.AuthGCENode, configsync.AuthGCPServiceAccount).
+		Sprintf("%ss which specify spec.git.auth as one of %q, %q, %q, or %q must not specify spec.git.secretRef",
+			kind, configsync.AuthNone, configsync.AuthGCENode, configsync.AuthGCPServiceAccount, configsync.AuthK8sServiceAccount).
 		BuildWithResources(o)
 }
 
@@ -220,8 +517,8 @@ func IllegalSecretRef(o client.Object) status.Error {
 func MissingSecretRef(o client.Object) status.Error {
 	kind := o.GetObjectKind().GroupVersionKind().Kind
 	return invalidSyncBuilder.
-		Sprintf("%ss which specify spec.git.auth as one of %q, %q or %q must also specify spec.git.secretRef",
-			kind, configsync.AuthSSH, configsync.AuthCookieFile, configsync.AuthToken).
+		Sprintf("%ss which specify spec.git.auth as one of %q, %q, %q or %q must also specify spec.git.secretRef",
+			kind, configsync.AuthSSH, configsync.AuthCookieFile, configsync.AuthToken, configsync.AuthGitHubApp).
 		BuildWithResources(o)
 }
 
@@ -246,6 +543,17 @@ func MissingGCPSAEmail(o client.Object) status.Error {
 		BuildWithResources(o)
 }
 
+// MissingTokenExchangeURL reports that a RepoSync/RootSync resource declares
+// the k8sserviceaccount auth mode without the token endpoint to exchange the
+// Kubernetes Service Account token with.
+func MissingTokenExchangeURL(o client.Object, sourceType v1beta1.SourceType) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss which specify spec.%s.auth as %q must also specify spec.%s.tokenExchange.url",
+			kind, sourceType, configsync.AuthK8sServiceAccount, sourceType).
+		BuildWithResources(o)
+}
+
 // validGCPServiceAccountEmail verifies whether GCP SA email has correct
 // prefix and suffix format.
 func validGCPServiceAccountEmail(email string) bool {
@@ -293,7 +601,7 @@ func MissingOciImage(o client.Object) status.Error {
 // InvalidOciAuthType reports that a RootSync/RepoSync doesn't use one of the known auth
 // methods for OCI image.
 func InvalidOciAuthType(o client.Object) status.Error {
-	types := []string{string(configsync.AuthGCENode), string(configsync.AuthGCPServiceAccount), string(configsync.AuthNone)}
+	types := []string{string(configsync.AuthGCENode), string(configsync.AuthGCPServiceAccount), string(configsync.AuthK8sServiceAccount), string(configsync.AuthNone)}
 	kind := o.GetObjectKind().GroupVersionKind().Kind
 	return invalidSyncBuilder.
 		Sprintf("%ss must specify spec.oci.auth to be one of %s", kind,
@@ -331,10 +639,174 @@ func MissingHelmChart(o client.Object) status.Error {
 // InvalidHelmAuthType reports that a RootSync/RepoSync doesn't use one of the known auth
 // methods for Helm.
 func InvalidHelmAuthType(o client.Object) status.Error {
-	types := []string{string(configsync.AuthGCENode), string(configsync.AuthGCPServiceAccount), string(configsync.AuthNone), string(configsync.AuthToken)}
+	types := []string{string(configsync.AuthGCENode), string(configsync.AuthGCPServiceAccount), string(configsync.AuthK8sServiceAccount), string(configsync.AuthNone), string(configsync.AuthToken)}
 	kind := o.GetObjectKind().GroupVersionKind().Kind
 	return invalidSyncBuilder.
 		Sprintf("%ss must specify spec.helm.auth to be one of %s", kind,
 			strings.Join(types, ",")).
 		BuildWithResources(o)
 }
+
+// MissingWebhookSecretRef reports that a RootSync/RepoSync enables the webhook
+// without a Secret to authenticate the requests.
+func MissingWebhookSecretRef(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.webhook.secretRef.name when spec.webhook is set", kind).
+		BuildWithResources(o)
+}
+
+// InvalidHelmValuesFile reports that a RootSync/RepoSync declares a Helm values
+// file that is not a relative path in the chart.
+func InvalidHelmValuesFile(o client.Object, valuesFile string) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.helm.valuesSources.chartFile as a path relative to the root of the chart, got %q", kind, valuesFile).
+		BuildWithResources(o)
+}
+
+// MissingHelmValuesFileRefName reports that a RootSync/RepoSync references a
+// Helm values file ConfigMap without a name.
+func MissingHelmValuesFileRefName(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.helm.valuesSources.configMapRef.name for each values file reference", kind).
+		BuildWithResources(o)
+}
+
+// InvalidHelmValuesSource reports that a RootSync/RepoSync declares a Helm
+// values source that doesn't set exactly one source of values.
+func InvalidHelmValuesSource(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify exactly one of chartFile, configMapRef and inline for each entry of spec.helm.valuesSources", kind).
+		BuildWithResources(o)
+}
+
+// InvalidVerificationProvider reports that a RootSync/RepoSync doesn't use one
+// of the known signature verification providers.
+func InvalidVerificationProvider(o client.Object, sourceType v1beta1.SourceType) status.Error {
+	providers := []string{string(configsync.VerificationProviderCosign), string(configsync.VerificationProviderNotation)}
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.%s.verification.provider to be one of %s", kind, sourceType,
+			strings.Join(providers, ",")).
+		BuildWithResources(o)
+}
+
+// MissingVerificationPublicKeys reports that a RootSync/RepoSync enables the
+// signature verification without trusted keys.
+func MissingVerificationPublicKeys(o client.Object, sourceType v1beta1.SourceType) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.%s.verification.publicKeys when spec.%s.verification is set", kind, sourceType, sourceType).
+		BuildWithResources(o)
+}
+
+// InvalidVerificationPublicKey reports that a RootSync/RepoSync references
+// trusted keys without a name, or in an object that is neither a Secret nor a
+// ConfigMap.
+func InvalidVerificationPublicKey(o client.Object, sourceType v1beta1.SourceType) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify a name and a kind of Secret or ConfigMap for each spec.%s.verification.publicKeys entry", kind, sourceType).
+		BuildWithResources(o)
+}
+
+// UnsupportedHelmVerification reports that a RootSync/RepoSync enables the
+// signature verification of a chart that is not stored in an OCI registry.
+func UnsupportedHelmVerification(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify an OCI spec.helm.repo, starting with oci://, when spec.helm.verification is set", kind).
+		BuildWithResources(o)
+}
+
+// InvalidSyncWindow reports that a RootSync/RepoSync declares a sync window
+// with an invalid kind, schedule, duration, or time zone.
+func InvalidSyncWindow(o client.Object, err error) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify valid spec.syncWindows: %v", kind, err).
+		BuildWithResources(o)
+}
+
+// UnsupportedPromotionSource reports that a RootSync/RepoSync declares a
+// promotion for a source whose revision can't be pinned to a commit.
+func UnsupportedPromotionSource(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.sourceType to be either %s or %s when spec.promotion is set", kind, v1beta1.GitSource, v1beta1.OciSource).
+		BuildWithResources(o)
+}
+
+// InvalidPromotionSourceRef reports that a RootSync/RepoSync doesn't reference
+// a valid sync to follow.
+func InvalidPromotionSourceRef(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.promotion.sourceRef with a kind of RootSync, RepoSync, or ConfigMap, and the name of another object", kind).
+		BuildWithResources(o)
+}
+
+// InvalidDependency reports that a RootSync/RepoSync doesn't reference a valid
+// sync to depend on.
+func InvalidDependency(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.dependsOn entries with a kind of RootSync or RepoSync, and the name of another sync", kind).
+		BuildWithResources(o)
+}
+
+// DependencyCycle reports that the spec.dependsOn of a RootSync/RepoSync, and
+// of the syncs it depends on, form a cycle.
+func DependencyCycle(err error) status.Error {
+	return invalidSyncBuilder.Wrap(err).
+		Sprint("RootSyncs and RepoSyncs must not depend on each other in a cycle").
+		Build()
+}
+
+// InvalidNotification reports that a RootSync/RepoSync declares a notification
+// with an invalid url, outcome, or template.
+func InvalidNotification(o client.Object, err error) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify valid spec.notifications: %v", kind, err).
+		BuildWithResources(o)
+}
+
+// InvalidPromotionSoakTime reports that a RootSync/RepoSync declares a
+// negative soak time.
+func InvalidPromotionSoakTime(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify a positive spec.promotion.soakTime", kind).
+		BuildWithResources(o)
+}
+
+// UnstructuredSourcesRequired reports that a RootSync declares additional
+// sources without using the unstructured source format.
+func UnstructuredSourcesRequired(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.sourceFormat to be %s when spec.sources is set", kind, filesystem.SourceFormatUnstructured).
+		BuildWithResources(o)
+}
+
+// InvalidSourceName reports that a RootSync declares an additional source
+// whose name is not a short DNS label.
+func InvalidSourceName(o client.Object, name string) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.sources names that are DNS labels of at most %d characters, got %q", kind, maxSourceNameLength, name).
+		BuildWithResources(o)
+}
+
+// InvalidSource reports that a RootSync declares an additional source that
+// can't be fetched.
+func InvalidSource(o client.Object, name string, err error) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify a valid spec.sources entry %q: %v", kind, name, err).
+		BuildWithResources(o)
+}
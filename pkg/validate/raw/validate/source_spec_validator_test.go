@@ -17,7 +17,10 @@ package validate
 import (
 	"errors"
 	"testing"
+	"time"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/reposync"
@@ -69,6 +72,20 @@ func gcpSAEmail(email string) func(sync *v1beta1.RepoSync) {
 	}
 }
 
+func tokenExchange(url string) func(*v1beta1.RepoSync) {
+	return func(sync *v1beta1.RepoSync) {
+		te := &v1beta1.TokenExchange{URL: url}
+		switch v1beta1.SourceType(sync.Spec.SourceType) {
+		case v1beta1.GitSource:
+			sync.Spec.Git.TokenExchange = te
+		case v1beta1.OciSource:
+			sync.Spec.Oci.TokenExchange = te
+		case v1beta1.HelmSource:
+			sync.Spec.Helm.TokenExchange = te
+		}
+	}
+}
+
 func missingRepo(rs *v1beta1.RepoSync) {
 	rs.Spec.Repo = ""
 }
@@ -85,6 +102,30 @@ func missingHelmChart(rs *v1beta1.RepoSync) {
 	rs.Spec.Helm.Chart = ""
 }
 
+func helmValuesSources(sources ...v1beta1.ValuesSource) func(*v1beta1.RepoSync) {
+	return func(rs *v1beta1.RepoSync) {
+		rs.Spec.Helm.ValuesSources = sources
+	}
+}
+
+func ociVerification(provider configsync.VerificationProvider, keys ...v1beta1.PublicKeyRef) func(*v1beta1.RepoSync) {
+	return func(rs *v1beta1.RepoSync) {
+		rs.Spec.Oci.Verification = &v1beta1.Verification{Provider: provider, PublicKeys: keys}
+	}
+}
+
+func helmVerification(provider configsync.VerificationProvider, keys ...v1beta1.PublicKeyRef) func(*v1beta1.RepoSync) {
+	return func(rs *v1beta1.RepoSync) {
+		rs.Spec.Helm.Verification = &v1beta1.Verification{Provider: provider, PublicKeys: keys}
+	}
+}
+
+func helmRepo(repo string) func(*v1beta1.RepoSync) {
+	return func(rs *v1beta1.RepoSync) {
+		rs.Spec.Helm.Repo = repo
+	}
+}
+
 func repoSyncWithGit(opts ...func(*v1beta1.RepoSync)) *v1beta1.RepoSync {
 	rs := fake.RepoSyncObjectV1Beta1("test-ns", configsync.RepoSyncName)
 	rs.Spec.SourceType = string(v1beta1.GitSource)
@@ -211,7 +252,44 @@ func TestValidateSourceSpec(t *testing.T) {
 			obj:     repoSyncWithGit(auth(configsync.AuthGCPServiceAccount)),
 			wantErr: fake.Error(InvalidSyncCode),
 		},
+		{
+			name: "valid githubapp",
+			obj:  repoSyncWithGit(auth(configsync.AuthGitHubApp), secret("github-app")),
+		},
+		{
+			name:    "missing secret for githubapp",
+			obj:     repoSyncWithGit(auth(configsync.AuthGitHubApp)),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "valid k8sserviceaccount for git",
+			obj:  repoSyncWithGit(auth(configsync.AuthK8sServiceAccount), tokenExchange("https://sts.example.com/token")),
+		},
+		{
+			name:    "missing token exchange url for git",
+			obj:     repoSyncWithGit(auth(configsync.AuthK8sServiceAccount)),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:    "illegal secret for k8sserviceaccount",
+			obj:     repoSyncWithGit(auth(configsync.AuthK8sServiceAccount), tokenExchange("https://sts.example.com/token"), secret("illegal secret")),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
 		// Validate OCI spec
+		{
+			name: "valid k8sserviceaccount for oci",
+			obj:  repoSyncWithOci(ociAuth(configsync.AuthK8sServiceAccount), tokenExchange("https://sts.example.com/token")),
+		},
+		{
+			name:    "missing token exchange url for oci",
+			obj:     repoSyncWithOci(ociAuth(configsync.AuthK8sServiceAccount)),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:    "githubapp for oci",
+			obj:     repoSyncWithOci(ociAuth(configsync.AuthGitHubApp)),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
 		{
 			name: "valid oci",
 			obj:  repoSyncWithOci(ociAuth(configsync.AuthNone)),
@@ -231,6 +309,31 @@ func TestValidateSourceSpec(t *testing.T) {
 			obj:     repoSyncWithOci(ociAuth(configsync.AuthGCPServiceAccount)),
 			wantErr: fake.Error(InvalidSyncCode),
 		},
+		{
+			name: "valid oci verification",
+			obj: repoSyncWithOci(ociAuth(configsync.AuthNone), ociVerification(configsync.VerificationProviderCosign,
+				v1beta1.PublicKeyRef{Kind: "Secret", Name: "cosign-keys"}, v1beta1.PublicKeyRef{Kind: "ConfigMap", Name: "more-keys"})),
+		},
+		{
+			name:    "invalid verification provider",
+			obj:     repoSyncWithOci(ociAuth(configsync.AuthNone), ociVerification("gpg", v1beta1.PublicKeyRef{Kind: "Secret", Name: "keys"})),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:    "missing verification public keys",
+			obj:     repoSyncWithOci(ociAuth(configsync.AuthNone), ociVerification(configsync.VerificationProviderNotation)),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:    "invalid verification public key kind",
+			obj:     repoSyncWithOci(ociAuth(configsync.AuthNone), ociVerification(configsync.VerificationProviderCosign, v1beta1.PublicKeyRef{Kind: "Pod", Name: "keys"})),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:    "missing verification public key name",
+			obj:     repoSyncWithOci(ociAuth(configsync.AuthNone), ociVerification(configsync.VerificationProviderCosign, v1beta1.PublicKeyRef{Kind: "Secret"})),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
 		{
 			name:    "invalid source type",
 			obj:     fake.RepoSyncObjectV1Beta1("test-ns", configsync.RepoSyncName, fake.WithRepoSyncSourceType("invalid")),
@@ -247,6 +350,15 @@ func TestValidateSourceSpec(t *testing.T) {
 			wantErr: fake.Error(InvalidSyncCode),
 		},
 		// Validate Helm spec
+		{
+			name: "valid k8sserviceaccount for helm",
+			obj:  repoSyncWithHelm(helmAuth(configsync.AuthK8sServiceAccount), tokenExchange("https://sts.example.com/token")),
+		},
+		{
+			name:    "missing token exchange url for helm",
+			obj:     repoSyncWithHelm(helmAuth(configsync.AuthK8sServiceAccount)),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
 		{
 			name: "valid helm",
 			obj:  repoSyncWithHelm(helmAuth(configsync.AuthNone)),
@@ -271,6 +383,54 @@ func TestValidateSourceSpec(t *testing.T) {
 			obj:     repoSyncWithHelm(helmAuth(configsync.AuthGCPServiceAccount)),
 			wantErr: fake.Error(InvalidSyncCode),
 		},
+		{
+			name: "valid helm values sources",
+			obj: repoSyncWithHelm(helmAuth(configsync.AuthNone), helmValuesSources(
+				v1beta1.ValuesSource{ConfigMapRef: &v1beta1.ValuesFileRef{Name: "platform-values"}},
+				v1beta1.ValuesSource{ChartFile: "values-prod.yaml"},
+				v1beta1.ValuesSource{Inline: &apiextensionsv1.JSON{Raw: []byte(`{"replicas":3}`)}},
+				v1beta1.ValuesSource{ChartFile: "env/values.yaml"},
+				v1beta1.ValuesSource{ConfigMapRef: &v1beta1.ValuesFileRef{Name: "app-values", DataKey: "prod.yaml"}})),
+		},
+		{
+			name:    "absolute helm values file",
+			obj:     repoSyncWithHelm(helmAuth(configsync.AuthNone), helmValuesSources(v1beta1.ValuesSource{ChartFile: "/etc/passwd"})),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:    "helm values file outside of the chart",
+			obj:     repoSyncWithHelm(helmAuth(configsync.AuthNone), helmValuesSources(v1beta1.ValuesSource{ChartFile: "env/../../values.yaml"})),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:    "empty helm values source",
+			obj:     repoSyncWithHelm(helmAuth(configsync.AuthNone), helmValuesSources(v1beta1.ValuesSource{})),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "helm values source with several sources of values",
+			obj: repoSyncWithHelm(helmAuth(configsync.AuthNone), helmValuesSources(v1beta1.ValuesSource{
+				ChartFile:    "values-prod.yaml",
+				ConfigMapRef: &v1beta1.ValuesFileRef{Name: "app-values"},
+			})),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:    "missing helm values file ref name",
+			obj:     repoSyncWithHelm(helmAuth(configsync.AuthNone), helmValuesSources(v1beta1.ValuesSource{ConfigMapRef: &v1beta1.ValuesFileRef{DataKey: "values.yaml"}})),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "valid helm verification",
+			obj: repoSyncWithHelm(helmAuth(configsync.AuthNone), helmRepo("oci://us-docker.pkg.dev/my-project/charts"),
+				helmVerification(configsync.VerificationProviderNotation, v1beta1.PublicKeyRef{Kind: "ConfigMap", Name: "notation-roots"})),
+		},
+		{
+			name: "helm verification of a non-OCI repo",
+			obj: repoSyncWithHelm(helmAuth(configsync.AuthNone), helmRepo("https://charts.example.com"),
+				helmVerification(configsync.VerificationProviderCosign, v1beta1.PublicKeyRef{Kind: "Secret", Name: "cosign-keys"})),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
 		{
 			name:    "redundant Helm spec",
 			obj:     repoSyncWithGit(withHelm()),
@@ -287,3 +447,379 @@ func TestValidateSourceSpec(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateWebhookSpec(t *testing.T) {
+	testCases := []struct {
+		name    string
+		webhook *v1beta1.Webhook
+		wantErr status.Error
+	}{
+		{
+			name: "webhook disabled",
+		},
+		{
+			name:    "valid webhook",
+			webhook: &v1beta1.Webhook{SecretRef: &v1beta1.SecretReference{Name: "webhook-token"}},
+		},
+		{
+			name:    "missing secretRef",
+			webhook: &v1beta1.Webhook{},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:    "missing secretRef name",
+			webhook: &v1beta1.Webhook{SecretRef: &v1beta1.SecretReference{}},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := repoSyncWithGit(auth(configsync.AuthNone))
+			rs.Spec.Webhook = tc.webhook
+			err := WebhookSpec(rs.Spec.Webhook, rs)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("Got WebhookSpec() error %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSyncWindowsSpec(t *testing.T) {
+	testCases := []struct {
+		name    string
+		windows []v1beta1.SyncWindow
+		wantErr status.Error
+	}{
+		{
+			name: "no sync windows",
+		},
+		{
+			name: "valid sync windows",
+			windows: []v1beta1.SyncWindow{
+				{Kind: configsync.SyncWindowAllow, Schedule: "0 9 * * 1-5", Duration: metav1.Duration{Duration: 8 * time.Hour}},
+				{Kind: configsync.SyncWindowDeny, Schedule: "0 18 * * FRI", Duration: metav1.Duration{Duration: 60 * time.Hour}, TimeZone: "Europe/Paris"},
+			},
+		},
+		{
+			name: "invalid schedule",
+			windows: []v1beta1.SyncWindow{
+				{Kind: configsync.SyncWindowDeny, Schedule: "0 18 * *", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "missing duration",
+			windows: []v1beta1.SyncWindow{
+				{Kind: configsync.SyncWindowAllow, Schedule: "@daily"},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := repoSyncWithGit(auth(configsync.AuthNone))
+			rs.Spec.SyncWindows = tc.windows
+			err := SyncWindowsSpec(rs.Spec.SyncWindows, rs)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("Got SyncWindowsSpec() error %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePromotionSpec(t *testing.T) {
+	testCases := []struct {
+		name       string
+		sourceType v1beta1.SourceType
+		promotion  *v1beta1.Promotion
+		wantErr    status.Error
+	}{
+		{
+			name:       "promotion disabled",
+			sourceType: v1beta1.GitSource,
+		},
+		{
+			name:       "valid promotion",
+			sourceType: v1beta1.GitSource,
+			promotion: &v1beta1.Promotion{
+				SourceRef: v1beta1.PromotionSourceRef{Kind: configsync.RepoSyncKind, Name: "canary"},
+				SoakTime:  &metav1.Duration{Duration: time.Hour},
+			},
+		},
+		{
+			name:       "helm source",
+			sourceType: v1beta1.HelmSource,
+			promotion: &v1beta1.Promotion{
+				SourceRef: v1beta1.PromotionSourceRef{Kind: configsync.RepoSyncKind, Name: "canary"},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:       "invalid kind",
+			sourceType: v1beta1.OciSource,
+			promotion: &v1beta1.Promotion{
+				SourceRef: v1beta1.PromotionSourceRef{Kind: "Secret", Name: "canary"},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:       "missing name",
+			sourceType: v1beta1.GitSource,
+			promotion: &v1beta1.Promotion{
+				SourceRef: v1beta1.PromotionSourceRef{Kind: "ConfigMap"},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:       "self reference",
+			sourceType: v1beta1.GitSource,
+			promotion: &v1beta1.Promotion{
+				SourceRef: v1beta1.PromotionSourceRef{Kind: configsync.RepoSyncKind, Name: configsync.RepoSyncName},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:       "negative soak time",
+			sourceType: v1beta1.GitSource,
+			promotion: &v1beta1.Promotion{
+				SourceRef: v1beta1.PromotionSourceRef{Kind: configsync.RootSyncKind, Name: "canary"},
+				SoakTime:  &metav1.Duration{Duration: -time.Hour},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := repoSyncWithGit(auth(configsync.AuthNone))
+			rs.Spec.Promotion = tc.promotion
+			err := PromotionSpec(rs.Spec.Promotion, string(tc.sourceType), rs)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("Got PromotionSpec() error %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDependsOnSpec(t *testing.T) {
+	testCases := []struct {
+		name         string
+		dependencies []v1beta1.Dependency
+		wantErr      status.Error
+	}{
+		{
+			name: "no dependencies",
+		},
+		{
+			name: "valid dependencies",
+			dependencies: []v1beta1.Dependency{
+				{Kind: configsync.RootSyncKind, Name: configsync.RootSyncName},
+				{Kind: configsync.RepoSyncKind, Name: "crds"},
+				{Kind: configsync.RepoSyncKind, Name: configsync.RepoSyncName, Namespace: "other-ns"},
+			},
+		},
+		{
+			name: "invalid kind",
+			dependencies: []v1beta1.Dependency{
+				{Kind: "ConfigMap", Name: "crds"},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "missing name",
+			dependencies: []v1beta1.Dependency{
+				{Kind: configsync.RootSyncKind},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "self reference",
+			dependencies: []v1beta1.Dependency{
+				{Kind: configsync.RepoSyncKind, Name: configsync.RepoSyncName},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "self reference with namespace",
+			dependencies: []v1beta1.Dependency{
+				{Kind: configsync.RepoSyncKind, Name: configsync.RepoSyncName, Namespace: "test-ns"},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := repoSyncWithGit(auth(configsync.AuthNone))
+			rs.Spec.DependsOn = tc.dependencies
+			err := DependsOnSpec(rs.Spec.DependsOn, rs)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("Got DependsOnSpec() error %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateNotificationsSpec(t *testing.T) {
+	testCases := []struct {
+		name          string
+		notifications []v1beta1.Notification
+		wantErr       status.Error
+	}{
+		{
+			name: "no notifications",
+		},
+		{
+			name: "valid notifications",
+			notifications: []v1beta1.Notification{
+				{URL: "https://example.com/hook"},
+				{URL: "http://receiver.default.svc/events", On: []configsync.NotificationEvent{configsync.NotificationFailed}, Template: `{"text": "{{.Data.Commit}}"}`},
+			},
+		},
+		{
+			name: "missing url",
+			notifications: []v1beta1.Notification{
+				{On: []configsync.NotificationEvent{configsync.NotificationSynced}},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "invalid template",
+			notifications: []v1beta1.Notification{
+				{URL: "https://example.com/hook", Template: "{{.Data"},
+			},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := repoSyncWithGit(auth(configsync.AuthNone))
+			rs.Spec.Notifications = tc.notifications
+			err := NotificationsSpec(rs.Spec.Notifications, rs)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("Got NotificationsSpec() error %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSourcesSpec(t *testing.T) {
+	gitSource := v1beta1.RootSyncSource{
+		Name:       "base",
+		SourceType: string(v1beta1.GitSource),
+		Git:        &v1beta1.Git{Repo: "https://github.com/test/base", Auth: configsync.AuthNone},
+	}
+	helmSource := v1beta1.RootSyncSource{
+		Name:       "cert-manager",
+		SourceType: string(v1beta1.HelmSource),
+		Helm: &v1beta1.HelmRootSync{HelmBase: v1beta1.HelmBase{
+			Repo: "https://charts.jetstack.io", Chart: "cert-manager", Auth: configsync.AuthNone,
+		}},
+	}
+	ociSource := v1beta1.RootSyncSource{
+		Name:       "policies",
+		SourceType: string(v1beta1.OciSource),
+		Oci:        &v1beta1.Oci{Image: "us-docker.pkg.dev/test/policies", Auth: configsync.AuthGCENode},
+	}
+	testCases := []struct {
+		name         string
+		sourceFormat string
+		sources      []v1beta1.RootSyncSource
+		wantErr      status.Error
+	}{
+		{
+			name:         "no sources",
+			sourceFormat: "hierarchy",
+		},
+		{
+			name:         "valid sources",
+			sourceFormat: "unstructured",
+			sources:      []v1beta1.RootSyncSource{gitSource, helmSource, ociSource},
+		},
+		{
+			name:         "valid git source with token",
+			sourceFormat: "unstructured",
+			sources: []v1beta1.RootSyncSource{{
+				Name:       "base",
+				SourceType: string(v1beta1.GitSource),
+				Git:        &v1beta1.Git{Repo: "https://github.com/test/base", Auth: configsync.AuthToken, SecretRef: &v1beta1.SecretReference{Name: "git-creds"}},
+			}},
+		},
+		{
+			name:         "hierarchy format",
+			sourceFormat: "hierarchy",
+			sources:      []v1beta1.RootSyncSource{gitSource},
+			wantErr:      fake.Error(InvalidSyncCode),
+		},
+		{
+			name:         "duplicate names",
+			sourceFormat: "unstructured",
+			sources:      []v1beta1.RootSyncSource{gitSource, gitSource},
+			wantErr:      fake.Error(InvalidSyncCode),
+		},
+		{
+			name:         "invalid name",
+			sourceFormat: "unstructured",
+			sources: []v1beta1.RootSyncSource{{
+				Name:       "Base",
+				SourceType: string(v1beta1.GitSource),
+				Git:        gitSource.Git,
+			}},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:         "missing git spec",
+			sourceFormat: "unstructured",
+			sources:      []v1beta1.RootSyncSource{{Name: "base", SourceType: string(v1beta1.GitSource)}},
+			wantErr:      fake.Error(InvalidSyncCode),
+		},
+		{
+			name:         "unsupported git auth",
+			sourceFormat: "unstructured",
+			sources: []v1beta1.RootSyncSource{{
+				Name:       "base",
+				SourceType: string(v1beta1.GitSource),
+				Git:        &v1beta1.Git{Repo: "git@github.com:test/base", Auth: configsync.AuthSSH, SecretRef: &v1beta1.SecretReference{Name: "ssh-key"}},
+			}},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:         "token auth without secret",
+			sourceFormat: "unstructured",
+			sources: []v1beta1.RootSyncSource{{
+				Name:       "base",
+				SourceType: string(v1beta1.GitSource),
+				Git:        &v1beta1.Git{Repo: "https://github.com/test/base", Auth: configsync.AuthToken},
+			}},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:         "helm values file refs",
+			sourceFormat: "unstructured",
+			sources: []v1beta1.RootSyncSource{{
+				Name:       "cert-manager",
+				SourceType: string(v1beta1.HelmSource),
+				Helm: &v1beta1.HelmRootSync{HelmBase: v1beta1.HelmBase{
+					Repo: "https://charts.jetstack.io", Chart: "cert-manager", Auth: configsync.AuthNone,
+					ValuesSources: []v1beta1.ValuesSource{{ConfigMapRef: &v1beta1.ValuesFileRef{Name: "values"}}},
+				}},
+			}},
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := fake.RootSyncObjectV1Beta1(configsync.RootSyncName)
+			rs.Spec.SourceFormat = tc.sourceFormat
+			rs.Spec.Sources = tc.sources
+			err := SourcesSpec(rs.Spec.Sources, rs.Spec.SourceFormat, rs)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("Got SourcesSpec() error %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
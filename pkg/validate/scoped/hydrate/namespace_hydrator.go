@@ -15,6 +15,8 @@
 package hydrate
 
 import (
+	"sort"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	v1 "kpt.dev/configsync/pkg/api/configmanagement/v1"
@@ -61,7 +63,8 @@ func NamespaceSelectors(objs *objects.Scoped) status.MultiError {
 
 // buildSelectorMap processes the given cluster-scoped objects to return a map
 // of NamespaceSelector names to the namespaces that are selected by each one.
-// Note that this modifies the Scoped objects to filter out the
+// Dynamic NamespaceSelectors also select the live Namespaces which are not
+// declared, by their current labels. Note that this modifies the Scoped objects to filter out the
 // NamespaceSelectors since they are no longer needed after this point.
 func buildSelectorMap(objs *objects.Scoped) (map[string][]string, status.MultiError) {
 	var namespaces, nsSelectors, others []ast.FileObject
@@ -79,9 +82,21 @@ func buildSelectorMap(objs *objects.Scoped) (map[string][]string, status.MultiEr
 	var errs status.MultiError
 	selectorMap := make(map[string][]string)
 
+	declared := make(map[string]bool)
+	for _, namespace := range namespaces {
+		declared[namespace.GetName()] = true
+	}
+	var liveNamespaces []string
+	for name := range objs.LiveNamespaceLabels {
+		if !declared[name] {
+			liveNamespaces = append(liveNamespaces, name)
+		}
+	}
+	sort.Strings(liveNamespaces)
+
 	for _, obj := range nsSelectors {
 		var selected []string
-		selector, err := labelSelector(obj)
+		selector, mode, err := labelSelector(obj)
 		if err != nil {
 			errs = status.Append(errs, err)
 			continue
@@ -92,6 +107,13 @@ func buildSelectorMap(objs *objects.Scoped) (map[string][]string, status.MultiEr
 				selected = append(selected, namespace.GetName())
 			}
 		}
+		if mode == v1.NSSelectorDynamicMode {
+			for _, name := range liveNamespaces {
+				if selector.Matches(labels.Set(objs.LiveNamespaceLabels[name])) {
+					selected = append(selected, name)
+				}
+			}
+		}
 
 		selectorMap[obj.GetName()] = selected
 	}
@@ -105,21 +127,30 @@ func buildSelectorMap(objs *objects.Scoped) (map[string][]string, status.MultiEr
 	return selectorMap, nil
 }
 
-func labelSelector(obj ast.FileObject) (labels.Selector, status.Error) {
+func labelSelector(obj ast.FileObject) (labels.Selector, v1.NSSelectorModeType, status.Error) {
 	s, sErr := obj.Structured()
 	if sErr != nil {
-		return nil, sErr
+		return nil, "", sErr
 	}
 	nss := s.(*v1.NamespaceSelector)
 
+	mode := nss.Spec.Mode
+	switch mode {
+	case "":
+		mode = v1.NSSelectorStaticMode
+	case v1.NSSelectorStaticMode, v1.NSSelectorDynamicMode:
+	default:
+		return nil, "", selectors.InvalidNamespaceSelectorModeError(obj, string(mode))
+	}
+
 	selector, err := metav1.LabelSelectorAsSelector(&nss.Spec.Selector)
 	if err != nil {
-		return nil, selectors.InvalidSelectorError(obj, err)
+		return nil, "", selectors.InvalidSelectorError(obj, err)
 	}
 	if selector.Empty() {
-		return nil, selectors.EmptySelectorError(obj)
+		return nil, "", selectors.EmptySelectorError(obj)
 	}
-	return selector, nil
+	return selector, mode, nil
 }
 
 // makeNamespaceCopies uses the given object's namespace selector to make a copy
@@ -45,7 +45,26 @@ var (
 				"environment": "xin prod",
 			}
 		})
-	invalidNSS = fake.FileObject(invalidNSSObject, "invalid-nss.yaml")
+	invalidNSS        = fake.FileObject(invalidNSSObject, "invalid-nss.yaml")
+	dynamicNSSelector = fake.FileObject(fake.NamespaceSelectorObject(core.Name("dev-only"),
+		func(o client.Object) {
+			o.(*v1.NamespaceSelector).Spec.Selector.MatchLabels = map[string]string{
+				"environment": "dev",
+			}
+			o.(*v1.NamespaceSelector).Spec.Mode = v1.NSSelectorDynamicMode
+		}), "dev-only-nss.yaml")
+	invalidModeNSS = fake.FileObject(fake.NamespaceSelectorObject(core.Name("invalid-mode"),
+		func(o client.Object) {
+			o.(*v1.NamespaceSelector).Spec.Selector.MatchLabels = map[string]string{
+				"environment": "dev",
+			}
+			o.(*v1.NamespaceSelector).Spec.Mode = "live"
+		}), "invalid-mode-nss.yaml")
+	liveNamespaceLabels = map[string]map[string]string{
+		"dev1":   {"environment": "dev"},
+		"tenant": {"environment": "dev"},
+		"other":  {"environment": "prod"},
+	}
 )
 
 func TestNamespaceSelectors(t *testing.T) {
@@ -109,6 +128,60 @@ func TestNamespaceSelectors(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Static namespace selector ignores live namespaces",
+			objs: &objects.Scoped{
+				Cluster: []ast.FileObject{
+					namespaceSelector,
+					fake.Namespace("namespaces/dev1", core.Label("environment", "dev")),
+				},
+				Namespace: []ast.FileObject{
+					fake.Role(core.Annotation(metadata.NamespaceSelectorAnnotationKey, "dev-only")),
+				},
+				LiveNamespaceLabels: liveNamespaceLabels,
+			},
+			want: &objects.Scoped{
+				Cluster: []ast.FileObject{
+					fake.Namespace("namespaces/dev1", core.Label("environment", "dev")),
+				},
+				Namespace: []ast.FileObject{
+					fake.Role(
+						core.Namespace("dev1"),
+						core.Annotation(metadata.NamespaceSelectorAnnotationKey, "dev-only")),
+				},
+				LiveNamespaceLabels: liveNamespaceLabels,
+			},
+		},
+		{
+			name: "Copy object into live namespaces with dynamic namespace selector",
+			objs: &objects.Scoped{
+				Cluster: []ast.FileObject{
+					dynamicNSSelector,
+					// The declared labels take precedence over the live labels.
+					fake.Namespace("namespaces/dev1", core.Label("environment", "prod")),
+					fake.Namespace("namespaces/dev2", core.Label("environment", "dev")),
+				},
+				Namespace: []ast.FileObject{
+					fake.Role(core.Annotation(metadata.NamespaceSelectorAnnotationKey, "dev-only")),
+				},
+				LiveNamespaceLabels: liveNamespaceLabels,
+			},
+			want: &objects.Scoped{
+				Cluster: []ast.FileObject{
+					fake.Namespace("namespaces/dev1", core.Label("environment", "prod")),
+					fake.Namespace("namespaces/dev2", core.Label("environment", "dev")),
+				},
+				Namespace: []ast.FileObject{
+					fake.Role(
+						core.Namespace("dev2"),
+						core.Annotation(metadata.NamespaceSelectorAnnotationKey, "dev-only")),
+					fake.Role(
+						core.Namespace("tenant"),
+						core.Annotation(metadata.NamespaceSelectorAnnotationKey, "dev-only")),
+				},
+				LiveNamespaceLabels: liveNamespaceLabels,
+			},
+		},
 		{
 			name: "Remove object with inactive namespace selector",
 			objs: &objects.Scoped{
@@ -213,6 +286,26 @@ func TestNamespaceSelectors(t *testing.T) {
 			},
 			wantErrs: selectors.InvalidSelectorError(invalidNSS, errors.New("")),
 		},
+		{
+			name: "Error for invalid namespace selector mode",
+			objs: &objects.Scoped{
+				Cluster: []ast.FileObject{
+					invalidModeNSS,
+				},
+				Namespace: []ast.FileObject{
+					fake.Role(core.Annotation(metadata.NamespaceSelectorAnnotationKey, "invalid-mode")),
+				},
+			},
+			want: &objects.Scoped{
+				Cluster: []ast.FileObject{
+					invalidModeNSS,
+				},
+				Namespace: []ast.FileObject{
+					fake.Role(core.Annotation(metadata.NamespaceSelectorAnnotationKey, "invalid-mode")),
+				},
+			},
+			wantErrs: selectors.InvalidNamespaceSelectorModeError(invalidModeNSS, "live"),
+		},
 	}
 
 	for _, tc := range testCases {
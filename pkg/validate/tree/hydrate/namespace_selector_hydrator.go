@@ -59,6 +59,14 @@ func labelSelector(obj ast.FileObject) (labels.Selector, status.Error) {
 	}
 	nss := s.(*v1.NamespaceSelector)
 
+	switch nss.Spec.Mode {
+	case "", v1.NSSelectorStaticMode:
+	case v1.NSSelectorDynamicMode:
+		return nil, selectors.UnsupportedDynamicNamespaceSelectorError(obj)
+	default:
+		return nil, selectors.InvalidNamespaceSelectorModeError(obj, string(nss.Spec.Mode))
+	}
+
 	selector, err := metav1.LabelSelectorAsSelector(&nss.Spec.Selector)
 	if err != nil {
 		return nil, selectors.InvalidSelectorError(obj, err)
@@ -17,10 +17,13 @@ package hydrate
 import (
 	"testing"
 
+	"errors"
 	"github.com/google/go-cmp/cmp"
+	v1 "kpt.dev/configsync/pkg/api/configmanagement/v1"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast/node"
+	"kpt.dev/configsync/pkg/importer/analyzer/transform/selectors"
 	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/testing/fake"
@@ -307,3 +310,26 @@ func TestNamespaceSelectors(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespaceSelectors_DynamicMode(t *testing.T) {
+	namespaceSelectorObject := fake.NamespaceSelectorObject(core.Name("sre"))
+	namespaceSelectorObject.Spec.Selector.MatchLabels = map[string]string{
+		"sre-support": "true",
+	}
+	namespaceSelectorObject.Spec.Mode = v1.NSSelectorDynamicMode
+	namespaceSelector := fake.FileObject(namespaceSelectorObject, "namespaces/foo/selector.yaml")
+
+	objs := &objects.Tree{
+		NamespaceSelectors: map[string]ast.FileObject{
+			"sre": namespaceSelector,
+		},
+		Tree: &ast.TreeNode{
+			Relative: cmpath.RelativeSlash("namespaces"),
+			Type:     node.AbstractNamespace,
+		},
+	}
+	want := selectors.UnsupportedDynamicNamespaceSelectorError(namespaceSelector)
+	if errs := NamespaceSelectors(objs); !errors.Is(errs, want) {
+		t.Errorf("Got NamespaceSelectors() error %v, want %v", errs, want)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"kpt.dev/configsync/pkg/util/discovery"
 	"kpt.dev/configsync/pkg/validate/final"
 	"kpt.dev/configsync/pkg/validate/objects"
+	"kpt.dev/configsync/pkg/validate/policy"
 	"kpt.dev/configsync/pkg/validate/raw"
 	"kpt.dev/configsync/pkg/validate/scoped"
 	"kpt.dev/configsync/pkg/validate/tree"
@@ -39,6 +40,11 @@ type Options struct {
 	// ClusterName is the spec.clusterName of the cluster's ConfigManagement. This
 	// is used when hydrating cluster selectors.
 	ClusterName string
+	// ClusterLabels are the labels of the cluster read from the cluster itself
+	// or passed to nomos with --cluster-labels. If non-nil, they are used when
+	// hydrating ClusterSelectors instead of the labels of the Cluster object
+	// declared in the repo.
+	ClusterLabels map[string]string
 	// ReconcilerName is the name of the reconciler.
 	ReconcilerName string
 	// PolicyDir is the relative path of the root policy directory within the
@@ -68,6 +74,14 @@ type Options struct {
 	// IsNamespaceReconciler is a flag to indicate if the caller is a namespace
 	// reconciler which adds some additional validation logic.
 	IsNamespaceReconciler bool
+	// LiveNamespaceLabels are the labels of the live Namespaces on the
+	// cluster, keyed by name. This is used when hydrating dynamic
+	// NamespaceSelectors in an unstructured repo.
+	LiveNamespaceLabels map[string]map[string]string
+	// Policies are the validation policies declared on the cluster. They are
+	// evaluated against the final objects along with the policies declared in
+	// the source.
+	Policies []*policy.Policy
 	// Visitors is a list of optional visitor functions which can be used to
 	// inject additional validation or hydration steps on the final objects.
 	Visitors []VisitorFunc
@@ -85,6 +99,7 @@ func Hierarchical(objs []ast.FileObject, opts Options) ([]ast.FileObject, status
 	//   - adding metadata to resources (such as their filepath in the repo)
 	rawObjects := &objects.Raw{
 		ClusterName:       opts.ClusterName,
+		ClusterLabels:     opts.ClusterLabels,
 		ReconcilerName:    opts.ReconcilerName,
 		PolicyDir:         opts.PolicyDir,
 		Objects:           objs,
@@ -143,6 +158,12 @@ func Hierarchical(objs []ast.FileObject, opts Options) ([]ast.FileObject, status
 		return nil, status.Append(nonBlockingErrs, errs)
 	}
 
+	// Then we evaluate the validation policies, declared in the repo and on the
+	// cluster, against the final objects.
+	if errs = policy.Validate(finalObjects, opts.Policies); errs != nil {
+		return nil, status.Append(nonBlockingErrs, errs)
+	}
+
 	for _, visitor := range opts.Visitors {
 		finalObjects, errs = visitor(finalObjects)
 		if errs != nil {
@@ -165,6 +186,7 @@ func Unstructured(objs []ast.FileObject, opts Options) ([]ast.FileObject, status
 	//   - adding metadata to resources (such as their filepath in the repo)
 	rawObjects := &objects.Raw{
 		ClusterName:       opts.ClusterName,
+		ClusterLabels:     opts.ClusterLabels,
 		ReconcilerName:    opts.ReconcilerName,
 		PolicyDir:         opts.PolicyDir,
 		Objects:           objs,
@@ -198,6 +220,7 @@ func Unstructured(objs []ast.FileObject, opts Options) ([]ast.FileObject, status
 
 	scopedObjects.DefaultNamespace = opts.DefaultNamespace
 	scopedObjects.IsNamespaceReconciler = opts.IsNamespaceReconciler
+	scopedObjects.LiveNamespaceLabels = opts.LiveNamespaceLabels
 	if errs := scoped.Unstructured(scopedObjects); errs != nil {
 		return nil, status.Append(nonBlockingErrs, errs)
 	}
@@ -212,6 +235,12 @@ func Unstructured(objs []ast.FileObject, opts Options) ([]ast.FileObject, status
 		return nil, status.Append(nonBlockingErrs, errs)
 	}
 
+	// Then we evaluate the validation policies, declared in the repo and on the
+	// cluster, against the final objects.
+	if errs := policy.Validate(finalObjects, opts.Policies); errs != nil {
+		return nil, status.Append(nonBlockingErrs, errs)
+	}
+
 	for _, visitor := range opts.Visitors {
 		var errs status.MultiError
 		finalObjects, errs = visitor(finalObjects)
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"kpt.dev/configsync/pkg/api/configmanagement"
 	v1 "kpt.dev/configsync/pkg/api/configmanagement/v1"
+	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
@@ -46,12 +47,40 @@ import (
 	"kpt.dev/configsync/pkg/testing/fake"
 	"kpt.dev/configsync/pkg/testing/openapitest"
 	"kpt.dev/configsync/pkg/util/discovery"
+	"kpt.dev/configsync/pkg/validate/policy"
 	"kpt.dev/configsync/pkg/validate/raw/validate"
 	"sigs.k8s.io/cli-utils/pkg/common"
 )
 
 const dir = "acme"
 
+// teamLabelPolicy requires the Roles to have a team label.
+const teamLabelPolicy = `match:
+  kinds: [Role]
+expression: has(object.metadata.labels.team)
+message: Roles must have a team label`
+
+// policyConfigMap returns a ConfigMap of the source declaring the validation
+// policy in the passed data key.
+func policyConfigMap(path, key, spec string, opts ...core.MetaMutator) ast.FileObject {
+	opts = append(opts, core.Annotation(csmetadata.ValidationPolicyAnnotationKey, csmetadata.ValidationPolicyAnnotationValue))
+	cm := fake.ConfigMapObject(opts...)
+	cm.Data = map[string]string{key: spec}
+	return fake.FileObject(cm, path)
+}
+
+// clusterPolicies returns the policies of the validation policies ConfigMap
+// declaring the validation policy in the passed data key.
+func clusterPolicies(key, spec string) []*policy.Policy {
+	cm := fake.ConfigMapObject(core.Namespace(configsync.ControllerNamespace), core.Name(configsync.ValidationPoliciesName))
+	cm.Data = map[string]string{key: spec}
+	policies, err := policy.FromConfigMap(cm)
+	if err != nil {
+		panic(err)
+	}
+	return policies
+}
+
 func validRootSync(name, path string, opts ...core.MetaMutator) ast.FileObject {
 	rs := fake.RootSyncObjectV1Beta1(name)
 	rs.Spec.SourceType = string(v1beta1.GitSource)
@@ -693,6 +722,32 @@ func TestHierarchical(t *testing.T) {
 			},
 			wantErrs: fake.Errors(nonhierarchical.NameCollisionErrorCode),
 		},
+		{
+			name: "object violating an inherited validation policy fails once",
+			objs: []ast.FileObject{
+				fake.Repo(),
+				fake.Namespace("namespaces/bar/foo"),
+				fake.Namespace("namespaces/bar/qux"),
+				policyConfigMap("namespaces/bar/policies.yaml", "team-label", teamLabelPolicy,
+					core.Name("policies")),
+				fake.RoleAtPath("namespaces/bar/foo/role.yaml",
+					core.Namespace("foo")),
+			},
+			wantErrs: fake.Errors(policy.ViolationErrorCode),
+		},
+		{
+			name: "object violating a cluster validation policy fails",
+			options: Options{
+				Policies: clusterPolicies("team-label", teamLabelPolicy),
+			},
+			objs: []ast.FileObject{
+				fake.Repo(),
+				fake.Namespace("namespaces/foo"),
+				fake.RoleAtPath("namespaces/foo/role.yaml",
+					core.Namespace("foo")),
+			},
+			wantErrs: fake.Errors(policy.ViolationErrorCode),
+		},
 		{
 			name: "invalid namespace name/directory fails",
 			objs: []ast.FileObject{
@@ -1265,6 +1320,54 @@ func TestUnstructured(t *testing.T) {
 			},
 			wantErrs: fake.Errors(validate.SelfReconcileErrorCode),
 		},
+		{
+			name: "object complying with a cluster validation policy",
+			options: Options{
+				Policies: clusterPolicies("team-label", teamLabelPolicy),
+			},
+			objs: []ast.FileObject{
+				fake.RoleAtPath("role.yaml",
+					core.Namespace("foo"),
+					core.Label("team", "shop")),
+			},
+			want: []ast.FileObject{
+				fake.RoleAtPath("role.yaml",
+					core.Namespace("foo"),
+					core.Label("team", "shop"),
+					core.Label(csmetadata.DeclaredVersionLabel, "v1"),
+					core.Annotation(csmetadata.DeclaredFieldsKey, `{"f:metadata":{"f:annotations":{},"f:labels":{"f:team":{}}},"f:rules":{}}`),
+					core.Annotation(csmetadata.SourcePathAnnotationKey, dir+"/role.yaml")),
+			},
+		},
+		{
+			name: "object violating a cluster validation policy",
+			options: Options{
+				Policies: clusterPolicies("team-label", teamLabelPolicy),
+			},
+			objs: []ast.FileObject{
+				fake.RoleAtPath("role.yaml",
+					core.Namespace("foo")),
+			},
+			wantErrs: fake.Errors(policy.ViolationErrorCode),
+		},
+		{
+			name: "object violating a validation policy declared in the repo",
+			objs: []ast.FileObject{
+				policyConfigMap("policies.yaml", "team-label", teamLabelPolicy,
+					core.Namespace("foo")),
+				fake.RoleAtPath("role.yaml",
+					core.Namespace("foo")),
+			},
+			wantErrs: fake.Errors(policy.ViolationErrorCode),
+		},
+		{
+			name: "invalid validation policy declared in the repo",
+			objs: []ast.FileObject{
+				policyConfigMap("policies.yaml", "team-label", "engine: rego\nexpression: deny[msg]",
+					core.Namespace("foo")),
+			},
+			wantErrs: fake.Errors(policy.InvalidPolicyErrorCode),
+		},
 		{
 			name: "RepoSync manages other RepoSync object",
 			options: Options{
@@ -0,0 +1,140 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"fmt"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/core"
+	csmetadata "kpt.dev/configsync/pkg/metadata"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// DefaultBreakGlassMaxDuration is the default longest duration of a
+// break-glass exemption.
+const DefaultBreakGlassMaxDuration = csmetadata.DefaultBreakGlassMaxDuration
+
+const (
+	// breakGlassExemptionReason is the reason of the Events recorded when a
+	// break-glass exemption is granted or revoked.
+	breakGlassExemptionReason = "BreakGlassExemption"
+	// breakGlassBypassReason is the reason of the Events recorded when a
+	// break-glass user modifies or deletes an exempted object.
+	breakGlassBypassReason = "BreakGlassBypass"
+)
+
+var (
+	breakGlassUntilAnnotation = csmetadata.BreakGlassUntilAnnotationKey
+	// breakGlassUntilPath is the path of the break-glass annotation in the
+	// Set returned by ConfigSyncMetadata.
+	breakGlassUntilPath = fieldpath.MakePathOrDie("annotations", breakGlassUntilAnnotation)
+)
+
+// BreakGlassOptions configures the users who may bypass the validation of
+// managed objects during an emergency.
+type BreakGlassOptions struct {
+	// Users are the usernames allowed to exempt managed objects.
+	Users []string
+	// Groups are the groups whose members are allowed to exempt managed
+	// objects.
+	Groups []string
+	// MaxDuration is the longest duration of an exemption.
+	MaxDuration time.Duration
+}
+
+// breakGlass grants and audits the break-glass exemptions of managed objects.
+type breakGlass struct {
+	users    map[string]bool
+	groups   map[string]bool
+	maxDur   time.Duration
+	recorder record.EventRecorder
+	now      func() time.Time
+}
+
+func newBreakGlass(opts BreakGlassOptions, recorder record.EventRecorder) *breakGlass {
+	b := &breakGlass{
+		users:    make(map[string]bool),
+		groups:   make(map[string]bool),
+		maxDur:   opts.MaxDuration,
+		recorder: recorder,
+		now:      time.Now,
+	}
+	for _, user := range opts.Users {
+		b.users[user] = true
+	}
+	for _, group := range opts.Groups {
+		b.groups[group] = true
+	}
+	return b
+}
+
+// allows returns true if the user may bypass the validation of exempted
+// objects.
+func (b *breakGlass) allows(userInfo authenticationv1.UserInfo) bool {
+	if b == nil {
+		return false
+	}
+	if b.users[userInfo.Username] {
+		return true
+	}
+	for _, group := range userInfo.Groups {
+		if b.groups[group] {
+			return true
+		}
+	}
+	return false
+}
+
+// exempted returns true if the object is exempted by an unexpired
+// break-glass annotation.
+func (b *breakGlass) exempted(obj client.Object) bool {
+	return csmetadata.BreakGlassExempted(obj, b.now())
+}
+
+// validateExemption returns an error if the break-glass annotation of the
+// updated object is not a future time within the maximum duration. Removing
+// the annotation is always valid.
+func (b *breakGlass) validateExemption(obj client.Object) error {
+	value, found := obj.GetAnnotations()[breakGlassUntilAnnotation]
+	if !found {
+		return nil
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return fmt.Errorf("the %s annotation must be an RFC 3339 time, but got %q", breakGlassUntilAnnotation, value)
+	}
+	now := b.now()
+	if !until.After(now) {
+		return fmt.Errorf("the %s annotation must be a future time, but got %q", breakGlassUntilAnnotation, value)
+	}
+	if until.Sub(now) > b.maxDur {
+		return fmt.Errorf("the %s annotation must be at most %s from now, but got %q", breakGlassUntilAnnotation, b.maxDur, value)
+	}
+	return nil
+}
+
+// audit logs the bypass and records it as an Event on the object.
+func (b *breakGlass) audit(obj client.Object, username, reason, message string) {
+	klog.Warningf("Break-glass audit: %s: %s %s of object %q", reason, username, message, core.GKNN(obj))
+	if b.recorder != nil {
+		b.recorder.Eventf(obj, corev1.EventTypeWarning, reason, "%s %s", username, message)
+	}
+}
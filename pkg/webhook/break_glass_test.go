@@ -0,0 +1,202 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"kpt.dev/configsync/pkg/core"
+	csmetadata "kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/testing/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestValidator_HandleBreakGlass(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	inHalfAnHour := now.Add(30 * time.Minute).Format(time.RFC3339)
+	inTwoHours := now.Add(2 * time.Hour).Format(time.RFC3339)
+	anHourAgo := now.Add(-time.Hour).Format(time.RFC3339)
+
+	managedRole := func(verbs []string, opts ...core.MetaMutator) client.Object {
+		opts = append([]core.MetaMutator{
+			core.Name("hello"),
+			core.Namespace("world"),
+			core.Label(csmetadata.ManagedByKey, csmetadata.ManagedByValue),
+			core.Annotation(csmetadata.ResourceManagementKey, csmetadata.ResourceManagementEnabled),
+			core.Annotation(csmetadata.ResourceIDKey, "rbac.authorization.k8s.io_role_world_hello"),
+			setRules([]rbacv1.PolicyRule{
+				{
+					APIGroups: []string{""},
+					Resources: []string{"pods"},
+					Verbs:     verbs,
+				},
+			}),
+			core.Annotation(csmetadata.DeclaredFieldsKey, `{"f:metadata":{"f:labels":{"f:app.kubernetes.io/managed-by":{}},"f:annotations":{"f:configmanagement.gke.io/managed":{}}},"f:rules":{}}`),
+		}, opts...)
+		return fake.RoleObject(opts...)
+	}
+	exemptedUntil := func(until string) core.MetaMutator {
+		return core.Annotation(csmetadata.BreakGlassUntilAnnotationKey, until)
+	}
+
+	testCases := []struct {
+		name       string
+		oldObj     client.Object
+		newObj     client.Object
+		user       authenticationv1.UserInfo
+		deny       metav1.StatusReason
+		wantEvents int
+	}{
+		{
+			name:       "On-call exempts a managed object",
+			oldObj:     managedRole([]string{"get"}),
+			newObj:     managedRole([]string{"get"}, exemptedUntil(inHalfAnHour)),
+			user:       onCall(),
+			wantEvents: 1,
+		},
+		{
+			name:       "On-call exempts a managed object and modifies its declared fields",
+			oldObj:     managedRole([]string{"get"}),
+			newObj:     managedRole([]string{"get", "list"}, exemptedUntil(inHalfAnHour)),
+			user:       onCall(),
+			wantEvents: 2,
+		},
+		{
+			name:       "On-call revokes the exemption of a managed object",
+			oldObj:     managedRole([]string{"get"}, exemptedUntil(inHalfAnHour)),
+			newObj:     managedRole([]string{"get"}),
+			user:       onCall(),
+			wantEvents: 1,
+		},
+		{
+			name:   "On-call exempts a managed object for too long",
+			oldObj: managedRole([]string{"get"}),
+			newObj: managedRole([]string{"get"}, exemptedUntil(inTwoHours)),
+			user:   onCall(),
+			deny:   metav1.StatusReasonForbidden,
+		},
+		{
+			name:   "On-call exempts a managed object in the past",
+			oldObj: managedRole([]string{"get"}),
+			newObj: managedRole([]string{"get"}, exemptedUntil(anHourAgo)),
+			user:   onCall(),
+			deny:   metav1.StatusReasonForbidden,
+		},
+		{
+			name:   "On-call exempts a managed object with an invalid time",
+			oldObj: managedRole([]string{"get"}),
+			newObj: managedRole([]string{"get"}, exemptedUntil("tomorrow")),
+			user:   onCall(),
+			deny:   metav1.StatusReasonForbidden,
+		},
+		{
+			name:   "On-call modifies the exemption and other Config Sync metadata",
+			oldObj: managedRole([]string{"get"}),
+			newObj: managedRole([]string{"get"}, exemptedUntil(inHalfAnHour),
+				core.Annotation(csmetadata.ResourceManagementKey, csmetadata.ResourceManagementDisabled)),
+			user: onCall(),
+			deny: metav1.StatusReasonForbidden,
+		},
+		{
+			name:       "On-call modifies the declared fields of an exempted object",
+			oldObj:     managedRole([]string{"get"}, exemptedUntil(inHalfAnHour)),
+			newObj:     managedRole([]string{"get", "list"}, exemptedUntil(inHalfAnHour)),
+			user:       onCall(),
+			wantEvents: 1,
+		},
+		{
+			name:   "On-call modifies the declared fields of an object whose exemption expired",
+			oldObj: managedRole([]string{"get"}, exemptedUntil(anHourAgo)),
+			newObj: managedRole([]string{"get", "list"}, exemptedUntil(anHourAgo)),
+			user:   onCall(),
+			deny:   metav1.StatusReasonForbidden,
+		},
+		{
+			name:   "On-call modifies the declared fields of an object which is not exempted",
+			oldObj: managedRole([]string{"get"}),
+			newObj: managedRole([]string{"get", "list"}),
+			user:   onCall(),
+			deny:   metav1.StatusReasonForbidden,
+		},
+		{
+			name:       "On-call deletes an exempted object",
+			oldObj:     managedRole([]string{"get"}, exemptedUntil(inHalfAnHour)),
+			user:       onCall(),
+			wantEvents: 1,
+		},
+		{
+			name:   "Bob exempts a managed object",
+			oldObj: managedRole([]string{"get"}),
+			newObj: managedRole([]string{"get"}, exemptedUntil(inHalfAnHour)),
+			user:   bob(),
+			deny:   metav1.StatusReasonForbidden,
+		},
+		{
+			name:   "Bob modifies the declared fields of an exempted object",
+			oldObj: managedRole([]string{"get"}, exemptedUntil(inHalfAnHour)),
+			newObj: managedRole([]string{"get", "list"}, exemptedUntil(inHalfAnHour)),
+			user:   bob(),
+			deny:   metav1.StatusReasonForbidden,
+		},
+		{
+			name:   "Bob deletes an exempted object",
+			oldObj: managedRole([]string{"get"}, exemptedUntil(inHalfAnHour)),
+			user:   bob(),
+			deny:   metav1.StatusReasonUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			v := validatorForTest(t)
+			v.breakGlass = newBreakGlass(BreakGlassOptions{
+				Groups:      []string{"on-call@acme.com"},
+				MaxDuration: time.Hour,
+			}, recorder)
+			v.breakGlass.now = func() time.Time { return now }
+
+			req := request(tc.oldObj, tc.newObj)
+			req.UserInfo = tc.user
+
+			resp := v.Handle(context.Background(), req)
+			if resp.Allowed {
+				if tc.deny != "" {
+					t.Errorf("got Handle() response allowed, want denied %q", tc.deny)
+				}
+			} else if tc.deny == "" {
+				t.Errorf("got Handle() response denied %q, want allowed", resp.Result.Message)
+			} else if tc.deny != resp.Result.Reason {
+				t.Errorf("got Handle() response denied %q, want denied %q", resp.Result.Reason, tc.deny)
+			}
+			if got := len(recorder.Events); got != tc.wantEvents {
+				t.Errorf("got %d audit Events, want %d", got, tc.wantEvents)
+			}
+		})
+	}
+}
+
+func onCall() authenticationv1.UserInfo {
+	return authenticationv1.UserInfo{
+		Groups:   []string{"on-call@acme.com"},
+		Username: "alice@acme.com",
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/pkg/errors"
 	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/discovery"
@@ -35,14 +36,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 )
 
 // AddValidator adds the admission webhook validator to the passed manager.
-func AddValidator(mgr manager.Manager) error {
+func AddValidator(mgr manager.Manager, breakGlassOpts BreakGlassOptions) error {
 	handler, err := handler(mgr.GetConfig())
 	if err != nil {
 		return err
 	}
+	handler.breakGlass = newBreakGlass(breakGlassOpts, mgr.GetEventRecorderFor("admission-webhook"))
 	mgr.GetWebhookServer().Register(configuration.ServingPath, &webhook.Admission{
 		Handler: handler,
 	})
@@ -53,6 +56,9 @@ func AddValidator(mgr manager.Manager) error {
 // requests and admits or denies them.
 type Validator struct {
 	differ *ObjectDiffer
+	// breakGlass grants the break-glass exemptions. It is nil if no user may
+	// bypass the validation.
+	breakGlass *breakGlass
 }
 
 var _ admission.Handler = &Validator{}
@@ -67,7 +73,7 @@ func handler(cfg *rest.Config) (*Validator, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Validator{&ObjectDiffer{vc}}, nil
+	return &Validator{differ: &ObjectDiffer{vc}}, nil
 }
 
 // Handle implements admission.Handler
@@ -113,9 +119,9 @@ func (v *Validator) Handle(_ context.Context, req admission.Request) admission.R
 	case admissionv1.Create:
 		return v.handleCreate(newObj, username)
 	case admissionv1.Delete:
-		return v.handleDelete(oldObj, username)
+		return v.handleDelete(oldObj, req.UserInfo)
 	case admissionv1.Update:
-		return v.handleUpdate(oldObj, newObj, username)
+		return v.handleUpdate(oldObj, newObj, req.UserInfo)
 	default:
 		klog.Errorf("Unsupported operation: %v from %s", req.Operation, username)
 		return allow()
@@ -130,20 +136,26 @@ func (v *Validator) handleCreate(newObj client.Object, username string) admissio
 	return allow()
 }
 
-func (v *Validator) handleDelete(oldObj client.Object, username string) admission.Response {
+func (v *Validator) handleDelete(oldObj client.Object, userInfo authenticationv1.UserInfo) admission.Response {
+	username := userInfo.Username
 	// This means a delete request was previously made and accepted, but removal of the API object is not yet complete.
 	// See http://b/199235728#comment16 for more details.
 	if oldObj.GetDeletionTimestamp() != nil {
 		return allow()
 	}
 	if differ.ManagedByConfigSync(oldObj) {
+		if v.breakGlass.allows(userInfo) && v.breakGlass.exempted(oldObj) {
+			v.breakGlass.audit(oldObj, username, breakGlassBypassReason, "deleted the exempted managed resource")
+			return allow()
+		}
 		klog.Errorf("%s is not authorized to delete managed resource %q", username, core.GKNN(oldObj))
 		return deny(metav1.StatusReasonUnauthorized, fmt.Sprintf("%s is not authorized to delete managed resource %q", username, core.GKNN(oldObj)))
 	}
 	return allow()
 }
 
-func (v *Validator) handleUpdate(oldObj, newObj client.Object, username string) admission.Response {
+func (v *Validator) handleUpdate(oldObj, newObj client.Object, userInfo authenticationv1.UserInfo) admission.Response {
+	username := userInfo.Username
 	if !differ.ManagedByConfigSync(oldObj) && !differ.ManagedByConfigSync(newObj) {
 		// Both oldObj and newObj are not managed by Config Sync.
 		// The webhook should be configured to only intercept resources which are
@@ -159,9 +171,28 @@ func (v *Validator) handleUpdate(oldObj, newObj client.Object, username string)
 		return allow()
 	}
 
+	// A break-glass user may grant, extend or revoke an exemption by changing
+	// the break-glass annotation, as long as no other Config Sync metadata
+	// changes.
+	csSet := ConfigSyncMetadata(diffSet)
+	if csSet.Has(breakGlassUntilPath) && v.breakGlass.allows(userInfo) {
+		csSet = csSet.Difference(fieldpath.NewSet(breakGlassUntilPath))
+		if csSet.Empty() {
+			if err := v.breakGlass.validateExemption(newObj); err != nil {
+				klog.Errorf("%s cannot exempt object %q: %v", username, core.GKNN(oldObj), err)
+				return deny(metav1.StatusReasonForbidden, fmt.Sprintf("%s cannot exempt object %q: %v", username, core.GKNN(oldObj), err))
+			}
+			if until, found := newObj.GetAnnotations()[breakGlassUntilAnnotation]; found {
+				v.breakGlass.audit(newObj, username, breakGlassExemptionReason, "exempted the managed resource until "+until)
+			} else {
+				v.breakGlass.audit(newObj, username, breakGlassExemptionReason, "revoked the exemption of the managed resource")
+			}
+		}
+	}
+
 	// If the diff set includes any ConfigSync labels or annotations, reject the
 	// request immediately.
-	if csSet := ConfigSyncMetadata(diffSet); !csSet.Empty() {
+	if !csSet.Empty() {
 		klog.Errorf("%s cannot modify Config Sync metadata of object %q: %s", username, core.GKNN(oldObj), csSet.String())
 		return deny(metav1.StatusReasonForbidden, fmt.Sprintf("%s cannot modify Config Sync metadata of object %q: %s", username, core.GKNN(oldObj), csSet.String()))
 	}
@@ -184,6 +215,10 @@ func (v *Validator) handleUpdate(oldObj, newObj client.Object, username string)
 	// request. Otherwise allow it.
 	invalidSet := diffSet.Intersection(declaredSet)
 	if !invalidSet.Empty() {
+		if v.breakGlass.allows(userInfo) && v.breakGlass.exempted(newObj) {
+			v.breakGlass.audit(newObj, username, breakGlassBypassReason, "modified the fields of the exempted managed resource: "+invalidSet.String())
+			return allow()
+		}
 		klog.Errorf("%s cannot modify fields of object %q managed by Config Sync: %s", username, core.GKNN(oldObj), invalidSet.String())
 		return deny(metav1.StatusReasonForbidden, fmt.Sprintf("%s cannot modify fields of object %q managed by Config Sync: %s", username, core.GKNN(oldObj), invalidSet.String()))
 	}